@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/storage"
+	"securities-marketplace/domains/users/projections"
+)
+
+func main() {
+	var action = flag.String("action", "list", "Action to perform (list/pause/resume/reset/fail)")
+	var name = flag.String("name", "", "Projection name (required for controls)")
+	var position = flag.Int64("position", 0, "Event number to reset the projection to")
+	var reason = flag.String("reason", "", "Reason for marking the projection failed")
+	flag.Parse()
+
+	// Get database connection
+	db, err := storage.NewPostgresConnection()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	monitor := events.NewProjectionMonitor(events.NewEventStore(db), projections.ProjectionNames()...)
+
+	if *action != "list" && *name == "" {
+		log.Fatalf("-name is required for %s", *action)
+	}
+
+	switch *action {
+	case "list":
+		err = listProjections(monitor)
+	case "pause":
+		err = monitor.Pause(*name)
+	case "resume":
+		err = monitor.Resume(*name)
+	case "reset":
+		err = monitor.Reset(*name, *position)
+	case "fail":
+		err = monitor.MarkFailed(*name, *reason)
+	default:
+		log.Fatalf("Invalid action: %s (use 'list', 'pause', 'resume', 'reset' or 'fail')", *action)
+	}
+
+	if err != nil {
+		log.Fatalf("Projection %s failed: %v", *action, err)
+	}
+
+	if *action != "list" {
+		log.Printf("Projection %s: %s completed", *name, *action)
+	}
+}
+
+// listProjections prints the health of every projection as a table
+func listProjections(monitor *events.ProjectionMonitor) error {
+	health, err := monitor.Health()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECTION\tSTATUS\tPOSITION\tHEAD\tLAG\tEVENTS/SEC\tLAST PROCESSED\tLAST ERROR")
+	for _, projection := range health {
+		lastProcessed := "-"
+		if !projection.LastProcessedAt.IsZero() {
+			lastProcessed = projection.LastProcessedAt.Format("2006-01-02 15:04:05")
+		}
+		lastError := "-"
+		if projection.LastError != "" {
+			lastError = projection.LastError
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%.1f\t%s\t%s\n",
+			projection.ProjectionName,
+			projection.Status,
+			projection.LastProcessedEventNumber,
+			projection.HeadEventNumber,
+			projection.Lag,
+			projection.Throughput,
+			lastProcessed,
+			lastError,
+		)
+	}
+
+	return w.Flush()
+}
@@ -2,14 +2,23 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"securities-marketplace/domains/securities"
 	"securities-marketplace/domains/shared/events"
 	"securities-marketplace/domains/shared/storage"
+	"securities-marketplace/domains/trading/calendar"
+	"securities-marketplace/domains/trading/execution"
+	"securities-marketplace/domains/trading/marketdata"
+	"securities-marketplace/domains/trading/risk"
+	"securities-marketplace/domains/trading/session"
+	"securities-marketplace/domains/users"
+	"securities-marketplace/domains/users/projections"
 )
 
 func main() {
@@ -33,12 +42,32 @@ func main() {
 	// Initialize event store
 	eventStore := events.NewEventStore(db)
 
+	// Load the trading calendar; without one trades settle T+2 on weekdays
+	// and market data reports the default calendar's market hours
+	tradingCalendar := loadTradingCalendar()
+
+	// Risk limits are edited by compliance and checked on every trade
+	riskEngine := newRiskEngine(db, eventStore, eventBus, tradingCalendar)
+
+	// Market data is computed from trade history and cached in Redis until
+	// the security trades again
+	marketData := marketdata.NewProvider(execution.NewEventSourcedTradeRepository(eventStore), marketdata.NewPostgresMarkStore(db), storage.NewRedisCache(redis), tradingCalendar)
+	if err := marketData.Subscribe(eventBus); err != nil {
+		log.Printf("Failed to subscribe market data to trades, relying on cache expiry: %v", err)
+	}
+
+	// Every command that can trade a security runs through one sequencer,
+	// whose leases keep other workers from trading the same security and
+	// whose actors own the books they match against
+	sequencer := execution.NewMatchingSequencer(storage.NewRedisCache(redis), eventStore)
+	defer sequencer.Close()
+
 	// Start background workers
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Start projection workers
-	go startProjectionWorkers(ctx, eventStore, eventBus)
+	go startProjectionWorkers(ctx, db, eventStore)
 
 	// Start settlement worker
 	go startSettlementWorker(ctx, eventStore, eventBus)
@@ -46,6 +75,28 @@ func main() {
 	// Start compliance monitoring worker
 	go startComplianceWorker(ctx, eventStore, eventBus)
 
+	// Start call auction worker
+	go startAuctionWorker(ctx, eventStore, eventBus, sequencer, tradingCalendar, riskEngine, marketData)
+
+	// Start request for quote expiry worker
+	go startQuoteExpiryWorker(ctx, eventStore, eventBus)
+
+	// Start stop order monitor
+	go startStopOrderWorker(ctx, eventStore, eventBus, sequencer, riskEngine, marketData)
+
+	// Start order expiry worker
+	go startOrderExpiryWorker(ctx, eventStore, eventBus, tradingCalendar)
+
+	// Start volatility halt reopening worker
+	go startVolatilityHaltWorker(ctx, eventStore, eventBus, sequencer, tradingCalendar)
+
+	// Start market session worker
+	go startSessionWorker(ctx, eventStore, eventBus, sequencer, tradingCalendar)
+
+	// Start quote worker, which keeps the best bid and ask in the market
+	// data projection current with the live books
+	go startQuoteWorker(ctx, db, eventStore, eventBus, tradingCalendar)
+
 	log.Println("Worker started")
 
 	// Wait for interrupt signal
@@ -62,9 +113,45 @@ func main() {
 	log.Println("Worker exited")
 }
 
-func startProjectionWorkers(ctx context.Context, eventStore events.EventStore, eventBus events.EventBus) {
+// loadTradingCalendar loads the calendar file named by TRADING_CALENDAR, or
+// returns nil when none is configured or it cannot be loaded
+func loadTradingCalendar() *calendar.Calendar {
+	tradingCalendar, err := calendar.LoadFromEnv()
+	if err != nil {
+		log.Printf("Failed to load trading calendar, trading without one: %v", err)
+		return nil
+	}
+	if tradingCalendar != nil {
+		log.Printf("Loaded trading calendar %s", tradingCalendar.Name)
+	}
+	return tradingCalendar
+}
+
+// newRiskEngine creates the risk engine that checks trades against the limits
+// stored in Postgres
+func newRiskEngine(db *sql.DB, eventStore events.EventStore, eventBus events.EventBus, tradingCalendar *calendar.Calendar) *risk.Engine {
+	exposures := risk.NewTradeExposures(
+		securities.NewEventSourcedSecurityRepository(eventStore),
+		execution.NewEventSourcedTradeRepository(eventStore),
+		tradingCalendar,
+	)
+	return risk.NewEngine(risk.NewPostgresLimitStore(db), exposures, eventStore, eventBus)
+}
+
+func startProjectionWorkers(ctx context.Context, db *sql.DB, eventStore *events.PostgresEventStore) {
 	log.Println("Starting projection workers...")
-	// TODO: Implement projection workers
+
+	// Each runner advances its own checkpoint, which read-your-writes
+	// queries wait on
+	userRepository := users.NewEventSourcedUserRepository(eventStore)
+	runners := []*events.ProjectionRunner{
+		events.NewProjectionRunner(eventStore, eventStore, projections.NewUserProfileProjection(db), userRepository.DecodeEvent),
+		events.NewProjectionRunner(eventStore, eventStore, projections.NewComplianceProjection(db), userRepository.DecodeEvent),
+	}
+
+	for _, runner := range runners {
+		go runner.Run(ctx)
+	}
 }
 
 func startSettlementWorker(ctx context.Context, eventStore events.EventStore, eventBus events.EventBus) {
@@ -75,4 +162,181 @@ func startSettlementWorker(ctx context.Context, eventStore events.EventStore, ev
 func startComplianceWorker(ctx context.Context, eventStore events.EventStore, eventBus events.EventBus) {
 	log.Println("Starting compliance worker...")
 	// TODO: Implement compliance worker
-}
\ No newline at end of file
+}
+
+func startAuctionWorker(ctx context.Context, eventStore events.EventStore, eventBus events.EventBus, sequencer *execution.MatchingSequencer, tradingCalendar *calendar.Calendar, riskEngine *risk.Engine, marketData *marketdata.Provider) {
+	log.Println("Starting call auction worker...")
+
+	securityService := securities.NewSecurityService(securities.NewEventSourcedSecurityRepository(eventStore), eventStore, eventBus)
+	executionService := execution.NewExecutionService(execution.NewEventSourcedTradeRepository(eventStore), eventStore, eventBus)
+
+	mode, err := execution.ParseSelfTradePreventionMode(os.Getenv("SELF_TRADE_PREVENTION"))
+	if err != nil {
+		log.Printf("Invalid SELF_TRADE_PREVENTION, cancelling newest orders: %v", err)
+		mode = execution.SelfTradeCancelNewest
+	}
+	executionService.SetSelfTradePrevention(mode)
+	executionService.SetRiskEngine(riskEngine)
+
+	// Bands are set around the reference prices market data supplies
+	executionService.SetMarketDataProvider(marketData)
+	executionService.SetPriceBands(execution.DefaultPriceBands())
+	if tradingCalendar != nil {
+		executionService.SetTradingCalendar(tradingCalendar)
+	}
+
+	executionService.SetMatchingSequencer(sequencer)
+
+	execution.NewAuctionScheduler(executionService, securityService).Run(ctx)
+}
+
+func startQuoteExpiryWorker(ctx context.Context, eventStore events.EventStore, eventBus events.EventBus) {
+	log.Println("Starting quote expiry worker...")
+
+	executionService := execution.NewExecutionService(execution.NewEventSourcedTradeRepository(eventStore), eventStore, eventBus)
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := executionService.ExpireQuoteRequests(); err != nil {
+				log.Printf("Failed to expire requests for quote: %v", err)
+			}
+		}
+	}
+}
+
+func startStopOrderWorker(ctx context.Context, eventStore events.EventStore, eventBus events.EventBus, sequencer *execution.MatchingSequencer, riskEngine *risk.Engine, marketData *marketdata.Provider) {
+	log.Println("Starting stop order worker...")
+
+	executionService := execution.NewExecutionService(execution.NewEventSourcedTradeRepository(eventStore), eventStore, eventBus)
+	executionService.SetRiskEngine(riskEngine)
+	executionService.SetMatchingSequencer(sequencer)
+
+	monitor := execution.NewStopOrderMonitor(executionService)
+	monitor.SetMarketDataProvider(marketData)
+	if err := monitor.Subscribe(eventBus); err != nil {
+		log.Printf("Failed to start stop order monitor: %v", err)
+		return
+	}
+	monitor.Run(ctx)
+}
+
+func startOrderExpiryWorker(ctx context.Context, eventStore events.EventStore, eventBus events.EventBus, tradingCalendar *calendar.Calendar) {
+	log.Println("Starting order expiry worker...")
+
+	// Day orders need market hours; without a trading calendar only
+	// good-till-date orders are expired
+	executionService := execution.NewExecutionService(execution.NewEventSourcedTradeRepository(eventStore), eventStore, eventBus)
+	if tradingCalendar != nil {
+		executionService.SetTradingCalendar(tradingCalendar)
+	}
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := executionService.ExpireOrders(); err != nil {
+				log.Printf("Failed to expire orders: %v", err)
+			}
+		}
+	}
+}
+
+func startVolatilityHaltWorker(ctx context.Context, eventStore events.EventStore, eventBus events.EventBus, sequencer *execution.MatchingSequencer, tradingCalendar *calendar.Calendar) {
+	log.Println("Starting volatility halt worker...")
+
+	executionService := execution.NewExecutionService(execution.NewEventSourcedTradeRepository(eventStore), eventStore, eventBus)
+	executionService.SetMatchingSequencer(sequencer)
+	if tradingCalendar != nil {
+		executionService.SetTradingCalendar(tradingCalendar)
+	}
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reopened, err := executionService.ReopenHaltedSecurities()
+			if err != nil {
+				log.Printf("Failed to reopen halted securities: %v", err)
+				continue
+			}
+			for _, securityID := range reopened {
+				log.Printf("Reopened %s after volatility halt", securityID)
+			}
+		}
+	}
+}
+
+// startSessionWorker moves the market session through pre-open, the opening
+// auction, continuous trading and the closing auction on the trading
+// calendar. Without a calendar sessions are not scheduled and matching is
+// not gated by them.
+func startSessionWorker(ctx context.Context, eventStore events.EventStore, eventBus events.EventBus, sequencer *execution.MatchingSequencer, tradingCalendar *calendar.Calendar) {
+	if tradingCalendar == nil {
+		log.Println("No trading calendar, market sessions are not scheduled")
+		return
+	}
+
+	schedule := session.DefaultSchedule()
+	if err := schedule.Validate(tradingCalendar); err != nil {
+		log.Printf("Invalid session schedule for %s, market sessions are not scheduled: %v", tradingCalendar.Name, err)
+		return
+	}
+	log.Println("Starting market session worker...")
+
+	executionService := execution.NewExecutionService(execution.NewEventSourcedTradeRepository(eventStore), eventStore, eventBus)
+	executionService.SetTradingCalendar(tradingCalendar)
+	executionService.SetMatchingSequencer(sequencer)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := executionService.SyncMarketSession(schedule, time.Now())
+			for _, state := range changed {
+				log.Printf("Market session is now %s", state)
+			}
+			if err != nil {
+				log.Printf("Failed to advance market session: %v", err)
+			}
+		}
+	}
+}
+
+func startQuoteWorker(ctx context.Context, db *sql.DB, eventStore events.EventStore, eventBus events.EventBus, tradingCalendar *calendar.Calendar) {
+	log.Println("Starting quote worker...")
+
+	executionService := execution.NewExecutionService(execution.NewEventSourcedTradeRepository(eventStore), eventStore, eventBus)
+	recorder := marketdata.NewQuoteRecorder(executionService, marketdata.NewPostgresQuoteStore(db), tradingCalendar)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := recorder.Record(); err != nil {
+				log.Printf("Failed to record quotes: %v", err)
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
 )
 
 // SecurityType represents the type of security
@@ -24,13 +25,14 @@ type SecurityStatus string
 const (
 	SecurityStatusActive    SecurityStatus = "active"
 	SecurityStatusSuspended SecurityStatus = "suspended"
+	SecurityStatusHalted    SecurityStatus = "halted" // Volatility halt, lifted by a reopening auction
 	SecurityStatusDelisted  SecurityStatus = "delisted"
 	SecurityStatusPending   SecurityStatus = "pending"
 )
 
 // DividendInfo holds dividend information
 type DividendInfo struct {
-	DividendPerShare float64   `json:"dividendPerShare"`
+	DividendPerShare money.Decimal `json:"dividendPerShare"`
 	ExDividendDate   time.Time `json:"exDividendDate"`
 	PaymentDate      time.Time `json:"paymentDate"`
 	RecordDate       time.Time `json:"recordDate"`
@@ -64,7 +66,8 @@ type SecurityAggregate struct {
 	Name         string            `json:"name"`
 	Symbol       string            `json:"symbol"`
 	TotalShares  int64             `json:"totalShares"`
-	ParValue     *float64          `json:"parValue,omitempty"`
+	ParValue     *money.Decimal    `json:"parValue,omitempty"`
+	Currency     string            `json:"currency"` // Currency the security trades in
 	Details      map[string]string `json:"details"`
 	
 	// Status and lifecycle
@@ -76,6 +79,11 @@ type SecurityAggregate struct {
 	SuspendedAt     *time.Time `json:"suspendedAt,omitempty"`
 	SuspensionUntil *time.Time `json:"suspensionUntil,omitempty"`
 	SuspensionReason string    `json:"suspensionReason,omitempty"`
+
+	// Volatility halt details
+	HaltedAt    *time.Time `json:"haltedAt,omitempty"`
+	HaltedUntil *time.Time `json:"haltedUntil,omitempty"`
+	HaltReason  string     `json:"haltReason,omitempty"`
 	
 	// Documents and compliance
 	Documents       []SecurityDocument `json:"documents"`
@@ -89,9 +97,12 @@ type SecurityAggregate struct {
 	Ownership map[string]*OwnershipRecord `json:"ownership"`
 	
 	// Market data cache (for performance)
-	LastTradePrice *float64   `json:"lastTradePrice,omitempty"`
-	MarketCap      *float64   `json:"marketCap,omitempty"`
+	LastTradePrice *money.Decimal `json:"lastTradePrice,omitempty"`
+	MarketCap      *money.Decimal `json:"marketCap,omitempty"`
 	LastUpdated    *time.Time `json:"lastUpdated,omitempty"`
+
+	// Periodic call auctions; nil for continuously traded securities
+	AuctionSchedule *AuctionSchedule `json:"auctionSchedule,omitempty"`
 }
 
 // NewSecurityAggregate creates a new security aggregate
@@ -108,12 +119,15 @@ func NewSecurityAggregate(securityID string) *SecurityAggregate {
 }
 
 // ListSecurity lists a new security
-func (s *SecurityAggregate) ListSecurity(issuerID string, securityType SecurityType, name, symbol string, totalShares int64, parValue *float64, details map[string]string) error {
+func (s *SecurityAggregate) ListSecurity(issuerID string, securityType SecurityType, name, symbol string, totalShares int64, parValue *money.Decimal, currency string, details map[string]string) error {
 	if s.Version > 0 {
 		return fmt.Errorf("security already exists")
 	}
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
 
-	event := NewSecurityListed(s.ID, issuerID, string(securityType), name, symbol, totalShares, parValue, details)
+	event := NewSecurityListed(s.ID, issuerID, string(securityType), name, symbol, totalShares, parValue, currency, details)
 	s.AddEvent(event)
 	return s.ApplyEvent(event)
 }
@@ -172,6 +186,30 @@ func (s *SecurityAggregate) ReinstateTrading(reinstatedBy, reason string) error
 	return s.ApplyEvent(event)
 }
 
+// HaltTrading halts trading after a price band breach. Orders may still be
+// placed and cancelled; they trade again in the reopening auction after
+// haltedUntil.
+func (s *SecurityAggregate) HaltTrading(reason string, triggerPrice, lowerBand, upperBand money.Decimal, haltedUntil time.Time) error {
+	if s.Status != SecurityStatusActive {
+		return fmt.Errorf("can only halt trading of active securities")
+	}
+
+	event := NewSecurityTradingHalted(s.ID, reason, triggerPrice, lowerBand, upperBand, haltedUntil)
+	s.AddEvent(event)
+	return s.ApplyEvent(event)
+}
+
+// ResumeTrading lifts a volatility halt once the reopening auction has run
+func (s *SecurityAggregate) ResumeTrading(resumedBy string, reopeningPrice *money.Decimal) error {
+	if s.Status != SecurityStatusHalted {
+		return fmt.Errorf("security is not halted")
+	}
+
+	event := NewSecurityTradingResumed(s.ID, resumedBy, reopeningPrice)
+	s.AddEvent(event)
+	return s.ApplyEvent(event)
+}
+
 // DelistSecurity delists the security
 func (s *SecurityAggregate) DelistSecurity(reason, delistedBy string, effectiveAt time.Time) error {
 	if s.Status == SecurityStatusDelisted {
@@ -203,8 +241,34 @@ func (s *SecurityAggregate) TransferOwnership(fromOwner, toOwner string, sharesC
 	return s.ApplyEvent(event)
 }
 
+// ReverseOwnershipTransfer returns shares a busted or corrected trade moved,
+// from the buyer back to the seller. Unlike a transfer it is allowed while
+// trading is suspended, since it undoes a trade rather than making one.
+func (s *SecurityAggregate) ReverseOwnershipTransfer(fromOwner, toOwner string, sharesCount int64, tradeID, reason string) error {
+	if s.Status == SecurityStatusDelisted {
+		return fmt.Errorf("cannot reverse ownership of delisted security")
+	}
+
+	if sharesCount <= 0 {
+		return fmt.Errorf("shares count must be greater than zero")
+	}
+
+	if reason == "" {
+		return fmt.Errorf("reversal reason is required")
+	}
+
+	fromRecord, exists := s.Ownership[fromOwner]
+	if !exists || fromRecord.SharesOwned < sharesCount {
+		return fmt.Errorf("%s no longer holds the %d shares to return", fromOwner, sharesCount)
+	}
+
+	event := NewSecurityOwnershipReversed(s.ID, fromOwner, toOwner, sharesCount, tradeID, reason)
+	s.AddEvent(event)
+	return s.ApplyEvent(event)
+}
+
 // DeclareDividend declares a dividend
-func (s *SecurityAggregate) DeclareDividend(dividendPerShare float64, exDividendDate, paymentDate, recordDate time.Time, declaredBy string) error {
+func (s *SecurityAggregate) DeclareDividend(dividendPerShare money.Decimal, exDividendDate, paymentDate, recordDate time.Time, declaredBy string) error {
 	if s.Status != SecurityStatusActive {
 		return fmt.Errorf("can only declare dividends for active securities")
 	}
@@ -225,6 +289,32 @@ func (s *SecurityAggregate) AnnounceSplit(splitRatio string, effectiveAt time.Ti
 	return s.ApplyEvent(event)
 }
 
+// SetAuctionSchedule moves the security to periodic call auctions, or changes
+// the schedule of its auctions
+func (s *SecurityAggregate) SetAuctionSchedule(schedule AuctionSchedule, setBy string) error {
+	if s.Status == SecurityStatusDelisted {
+		return fmt.Errorf("cannot schedule auctions for a delisted security")
+	}
+	if err := schedule.Validate(); err != nil {
+		return fmt.Errorf("invalid auction schedule: %w", err)
+	}
+
+	event := NewSecurityAuctionScheduleSet(s.ID, schedule, setBy)
+	s.AddEvent(event)
+	return s.ApplyEvent(event)
+}
+
+// ClearAuctionSchedule returns the security to continuous trading
+func (s *SecurityAggregate) ClearAuctionSchedule(clearedBy string) error {
+	if s.AuctionSchedule == nil {
+		return fmt.Errorf("security has no auction schedule")
+	}
+
+	event := NewSecurityAuctionScheduleCleared(s.ID, clearedBy)
+	s.AddEvent(event)
+	return s.ApplyEvent(event)
+}
+
 // ApplyEvent applies an event to the aggregate
 func (s *SecurityAggregate) ApplyEvent(event events.DomainEvent) error {
 	switch e := event.(type) {
@@ -242,10 +332,20 @@ func (s *SecurityAggregate) ApplyEvent(event events.DomainEvent) error {
 		return s.applySecurityDelisted(e)
 	case *SecurityOwnershipChanged:
 		return s.applySecurityOwnershipChanged(e)
+	case *SecurityOwnershipReversed:
+		return s.applySecurityOwnershipReversed(e)
 	case *SecurityDividendDeclared:
 		return s.applySecurityDividendDeclared(e)
 	case *SecuritySplitAnnounced:
 		return s.applySecuritySplitAnnounced(e)
+	case *SecurityAuctionScheduleSet:
+		return s.applySecurityAuctionScheduleSet(e)
+	case *SecurityAuctionScheduleCleared:
+		return s.applySecurityAuctionScheduleCleared(e)
+	case *SecurityTradingHalted:
+		return s.applySecurityTradingHalted(e)
+	case *SecurityTradingResumed:
+		return s.applySecurityTradingResumed(e)
 	default:
 		return fmt.Errorf("unknown event type: %T", event)
 	}
@@ -271,6 +371,11 @@ func (s *SecurityAggregate) applySecurityListed(event *SecurityListed) error {
 	s.Symbol = event.Symbol
 	s.TotalShares = event.TotalShares
 	s.ParValue = event.ParValue
+	s.Currency = event.Currency
+	if s.Currency == "" {
+		// Listed before securities had a currency
+		s.Currency = money.DefaultCurrency
+	}
 	s.Details = event.Details
 	s.Status = SecurityStatusActive
 	s.ListedAt = event.Timestamp
@@ -310,7 +415,8 @@ func (s *SecurityAggregate) applySecurityUpdated(event *SecurityUpdated) error {
 			}
 		case "parValue":
 			if v, ok := value.(float64); ok {
-				s.ParValue = &v
+				parValue := money.NewDecimalFromFloat(v)
+				s.ParValue = &parValue
 			}
 		}
 	}
@@ -348,26 +454,38 @@ func (s *SecurityAggregate) applySecurityDelisted(event *SecurityDelisted) error
 }
 
 func (s *SecurityAggregate) applySecurityOwnershipChanged(event *SecurityOwnershipChanged) error {
+	s.moveShares(event.FromOwner, event.ToOwner, event.SharesCount)
+	
+	s.IncrementVersion()
+	return nil
+}
+
+func (s *SecurityAggregate) applySecurityOwnershipReversed(event *SecurityOwnershipReversed) error {
+	s.moveShares(event.FromOwner, event.ToOwner, event.SharesCount)
+	
+	s.IncrementVersion()
+	return nil
+}
+
+// moveShares moves shares between owners, dropping owners left with none
+func (s *SecurityAggregate) moveShares(fromOwner, toOwner string, sharesCount int64) {
 	// Update from owner
-	fromRecord := s.Ownership[event.FromOwner]
-	fromRecord.SharesOwned -= event.SharesCount
+	fromRecord := s.Ownership[fromOwner]
+	fromRecord.SharesOwned -= sharesCount
 	if fromRecord.SharesOwned == 0 {
-		delete(s.Ownership, event.FromOwner)
+		delete(s.Ownership, fromOwner)
 	}
 	
 	// Update to owner
-	toRecord, exists := s.Ownership[event.ToOwner]
+	toRecord, exists := s.Ownership[toOwner]
 	if !exists {
 		toRecord = &OwnershipRecord{
-			OwnerID:     event.ToOwner,
+			OwnerID:     toOwner,
 			SharesOwned: 0,
 		}
-		s.Ownership[event.ToOwner] = toRecord
+		s.Ownership[toOwner] = toRecord
 	}
-	toRecord.SharesOwned += event.SharesCount
-	
-	s.IncrementVersion()
-	return nil
+	toRecord.SharesOwned += sharesCount
 }
 
 func (s *SecurityAggregate) applySecurityDividendDeclared(event *SecurityDividendDeclared) error {
@@ -402,6 +520,44 @@ func (s *SecurityAggregate) applySecuritySplitAnnounced(event *SecuritySplitAnno
 	return nil
 }
 
+func (s *SecurityAggregate) applySecurityAuctionScheduleSet(event *SecurityAuctionScheduleSet) error {
+	schedule := event.Schedule
+	s.AuctionSchedule = &schedule
+
+	s.IncrementVersion()
+	return nil
+}
+
+func (s *SecurityAggregate) applySecurityAuctionScheduleCleared(event *SecurityAuctionScheduleCleared) error {
+	s.AuctionSchedule = nil
+
+	s.IncrementVersion()
+	return nil
+}
+
+func (s *SecurityAggregate) applySecurityTradingHalted(event *SecurityTradingHalted) error {
+	s.Status = SecurityStatusHalted
+	s.HaltedAt = &event.Timestamp
+	s.HaltedUntil = &event.HaltedUntil
+	s.HaltReason = event.Reason
+
+	s.IncrementVersion()
+	return nil
+}
+
+func (s *SecurityAggregate) applySecurityTradingResumed(event *SecurityTradingResumed) error {
+	s.Status = SecurityStatusActive
+	s.HaltedAt = nil
+	s.HaltedUntil = nil
+	s.HaltReason = ""
+	if event.ReopeningPrice != nil {
+		s.LastTradePrice = event.ReopeningPrice
+	}
+
+	s.IncrementVersion()
+	return nil
+}
+
 // Helper methods
 
 // IsActive returns true if the security is actively trading
@@ -414,6 +570,17 @@ func (s *SecurityAggregate) IsTradable() bool {
 	return s.Status == SecurityStatusActive
 }
 
+// IsHalted returns true if trading is halted pending a reopening auction
+func (s *SecurityAggregate) IsHalted() bool {
+	return s.Status == SecurityStatusHalted
+}
+
+// IsReadyToReopen returns true if the security is halted and its cooling-off
+// period has ended
+func (s *SecurityAggregate) IsReadyToReopen(now time.Time) bool {
+	return s.IsHalted() && s.HaltedUntil != nil && !now.Before(*s.HaltedUntil)
+}
+
 // GetOwnershipPercentage returns the ownership percentage for a given owner
 func (s *SecurityAggregate) GetOwnershipPercentage(ownerID string) float64 {
 	if record, exists := s.Ownership[ownerID]; exists {
@@ -0,0 +1,118 @@
+package securities
+
+import (
+	"fmt"
+	"time"
+)
+
+// AuctionFrequency is how often a security's call auctions run
+type AuctionFrequency string
+
+const (
+	AuctionFrequencyDaily    AuctionFrequency = "daily"    // Once a day at TimeOfDay
+	AuctionFrequencyInterval AuctionFrequency = "interval" // Every IntervalMinutes from midnight
+)
+
+// AuctionSchedule configures periodic call auctions for a thinly traded
+// security. Orders are collected between auctions; for the last
+// IndicativeMinutes before an auction the indicative price is published, and
+// the auction ends up to MaxJitterSeconds after its scheduled time so the
+// exact end cannot be gamed.
+type AuctionSchedule struct {
+	Frequency         AuctionFrequency `json:"frequency"`
+	TimeOfDay         string           `json:"timeOfDay,omitempty"` // "15:04", daily auctions only
+	IntervalMinutes   int              `json:"intervalMinutes,omitempty"`
+	Timezone          string           `json:"timezone,omitempty"` // IANA name, UTC if empty
+	IndicativeMinutes int              `json:"indicativeMinutes"`
+	MaxJitterSeconds  int              `json:"maxJitterSeconds"`
+}
+
+// Validate checks that the schedule describes a runnable auction cycle
+func (s AuctionSchedule) Validate() error {
+	if _, err := s.location(); err != nil {
+		return NewValidationError("timezone", "Unknown timezone")
+	}
+
+	var cycle time.Duration
+	switch s.Frequency {
+	case AuctionFrequencyDaily:
+		if _, err := time.Parse("15:04", s.TimeOfDay); err != nil {
+			return NewValidationError("timeOfDay", "Time of day must be in HH:MM format")
+		}
+		cycle = 24 * time.Hour
+	case AuctionFrequencyInterval:
+		if s.IntervalMinutes <= 0 || s.IntervalMinutes > 24*60 {
+			return NewValidationError("intervalMinutes", "Interval must be between 1 minute and 24 hours")
+		}
+		cycle = time.Duration(s.IntervalMinutes) * time.Minute
+	default:
+		return NewValidationError("frequency", "Frequency must be daily or interval")
+	}
+
+	if s.IndicativeMinutes < 0 || s.MaxJitterSeconds < 0 {
+		return NewValidationError("indicativeMinutes", "Phase lengths cannot be negative")
+	}
+	if s.IndicativePhase()+s.MaxJitter() >= cycle {
+		return NewValidationError("indicativeMinutes", "Indicative phase and jitter must leave time to collect orders")
+	}
+	return nil
+}
+
+// IndicativePhase returns how long before the scheduled time the indicative
+// price is published
+func (s AuctionSchedule) IndicativePhase() time.Duration {
+	return time.Duration(s.IndicativeMinutes) * time.Minute
+}
+
+// MaxJitter returns the longest an auction may run past its scheduled time
+func (s AuctionSchedule) MaxJitter() time.Duration {
+	return time.Duration(s.MaxJitterSeconds) * time.Second
+}
+
+// NextAuction returns the first scheduled auction time strictly after the
+// given time. Jitter is not included.
+func (s AuctionSchedule) NextAuction(after time.Time) (time.Time, error) {
+	location, err := s.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := after.In(location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+
+	switch s.Frequency {
+	case AuctionFrequencyDaily:
+		clock, err := time.Parse("15:04", s.TimeOfDay)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time of day %q: %w", s.TimeOfDay, err)
+		}
+		next := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, location)
+		if !next.After(after) {
+			next = time.Date(local.Year(), local.Month(), local.Day()+1, clock.Hour(), clock.Minute(), 0, 0, location)
+		}
+		return next, nil
+
+	case AuctionFrequencyInterval:
+		if s.IntervalMinutes <= 0 {
+			return time.Time{}, fmt.Errorf("invalid auction interval %d", s.IntervalMinutes)
+		}
+		// Intervals restart at midnight so auction times are the same every day
+		interval := time.Duration(s.IntervalMinutes) * time.Minute
+		next := midnight.Add((local.Sub(midnight)/interval + 1) * interval)
+		tomorrow := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, location)
+		if !next.Before(tomorrow) {
+			next = tomorrow
+		}
+		return next, nil
+
+	default:
+		return time.Time{}, fmt.Errorf("unknown auction frequency: %s", s.Frequency)
+	}
+}
+
+func (s AuctionSchedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
@@ -0,0 +1,109 @@
+package securities
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"securities-marketplace/domains/shared/audit"
+	"securities-marketplace/domains/shared/auth"
+)
+
+// AuctionScheduleHandler lets administrators move securities between
+// continuous trading and periodic call auctions
+type AuctionScheduleHandler struct {
+	service *SecurityService
+}
+
+// NewAuctionScheduleHandler creates a new auction schedule handler
+func NewAuctionScheduleHandler(service *SecurityService) *AuctionScheduleHandler {
+	return &AuctionScheduleHandler{service: service}
+}
+
+// RegisterRoutes registers the handler routes. Callers are expected to mount
+// the router behind admin authorization.
+func (h *AuctionScheduleHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/securities/{id}/auction-schedule", h.HandleGet).Methods("GET")
+	router.HandleFunc("/securities/{id}/auction-schedule", h.HandleSet).Methods("PUT")
+	router.HandleFunc("/securities/{id}/auction-schedule", h.HandleClear).Methods("DELETE")
+}
+
+// HandleGet returns a security's auction schedule, which is null for
+// continuously traded securities
+func (h *AuctionScheduleHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	security, err := h.service.GetSecurity(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Security not found", http.StatusNotFound)
+		return
+	}
+
+	writeAuctionSchedule(w, security.ID, security.AuctionSchedule)
+}
+
+// HandleSet replaces a security's auction schedule
+func (h *AuctionScheduleHandler) HandleSet(w http.ResponseWriter, r *http.Request) {
+	var schedule AuctionSchedule
+	if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	cmd := &SetAuctionScheduleCommand{
+		SecurityID: mux.Vars(r)["id"],
+		Schedule:   schedule,
+		SetBy:      adminID(r),
+	}
+	if err := h.service.WithActor(audit.ActorFromContext(r.Context())).SetAuctionSchedule(cmd); err != nil {
+		writeAuctionScheduleError(w, err)
+		return
+	}
+
+	writeAuctionSchedule(w, cmd.SecurityID, &schedule)
+}
+
+// HandleClear returns a security to continuous trading
+func (h *AuctionScheduleHandler) HandleClear(w http.ResponseWriter, r *http.Request) {
+	cmd := &ClearAuctionScheduleCommand{
+		SecurityID: mux.Vars(r)["id"],
+		ClearedBy:  adminID(r),
+	}
+	if err := h.service.WithActor(audit.ActorFromContext(r.Context())).ClearAuctionSchedule(cmd); err != nil {
+		writeAuctionScheduleError(w, err)
+		return
+	}
+
+	writeAuctionSchedule(w, cmd.SecurityID, nil)
+}
+
+func adminID(r *http.Request) string {
+	if user := auth.GetUserFromContext(r.Context()); user != nil {
+		return user.UserID
+	}
+	return ""
+}
+
+func writeAuctionSchedule(w http.ResponseWriter, securityID string, schedule *AuctionSchedule) {
+	response := map[string]interface{}{
+		"success":    true,
+		"securityId": securityID,
+		"schedule":   schedule,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeAuctionScheduleError(w http.ResponseWriter, err error) {
+	var validationErr *ValidationError
+	var notFoundErr *NotFoundError
+	switch {
+	case errors.As(err, &validationErr):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.As(err, &notFoundErr):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusConflict)
+	}
+}
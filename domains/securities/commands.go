@@ -2,6 +2,8 @@ package securities
 
 import (
 	"time"
+
+	"securities-marketplace/domains/shared/money"
 )
 
 // Security Domain Commands
@@ -14,7 +16,8 @@ type ListSecurityCommand struct {
 	Name         string            `json:"name"`
 	Symbol       string            `json:"symbol"`
 	TotalShares  int64             `json:"totalShares"`
-	ParValue     *float64          `json:"parValue,omitempty"`
+	ParValue     *money.Decimal    `json:"parValue,omitempty"`
+	Currency     string            `json:"currency,omitempty"` // Trading currency; defaults to USD
 	Details      map[string]string `json:"details"`
 }
 
@@ -68,7 +71,7 @@ type TransferOwnershipCommand struct {
 // DeclareDividendCommand represents a command to declare a dividend
 type DeclareDividendCommand struct {
 	SecurityID       string    `json:"securityId"`
-	DividendPerShare float64   `json:"dividendPerShare"`
+	DividendPerShare money.Decimal `json:"dividendPerShare"`
 	ExDividendDate   time.Time `json:"exDividendDate"`
 	PaymentDate      time.Time `json:"paymentDate"`
 	RecordDate       time.Time `json:"recordDate"`
@@ -84,6 +87,21 @@ type AnnounceSplitCommand struct {
 	Description string    `json:"description"`
 }
 
+// SetAuctionScheduleCommand represents a command to trade a security in
+// periodic call auctions
+type SetAuctionScheduleCommand struct {
+	SecurityID string          `json:"securityId"`
+	Schedule   AuctionSchedule `json:"schedule"`
+	SetBy      string          `json:"setBy"`
+}
+
+// ClearAuctionScheduleCommand represents a command to return a security to
+// continuous trading
+type ClearAuctionScheduleCommand struct {
+	SecurityID string `json:"securityId"`
+	ClearedBy  string `json:"clearedBy"`
+}
+
 // Command validation methods
 
 // Validate validates the ListSecurityCommand
@@ -112,9 +130,12 @@ func (c *ListSecurityCommand) Validate() error {
 	if c.TotalShares <= 0 {
 		return NewValidationError("totalShares", "Total shares must be greater than zero")
 	}
-	if c.ParValue != nil && *c.ParValue < 0 {
+	if c.ParValue != nil && c.ParValue.IsNegative() {
 		return NewValidationError("parValue", "Par value cannot be negative")
 	}
+	if c.Currency != "" && !isValidCurrency(c.Currency) {
+		return NewValidationError("currency", "Currency must be a three-letter ISO 4217 code")
+	}
 	return nil
 }
 
@@ -259,7 +280,7 @@ func (c *DeclareDividendCommand) Validate() error {
 	if c.SecurityID == "" {
 		return NewValidationError("securityId", "Security ID is required")
 	}
-	if c.DividendPerShare <= 0 {
+	if !c.DividendPerShare.IsPositive() {
 		return NewValidationError("dividendPerShare", "Dividend per share must be greater than zero")
 	}
 	if c.ExDividendDate.IsZero() {
@@ -316,6 +337,28 @@ func (c *AnnounceSplitCommand) Validate() error {
 	return nil
 }
 
+// Validate validates the SetAuctionScheduleCommand
+func (c *SetAuctionScheduleCommand) Validate() error {
+	if c.SecurityID == "" {
+		return NewValidationError("securityId", "Security ID is required")
+	}
+	if c.SetBy == "" {
+		return NewValidationError("setBy", "Set by is required")
+	}
+	return c.Schedule.Validate()
+}
+
+// Validate validates the ClearAuctionScheduleCommand
+func (c *ClearAuctionScheduleCommand) Validate() error {
+	if c.SecurityID == "" {
+		return NewValidationError("securityId", "Security ID is required")
+	}
+	if c.ClearedBy == "" {
+		return NewValidationError("clearedBy", "Cleared by is required")
+	}
+	return nil
+}
+
 // Helper functions
 
 func isValidSecurityType(securityType string) bool {
@@ -371,4 +414,15 @@ func NewValidationError(field, message string) *ValidationError {
 		Field:   field,
 		Message: message,
 	}
-}
\ No newline at end of file
+}
+func isValidCurrency(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for _, c := range currency {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
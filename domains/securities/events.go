@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
 )
 
 // Security Domain Events
@@ -17,12 +18,13 @@ type SecurityListed struct {
 	Name         string            `json:"name"`
 	Symbol       string            `json:"symbol"`
 	TotalShares  int64             `json:"totalShares"`
-	ParValue     *float64          `json:"parValue,omitempty"`
+	ParValue     *money.Decimal    `json:"parValue,omitempty"`
+	Currency     string            `json:"currency,omitempty"`
 	Details      map[string]string `json:"details"`
 }
 
 // NewSecurityListed creates a new SecurityListed event
-func NewSecurityListed(securityID, issuerID, securityType, name, symbol string, totalShares int64, parValue *float64, details map[string]string) *SecurityListed {
+func NewSecurityListed(securityID, issuerID, securityType, name, symbol string, totalShares int64, parValue *money.Decimal, currency string, details map[string]string) *SecurityListed {
 	return &SecurityListed{
 		BaseEvent:    events.NewBaseEvent(securityID, "Security"),
 		IssuerID:     issuerID,
@@ -31,6 +33,7 @@ func NewSecurityListed(securityID, issuerID, securityType, name, symbol string,
 		Symbol:       symbol,
 		TotalShares:  totalShares,
 		ParValue:     parValue,
+		Currency:     currency,
 		Details:      details,
 	}
 }
@@ -280,17 +283,58 @@ func (e *SecurityOwnershipChanged) GetMetadata() events.Metadata {
 	}
 }
 
+// SecurityOwnershipReversed event is emitted when shares a trade moved are
+// returned because the trade was busted or corrected
+type SecurityOwnershipReversed struct {
+	events.BaseEvent
+	FromOwner   string `json:"fromOwner"`
+	ToOwner     string `json:"toOwner"`
+	SharesCount int64  `json:"sharesCount"`
+	TradeID     string `json:"tradeId"`
+	Reason      string `json:"reason"`
+}
+
+func NewSecurityOwnershipReversed(securityID, fromOwner, toOwner string, sharesCount int64, tradeID, reason string) *SecurityOwnershipReversed {
+	return &SecurityOwnershipReversed{
+		BaseEvent:   events.NewBaseEvent(securityID, "Security"),
+		FromOwner:   fromOwner,
+		ToOwner:     toOwner,
+		SharesCount: sharesCount,
+		TradeID:     tradeID,
+		Reason:      reason,
+	}
+}
+
+func (e *SecurityOwnershipReversed) GetEventType() string     { return "SecurityOwnershipReversed" }
+func (e *SecurityOwnershipReversed) GetAggregateID() string   { return e.AggregateID }
+func (e *SecurityOwnershipReversed) GetAggregateType() string { return e.AggregateType }
+
+func (e *SecurityOwnershipReversed) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *SecurityOwnershipReversed) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
 // SecurityDividendDeclared event is emitted when a dividend is declared
 type SecurityDividendDeclared struct {
 	events.BaseEvent
-	DividendPerShare float64   `json:"dividendPerShare"`
+	DividendPerShare money.Decimal `json:"dividendPerShare"`
 	ExDividendDate   time.Time `json:"exDividendDate"`
 	PaymentDate      time.Time `json:"paymentDate"`
 	RecordDate       time.Time `json:"recordDate"`
 	DeclaredBy       string    `json:"declaredBy"`
 }
 
-func NewSecurityDividendDeclared(securityID string, dividendPerShare float64, exDividendDate, paymentDate, recordDate time.Time, declaredBy string) *SecurityDividendDeclared {
+func NewSecurityDividendDeclared(securityID string, dividendPerShare money.Decimal, exDividendDate, paymentDate, recordDate time.Time, declaredBy string) *SecurityDividendDeclared {
 	return &SecurityDividendDeclared{
 		BaseEvent:        events.NewBaseEvent(securityID, "Security"),
 		DividendPerShare: dividendPerShare,
@@ -356,4 +400,147 @@ func (e *SecuritySplitAnnounced) GetMetadata() events.Metadata {
 		EventVersion:  e.Version,
 		Timestamp:     e.Timestamp,
 	}
-}
\ No newline at end of file
+}
+
+// SecurityAuctionScheduleSet event is emitted when a security is moved to, or
+// rescheduled within, periodic call auctions
+type SecurityAuctionScheduleSet struct {
+	events.BaseEvent
+	Schedule AuctionSchedule `json:"schedule"`
+	SetBy    string          `json:"setBy"`
+}
+
+func NewSecurityAuctionScheduleSet(securityID string, schedule AuctionSchedule, setBy string) *SecurityAuctionScheduleSet {
+	return &SecurityAuctionScheduleSet{
+		BaseEvent: events.NewBaseEvent(securityID, "Security"),
+		Schedule:  schedule,
+		SetBy:     setBy,
+	}
+}
+
+func (e *SecurityAuctionScheduleSet) GetEventType() string     { return "SecurityAuctionScheduleSet" }
+func (e *SecurityAuctionScheduleSet) GetAggregateID() string   { return e.AggregateID }
+func (e *SecurityAuctionScheduleSet) GetAggregateType() string { return e.AggregateType }
+
+func (e *SecurityAuctionScheduleSet) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *SecurityAuctionScheduleSet) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// SecurityAuctionScheduleCleared event is emitted when a security stops
+// trading in periodic call auctions
+type SecurityAuctionScheduleCleared struct {
+	events.BaseEvent
+	ClearedBy string `json:"clearedBy"`
+}
+
+func NewSecurityAuctionScheduleCleared(securityID, clearedBy string) *SecurityAuctionScheduleCleared {
+	return &SecurityAuctionScheduleCleared{
+		BaseEvent: events.NewBaseEvent(securityID, "Security"),
+		ClearedBy: clearedBy,
+	}
+}
+
+func (e *SecurityAuctionScheduleCleared) GetEventType() string     { return "SecurityAuctionScheduleCleared" }
+func (e *SecurityAuctionScheduleCleared) GetAggregateID() string   { return e.AggregateID }
+func (e *SecurityAuctionScheduleCleared) GetAggregateType() string { return e.AggregateType }
+
+func (e *SecurityAuctionScheduleCleared) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *SecurityAuctionScheduleCleared) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// SecurityTradingHalted event is emitted when a trade outside the security's
+// price band halts trading for a cooling-off period
+type SecurityTradingHalted struct {
+	events.BaseEvent
+	Reason       string        `json:"reason"`
+	TriggerPrice money.Decimal `json:"triggerPrice"`
+	LowerBand    money.Decimal `json:"lowerBand"`
+	UpperBand    money.Decimal `json:"upperBand"`
+	HaltedUntil  time.Time     `json:"haltedUntil"` // End of the cooling-off period, when the reopening auction may run
+}
+
+func NewSecurityTradingHalted(securityID, reason string, triggerPrice, lowerBand, upperBand money.Decimal, haltedUntil time.Time) *SecurityTradingHalted {
+	return &SecurityTradingHalted{
+		BaseEvent:    events.NewBaseEvent(securityID, "Security"),
+		Reason:       reason,
+		TriggerPrice: triggerPrice,
+		LowerBand:    lowerBand,
+		UpperBand:    upperBand,
+		HaltedUntil:  haltedUntil,
+	}
+}
+
+func (e *SecurityTradingHalted) GetEventType() string     { return "SecurityTradingHalted" }
+func (e *SecurityTradingHalted) GetAggregateID() string   { return e.AggregateID }
+func (e *SecurityTradingHalted) GetAggregateType() string { return e.AggregateType }
+
+func (e *SecurityTradingHalted) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *SecurityTradingHalted) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// SecurityTradingResumed event is emitted when a halted security reopens
+type SecurityTradingResumed struct {
+	events.BaseEvent
+	ResumedBy      string         `json:"resumedBy"`
+	ReopeningPrice *money.Decimal `json:"reopeningPrice,omitempty"` // Clearing price of the reopening auction, nil if it did not trade
+}
+
+func NewSecurityTradingResumed(securityID, resumedBy string, reopeningPrice *money.Decimal) *SecurityTradingResumed {
+	return &SecurityTradingResumed{
+		BaseEvent:      events.NewBaseEvent(securityID, "Security"),
+		ResumedBy:      resumedBy,
+		ReopeningPrice: reopeningPrice,
+	}
+}
+
+func (e *SecurityTradingResumed) GetEventType() string     { return "SecurityTradingResumed" }
+func (e *SecurityTradingResumed) GetAggregateID() string   { return e.AggregateID }
+func (e *SecurityTradingResumed) GetAggregateType() string { return e.AggregateType }
+
+func (e *SecurityTradingResumed) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *SecurityTradingResumed) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
@@ -2,6 +2,7 @@ package securities
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"securities-marketplace/domains/shared/events"
@@ -71,6 +72,10 @@ func (r *EventSourcedSecurityRepository) FindByID(securityID string) (*SecurityA
 		return nil, fmt.Errorf("failed to load from history: %w", err)
 	}
 
+	if len(eventRecords) > 0 {
+		security.SetLastEventNumber(eventRecords[len(eventRecords)-1].EventNumber)
+	}
+
 	return security, nil
 }
 
@@ -272,67 +277,44 @@ func (r *EventSourcedSecurityRepository) convertEventRecordsToDomainEvents(event
 
 // convertEventRecordToDomainEvent converts a single event record to domain event
 func (r *EventSourcedSecurityRepository) convertEventRecordToDomainEvent(eventRecord *events.Event) (events.DomainEvent, error) {
+	var event events.DomainEvent
 	switch eventRecord.EventType {
 	case "SecurityListed":
-		return r.deserializeSecurityListed(eventRecord.EventData)
+		event = &SecurityListed{}
 	case "SecurityDocumentAdded":
-		return r.deserializeSecurityDocumentAdded(eventRecord.EventData)
+		event = &SecurityDocumentAdded{}
 	case "SecurityUpdated":
-		return r.deserializeSecurityUpdated(eventRecord.EventData)
+		event = &SecurityUpdated{}
 	case "SecuritySuspended":
-		return r.deserializeSecuritySuspended(eventRecord.EventData)
+		event = &SecuritySuspended{}
 	case "SecurityReinstated":
-		return r.deserializeSecurityReinstated(eventRecord.EventData)
+		event = &SecurityReinstated{}
 	case "SecurityDelisted":
-		return r.deserializeSecurityDelisted(eventRecord.EventData)
+		event = &SecurityDelisted{}
 	case "SecurityOwnershipChanged":
-		return r.deserializeSecurityOwnershipChanged(eventRecord.EventData)
+		event = &SecurityOwnershipChanged{}
+	case "SecurityOwnershipReversed":
+		event = &SecurityOwnershipReversed{}
 	case "SecurityDividendDeclared":
-		return r.deserializeSecurityDividendDeclared(eventRecord.EventData)
+		event = &SecurityDividendDeclared{}
 	case "SecuritySplitAnnounced":
-		return r.deserializeSecuritySplitAnnounced(eventRecord.EventData)
+		event = &SecuritySplitAnnounced{}
+	case "SecurityAuctionScheduleSet":
+		event = &SecurityAuctionScheduleSet{}
+	case "SecurityAuctionScheduleCleared":
+		event = &SecurityAuctionScheduleCleared{}
+	case "SecurityTradingHalted":
+		event = &SecurityTradingHalted{}
+	case "SecurityTradingResumed":
+		event = &SecurityTradingResumed{}
 	default:
 		return nil, fmt.Errorf("unknown event type: %s", eventRecord.EventType)
 	}
-}
-
-// Event deserialization methods (simplified implementations)
-func (r *EventSourcedSecurityRepository) deserializeSecurityListed(data []byte) (*SecurityListed, error) {
-	// Implementation would deserialize JSON to SecurityListed
-	// For now, return a basic event
-	return &SecurityListed{}, nil
-}
-
-func (r *EventSourcedSecurityRepository) deserializeSecurityDocumentAdded(data []byte) (*SecurityDocumentAdded, error) {
-	return &SecurityDocumentAdded{}, nil
-}
-
-func (r *EventSourcedSecurityRepository) deserializeSecurityUpdated(data []byte) (*SecurityUpdated, error) {
-	return &SecurityUpdated{}, nil
-}
-
-func (r *EventSourcedSecurityRepository) deserializeSecuritySuspended(data []byte) (*SecuritySuspended, error) {
-	return &SecuritySuspended{}, nil
-}
-
-func (r *EventSourcedSecurityRepository) deserializeSecurityReinstated(data []byte) (*SecurityReinstated, error) {
-	return &SecurityReinstated{}, nil
-}
-
-func (r *EventSourcedSecurityRepository) deserializeSecurityDelisted(data []byte) (*SecurityDelisted, error) {
-	return &SecurityDelisted{}, nil
-}
 
-func (r *EventSourcedSecurityRepository) deserializeSecurityOwnershipChanged(data []byte) (*SecurityOwnershipChanged, error) {
-	return &SecurityOwnershipChanged{}, nil
-}
-
-func (r *EventSourcedSecurityRepository) deserializeSecurityDividendDeclared(data []byte) (*SecurityDividendDeclared, error) {
-	return &SecurityDividendDeclared{}, nil
-}
-
-func (r *EventSourcedSecurityRepository) deserializeSecuritySplitAnnounced(data []byte) (*SecuritySplitAnnounced, error) {
-	return &SecuritySplitAnnounced{}, nil
+	if err := json.Unmarshal(eventRecord.EventData, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize %s: %w", eventRecord.EventType, err)
+	}
+	return event, nil
 }
 
 // ProjectionSecurityRepository implements SecurityRepository using read model projections
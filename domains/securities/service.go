@@ -6,7 +6,9 @@ import (
 
 	"github.com/google/uuid"
 	
+	"securities-marketplace/domains/shared/audit"
 	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
 )
 
 // SecurityService provides application services for security domain
@@ -14,6 +16,8 @@ type SecurityService struct {
 	repository SecurityRepository
 	eventStore events.EventStore
 	eventBus   events.EventBus
+	auditLog   audit.Logger
+	actor      *audit.Actor
 }
 
 // NewSecurityService creates a new security service
@@ -25,6 +29,19 @@ func NewSecurityService(repository SecurityRepository, eventStore events.EventSt
 	}
 }
 
+// SetAuditLogger enables audit logging of every command executed through the service
+func (s *SecurityService) SetAuditLogger(logger audit.Logger) {
+	s.auditLog = logger
+}
+
+// WithActor returns a copy of the service that attributes its audit entries to
+// the given actor. Handlers pass the actor from the request context.
+func (s *SecurityService) WithActor(actor *audit.Actor) *SecurityService {
+	scoped := *s
+	scoped.actor = actor
+	return &scoped
+}
+
 // ListSecurity handles security listing
 func (s *SecurityService) ListSecurity(cmd *ListSecurityCommand) (*SecurityAggregate, error) {
 	if err := cmd.Validate(); err != nil {
@@ -54,6 +71,7 @@ func (s *SecurityService) ListSecurity(cmd *ListSecurityCommand) (*SecurityAggre
 		cmd.Symbol,
 		cmd.TotalShares,
 		cmd.ParValue,
+		cmd.Currency,
 		cmd.Details,
 	)
 	if err != nil {
@@ -240,6 +258,59 @@ func (s *SecurityService) AnnounceSplit(cmd *AnnounceSplitCommand) error {
 	return s.saveAggregateEvents(security, cmd.AnnouncedBy)
 }
 
+// SetAuctionSchedule moves a security to periodic call auctions
+func (s *SecurityService) SetAuctionSchedule(cmd *SetAuctionScheduleCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	security, err := s.repository.FindByID(cmd.SecurityID)
+	if err != nil {
+		return fmt.Errorf("failed to find security: %w", err)
+	}
+
+	if err := security.SetAuctionSchedule(cmd.Schedule, cmd.SetBy); err != nil {
+		return fmt.Errorf("failed to set auction schedule: %w", err)
+	}
+
+	return s.saveAggregateEvents(security, cmd.SetBy)
+}
+
+// ClearAuctionSchedule returns a security to continuous trading
+func (s *SecurityService) ClearAuctionSchedule(cmd *ClearAuctionScheduleCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	security, err := s.repository.FindByID(cmd.SecurityID)
+	if err != nil {
+		return fmt.Errorf("failed to find security: %w", err)
+	}
+
+	if err := security.ClearAuctionSchedule(cmd.ClearedBy); err != nil {
+		return fmt.Errorf("failed to clear auction schedule: %w", err)
+	}
+
+	return s.saveAggregateEvents(security, cmd.ClearedBy)
+}
+
+// GetAuctionSchedules returns the auction schedule of every active security
+// traded in call auctions, keyed by security ID
+func (s *SecurityService) GetAuctionSchedules() (map[string]AuctionSchedule, error) {
+	active, err := s.GetActiveSecurities()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active securities: %w", err)
+	}
+
+	schedules := make(map[string]AuctionSchedule)
+	for _, security := range active {
+		if security.AuctionSchedule != nil {
+			schedules[security.ID] = *security.AuctionSchedule
+		}
+	}
+	return schedules, nil
+}
+
 // GetSecurity retrieves a security by ID
 func (s *SecurityService) GetSecurity(securityID string) (*SecurityAggregate, error) {
 	return s.repository.FindByID(securityID)
@@ -295,17 +366,23 @@ func (s *SecurityService) GetUserSecurities(userID string) ([]*SecurityAggregate
 }
 
 // CalculateMarketValue calculates the market value of a user's holdings
-func (s *SecurityService) CalculateMarketValue(userID string) (float64, error) {
+func (s *SecurityService) CalculateMarketValue(userID string) (money.Decimal, error) {
 	securities, err := s.GetUserSecurities(userID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get user securities: %w", err)
+		return money.Zero, fmt.Errorf("failed to get user securities: %w", err)
 	}
 
-	var totalValue float64
+	var totalValue money.Decimal
 	for _, security := range securities {
 		sharesOwned := security.GetSharesOwned(userID)
 		if sharesOwned > 0 && security.LastTradePrice != nil {
-			totalValue += float64(sharesOwned) * *security.LastTradePrice
+			value, err := security.LastTradePrice.MulInt(sharesOwned)
+			if err != nil {
+				return money.Zero, fmt.Errorf("failed to value holding: %w", err)
+			}
+			if totalValue, err = totalValue.Add(value); err != nil {
+				return money.Zero, fmt.Errorf("failed to total holdings: %w", err)
+			}
 		}
 	}
 
@@ -319,6 +396,14 @@ func (s *SecurityService) saveAggregateEvents(security *SecurityAggregate, userI
 		return nil
 	}
 
+	// Capture the stored state before this command for the audit diff
+	var before *SecurityAggregate
+	if s.auditLog != nil {
+		if previous, err := s.repository.FindByID(security.ID); err == nil {
+			before = previous
+		}
+	}
+
 	// Convert domain events to event store events
 	var events []*events.Event
 	correlationID := uuid.New().String()
@@ -342,9 +427,14 @@ func (s *SecurityService) saveAggregateEvents(security *SecurityAggregate, userI
 	// Save events
 	err := s.eventStore.SaveEvents(events)
 	if err != nil {
+		s.recordAudit(before, security, uncommittedEvents, userID, correlationID, err)
 		return fmt.Errorf("failed to save events: %w", err)
 	}
 
+	// Remember where this command landed in the global stream so callers can
+	// return it as a consistency token
+	security.SetLastEventNumber(events[len(events)-1].EventNumber)
+
 	// Publish events to event bus
 	for _, domainEvent := range uncommittedEvents {
 		err = s.eventBus.Publish(domainEvent)
@@ -355,12 +445,37 @@ func (s *SecurityService) saveAggregateEvents(security *SecurityAggregate, userI
 		}
 	}
 
+	s.recordAudit(before, security, uncommittedEvents, userID, correlationID, nil)
+
 	// Mark events as committed
 	security.MarkEventsAsCommitted()
 
 	return nil
 }
 
+// recordAudit writes an audit entry for a command's events and state change
+func (s *SecurityService) recordAudit(before, after *SecurityAggregate, uncommittedEvents []events.DomainEvent, userID, correlationID string, cmdErr error) {
+	if s.auditLog == nil {
+		return
+	}
+
+	err := audit.RecordCommand(s.auditLog, audit.CommandRecord{
+		AggregateID:   after.ID,
+		AggregateType: after.Type,
+		UserID:        userID,
+		Actor:         s.actor,
+		CorrelationID: correlationID,
+		Before:        before,
+		After:         after,
+		Events:        uncommittedEvents,
+		Err:           cmdErr,
+	})
+	if err != nil {
+		// Log error but don't fail the operation
+		fmt.Printf("Failed to record audit entry for %s %s: %v\n", after.Type, after.ID, err)
+	}
+}
+
 // NotFoundError represents a resource not found error
 type NotFoundError struct {
 	Resource string
@@ -384,4 +499,4 @@ func NewNotFoundError(resource, id string) *NotFoundError {
 func IsNotFoundError(err error) bool {
 	_, ok := err.(*NotFoundError)
 	return ok
-}
\ No newline at end of file
+}
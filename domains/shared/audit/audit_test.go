@@ -0,0 +1,177 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/shared/testutil"
+)
+
+type testAggregate struct {
+	ID                string
+	Version           int
+	UncommittedEvents []string
+	Status            string `json:"status"`
+	Shares            int64  `json:"shares"`
+	PasswordHash      string `json:"passwordHash"`
+}
+
+func TestDiff_ReturnsOnlyChangedFields(t *testing.T) {
+	before := &testAggregate{ID: "agg-1", Version: 1, Status: "active", Shares: 100, PasswordHash: "old"}
+	after := &testAggregate{ID: "agg-1", Version: 2, Status: "suspended", Shares: 100, PasswordHash: "new"}
+
+	oldValues, newValues, err := Diff(before, after)
+
+	testutil.AssertNoError(t, err, "Diff should succeed")
+	testutil.AssertEqual(t, "active", oldValues["status"], "Old status should be recorded")
+	testutil.AssertEqual(t, "suspended", newValues["status"], "New status should be recorded")
+	testutil.AssertEqual(t, redactedValue, newValues["passwordHash"], "Password hash should be redacted")
+	_, sharesChanged := newValues["shares"]
+	testutil.AssertFalse(t, sharesChanged, "Unchanged fields should be omitted")
+	_, versionChanged := newValues["Version"]
+	testutil.AssertFalse(t, versionChanged, "Aggregate bookkeeping should be omitted")
+}
+
+func TestDiff_NilBeforeTreatsAllFieldsAsNew(t *testing.T) {
+	var before *testAggregate
+	after := &testAggregate{ID: "agg-1", Status: "active", Shares: 50}
+
+	oldValues, newValues, err := Diff(before, after)
+
+	testutil.AssertNoError(t, err, "Diff should succeed")
+	testutil.AssertLengthEqual(t, 0, oldValues, "No old values for a new aggregate")
+	testutil.AssertEqual(t, float64(50), newValues["shares"], "New fields should be recorded")
+}
+
+func TestNewCommandEntry_RecordsFailure(t *testing.T) {
+	entry, err := NewCommandEntry(CommandRecord{
+		AggregateID:   "trade-1",
+		AggregateType: "Trade",
+		UserID:        "user-1",
+		CorrelationID: "corr-1",
+		After:         &testAggregate{Status: "matched"},
+		Err:           errors.New("store unavailable"),
+	})
+
+	testutil.AssertNoError(t, err, "Entry should be built")
+	testutil.AssertEqual(t, StatusFailure, entry.Status, "Failed commands should be marked as failures")
+	testutil.AssertEqual(t, "store unavailable", entry.ErrorMessage, "Error message should be recorded")
+	testutil.AssertEqual(t, CategoryTrading, entry.EventCategory, "Trades belong to the trading category")
+	testutil.AssertEqual(t, "corr-1", entry.Metadata["correlationId"], "Correlation ID should be kept in metadata")
+}
+
+func TestNewCommandEntry_RecordsActor(t *testing.T) {
+	actor := &Actor{UserID: "admin-1", Role: "admin", SessionID: "sess-1", IPAddress: "10.0.0.1", UserAgent: "curl"}
+
+	entry, err := NewCommandEntry(CommandRecord{AggregateID: "user-1", AggregateType: "User", UserID: "admin-1", Actor: actor})
+	claimed, claimedErr := NewCommandEntry(CommandRecord{AggregateID: "user-1", AggregateType: "User", UserID: "system", Actor: actor})
+	anonymous, anonymousErr := NewCommandEntry(CommandRecord{AggregateID: "user-1", AggregateType: "User", UserID: "system", Actor: &Actor{IPAddress: "10.0.0.2"}})
+
+	testutil.AssertNoError(t, err, "Entry should be built")
+	testutil.AssertNoError(t, claimedErr, "Entry should be built")
+	testutil.AssertNoError(t, anonymousErr, "Entry should be built")
+	testutil.AssertEqual(t, "admin-1", entry.UserID, "Actor should be recorded as the user")
+	testutil.AssertEqual(t, "admin", entry.UserRole, "Actor role should be recorded")
+	testutil.AssertEqual(t, "10.0.0.1", entry.IPAddress, "Actor address should be recorded")
+	testutil.AssertEqual(t, "sess-1", entry.SessionID, "Actor session should be recorded")
+	testutil.AssertEqual(t, "curl", entry.UserAgent, "Actor user agent should be recorded")
+	_, hasCommandUser := entry.Metadata["commandUserId"]
+	testutil.AssertFalse(t, hasCommandUser, "Matching command user should not be repeated")
+	testutil.AssertEqual(t, "admin-1", claimed.UserID, "Authenticated actor should win over the command user")
+	testutil.AssertEqual(t, "system", claimed.Metadata["commandUserId"], "Differing command user should be kept")
+	testutil.AssertEqual(t, "system", anonymous.UserID, "Command user should be kept without an authenticated actor")
+}
+
+func TestParseFilter_AcceptsDates(t *testing.T) {
+	r := httptest.NewRequest("GET", "/audit?from=2024-03-01&to=2024-03-02T10:00:00Z", nil)
+	day := httptest.NewRequest("GET", "/audit?to=2024-03-01", nil)
+
+	filter, err := ParseFilter(r)
+	dayFilter, dayErr := ParseFilter(day)
+
+	testutil.AssertNoError(t, err, "Dates and times should parse")
+	testutil.AssertNoError(t, dayErr, "Dates should parse")
+	testutil.AssertTimeEqual(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), *filter.From, "From date should start the day")
+	testutil.AssertTimeEqual(t, time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC), *filter.To, "RFC3339 times should be kept")
+	testutil.AssertTimeEqual(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond), *dayFilter.To, "To date should cover the whole day")
+}
+
+func TestInMemoryAuditLog_QueryFilters(t *testing.T) {
+	log := NewInMemoryAuditLog()
+	now := time.Now()
+	log.Record(&Entry{EventType: "TradeMatched", EventCategory: CategoryTrading, UserID: "user-1", EventTimestamp: now.Add(-2 * time.Hour)})
+	log.Record(&Entry{EventType: "UserSuspended", EventCategory: CategoryCompliance, UserID: "admin-1", EventTimestamp: now.Add(-1 * time.Hour)})
+	log.Record(&Entry{EventType: "TradeSettled", EventCategory: CategoryTrading, UserID: "user-1", EventTimestamp: now})
+
+	entries, err := log.Query(Filter{UserID: "user-1"})
+	testutil.AssertNoError(t, err, "Query should succeed")
+	testutil.AssertLengthEqual(t, 2, entries, "Should return the user's entries")
+	testutil.AssertEqual(t, "TradeSettled", entries[0].EventType, "Newest entries should come first")
+
+	from := now.Add(-90 * time.Minute)
+	entries, _ = log.Query(Filter{EventCategory: CategoryTrading, From: &from})
+	testutil.AssertLengthEqual(t, 1, entries, "Time range should be applied")
+
+	entries, _ = log.Query(Filter{Limit: 1, Offset: 1})
+	testutil.AssertLengthEqual(t, 1, entries, "Paging should be applied")
+	testutil.AssertEqual(t, "UserSuspended", entries[0].EventType, "Offset should skip the newest entry")
+}
+
+func TestExportCSV(t *testing.T) {
+	log := NewInMemoryAuditLog()
+	log.Record(&Entry{
+		EventType:       "TradeMatched",
+		EventCategory:   CategoryTrading,
+		UserID:          "user-1",
+		ResourceID:      `=HYPERLINK("http://example.com")`,
+		NewValues:       map[string]interface{}{"status": "matched"},
+		ComplianceFlags: []string{"large_trade", "new_counterparty"},
+	})
+
+	var buf bytes.Buffer
+	err := ExportCSV(&buf, log, Filter{})
+	testutil.AssertNoError(t, err, "Export should succeed")
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	testutil.AssertNoError(t, err, "Export should be valid CSV")
+	testutil.AssertLengthEqual(t, 2, records, "Should have header and one row")
+	testutil.AssertEqual(t, "log_id", records[0][0], "Header should come first")
+	testutil.AssertEqual(t, "large_trade;new_counterparty", records[1][17], "Flags should be joined")
+	testutil.AssertEqual(t, `{"status":"matched"}`, records[1][19], "New values should be JSON")
+	testutil.AssertEqual(t, `'=HYPERLINK("http://example.com")`, records[1][13], "Formula-like cells should be quoted")
+}
+
+func TestMutationMiddleware_RecordsActorAndStatus(t *testing.T) {
+	log := NewInMemoryAuditLog()
+	resolve := func(r *http.Request) *Actor {
+		return &Actor{UserID: "admin-1", Role: "admin", IPAddress: "10.0.0.1", UserAgent: r.UserAgent(), SessionID: "sess-1"}
+	}
+	var handlerActor *Actor
+	handler := MutationMiddleware(log, CategoryAdmin, resolve)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerActor = ActorFromContext(r.Context())
+		w.WriteHeader(http.StatusForbidden)
+	}))
+
+	get := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), get)
+
+	post := httptest.NewRequest(http.MethodPost, "/admin/users", nil)
+	post.Header.Set("User-Agent", "audit-test")
+	handler.ServeHTTP(httptest.NewRecorder(), post)
+
+	testutil.AssertNotNil(t, handlerActor, "Actor should be available to handlers")
+
+	entries, _ := log.Query(Filter{})
+	testutil.AssertLengthEqual(t, 1, entries, "Only mutations should be recorded")
+	entry := entries[0]
+	testutil.AssertEqual(t, "admin-1", entry.UserID, "Actor should be recorded")
+	testutil.AssertEqual(t, "audit-test", entry.UserAgent, "User agent should be recorded")
+	testutil.AssertEqual(t, "sess-1", entry.SessionID, "Session should be recorded")
+	testutil.AssertEqual(t, StatusWarning, entry.Status, "Client errors should be warnings")
+	testutil.AssertTrue(t, entry.RequestID != "", "Request ID should be assigned")
+}
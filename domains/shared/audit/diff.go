@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// ignoredFields are aggregate root bookkeeping fields that never belong in a diff
+var ignoredFields = map[string]bool{
+	"UncommittedEvents": true,
+	"Version":           true,
+	"LastEventNumber":   true,
+}
+
+// redactedFields hold secrets that must never be written to the audit log
+var redactedFields = map[string]bool{
+	"password":     true,
+	"passwordHash": true,
+}
+
+const redactedValue = "[REDACTED]"
+
+// Diff compares two values by their JSON representation and returns only the
+// top-level fields that changed. Either side may be nil, e.g. for newly created
+// aggregates.
+func Diff(before, after interface{}) (oldValues, newValues map[string]interface{}, err error) {
+	beforeMap, err := toMap(before)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to serialize previous state: %w", err)
+	}
+	afterMap, err := toMap(after)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to serialize new state: %w", err)
+	}
+
+	oldValues = make(map[string]interface{})
+	newValues = make(map[string]interface{})
+
+	for key, newValue := range afterMap {
+		if ignoredFields[key] {
+			continue
+		}
+		oldValue, existed := beforeMap[key]
+		if !existed || !reflect.DeepEqual(oldValue, newValue) {
+			if existed {
+				oldValues[key] = oldValue
+			}
+			newValues[key] = newValue
+		}
+	}
+
+	for key, oldValue := range beforeMap {
+		if ignoredFields[key] {
+			continue
+		}
+		if _, stillExists := afterMap[key]; !stillExists {
+			oldValues[key] = oldValue
+		}
+	}
+
+	redact(oldValues)
+	redact(newValues)
+
+	return oldValues, newValues, nil
+}
+
+// redact masks sensitive fields in place
+func redact(values map[string]interface{}) {
+	for key := range values {
+		if redactedFields[key] {
+			values[key] = redactedValue
+		}
+	}
+}
+
+// toMap converts a value into a generic map through JSON
+func toMap(value interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if value == nil {
+		return result, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return result, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvHeader lists the exported audit columns in order
+var csvHeader = []string{
+	"log_id", "event_timestamp", "event_type", "event_category", "event_description",
+	"user_id", "username", "user_role", "session_id", "ip_address", "user_agent", "request_id",
+	"resource_type", "resource_id", "status", "error_message", "risk_score", "compliance_flags",
+	"old_values", "new_values", "metadata",
+}
+
+// maxExportRows caps a single CSV export
+const maxExportRows = 50000
+
+// ExportCSV writes all entries matching the filter as CSV, paging through the
+// logger so large exports do not need to fit in a single query
+func ExportCSV(w io.Writer, logger Logger, filter Filter) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	pageSize := filter.Limit
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+
+	written := 0
+	page := filter
+	page.Limit = pageSize
+	for written < maxExportRows {
+		entries, err := logger.Query(page)
+		if err != nil {
+			return fmt.Errorf("failed to query audit log: %w", err)
+		}
+
+		for _, entry := range entries {
+			record, err := entryToCSV(entry)
+			if err != nil {
+				return err
+			}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+			written++
+		}
+
+		if len(entries) < pageSize {
+			break
+		}
+		page.Offset += pageSize
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// entryToCSV converts an entry to a CSV record
+func entryToCSV(entry *Entry) ([]string, error) {
+	oldValues, err := jsonString(entry.OldValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize old values: %w", err)
+	}
+	newValues, err := jsonString(entry.NewValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize new values: %w", err)
+	}
+	metadata, err := jsonString(entry.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize metadata: %w", err)
+	}
+
+	riskScore := ""
+	if entry.RiskScore != nil {
+		riskScore = strconv.Itoa(*entry.RiskScore)
+	}
+
+	record := []string{
+		entry.LogID,
+		entry.EventTimestamp.UTC().Format(time.RFC3339),
+		entry.EventType,
+		string(entry.EventCategory),
+		entry.EventDescription,
+		entry.UserID,
+		entry.Username,
+		entry.UserRole,
+		entry.SessionID,
+		entry.IPAddress,
+		entry.UserAgent,
+		entry.RequestID,
+		entry.ResourceType,
+		entry.ResourceID,
+		string(entry.Status),
+		entry.ErrorMessage,
+		riskScore,
+		strings.Join(entry.ComplianceFlags, ";"),
+		oldValues,
+		newValues,
+		metadata,
+	}
+	for i, value := range record {
+		record[i] = escapeFormula(value)
+	}
+	return record, nil
+}
+
+// escapeFormula keeps spreadsheets from evaluating user-controlled cells:
+// values starting with =, +, - or @ are prefixed with a single quote
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func jsonString(value map[string]interface{}) (string, error) {
+	if len(value) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
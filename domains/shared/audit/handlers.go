@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes the audit log query API and CSV export
+type Handler struct {
+	logger Logger
+}
+
+// NewHandler creates a new audit handler
+func NewHandler(logger Logger) *Handler {
+	return &Handler{logger: logger}
+}
+
+// RegisterRoutes registers the handler routes. Callers are expected to mount
+// the router behind compliance authorization.
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/audit", h.HandleQuery).Methods("GET")
+	router.HandleFunc("/audit/export", h.HandleExport).Methods("GET")
+}
+
+// HandleQuery returns audit entries matching the query parameters as JSON
+func (h *Handler) HandleQuery(w http.ResponseWriter, r *http.Request) {
+	filter, err := ParseFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.logger.Query(filter)
+	if err != nil {
+		http.Error(w, "Failed to query audit log", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"entries": entries,
+		"count":   len(entries),
+		"limit":   filter.Limit,
+		"offset":  filter.Offset,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleExport streams audit entries matching the query parameters as CSV
+func (h *Handler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	filter, err := ParseFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// The export pages through everything that matches
+	filter.Limit = 0
+	filter.Offset = 0
+
+	filename := fmt.Sprintf("audit_log_%s.csv", time.Now().UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if err := ExportCSV(w, h.logger, filter); err != nil {
+		// Headers are already sent; the truncated file is the best we can do
+		log.Printf("Failed to export audit log: %v", err)
+	}
+}
+
+// ParseFilter builds a Filter from request query parameters. Times are RFC3339
+// or plain dates; a plain "to" date covers the whole day.
+func ParseFilter(r *http.Request) (Filter, error) {
+	query := r.URL.Query()
+	filter := Filter{
+		UserID:        query.Get("userId"),
+		EventType:     query.Get("eventType"),
+		EventCategory: Category(query.Get("category")),
+		Status:        Status(query.Get("status")),
+		ResourceType:  query.Get("resourceType"),
+		ResourceID:    query.Get("resourceId"),
+		IPAddress:     query.Get("ipAddress"),
+	}
+
+	if from := query.Get("from"); from != "" {
+		t, err := parseFilterTime(from, false)
+		if err != nil {
+			return filter, fmt.Errorf("invalid from time: %w", err)
+		}
+		filter.From = &t
+	}
+
+	if to := query.Get("to"); to != "" {
+		t, err := parseFilterTime(to, true)
+		if err != nil {
+			return filter, fmt.Errorf("invalid to time: %w", err)
+		}
+		filter.To = &t
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			return filter, fmt.Errorf("invalid limit")
+		}
+		filter.Limit = limit
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultQueryLimit
+	}
+
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return filter, fmt.Errorf("invalid offset")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
+
+// parseFilterTime parses an RFC3339 time or a YYYY-MM-DD date. With endOfDay
+// a date is moved to the last instant of that day.
+func parseFilterTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ActorResolver extracts the acting user and request information from a request
+type ActorResolver func(r *http.Request) *Actor
+
+// statusRecorder captures the response status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Middleware records every request passing through it as an audit entry.
+// Use it on privileged routers such as admin and compliance.
+func Middleware(logger Logger, category Category, resolve ActorResolver) func(http.Handler) http.Handler {
+	return newMiddleware(logger, category, resolve, false)
+}
+
+// MutationMiddleware records only state-changing requests (anything other
+// than GET, HEAD and OPTIONS)
+func MutationMiddleware(logger Logger, category Category, resolve ActorResolver) func(http.Handler) http.Handler {
+	return newMiddleware(logger, category, resolve, true)
+}
+
+func newMiddleware(logger Logger, category Category, resolve ActorResolver, mutationsOnly bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if mutationsOnly && isSafeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var actor *Actor
+			if resolve != nil {
+				actor = resolve(r)
+			}
+			if actor == nil {
+				actor = &Actor{}
+			}
+			if actor.RequestID == "" {
+				actor.RequestID = r.Header.Get("X-Request-ID")
+			}
+			if actor.RequestID == "" {
+				actor.RequestID = uuid.New().String()
+			}
+			w.Header().Set("X-Request-ID", actor.RequestID)
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			start := time.Now()
+
+			next.ServeHTTP(recorder, r.WithContext(WithActor(r.Context(), actor)))
+
+			entry := &Entry{
+				EventType:        "HTTP" + r.Method,
+				EventCategory:    category,
+				EventDescription: fmt.Sprintf("%s %s", r.Method, r.URL.Path),
+				ResourceType:     "http",
+				ResourceID:       r.URL.Path,
+				Status:           statusFromCode(recorder.status),
+				EventTimestamp:   start,
+				Metadata: map[string]interface{}{
+					"method":     r.Method,
+					"path":       r.URL.Path,
+					"query":      r.URL.RawQuery,
+					"statusCode": recorder.status,
+					"durationMs": time.Since(start).Milliseconds(),
+				},
+			}
+			actor.Apply(entry)
+			if recorder.status >= http.StatusBadRequest {
+				entry.ErrorMessage = http.StatusText(recorder.status)
+			}
+
+			if err := logger.Record(entry); err != nil {
+				// Log error but don't fail the request
+				log.Printf("Failed to record audit entry for %s %s: %v", r.Method, r.URL.Path, err)
+			}
+		})
+	}
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func statusFromCode(code int) Status {
+	switch {
+	case code >= http.StatusInternalServerError:
+		return StatusFailure
+	case code >= http.StatusBadRequest:
+		return StatusWarning
+	default:
+		return StatusSuccess
+	}
+}
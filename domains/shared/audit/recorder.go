@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"securities-marketplace/domains/shared/events"
+)
+
+// CommandRecord describes a command executed through a domain service
+type CommandRecord struct {
+	AggregateID   string
+	AggregateType string
+	UserID        string
+	Actor         *Actor
+	CorrelationID string
+	Before        interface{}
+	After         interface{}
+	Events        []events.DomainEvent
+	Err           error
+}
+
+// NewCommandEntry builds an audit entry for a command, including the diff
+// between the aggregate state before and after the command
+func NewCommandEntry(record CommandRecord) (*Entry, error) {
+	oldValues, newValues, err := Diff(record.Before, record.After)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff aggregate state: %w", err)
+	}
+
+	eventTypes := make([]string, 0, len(record.Events))
+	for _, domainEvent := range record.Events {
+		eventTypes = append(eventTypes, domainEvent.GetEventType())
+	}
+
+	eventType := record.AggregateType + "Command"
+	if len(eventTypes) > 0 {
+		eventType = eventTypes[0]
+	}
+
+	entry := &Entry{
+		EventType:        eventType,
+		EventCategory:    CategoryForAggregate(record.AggregateType),
+		EventDescription: fmt.Sprintf("%s %s: %s", record.AggregateType, record.AggregateID, strings.Join(eventTypes, ", ")),
+		UserID:           record.UserID,
+		ResourceType:     record.AggregateType,
+		ResourceID:       record.AggregateID,
+		OldValues:        oldValues,
+		NewValues:        newValues,
+		Status:           StatusSuccess,
+		EventTimestamp:   time.Now(),
+		Metadata: map[string]interface{}{
+			"correlationId": record.CorrelationID,
+			"events":        eventTypes,
+		},
+	}
+
+	// The authenticated actor is who sent the request; a different user named
+	// by the command is kept alongside it
+	if record.Actor != nil && record.Actor.UserID != "" {
+		record.Actor.Apply(entry)
+		if record.UserID != "" && record.UserID != record.Actor.UserID {
+			entry.Metadata["commandUserId"] = record.UserID
+		}
+	}
+
+	if record.Err != nil {
+		entry.Status = StatusFailure
+		entry.ErrorMessage = record.Err.Error()
+	}
+
+	return entry, nil
+}
+
+// RecordCommand builds and records an audit entry for a command. Audit failures
+// are returned to the caller, which decides whether they are fatal.
+func RecordCommand(logger Logger, record CommandRecord) error {
+	if logger == nil {
+		return nil
+	}
+
+	entry, err := NewCommandEntry(record)
+	if err != nil {
+		return err
+	}
+
+	if err := logger.Record(entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,401 @@
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const defaultQueryLimit = 100
+
+// PostgresAuditLog writes audit entries to the audit_log table
+type PostgresAuditLog struct {
+	db *sql.DB
+}
+
+// NewPostgresAuditLog creates a new PostgreSQL-backed audit log
+func NewPostgresAuditLog(db *sql.DB) *PostgresAuditLog {
+	return &PostgresAuditLog{db: db}
+}
+
+// Record inserts an audit entry
+func (l *PostgresAuditLog) Record(entry *Entry) error {
+	if entry.LogID == "" {
+		entry.LogID = uuid.New().String()
+	}
+	if entry.EventTimestamp.IsZero() {
+		entry.EventTimestamp = time.Now()
+	}
+	if entry.Status == "" {
+		entry.Status = StatusSuccess
+	}
+
+	// user_id, resource_id and request_id are UUID columns; identifiers that
+	// are not UUIDs (e.g. "system" or "trade_123") are kept in metadata instead.
+	// user_id also references users_projection, which may not have caught up
+	// with the actor (or never will, for "system"), so the actor is always
+	// kept in metadata as text and user_id is only set when the row exists.
+	metadata := make(map[string]interface{}, len(entry.Metadata)+3)
+	for key, value := range entry.Metadata {
+		metadata[key] = value
+	}
+	if entry.UserID != "" {
+		metadata["userId"] = entry.UserID
+	}
+	userID := uuidOrNil(entry.UserID, "userId", metadata)
+	resourceID := uuidOrNil(entry.ResourceID, "resourceId", metadata)
+	requestID := uuidOrNil(entry.RequestID, "requestId", metadata)
+
+	oldValuesJSON, err := jsonOrNil(entry.OldValues)
+	if err != nil {
+		return fmt.Errorf("failed to serialize old values: %w", err)
+	}
+	newValuesJSON, err := jsonOrNil(entry.NewValues)
+	if err != nil {
+		return fmt.Errorf("failed to serialize new values: %w", err)
+	}
+	metadataJSON, err := jsonOrNil(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to serialize metadata: %w", err)
+	}
+
+	var ipAddress interface{}
+	if net.ParseIP(entry.IPAddress) != nil {
+		ipAddress = entry.IPAddress
+	}
+
+	query := `
+		INSERT INTO audit_log (
+			log_id, event_type, event_category, event_description,
+			user_id, username, user_role, session_id,
+			ip_address, user_agent, request_id,
+			resource_type, resource_id, old_values, new_values,
+			status, error_message, risk_score, compliance_flags,
+			event_timestamp, metadata
+		) VALUES ($1, $2, $3, $4, (SELECT user_id FROM users_projection WHERE user_id = $5::uuid), $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	`
+
+	_, err = l.db.Exec(query,
+		entry.LogID,
+		entry.EventType,
+		string(entry.EventCategory),
+		entry.EventDescription,
+		userID,
+		nullString(entry.Username),
+		nullString(entry.UserRole),
+		nullString(entry.SessionID),
+		ipAddress,
+		nullString(entry.UserAgent),
+		requestID,
+		nullString(entry.ResourceType),
+		resourceID,
+		oldValuesJSON,
+		newValuesJSON,
+		string(entry.Status),
+		nullString(entry.ErrorMessage),
+		entry.RiskScore,
+		pq.Array(entry.ComplianceFlags),
+		entry.EventTimestamp,
+		metadataJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// Query returns audit entries matching the filter, newest first
+func (l *PostgresAuditLog) Query(filter Filter) ([]*Entry, error) {
+	baseQuery := `
+		SELECT log_id, event_type, event_category, event_description,
+			COALESCE(user_id::text, ''), COALESCE(username, ''), COALESCE(user_role, ''), COALESCE(session_id, ''),
+			COALESCE(host(ip_address), ''), COALESCE(user_agent, ''), COALESCE(request_id::text, ''),
+			COALESCE(resource_type, ''), COALESCE(resource_id::text, ''), old_values, new_values,
+			status, COALESCE(error_message, ''), risk_score, compliance_flags,
+			event_timestamp, metadata
+		FROM audit_log
+	`
+
+	conditions := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	addCondition := func(clause string, value interface{}) {
+		conditions = append(conditions, fmt.Sprintf(clause, argIndex))
+		args = append(args, value)
+		argIndex++
+	}
+
+	if filter.UserID != "" {
+		addCondition("(user_id::text = $%[1]d OR metadata->>'userId' = $%[1]d)", filter.UserID)
+	}
+	if filter.EventType != "" {
+		addCondition("event_type = $%d", filter.EventType)
+	}
+	if filter.EventCategory != "" {
+		addCondition("event_category = $%d", string(filter.EventCategory))
+	}
+	if filter.Status != "" {
+		addCondition("status = $%d", string(filter.Status))
+	}
+	if filter.ResourceType != "" {
+		addCondition("resource_type = $%d", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		addCondition("(resource_id::text = $%[1]d OR metadata->>'resourceId' = $%[1]d)", filter.ResourceID)
+	}
+	if filter.IPAddress != "" {
+		addCondition("host(ip_address) = $%d", filter.IPAddress)
+	}
+	if filter.From != nil {
+		addCondition("event_timestamp >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("event_timestamp <= $%d", *filter.To)
+	}
+
+	whereClause := ""
+	for i, condition := range conditions {
+		if i == 0 {
+			whereClause = " WHERE " + condition
+		} else {
+			whereClause += " AND " + condition
+		}
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultQueryLimit
+	}
+
+	query := baseQuery + whereClause + fmt.Sprintf(" ORDER BY event_timestamp DESC LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	args = append(args, limit, filter.Offset)
+
+	rows, err := l.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*Entry
+	for rows.Next() {
+		entry := &Entry{}
+		var category, status string
+		var oldValuesJSON, newValuesJSON, metadataJSON []byte
+		var riskScore sql.NullInt64
+		var flags pq.StringArray
+
+		err := rows.Scan(
+			&entry.LogID,
+			&entry.EventType,
+			&category,
+			&entry.EventDescription,
+			&entry.UserID,
+			&entry.Username,
+			&entry.UserRole,
+			&entry.SessionID,
+			&entry.IPAddress,
+			&entry.UserAgent,
+			&entry.RequestID,
+			&entry.ResourceType,
+			&entry.ResourceID,
+			&oldValuesJSON,
+			&newValuesJSON,
+			&status,
+			&entry.ErrorMessage,
+			&riskScore,
+			&flags,
+			&entry.EventTimestamp,
+			&metadataJSON,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+
+		entry.EventCategory = Category(category)
+		entry.Status = Status(status)
+		entry.ComplianceFlags = []string(flags)
+		if riskScore.Valid {
+			score := int(riskScore.Int64)
+			entry.RiskScore = &score
+		}
+
+		if err := unmarshalIfPresent(oldValuesJSON, &entry.OldValues); err != nil {
+			return nil, fmt.Errorf("failed to deserialize old values: %w", err)
+		}
+		if err := unmarshalIfPresent(newValuesJSON, &entry.NewValues); err != nil {
+			return nil, fmt.Errorf("failed to deserialize new values: %w", err)
+		}
+		if err := unmarshalIfPresent(metadataJSON, &entry.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to deserialize metadata: %w", err)
+		}
+
+		// Restore non-UUID identifiers that were moved into metadata on write
+		if entry.UserID == "" {
+			entry.UserID = metadataString(entry.Metadata, "userId")
+		}
+		if entry.ResourceID == "" {
+			entry.ResourceID = metadataString(entry.Metadata, "resourceId")
+		}
+		if entry.RequestID == "" {
+			entry.RequestID = metadataString(entry.Metadata, "requestId")
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// InMemoryAuditLog keeps audit entries in memory for testing and development
+type InMemoryAuditLog struct {
+	mu      sync.RWMutex
+	entries []*Entry
+}
+
+// NewInMemoryAuditLog creates a new in-memory audit log
+func NewInMemoryAuditLog() *InMemoryAuditLog {
+	return &InMemoryAuditLog{
+		entries: make([]*Entry, 0),
+	}
+}
+
+// Record stores an audit entry
+func (l *InMemoryAuditLog) Record(entry *Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if entry.LogID == "" {
+		entry.LogID = uuid.New().String()
+	}
+	if entry.EventTimestamp.IsZero() {
+		entry.EventTimestamp = time.Now()
+	}
+	if entry.Status == "" {
+		entry.Status = StatusSuccess
+	}
+
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+// Query returns audit entries matching the filter, newest first
+func (l *InMemoryAuditLog) Query(filter Filter) ([]*Entry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var matched []*Entry
+	for _, entry := range l.entries {
+		if filter.Matches(entry) {
+			matched = append(matched, entry)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].EventTimestamp.After(matched[j].EventTimestamp)
+	})
+
+	if filter.Offset >= len(matched) {
+		return []*Entry{}, nil
+	}
+	matched = matched[filter.Offset:]
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultQueryLimit
+	}
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+
+	return matched, nil
+}
+
+// Matches reports whether an entry satisfies the filter
+func (f Filter) Matches(entry *Entry) bool {
+	if f.UserID != "" && entry.UserID != f.UserID {
+		return false
+	}
+	if f.EventType != "" && entry.EventType != f.EventType {
+		return false
+	}
+	if f.EventCategory != "" && entry.EventCategory != f.EventCategory {
+		return false
+	}
+	if f.Status != "" && entry.Status != f.Status {
+		return false
+	}
+	if f.ResourceType != "" && entry.ResourceType != f.ResourceType {
+		return false
+	}
+	if f.ResourceID != "" && entry.ResourceID != f.ResourceID {
+		return false
+	}
+	if f.IPAddress != "" && entry.IPAddress != f.IPAddress {
+		return false
+	}
+	if f.From != nil && entry.EventTimestamp.Before(*f.From) {
+		return false
+	}
+	if f.To != nil && entry.EventTimestamp.After(*f.To) {
+		return false
+	}
+	return true
+}
+
+// Helper functions
+
+func uuidOrNil(value, metadataKey string, metadata map[string]interface{}) interface{} {
+	if value == "" {
+		return nil
+	}
+	if _, err := uuid.Parse(value); err != nil {
+		metadata[metadataKey] = value
+		return nil
+	}
+	return value
+}
+
+func nullString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func jsonOrNil(value map[string]interface{}) (interface{}, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func unmarshalIfPresent(data []byte, target *map[string]interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, target)
+}
+
+func metadataString(metadata map[string]interface{}, key string) string {
+	if value, ok := metadata[key].(string); ok {
+		return value
+	}
+	return ""
+}
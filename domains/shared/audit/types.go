@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// Category groups audit entries for compliance reporting
+type Category string
+
+const (
+	CategorySecurity   Category = "security"
+	CategoryTrading    Category = "trading"
+	CategoryCompliance Category = "compliance"
+	CategoryAdmin      Category = "admin"
+)
+
+// Status represents the outcome of an audited action
+type Status string
+
+const (
+	StatusSuccess Status = "success"
+	StatusFailure Status = "failure"
+	StatusWarning Status = "warning"
+)
+
+// Entry represents a single row in the audit_log table
+type Entry struct {
+	LogID            string                 `json:"logId"`
+	EventType        string                 `json:"eventType"`
+	EventCategory    Category               `json:"eventCategory"`
+	EventDescription string                 `json:"eventDescription"`
+	UserID           string                 `json:"userId,omitempty"`
+	Username         string                 `json:"username,omitempty"`
+	UserRole         string                 `json:"userRole,omitempty"`
+	SessionID        string                 `json:"sessionId,omitempty"`
+	IPAddress        string                 `json:"ipAddress,omitempty"`
+	UserAgent        string                 `json:"userAgent,omitempty"`
+	RequestID        string                 `json:"requestId,omitempty"`
+	ResourceType     string                 `json:"resourceType,omitempty"`
+	ResourceID       string                 `json:"resourceId,omitempty"`
+	OldValues        map[string]interface{} `json:"oldValues,omitempty"`
+	NewValues        map[string]interface{} `json:"newValues,omitempty"`
+	Status           Status                 `json:"status"`
+	ErrorMessage     string                 `json:"errorMessage,omitempty"`
+	RiskScore        *int                   `json:"riskScore,omitempty"`
+	ComplianceFlags  []string               `json:"complianceFlags,omitempty"`
+	EventTimestamp   time.Time              `json:"eventTimestamp"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Filter narrows audit log queries
+type Filter struct {
+	UserID        string     `json:"userId,omitempty"`
+	EventType     string     `json:"eventType,omitempty"`
+	EventCategory Category   `json:"eventCategory,omitempty"`
+	Status        Status     `json:"status,omitempty"`
+	ResourceType  string     `json:"resourceType,omitempty"`
+	ResourceID    string     `json:"resourceId,omitempty"`
+	IPAddress     string     `json:"ipAddress,omitempty"`
+	From          *time.Time `json:"from,omitempty"`
+	To            *time.Time `json:"to,omitempty"`
+	Limit         int        `json:"limit,omitempty"`
+	Offset        int        `json:"offset,omitempty"`
+}
+
+// Logger records and queries audit entries
+type Logger interface {
+	Record(entry *Entry) error
+	Query(filter Filter) ([]*Entry, error)
+}
+
+// Actor identifies who performed an action and from where
+type Actor struct {
+	UserID    string `json:"userId"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	SessionID string `json:"sessionId"`
+	IPAddress string `json:"ipAddress"`
+	UserAgent string `json:"userAgent"`
+	RequestID string `json:"requestId"`
+}
+
+type contextKey string
+
+// ActorContextKey is the key for storing the audit actor in request context
+const ActorContextKey contextKey = "audit_actor"
+
+// WithActor returns a copy of ctx carrying the given actor
+func WithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, ActorContextKey, actor)
+}
+
+// ActorFromContext extracts the audit actor from context
+func ActorFromContext(ctx context.Context) *Actor {
+	actor, ok := ctx.Value(ActorContextKey).(*Actor)
+	if !ok {
+		return nil
+	}
+	return actor
+}
+
+// Apply copies the actor's identity and request information onto an entry
+func (a *Actor) Apply(entry *Entry) {
+	if a == nil {
+		return
+	}
+	entry.UserID = a.UserID
+	entry.Username = a.Username
+	entry.UserRole = a.Role
+	entry.SessionID = a.SessionID
+	entry.IPAddress = a.IPAddress
+	entry.UserAgent = a.UserAgent
+	entry.RequestID = a.RequestID
+}
+
+// CategoryForAggregate maps an aggregate type to its audit category
+func CategoryForAggregate(aggregateType string) Category {
+	switch aggregateType {
+	case "User":
+		return CategoryCompliance
+	case "Security":
+		return CategoryAdmin
+	default:
+		return CategoryTrading
+	}
+}
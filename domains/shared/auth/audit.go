@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"securities-marketplace/domains/shared/audit"
+)
+
+// AuditActorFromRequest builds an audit actor from the authenticated user and
+// request headers. It satisfies audit.ActorResolver.
+func AuditActorFromRequest(r *http.Request) *audit.Actor {
+	actor := &audit.Actor{
+		IPAddress: getClientIP(r),
+		UserAgent: r.UserAgent(),
+		RequestID: r.Header.Get("X-Request-ID"),
+	}
+
+	if userCtx := GetUserFromContext(r.Context()); userCtx != nil {
+		actor.UserID = userCtx.UserID
+		actor.Username = userCtx.Email
+		actor.Role = strings.Join(userCtx.Roles, ",")
+		actor.SessionID = userCtx.SessionID
+	}
+
+	if actor.SessionID == "" {
+		if session := GetSessionFromContext(r.Context()); session != nil {
+			actor.SessionID = session.ID
+		}
+	}
+
+	return actor
+}
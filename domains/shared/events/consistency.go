@@ -0,0 +1,171 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// ConsistencyTokenHeader carries the global event_number a client has
+	// written up to. Commands return it; queries accept it.
+	ConsistencyTokenHeader = "X-Consistency-Token"
+
+	// ConsistencyTokenQueryParam carries the token across browser redirects,
+	// e.g. /bids?consistency=42 after placing a bid
+	ConsistencyTokenQueryParam = "consistency"
+
+	// ConsistencyStaleHeader is set on query responses that were served before
+	// the projection reached the requested position
+	ConsistencyStaleHeader = "X-Consistency-Stale"
+
+	// DefaultConsistencyTimeout bounds how long a query waits for projections
+	DefaultConsistencyTimeout = 2 * time.Second
+
+	defaultConsistencyPollInterval = 25 * time.Millisecond
+)
+
+// ErrConsistencyTimeout is returned when a projection does not reach the
+// requested position before the timeout
+var ErrConsistencyTimeout = errors.New("projection did not reach requested position before timeout")
+
+// ConsistencyWaiter blocks queries until projection checkpoints have caught
+// up with a client's consistency token
+type ConsistencyWaiter struct {
+	checkpoints  CheckpointStore
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+// NewConsistencyWaiter creates a new consistency waiter. A non-positive
+// timeout uses DefaultConsistencyTimeout.
+func NewConsistencyWaiter(checkpoints CheckpointStore, timeout time.Duration) *ConsistencyWaiter {
+	if timeout <= 0 {
+		timeout = DefaultConsistencyTimeout
+	}
+
+	return &ConsistencyWaiter{
+		checkpoints:  checkpoints,
+		timeout:      timeout,
+		pollInterval: defaultConsistencyPollInterval,
+	}
+}
+
+// WaitFor waits until every named projection has processed the given event
+// number, the timeout elapses or the context is cancelled
+func (w *ConsistencyWaiter) WaitFor(ctx context.Context, eventNumber int64, projectionNames ...string) error {
+	if eventNumber <= 0 || len(projectionNames) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		caughtUp, err := w.caughtUp(eventNumber, projectionNames)
+		if err != nil {
+			return err
+		}
+		if caughtUp {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrConsistencyTimeout
+		case <-ticker.C:
+		}
+	}
+}
+
+// caughtUp reports whether every named projection has reached the event number
+func (w *ConsistencyWaiter) caughtUp(eventNumber int64, projectionNames []string) (bool, error) {
+	for _, name := range projectionNames {
+		checkpoint, err := w.checkpoints.GetProjectionCheckpoint(name)
+		if err != nil {
+			return false, fmt.Errorf("failed to get checkpoint for %s: %w", name, err)
+		}
+		if checkpoint.Status == ProjectionStatusFailed {
+			// A failed projection will not advance until an operator intervenes
+			return false, fmt.Errorf("projection %s has failed", name)
+		}
+		if checkpoint.LastProcessedEventNumber < eventNumber {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Middleware waits for the named projections before serving GET and HEAD
+// requests that carry a consistency token. Requests are still served after a
+// timeout, with ConsistencyStaleHeader set so clients can retry or warn the user.
+func (w *ConsistencyWaiter) Middleware(projectionNames ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			// Only reads wait; commands return a fresh token instead
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(rw, r)
+				return
+			}
+
+			eventNumber, err := ConsistencyTokenFromRequest(r)
+			if err != nil {
+				http.Error(rw, "Invalid consistency token", http.StatusBadRequest)
+				return
+			}
+
+			if eventNumber > 0 {
+				if err := w.WaitFor(r.Context(), eventNumber, projectionNames...); err != nil {
+					log.Printf("Serving stale read for %s at position %d: %v", r.URL.Path, eventNumber, err)
+					rw.Header().Set(ConsistencyStaleHeader, "true")
+				}
+				// Echo the token so clients can keep passing it along
+				SetConsistencyToken(rw, eventNumber)
+			}
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// FormatConsistencyToken encodes an event number as a consistency token
+func FormatConsistencyToken(eventNumber int64) string {
+	return strconv.FormatInt(eventNumber, 10)
+}
+
+// ParseConsistencyToken decodes a consistency token into an event number
+func ParseConsistencyToken(token string) (int64, error) {
+	eventNumber, err := strconv.ParseInt(token, 10, 64)
+	if err != nil || eventNumber < 0 {
+		return 0, fmt.Errorf("invalid consistency token: %q", token)
+	}
+	return eventNumber, nil
+}
+
+// SetConsistencyToken writes the consistency token header for a known position
+func SetConsistencyToken(w http.ResponseWriter, eventNumber int64) {
+	if eventNumber <= 0 {
+		return
+	}
+	w.Header().Set(ConsistencyTokenHeader, FormatConsistencyToken(eventNumber))
+}
+
+// ConsistencyTokenFromRequest returns the event number from the request's
+// consistency token header or query parameter, or zero when none was sent
+func ConsistencyTokenFromRequest(r *http.Request) (int64, error) {
+	token := r.Header.Get(ConsistencyTokenHeader)
+	if token == "" {
+		token = r.URL.Query().Get(ConsistencyTokenQueryParam)
+	}
+	if token == "" {
+		return 0, nil
+	}
+	return ParseConsistencyToken(token)
+}
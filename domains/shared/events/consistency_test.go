@@ -0,0 +1,138 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/testutil"
+)
+
+type recordedEvent struct {
+	record *events.Event
+}
+
+func (e *recordedEvent) GetEventType() string          { return e.record.EventType }
+func (e *recordedEvent) GetAggregateID() string        { return e.record.AggregateID }
+func (e *recordedEvent) GetAggregateType() string      { return e.record.AggregateType }
+func (e *recordedEvent) GetEventData() ([]byte, error) { return e.record.EventData, nil }
+func (e *recordedEvent) GetMetadata() events.Metadata  { return e.record.Metadata }
+
+type countingProjection struct {
+	handled []string
+	failOn  string
+}
+
+func (p *countingProjection) GetProjectionName() string { return "counting_projection" }
+
+func (p *countingProjection) Handle(event events.DomainEvent) error {
+	if event.GetEventType() == p.failOn {
+		return errors.New("projection write failed")
+	}
+	p.handled = append(p.handled, event.GetEventType())
+	return nil
+}
+
+func decodeRecorded(record *events.Event) (events.DomainEvent, error) {
+	if record.AggregateType != "User" {
+		return nil, nil
+	}
+	return &recordedEvent{record: record}, nil
+}
+
+func seedEvents(t *testing.T, store *testutil.TestEventStore, eventTypes ...string) []*events.Event {
+	var records []*events.Event
+	for _, eventType := range eventTypes {
+		records = append(records, &events.Event{EventType: eventType, AggregateID: "user-1", AggregateType: "User"})
+	}
+	testutil.AssertNoError(t, store.SaveEvents(records), "Events should be saved")
+	return records
+}
+
+func TestProjectionRunner_AdvancesCheckpoint(t *testing.T) {
+	store := testutil.NewTestEventStore()
+	records := seedEvents(t, store, "UserRegistered", "UserProfileUpdated")
+	store.SaveEvent(&events.Event{EventType: "TradeMatched", AggregateType: "Trade"})
+
+	projection := &countingProjection{}
+	runner := events.NewProjectionRunner(store, store, projection, decodeRecorded)
+
+	processed, err := runner.RunOnce()
+	testutil.AssertNoError(t, err, "Runner should succeed")
+	testutil.AssertEqual(t, 3, processed, "All events should be processed")
+	testutil.AssertLengthEqual(t, 2, projection.handled, "Only decoded events reach the projection")
+	testutil.AssertEqual(t, int64(2), records[1].EventNumber, "Saved events should be assigned event numbers")
+
+	checkpoint, _ := store.GetProjectionCheckpoint("counting_projection")
+	testutil.AssertEqual(t, int64(3), checkpoint.LastProcessedEventNumber, "Checkpoint should reach the last event")
+}
+
+func TestProjectionRunner_HandlerErrorMarksFailed(t *testing.T) {
+	store := testutil.NewTestEventStore()
+	seedEvents(t, store, "UserRegistered", "UserSuspended", "UserReinstated")
+
+	runner := events.NewProjectionRunner(store, store, &countingProjection{failOn: "UserSuspended"}, decodeRecorded)
+
+	processed, err := runner.RunOnce()
+	testutil.AssertError(t, err, "Handler errors should be returned")
+	testutil.AssertEqual(t, 1, processed, "Events before the failure should be processed")
+
+	checkpoint, _ := store.GetProjectionCheckpoint("counting_projection")
+	testutil.AssertEqual(t, "failed", checkpoint.Status, "Projection should be marked failed")
+	testutil.AssertEqual(t, int64(1), checkpoint.LastProcessedEventNumber, "Checkpoint should stay on the last applied event")
+}
+
+func TestConsistencyWaiter_WaitsForCheckpoint(t *testing.T) {
+	store := testutil.NewTestEventStore()
+	records := seedEvents(t, store, "UserRegistered")
+	runner := events.NewProjectionRunner(store, store, &countingProjection{}, decodeRecorded)
+	waiter := events.NewConsistencyWaiter(store, time.Second)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		runner.RunOnce()
+	}()
+
+	err := waiter.WaitFor(context.Background(), records[0].EventNumber, "counting_projection")
+	testutil.AssertNoError(t, err, "Waiter should return once the projection catches up")
+}
+
+func TestConsistencyWaiter_TimesOut(t *testing.T) {
+	store := testutil.NewTestEventStore()
+	waiter := events.NewConsistencyWaiter(store, 50*time.Millisecond)
+
+	err := waiter.WaitFor(context.Background(), 10, "counting_projection")
+	testutil.AssertTrue(t, errors.Is(err, events.ErrConsistencyTimeout), "Waiter should time out")
+}
+
+func TestConsistencyMiddleware(t *testing.T) {
+	store := testutil.NewTestEventStore()
+	waiter := events.NewConsistencyWaiter(store, 50*time.Millisecond)
+	handler := waiter.Middleware("counting_projection")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	stale := httptest.NewRequest(http.MethodGet, "/users", nil)
+	stale.Header.Set(events.ConsistencyTokenHeader, "7")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, stale)
+	testutil.AssertEqual(t, http.StatusOK, rec.Code, "Stale reads should still be served")
+	testutil.AssertEqual(t, "true", rec.Header().Get(events.ConsistencyStaleHeader), "Stale reads should be flagged")
+	testutil.AssertEqual(t, "7", rec.Header().Get(events.ConsistencyTokenHeader), "Token should be echoed")
+
+	store.SaveProjectionCheckpoint(&events.ProjectionCheckpoint{ProjectionName: "counting_projection", LastProcessedEventNumber: 7, Status: "active"})
+	fresh := httptest.NewRequest(http.MethodGet, "/users?consistency=7", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, fresh)
+	testutil.AssertEqual(t, "", rec.Header().Get(events.ConsistencyStaleHeader), "Caught-up reads should not be flagged")
+
+	invalid := httptest.NewRequest(http.MethodGet, "/users", nil)
+	invalid.Header.Set(events.ConsistencyTokenHeader, "abc")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, invalid)
+	testutil.AssertEqual(t, http.StatusBadRequest, rec.Code, "Invalid tokens should be rejected")
+}
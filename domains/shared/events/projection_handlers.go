@@ -0,0 +1,132 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// ProjectionHandler exposes projection health and operator controls
+type ProjectionHandler struct {
+	monitor *ProjectionMonitor
+}
+
+// NewProjectionHandler creates a new projection handler
+func NewProjectionHandler(monitor *ProjectionMonitor) *ProjectionHandler {
+	return &ProjectionHandler{monitor: monitor}
+}
+
+// RegisterRoutes registers the handler routes. Callers are expected to mount
+// the router behind admin authorization.
+func (h *ProjectionHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/projections", h.HandleList).Methods("GET")
+	router.HandleFunc("/projections/{name}", h.HandleGet).Methods("GET")
+	router.HandleFunc("/projections/{name}/pause", h.HandlePause).Methods("POST")
+	router.HandleFunc("/projections/{name}/resume", h.HandleResume).Methods("POST")
+	router.HandleFunc("/projections/{name}/reset", h.HandleReset).Methods("POST")
+	router.HandleFunc("/projections/{name}/fail", h.HandleMarkFailed).Methods("POST")
+}
+
+// HandleList returns the health of every projection
+func (h *ProjectionHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	health, err := h.monitor.Health()
+	if err != nil {
+		http.Error(w, "Failed to get projection health", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"success":     true,
+		"projections": health,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleGet returns the health of a single projection
+func (h *ProjectionHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	health, err := h.monitor.Get(mux.Vars(r)["name"])
+	if err != nil {
+		writeProjectionError(w, err)
+		return
+	}
+
+	writeProjectionHealth(w, health)
+}
+
+// HandlePause pauses a projection
+func (h *ProjectionHandler) HandlePause(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	h.control(w, name, h.monitor.Pause(name))
+}
+
+// HandleResume resumes a paused or failed projection
+func (h *ProjectionHandler) HandleResume(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	h.control(w, name, h.monitor.Resume(name))
+}
+
+// HandleReset moves a projection's checkpoint to the requested position
+func (h *ProjectionHandler) HandleReset(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Position int64 `json:"position"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	h.control(w, name, h.monitor.Reset(name, request.Position))
+}
+
+// HandleMarkFailed marks a projection failed with an operator supplied reason
+func (h *ProjectionHandler) HandleMarkFailed(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	h.control(w, name, h.monitor.MarkFailed(name, request.Reason))
+}
+
+// control writes the result of an operator control
+func (h *ProjectionHandler) control(w http.ResponseWriter, name string, err error) {
+	if err != nil {
+		writeProjectionError(w, err)
+		return
+	}
+
+	health, err := h.monitor.Get(name)
+	if err != nil {
+		writeProjectionError(w, err)
+		return
+	}
+
+	writeProjectionHealth(w, health)
+}
+
+func writeProjectionHealth(w http.ResponseWriter, health *ProjectionHealth) {
+	response := map[string]interface{}{
+		"success":    true,
+		"projection": health,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeProjectionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrUnknownProjection) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
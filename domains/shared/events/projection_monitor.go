@@ -0,0 +1,215 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrUnknownProjection is returned for controls on a projection that is
+// neither registered nor has a checkpoint
+var ErrUnknownProjection = errors.New("unknown projection")
+
+// ProjectionHealth describes how far a projection is behind the event stream
+type ProjectionHealth struct {
+	ProjectionName           string     `json:"projectionName"`
+	Status                   string     `json:"status"`
+	LastProcessedEventNumber int64      `json:"lastProcessedEventNumber"`
+	HeadEventNumber          int64      `json:"headEventNumber"`
+	Lag                      int64      `json:"lag"`
+	Throughput               float64    `json:"throughput"`
+	EventsProcessed          int64      `json:"eventsProcessed"`
+	LastProcessedAt          time.Time  `json:"lastProcessedAt"`
+	LastError                string     `json:"lastError,omitempty"`
+	LastErrorAt              *time.Time `json:"lastErrorAt,omitempty"`
+}
+
+// ProjectionMonitor reports projection health and applies operator controls.
+// Controls only change the checkpoint; projection runners pick them up on
+// their next batch.
+type ProjectionMonitor struct {
+	store      ProjectionStatusStore
+	registered []string
+}
+
+// NewProjectionMonitor creates a new projection monitor for the registered
+// projection names. Projections with a stored checkpoint are always included.
+func NewProjectionMonitor(store ProjectionStatusStore, registered ...string) *ProjectionMonitor {
+	return &ProjectionMonitor{
+		store:      store,
+		registered: registered,
+	}
+}
+
+// Health returns the health of every known projection, ordered by name
+func (m *ProjectionMonitor) Health() ([]*ProjectionHealth, error) {
+	head, err := m.store.GetHeadEventNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := m.store.ListProjectionCheckpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*ProjectionCheckpoint)
+	for _, checkpoint := range checkpoints {
+		byName[checkpoint.ProjectionName] = checkpoint
+	}
+	for _, name := range m.registered {
+		if _, exists := byName[name]; !exists {
+			// Registered but never run; it is behind by the whole stream
+			byName[name] = &ProjectionCheckpoint{ProjectionName: name, Status: ProjectionStatusActive}
+		}
+	}
+
+	health := make([]*ProjectionHealth, 0, len(byName))
+	for _, checkpoint := range byName {
+		health = append(health, newProjectionHealth(checkpoint, head))
+	}
+	sort.Slice(health, func(i, j int) bool {
+		return health[i].ProjectionName < health[j].ProjectionName
+	})
+
+	return health, nil
+}
+
+// Get returns the health of a single projection
+func (m *ProjectionMonitor) Get(projectionName string) (*ProjectionHealth, error) {
+	checkpoint, err := m.checkpoint(projectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := m.store.GetHeadEventNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	return newProjectionHealth(checkpoint, head), nil
+}
+
+// Pause stops a projection from processing further events
+func (m *ProjectionMonitor) Pause(projectionName string) error {
+	return m.update(projectionName, func(checkpoint *ProjectionCheckpoint) error {
+		if checkpoint.Status == ProjectionStatusFailed {
+			return fmt.Errorf("projection %s has failed; resume or reset it instead", projectionName)
+		}
+		checkpoint.Status = ProjectionStatusPaused
+		return nil
+	})
+}
+
+// Resume lets a paused or failed projection continue from its checkpoint
+func (m *ProjectionMonitor) Resume(projectionName string) error {
+	return m.update(projectionName, func(checkpoint *ProjectionCheckpoint) error {
+		checkpoint.Status = ProjectionStatusActive
+		return nil
+	})
+}
+
+// Reset moves a projection's checkpoint to the given event number so it
+// replays everything after it. The projection rebuilds until it reaches the head.
+// Rows already in the read model are not cleared, so projections must apply
+// replayed events over them: events that create rows replace any existing one.
+func (m *ProjectionMonitor) Reset(projectionName string, eventNumber int64) error {
+	head, err := m.store.GetHeadEventNumber()
+	if err != nil {
+		return err
+	}
+	if eventNumber < 0 || eventNumber > head {
+		return fmt.Errorf("reset position %d is outside the event stream (0-%d)", eventNumber, head)
+	}
+
+	return m.update(projectionName, func(checkpoint *ProjectionCheckpoint) error {
+		checkpoint.LastProcessedEventNumber = eventNumber
+		checkpoint.Status = ProjectionStatusRebuilding
+		checkpoint.Throughput = 0
+		return nil
+	})
+}
+
+// MarkFailed stops a projection and records why, e.g. when an operator finds
+// its read model corrupt
+func (m *ProjectionMonitor) MarkFailed(projectionName, reason string) error {
+	if reason == "" {
+		return fmt.Errorf("a reason is required to mark a projection failed")
+	}
+
+	return m.update(projectionName, func(checkpoint *ProjectionCheckpoint) error {
+		now := time.Now()
+		checkpoint.Status = ProjectionStatusFailed
+		checkpoint.LastError = reason
+		checkpoint.LastErrorAt = &now
+		return nil
+	})
+}
+
+// update loads, changes and saves a projection checkpoint
+func (m *ProjectionMonitor) update(projectionName string, change func(*ProjectionCheckpoint) error) error {
+	checkpoint, err := m.checkpoint(projectionName)
+	if err != nil {
+		return err
+	}
+
+	if err := change(checkpoint); err != nil {
+		return err
+	}
+
+	return m.store.SaveProjectionCheckpoint(checkpoint)
+}
+
+// checkpoint returns the checkpoint of a known projection
+func (m *ProjectionMonitor) checkpoint(projectionName string) (*ProjectionCheckpoint, error) {
+	if !m.isRegistered(projectionName) {
+		checkpoints, err := m.store.ListProjectionCheckpoints()
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, checkpoint := range checkpoints {
+			if checkpoint.ProjectionName == projectionName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownProjection, projectionName)
+		}
+	}
+
+	return m.store.GetProjectionCheckpoint(projectionName)
+}
+
+// isRegistered reports whether the projection name was registered
+func (m *ProjectionMonitor) isRegistered(projectionName string) bool {
+	for _, name := range m.registered {
+		if name == projectionName {
+			return true
+		}
+	}
+	return false
+}
+
+// newProjectionHealth builds a health report from a checkpoint and the stream head
+func newProjectionHealth(checkpoint *ProjectionCheckpoint, head int64) *ProjectionHealth {
+	lag := head - checkpoint.LastProcessedEventNumber
+	if lag < 0 {
+		lag = 0
+	}
+
+	return &ProjectionHealth{
+		ProjectionName:           checkpoint.ProjectionName,
+		Status:                   checkpoint.Status,
+		LastProcessedEventNumber: checkpoint.LastProcessedEventNumber,
+		HeadEventNumber:          head,
+		Lag:                      lag,
+		Throughput:               checkpoint.Throughput,
+		EventsProcessed:          checkpoint.EventsProcessed,
+		LastProcessedAt:          checkpoint.LastProcessedAt,
+		LastError:                checkpoint.LastError,
+		LastErrorAt:              checkpoint.LastErrorAt,
+	}
+}
@@ -0,0 +1,80 @@
+package events_test
+
+import (
+	"errors"
+	"testing"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/testutil"
+)
+
+func TestProjectionMonitor_HealthReportsLag(t *testing.T) {
+	store := testutil.NewTestEventStore()
+	seedEvents(t, store, "UserRegistered", "UserSuspended")
+	runner := events.NewProjectionRunner(store, store, &countingProjection{}, decodeRecorded)
+	runner.RunOnce()
+	seedEvents(t, store, "UserReinstated")
+
+	monitor := events.NewProjectionMonitor(store, "counting_projection", "idle_projection")
+	health, err := monitor.Health()
+
+	testutil.AssertNoError(t, err, "Health should succeed")
+	testutil.AssertLengthEqual(t, 2, health, "Registered projections should be listed even if never run")
+	testutil.AssertEqual(t, "counting_projection", health[0].ProjectionName, "Projections should be ordered by name")
+	testutil.AssertEqual(t, int64(3), health[0].HeadEventNumber, "Head should be the newest event")
+	testutil.AssertEqual(t, int64(1), health[0].Lag, "Lag should count unprocessed events")
+	testutil.AssertEqual(t, int64(2), health[0].EventsProcessed, "Processed events should be counted")
+	testutil.AssertEqual(t, int64(3), health[1].Lag, "A projection that never ran lags the whole stream")
+}
+
+func TestProjectionMonitor_PauseAndResume(t *testing.T) {
+	store := testutil.NewTestEventStore()
+	seedEvents(t, store, "UserRegistered")
+	runner := events.NewProjectionRunner(store, store, &countingProjection{}, decodeRecorded)
+	monitor := events.NewProjectionMonitor(store, "counting_projection")
+
+	testutil.AssertNoError(t, monitor.Pause("counting_projection"), "Pause should succeed")
+	processed, _ := runner.RunOnce()
+	testutil.AssertEqual(t, 0, processed, "Paused projections should not process events")
+
+	testutil.AssertNoError(t, monitor.Resume("counting_projection"), "Resume should succeed")
+	processed, _ = runner.RunOnce()
+	testutil.AssertEqual(t, 1, processed, "Resumed projections should process events")
+}
+
+func TestProjectionMonitor_ResetRebuildsToHead(t *testing.T) {
+	store := testutil.NewTestEventStore()
+	seedEvents(t, store, "UserRegistered", "UserSuspended", "UserReinstated")
+	projection := &countingProjection{}
+	runner := events.NewProjectionRunner(store, store, projection, decodeRecorded)
+	runner.RunOnce()
+	monitor := events.NewProjectionMonitor(store, "counting_projection")
+
+	testutil.AssertError(t, monitor.Reset("counting_projection", 10), "Reset beyond the head should be rejected")
+	testutil.AssertNoError(t, monitor.Reset("counting_projection", 1), "Reset should succeed")
+
+	health, _ := monitor.Get("counting_projection")
+	testutil.AssertEqual(t, events.ProjectionStatusRebuilding, health.Status, "Reset projections should rebuild")
+
+	runner.RunOnce()
+	health, _ = monitor.Get("counting_projection")
+	testutil.AssertEqual(t, events.ProjectionStatusActive, health.Status, "Rebuilt projections should become active")
+	testutil.AssertEqual(t, int64(0), health.Lag, "Rebuilt projections should reach the head")
+	testutil.AssertLengthEqual(t, 5, projection.handled, "Events after the reset position should be replayed")
+}
+
+func TestProjectionMonitor_MarkFailed(t *testing.T) {
+	store := testutil.NewTestEventStore()
+	monitor := events.NewProjectionMonitor(store, "counting_projection")
+
+	testutil.AssertError(t, monitor.MarkFailed("counting_projection", ""), "A reason should be required")
+	testutil.AssertNoError(t, monitor.MarkFailed("counting_projection", "read model corrupt"), "MarkFailed should succeed")
+
+	health, _ := monitor.Get("counting_projection")
+	testutil.AssertEqual(t, events.ProjectionStatusFailed, health.Status, "Projection should be failed")
+	testutil.AssertEqual(t, "read model corrupt", health.LastError, "Reason should be recorded")
+	testutil.AssertNotNil(t, health.LastErrorAt, "Failure time should be recorded")
+
+	err := monitor.Pause("unknown_projection")
+	testutil.AssertTrue(t, errors.Is(err, events.ErrUnknownProjection), "Unknown projections should be rejected")
+}
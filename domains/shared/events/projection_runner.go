@@ -0,0 +1,175 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Projection builds a read model from domain events
+type Projection interface {
+	GetProjectionName() string
+	Handle(event DomainEvent) error
+}
+
+// EventDecoder converts a stored event into the domain event a projection
+// understands. Decoders return nil, nil for events the projection ignores.
+type EventDecoder func(event *Event) (DomainEvent, error)
+
+const (
+	defaultProjectionBatchSize    = 500
+	defaultProjectionPollInterval = 100 * time.Millisecond
+)
+
+// ProjectionRunner feeds events to a projection in event_number order and
+// advances its checkpoint, which is what consistency tokens are compared to
+type ProjectionRunner struct {
+	eventStore   EventStore
+	checkpoints  CheckpointStore
+	projection   Projection
+	decode       EventDecoder
+	batchSize    int
+	pollInterval time.Duration
+}
+
+// NewProjectionRunner creates a new projection runner
+func NewProjectionRunner(eventStore EventStore, checkpoints CheckpointStore, projection Projection, decode EventDecoder) *ProjectionRunner {
+	return &ProjectionRunner{
+		eventStore:   eventStore,
+		checkpoints:  checkpoints,
+		projection:   projection,
+		decode:       decode,
+		batchSize:    defaultProjectionBatchSize,
+		pollInterval: defaultProjectionPollInterval,
+	}
+}
+
+// Run processes events until the context is cancelled
+func (r *ProjectionRunner) Run(ctx context.Context) {
+	name := r.projection.GetProjectionName()
+	log.Printf("Starting projection %s", name)
+
+	for {
+		processed, err := r.RunOnce()
+		if err != nil {
+			log.Printf("Projection %s stopped advancing: %v", name, err)
+		}
+
+		if processed == 0 || err != nil {
+			select {
+			case <-ctx.Done():
+				log.Printf("Projection %s stopped", name)
+				return
+			case <-time.After(r.pollInterval):
+			}
+			continue
+		}
+
+		if ctx.Err() != nil {
+			log.Printf("Projection %s stopped", name)
+			return
+		}
+	}
+}
+
+// RunOnce processes the next batch of events after the checkpoint and returns
+// how many were processed. Paused and failed projections are skipped. A handler
+// error marks the projection failed and leaves the checkpoint on the last
+// successfully applied event.
+func (r *ProjectionRunner) RunOnce() (int, error) {
+	name := r.projection.GetProjectionName()
+
+	checkpoint, err := r.checkpoints.GetProjectionCheckpoint(name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get checkpoint: %w", err)
+	}
+	if checkpoint.Status == ProjectionStatusFailed || checkpoint.Status == ProjectionStatusPaused {
+		return 0, nil
+	}
+	started := *checkpoint
+	startedAt := time.Now()
+
+	storedEvents, err := r.eventStore.GetAllEvents(checkpoint.LastProcessedEventNumber, r.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get events: %w", err)
+	}
+	if len(storedEvents) == 0 {
+		if checkpoint.Status == ProjectionStatusRebuilding {
+			checkpoint.Status = ProjectionStatusActive
+			return 0, r.save(&started, checkpoint)
+		}
+		return 0, nil
+	}
+
+	processed := 0
+	for _, event := range storedEvents {
+		if err := r.apply(event); err != nil {
+			applyErr := fmt.Errorf("failed to apply event %d (%s): %w", event.EventNumber, event.EventType, err)
+			now := time.Now()
+			checkpoint.Status = ProjectionStatusFailed
+			checkpoint.LastError = applyErr.Error()
+			checkpoint.LastErrorAt = &now
+			r.recordBatch(checkpoint, processed, startedAt)
+			if saveErr := r.save(&started, checkpoint); saveErr != nil {
+				log.Printf("Failed to save checkpoint for projection %s: %v", name, saveErr)
+			}
+			return processed, applyErr
+		}
+		checkpoint.LastProcessedEventNumber = event.EventNumber
+		processed++
+	}
+
+	// A short batch means the projection has reached the head of the stream
+	if checkpoint.Status == ProjectionStatusRebuilding && len(storedEvents) < r.batchSize {
+		checkpoint.Status = ProjectionStatusActive
+	}
+
+	r.recordBatch(checkpoint, processed, startedAt)
+	if err := r.save(&started, checkpoint); err != nil {
+		return processed, err
+	}
+
+	return processed, nil
+}
+
+// recordBatch updates the checkpoint's operational statistics
+func (r *ProjectionRunner) recordBatch(checkpoint *ProjectionCheckpoint, processed int, startedAt time.Time) {
+	now := time.Now()
+	checkpoint.LastProcessedAt = now
+	checkpoint.EventsProcessed += int64(processed)
+	if elapsed := now.Sub(startedAt).Seconds(); elapsed > 0 {
+		checkpoint.Throughput = float64(processed) / elapsed
+	}
+}
+
+// save writes the checkpoint unless an operator paused, reset or failed the
+// projection while the batch was running, in which case their change wins
+// and the batch is re-applied or skipped on the next run
+func (r *ProjectionRunner) save(started, checkpoint *ProjectionCheckpoint) error {
+	current, err := r.checkpoints.GetProjectionCheckpoint(checkpoint.ProjectionName)
+	if err != nil {
+		return fmt.Errorf("failed to get checkpoint: %w", err)
+	}
+	if current.Status != started.Status || current.LastProcessedEventNumber != started.LastProcessedEventNumber {
+		log.Printf("Projection %s checkpoint changed during batch; discarding progress", checkpoint.ProjectionName)
+		return nil
+	}
+
+	if err := r.checkpoints.SaveProjectionCheckpoint(checkpoint); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// apply decodes a stored event and hands it to the projection
+func (r *ProjectionRunner) apply(event *Event) error {
+	domainEvent, err := r.decode(event)
+	if err != nil {
+		return fmt.Errorf("failed to decode event: %w", err)
+	}
+	if domainEvent == nil {
+		return nil
+	}
+	return r.projection.Handle(domainEvent)
+}
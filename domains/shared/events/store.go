@@ -12,6 +12,9 @@ import (
 	"github.com/lib/pq"
 )
 
+// eventAppendLockKey is the advisory lock appends take turns on
+const eventAppendLockKey int64 = 0x6576656e7473 // "events"
+
 // PostgresEventStore implements EventStore using PostgreSQL
 type PostgresEventStore struct {
 	db *sql.DB
@@ -39,12 +42,21 @@ func (es *PostgresEventStore) SaveEvents(events []*Event) error {
 	}
 	defer tx.Rollback()
 
+	// event_number is assigned at insert but only becomes visible at commit.
+	// Appends hold this lock until they commit so numbers become visible in
+	// order, and readers that have moved past a number never see an earlier
+	// one appear later.
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, eventAppendLockKey); err != nil {
+		return fmt.Errorf("failed to lock event stream: %w", err)
+	}
+
 	stmt, err := tx.Prepare(`
 		INSERT INTO events (
 			event_id, event_type, aggregate_id, aggregate_type, aggregate_version, 
 			event_version, event_data, metadata, occurred_at, user_id, correlation_id, 
 			causation_id, ip_address, user_agent, session_id, checksum
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING event_number
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
@@ -61,7 +73,9 @@ func (es *PostgresEventStore) SaveEvents(events []*Event) error {
 			return fmt.Errorf("failed to serialize metadata: %w", err)
 		}
 
-		_, err = stmt.Exec(
+		// The assigned global position is returned so callers can hand out
+		// read-your-writes consistency tokens
+		err = stmt.QueryRow(
 			event.EventID,
 			event.EventType,
 			event.AggregateID,
@@ -78,7 +92,7 @@ func (es *PostgresEventStore) SaveEvents(events []*Event) error {
 			event.UserAgent,
 			event.SessionID,
 			event.Checksum,
-		)
+		).Scan(&event.EventNumber)
 		if err != nil {
 			if pqErr, ok := err.(*pq.Error); ok {
 				if pqErr.Code == "23505" { // unique_violation
@@ -171,7 +185,9 @@ func (es *PostgresEventStore) GetEventsByType(eventType string, limit int) ([]*E
 	return es.scanEvents(rows)
 }
 
-// GetAllEvents retrieves all events from a specific event number
+// GetAllEvents retrieves all events from a specific event number. Events
+// commit in event_number order (see SaveEvents), so a reader can checkpoint
+// the last number it read without missing later commits.
 func (es *PostgresEventStore) GetAllEvents(fromEventNumber int64, limit int) ([]*Event, error) {
 	query := `
 		SELECT event_number, event_id, event_type, aggregate_id, aggregate_type, 
@@ -249,18 +265,13 @@ func (es *PostgresEventStore) SaveSnapshot(snapshot *Snapshot) error {
 // GetProjectionCheckpoint retrieves the checkpoint for a projection
 func (es *PostgresEventStore) GetProjectionCheckpoint(projectionName string) (*ProjectionCheckpoint, error) {
 	query := `
-		SELECT projection_name, last_processed_event_number, last_processed_at, status
+		SELECT projection_name, last_processed_event_number, last_processed_at, status,
+			   events_processed, throughput, last_error, last_error_at
 		FROM projection_checkpoints
 		WHERE projection_name = $1
 	`
 
-	checkpoint := &ProjectionCheckpoint{}
-	err := es.db.QueryRow(query, projectionName).Scan(
-		&checkpoint.ProjectionName,
-		&checkpoint.LastProcessedEventNumber,
-		&checkpoint.LastProcessedAt,
-		&checkpoint.Status,
-	)
+	checkpoint, err := scanProjectionCheckpoint(es.db.QueryRow(query, projectionName))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// Return default checkpoint if not found
@@ -268,7 +279,7 @@ func (es *PostgresEventStore) GetProjectionCheckpoint(projectionName string) (*P
 				ProjectionName:            projectionName,
 				LastProcessedEventNumber:  0,
 				LastProcessedAt:           time.Now(),
-				Status:                    "active",
+				Status:                    ProjectionStatusActive,
 			}, nil
 		}
 		return nil, fmt.Errorf("failed to get projection checkpoint: %w", err)
@@ -277,23 +288,93 @@ func (es *PostgresEventStore) GetProjectionCheckpoint(projectionName string) (*P
 	return checkpoint, nil
 }
 
+// ListProjectionCheckpoints retrieves the checkpoints of every projection that has run
+func (es *PostgresEventStore) ListProjectionCheckpoints() ([]*ProjectionCheckpoint, error) {
+	query := `
+		SELECT projection_name, last_processed_event_number, last_processed_at, status,
+			   events_processed, throughput, last_error, last_error_at
+		FROM projection_checkpoints
+		ORDER BY projection_name ASC
+	`
+
+	rows, err := es.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projection checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	var checkpoints []*ProjectionCheckpoint
+	for rows.Next() {
+		checkpoint, err := scanProjectionCheckpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan projection checkpoint: %w", err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, rows.Err()
+}
+
+// scanProjectionCheckpoint scans a checkpoint row
+func scanProjectionCheckpoint(row interface{ Scan(dest ...interface{}) error }) (*ProjectionCheckpoint, error) {
+	checkpoint := &ProjectionCheckpoint{}
+	var lastError sql.NullString
+	var lastErrorAt sql.NullTime
+
+	err := row.Scan(
+		&checkpoint.ProjectionName,
+		&checkpoint.LastProcessedEventNumber,
+		&checkpoint.LastProcessedAt,
+		&checkpoint.Status,
+		&checkpoint.EventsProcessed,
+		&checkpoint.Throughput,
+		&lastError,
+		&lastErrorAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint.LastError = lastError.String
+	if lastErrorAt.Valid {
+		checkpoint.LastErrorAt = &lastErrorAt.Time
+	}
+
+	return checkpoint, nil
+}
+
 // SaveProjectionCheckpoint saves a projection checkpoint
 func (es *PostgresEventStore) SaveProjectionCheckpoint(checkpoint *ProjectionCheckpoint) error {
 	query := `
-		INSERT INTO projection_checkpoints (projection_name, last_processed_event_number, last_processed_at, status)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO projection_checkpoints (
+			projection_name, last_processed_event_number, last_processed_at, status,
+			events_processed, throughput, last_error, last_error_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT (projection_name)
 		DO UPDATE SET
 			last_processed_event_number = EXCLUDED.last_processed_event_number,
 			last_processed_at = EXCLUDED.last_processed_at,
-			status = EXCLUDED.status
+			status = EXCLUDED.status,
+			events_processed = EXCLUDED.events_processed,
+			throughput = EXCLUDED.throughput,
+			last_error = EXCLUDED.last_error,
+			last_error_at = EXCLUDED.last_error_at
 	`
 
+	var lastError *string
+	if checkpoint.LastError != "" {
+		lastError = &checkpoint.LastError
+	}
+
 	_, err := es.db.Exec(query,
 		checkpoint.ProjectionName,
 		checkpoint.LastProcessedEventNumber,
 		checkpoint.LastProcessedAt,
 		checkpoint.Status,
+		checkpoint.EventsProcessed,
+		checkpoint.Throughput,
+		lastError,
+		checkpoint.LastErrorAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save projection checkpoint: %w", err)
@@ -302,6 +383,16 @@ func (es *PostgresEventStore) SaveProjectionCheckpoint(checkpoint *ProjectionChe
 	return nil
 }
 
+// GetHeadEventNumber returns the event_number of the newest stored event
+func (es *PostgresEventStore) GetHeadEventNumber() (int64, error) {
+	var head int64
+	err := es.db.QueryRow(`SELECT COALESCE(MAX(event_number), 0) FROM events`).Scan(&head)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get head event number: %w", err)
+	}
+	return head, nil
+}
+
 // CreateEventFromDomain creates an Event from a DomainEvent
 func (es *PostgresEventStore) CreateEventFromDomain(domainEvent DomainEvent, userID, correlationID string, causationID *string) (*Event, error) {
 	eventData, err := domainEvent.GetEventData()
@@ -112,7 +112,34 @@ type ProjectionCheckpoint struct {
 	ProjectionName            string    `json:"projection_name" db:"projection_name"`
 	LastProcessedEventNumber  int64     `json:"last_processed_event_number" db:"last_processed_event_number"`
 	LastProcessedAt           time.Time `json:"last_processed_at" db:"last_processed_at"`
-	Status                    string    `json:"status" db:"status"` // active, rebuilding, failed
+	Status                    string    `json:"status" db:"status"` // active, rebuilding, paused, failed
+
+	// Operational statistics maintained by the projection runner
+	EventsProcessed int64      `json:"events_processed" db:"events_processed"`
+	Throughput      float64    `json:"throughput" db:"throughput"` // events per second over the last batch
+	LastError       string     `json:"last_error" db:"last_error"`
+	LastErrorAt     *time.Time `json:"last_error_at" db:"last_error_at"`
+}
+
+// Projection checkpoint statuses
+const (
+	ProjectionStatusActive     = "active"
+	ProjectionStatusRebuilding = "rebuilding"
+	ProjectionStatusPaused     = "paused"
+	ProjectionStatusFailed     = "failed"
+)
+
+// CheckpointStore reads and writes projection checkpoints
+type CheckpointStore interface {
+	GetProjectionCheckpoint(projectionName string) (*ProjectionCheckpoint, error)
+	SaveProjectionCheckpoint(checkpoint *ProjectionCheckpoint) error
+}
+
+// ProjectionStatusStore exposes what operators need to judge projection health
+type ProjectionStatusStore interface {
+	CheckpointStore
+	ListProjectionCheckpoints() ([]*ProjectionCheckpoint, error)
+	GetHeadEventNumber() (int64, error)
 }
 
 // Aggregate interface that all aggregates must implement
@@ -132,6 +159,11 @@ type AggregateRoot struct {
 	Type              string
 	Version           int
 	UncommittedEvents []DomainEvent
+
+	// LastEventNumber is the global event_number of the newest stored event
+	// for this aggregate, when known. It is the read-your-writes position for
+	// queries that follow a command.
+	LastEventNumber int64
 }
 
 // NewAggregateRoot creates a new aggregate root
@@ -177,4 +209,16 @@ func (a *AggregateRoot) MarkEventsAsCommitted() {
 // IncrementVersion increments the aggregate version
 func (a *AggregateRoot) IncrementVersion() {
 	a.Version++
-}
\ No newline at end of file
+}
+
+// GetLastEventNumber returns the global position of the aggregate's newest stored event
+func (a *AggregateRoot) GetLastEventNumber() int64 {
+	return a.LastEventNumber
+}
+
+// SetLastEventNumber records the global position of the aggregate's newest stored event
+func (a *AggregateRoot) SetLastEventNumber(eventNumber int64) {
+	if eventNumber > a.LastEventNumber {
+		a.LastEventNumber = eventNumber
+	}
+}
@@ -0,0 +1,398 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimal places a Decimal holds. Six places cover
+// per-share prices of low priced securities as well as currency amounts.
+const Scale = 6
+
+// scaleFactor is 10^Scale
+const scaleFactor int64 = 1_000_000
+
+// RoundingMode selects how results that need more than Scale places, or an
+// explicit Round, are rounded
+type RoundingMode int
+
+const (
+	RoundHalfEven RoundingMode = iota // Ties to the even neighbour (banker's rounding)
+	RoundHalfUp                       // Ties away from zero
+	RoundDown                         // Toward zero (truncate)
+	RoundUp                           // Away from zero
+)
+
+// Decimal is a signed fixed-point decimal number with Scale decimal places.
+// Its zero value is 0. Decimals are comparable with == and serialize to JSON
+// as strings so no precision is lost in events or API responses.
+type Decimal struct {
+	units int64 // value * 10^Scale
+}
+
+// Zero is the zero decimal
+var Zero = Decimal{}
+
+// ErrOverflow is returned when a result does not fit in a Decimal, i.e. its
+// magnitude is above about 9.2 trillion
+var ErrOverflow = errors.New("decimal overflow")
+
+// NewDecimalFromInt creates a decimal from a whole number. Numbers past the
+// range saturate to the largest or smallest Decimal, so arithmetic on them
+// reports ErrOverflow instead of working on a wrapped value.
+func NewDecimalFromInt(value int64) Decimal {
+	switch {
+	case value > math.MaxInt64/scaleFactor:
+		return Decimal{units: math.MaxInt64}
+	case value < math.MinInt64/scaleFactor:
+		return Decimal{units: math.MinInt64}
+	}
+	return Decimal{units: value * scaleFactor}
+}
+
+// NewDecimalFromFloat converts a float to the nearest decimal. The float's
+// shortest decimal representation is used, so 0.1 becomes exactly 0.1.
+func NewDecimalFromFloat(value float64) Decimal {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return Zero
+	}
+	d, _ := ParseDecimal(strconv.FormatFloat(value, 'g', -1, 64))
+	return d
+}
+
+// ParseDecimal parses a decimal string such as "12.50", "-3" or "1e-3".
+// Digits beyond Scale places are rounded half to even.
+func ParseDecimal(value string) (Decimal, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.ContainsAny(value, "/") {
+		return Zero, fmt.Errorf("invalid decimal %q", value)
+	}
+
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return Zero, fmt.Errorf("invalid decimal %q", value)
+	}
+
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt64(scaleFactor))
+	units, err := roundQuotient(scaled.Num(), scaled.Denom(), RoundHalfEven)
+	if err != nil {
+		return Zero, fmt.Errorf("invalid decimal %q: %w", value, err)
+	}
+	return Decimal{units: units}, nil
+}
+
+// MustParseDecimal parses a decimal string and panics if it is invalid. It is
+// intended for constants and tests.
+func MustParseDecimal(value string) Decimal {
+	d, err := ParseDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) (Decimal, error) {
+	units := d.units + other.units
+	if (units > d.units) != (other.units > 0) {
+		return Zero, ErrOverflow
+	}
+	return Decimal{units: units}, nil
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) (Decimal, error) {
+	units := d.units - other.units
+	if (units < d.units) != (other.units > 0) {
+		return Zero, ErrOverflow
+	}
+	return Decimal{units: units}, nil
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{units: -d.units}
+}
+
+// Abs returns the absolute value of d
+func (d Decimal) Abs() Decimal {
+	if d.units < 0 {
+		return d.Neg()
+	}
+	return d
+}
+
+// MulInt returns d * n. Multiplying a price by a share count is exact.
+func (d Decimal) MulInt(n int64) (Decimal, error) {
+	product := new(big.Int).Mul(big.NewInt(d.units), big.NewInt(n))
+	if !product.IsInt64() {
+		return Zero, ErrOverflow
+	}
+	return Decimal{units: product.Int64()}, nil
+}
+
+// Mul returns d * other rounded to Scale places
+func (d Decimal) Mul(other Decimal, mode RoundingMode) (Decimal, error) {
+	num := new(big.Int).Mul(big.NewInt(d.units), big.NewInt(other.units))
+	units, err := roundQuotient(num, big.NewInt(scaleFactor), mode)
+	if err != nil {
+		return Zero, err
+	}
+	return Decimal{units: units}, nil
+}
+
+// Div returns d / other rounded to Scale places
+func (d Decimal) Div(other Decimal, mode RoundingMode) (Decimal, error) {
+	if other.units == 0 {
+		return Zero, fmt.Errorf("division by zero")
+	}
+	num := new(big.Int).Mul(big.NewInt(d.units), big.NewInt(scaleFactor))
+	units, err := roundQuotient(num, big.NewInt(other.units), mode)
+	if err != nil {
+		return Zero, err
+	}
+	return Decimal{units: units}, nil
+}
+
+// DivInt returns d / n rounded to Scale places
+func (d Decimal) DivInt(n int64, mode RoundingMode) (Decimal, error) {
+	if n == 0 {
+		return Zero, fmt.Errorf("division by zero")
+	}
+	units, err := roundQuotient(big.NewInt(d.units), big.NewInt(n), mode)
+	if err != nil {
+		return Zero, err
+	}
+	return Decimal{units: units}, nil
+}
+
+// Round rounds d to the given number of decimal places
+func (d Decimal) Round(places int, mode RoundingMode) Decimal {
+	if places >= Scale {
+		return d
+	}
+	if places < 0 {
+		places = 0
+	}
+	step := int64(math.Pow10(Scale - places))
+	units, _ := roundQuotient(big.NewInt(d.units), big.NewInt(step), mode)
+	return Decimal{units: units * step}
+}
+
+// Cmp compares d and other and returns -1, 0 or +1
+func (d Decimal) Cmp(other Decimal) int {
+	switch {
+	case d.units < other.units:
+		return -1
+	case d.units > other.units:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Equal reports whether d == other
+func (d Decimal) Equal(other Decimal) bool {
+	return d.units == other.units
+}
+
+// LessThan reports whether d < other
+func (d Decimal) LessThan(other Decimal) bool {
+	return d.units < other.units
+}
+
+// GreaterThan reports whether d > other
+func (d Decimal) GreaterThan(other Decimal) bool {
+	return d.units > other.units
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.Cmp(Zero)
+}
+
+// IsZero reports whether d is zero
+func (d Decimal) IsZero() bool {
+	return d.units == 0
+}
+
+// IsPositive reports whether d > 0
+func (d Decimal) IsPositive() bool {
+	return d.units > 0
+}
+
+// IsNegative reports whether d < 0
+func (d Decimal) IsNegative() bool {
+	return d.units < 0
+}
+
+// Float64 returns the nearest float to d. It is meant for ratios and
+// statistics, never for amounts that are stored or compared.
+func (d Decimal) Float64() float64 {
+	return float64(d.units) / float64(scaleFactor)
+}
+
+// String returns d without trailing zeros, e.g. "12.5" or "100"
+func (d Decimal) String() string {
+	s := d.StringFixed(Scale)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimSuffix(s, ".")
+	}
+	return s
+}
+
+// StringFixed returns d rounded half up to the given number of places,
+// padded with zeros, e.g. StringFixed(2) gives "12.50"
+func (d Decimal) StringFixed(places int) string {
+	if places > Scale {
+		places = Scale
+	}
+	if places < 0 {
+		places = 0
+	}
+	rounded := d.Round(places, RoundHalfUp)
+
+	units := rounded.units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+	}
+	abs := new(big.Int).Abs(big.NewInt(units)).String()
+	if len(abs) <= Scale {
+		abs = strings.Repeat("0", Scale-len(abs)+1) + abs
+	}
+
+	whole, fraction := abs[:len(abs)-Scale], abs[len(abs)-Scale:]
+	if places == 0 {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction[:places]
+}
+
+// Format implements fmt.Formatter so templates can keep using printf "%.2f"
+func (d Decimal) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'f', 'F':
+		places, ok := f.Precision()
+		if !ok {
+			places = Scale
+		}
+		fmt.Fprint(f, d.StringFixed(places))
+	case 'q':
+		fmt.Fprint(f, strconv.Quote(d.String()))
+	default:
+		fmt.Fprint(f, d.String())
+	}
+}
+
+// MarshalJSON encodes d as a JSON string
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a JSON string. JSON numbers are accepted too so that
+// events written when amounts were floats still load; the number's literal
+// digits are parsed, so no float rounding is introduced.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		*d = Zero
+		return nil
+	}
+
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return fmt.Errorf("invalid decimal: %w", err)
+		}
+	}
+
+	parsed, err := ParseDecimal(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value implements driver.Valuer so decimals are stored in NUMERIC columns exactly
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner for NUMERIC columns
+func (d *Decimal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Zero
+		return nil
+	case []byte:
+		parsed, err := ParseDecimal(string(v))
+		if err != nil {
+			return err
+		}
+		*d = parsed
+	case string:
+		parsed, err := ParseDecimal(v)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+	case int64:
+		*d = NewDecimalFromInt(v)
+	case float64:
+		*d = NewDecimalFromFloat(v)
+	default:
+		return fmt.Errorf("cannot scan %T into decimal", src)
+	}
+	return nil
+}
+
+// roundQuotient divides num by den and rounds the quotient to an integer
+func roundQuotient(num, den *big.Int, mode RoundingMode) (int64, error) {
+	if den.Sign() < 0 {
+		num = new(big.Int).Neg(num)
+		den = new(big.Int).Neg(den)
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	if remainder.Sign() != 0 {
+		negative := num.Sign() < 0
+
+		// Compare twice the remainder with the divisor to find ties
+		twice := new(big.Int).Abs(remainder)
+		twice.Lsh(twice, 1)
+		half := twice.Cmp(den)
+
+		roundAway := false
+		switch mode {
+		case RoundUp:
+			roundAway = true
+		case RoundDown:
+			roundAway = false
+		case RoundHalfUp:
+			roundAway = half >= 0
+		default:
+			roundAway = half > 0 || (half == 0 && quotient.Bit(0) == 1)
+		}
+
+		if roundAway {
+			if negative {
+				quotient.Sub(quotient, big.NewInt(1))
+			} else {
+				quotient.Add(quotient, big.NewInt(1))
+			}
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return quotient.Int64(), nil
+}
@@ -0,0 +1,112 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultCurrency is used for amounts recorded before currencies were tracked
+const DefaultCurrency = "USD"
+
+// ErrCurrencyMismatch is returned when amounts in different currencies are combined
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// minorUnits lists currencies whose smallest unit is not a hundredth
+var minorUnits = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+	"BHD": 3,
+	"KWD": 3,
+}
+
+// Money is a decimal amount in a currency
+type Money struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// New creates an amount of money. An empty currency means DefaultCurrency.
+func New(amount Decimal, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ZeroIn returns a zero amount in the currency
+func ZeroIn(currency string) Money {
+	return New(Zero, currency)
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	amount, err := m.Amount.Add(other.Amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return New(amount, m.Currency), nil
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	amount, err := m.Amount.Sub(other.Amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return New(amount, m.Currency), nil
+}
+
+// Cmp compares two amounts in the same currency and returns -1, 0 or +1
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	return m.Amount.Cmp(other.Amount), nil
+}
+
+// Equal reports whether both the amount and the currency match
+func (m Money) Equal(other Money) bool {
+	return m.Currency == other.Currency && m.Amount.Equal(other.Amount)
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m.Amount.IsPositive()
+}
+
+// RoundToMinorUnit rounds the amount to the currency's smallest unit, e.g.
+// cents for USD
+func (m Money) RoundToMinorUnit(mode RoundingMode) Money {
+	return New(m.Amount.Round(MinorUnits(m.Currency), mode), m.Currency)
+}
+
+// String returns the amount in minor units followed by the currency, e.g. "5000.00 USD"
+func (m Money) String() string {
+	return m.Amount.StringFixed(MinorUnits(m.Currency)) + " " + m.Currency
+}
+
+// MinorUnits returns the number of decimal places of a currency's smallest unit
+func MinorUnits(currency string) int {
+	if places, exists := minorUnits[currency]; exists {
+		return places
+	}
+	return 2
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package money_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/testutil"
+)
+
+func TestParseDecimal_ExactArithmetic(t *testing.T) {
+	a := money.MustParseDecimal("0.1")
+	b := money.MustParseDecimal("0.2")
+
+	sum, err := a.Add(b)
+	testutil.AssertNoError(t, err, "Addition should succeed")
+	testutil.AssertEqual(t, money.MustParseDecimal("0.3"), sum, "Decimal addition should be exact")
+	product, err := money.MustParseDecimal("50").MulInt(100)
+	testutil.AssertNoError(t, err, "Multiplication should succeed")
+	testutil.AssertEqual(t, "5000", product.String(), "Price times shares should be exact")
+	testutil.AssertEqual(t, "12.5", money.MustParseDecimal("12.500000").String(), "Trailing zeros should be trimmed")
+	testutil.AssertEqual(t, "-0.000001", money.MustParseDecimal("-1e-6").String(), "Exponents should be accepted")
+
+	_, err = money.ParseDecimal("1/3")
+	testutil.AssertError(t, err, "Fractions should be rejected")
+	_, err = money.ParseDecimal("abc")
+	testutil.AssertError(t, err, "Non-numbers should be rejected")
+}
+
+func TestDecimal_ArithmeticReportsOverflow(t *testing.T) {
+	large := money.MustParseDecimal("9000000000000")
+
+	_, err := large.Add(large)
+	testutil.AssertTrue(t, errors.Is(err, money.ErrOverflow), "Addition past the range should overflow")
+	_, err = large.Neg().Sub(large)
+	testutil.AssertTrue(t, errors.Is(err, money.ErrOverflow), "Subtraction past the range should overflow")
+	_, err = money.MustParseDecimal("1000").MulInt(10_000_000_000)
+	testutil.AssertTrue(t, errors.Is(err, money.ErrOverflow), "A notional past the range should overflow")
+	_, err = large.Mul(money.NewDecimalFromInt(2), money.RoundHalfEven)
+	testutil.AssertTrue(t, errors.Is(err, money.ErrOverflow), "Multiplication past the range should overflow")
+
+	difference, err := large.Sub(large.Neg())
+	testutil.AssertError(t, err, "Subtracting a negative past the range should overflow")
+	testutil.AssertEqual(t, money.Zero, difference, "Overflowed results should be zero")
+	total, err := large.Add(large.Neg())
+	testutil.AssertNoError(t, err, "Results in range should not overflow")
+	testutil.AssertTrue(t, total.IsZero(), "Opposite amounts should cancel")
+}
+
+func TestNewDecimalFromInt_SaturatesPastTheRange(t *testing.T) {
+	shares := int64(10_000_000_000_000)
+	positive := money.NewDecimalFromInt(shares)
+	negative := money.NewDecimalFromInt(-shares)
+
+	testutil.AssertTrue(t, positive.IsPositive(), "Large whole numbers should not wrap negative")
+	testutil.AssertTrue(t, negative.IsNegative(), "Large negative whole numbers should not wrap positive")
+	_, err := positive.Add(money.NewDecimalFromInt(1))
+	testutil.AssertTrue(t, errors.Is(err, money.ErrOverflow), "Arithmetic on a saturated decimal should overflow")
+	testutil.AssertEqual(t, "9000000000000", money.NewDecimalFromInt(9_000_000_000_000).String(), "Whole numbers in range should convert exactly")
+}
+
+func TestDecimal_RoundingModes(t *testing.T) {
+	cases := []struct {
+		value    string
+		mode     money.RoundingMode
+		expected string
+	}{
+		{"2.345", money.RoundHalfEven, "2.34"},
+		{"2.355", money.RoundHalfEven, "2.36"},
+		{"2.345", money.RoundHalfUp, "2.35"},
+		{"-2.345", money.RoundHalfUp, "-2.35"},
+		{"2.349", money.RoundDown, "2.34"},
+		{"-2.349", money.RoundDown, "-2.34"},
+		{"2.341", money.RoundUp, "2.35"},
+	}
+
+	for _, c := range cases {
+		rounded := money.MustParseDecimal(c.value).Round(2, c.mode)
+		testutil.AssertEqual(t, c.expected, rounded.StringFixed(2), fmt.Sprintf("Rounding %s", c.value))
+	}
+
+	third, err := money.NewDecimalFromInt(1).DivInt(3, money.RoundHalfEven)
+	testutil.AssertNoError(t, err, "Division should succeed")
+	testutil.AssertEqual(t, "0.333333", third.String(), "Division should round to six places")
+
+	_, err = money.NewDecimalFromInt(1).DivInt(0, money.RoundHalfEven)
+	testutil.AssertError(t, err, "Division by zero should fail")
+}
+
+func TestDecimal_JSONRoundTripAndLegacyNumbers(t *testing.T) {
+	type payload struct {
+		Price money.Decimal  `json:"price"`
+		Limit *money.Decimal `json:"limit,omitempty"`
+	}
+
+	data, err := json.Marshal(payload{Price: money.MustParseDecimal("50.25")})
+	testutil.AssertNoError(t, err, "Marshal should succeed")
+	testutil.AssertEqual(t, `{"price":"50.25"}`, string(data), "Decimals should serialize as strings")
+
+	var decoded payload
+	testutil.AssertNoError(t, json.Unmarshal(data, &decoded), "String decimals should decode")
+	testutil.AssertEqual(t, money.MustParseDecimal("50.25"), decoded.Price, "Round trip should preserve the value")
+
+	var legacy payload
+	testutil.AssertNoError(t, json.Unmarshal([]byte(`{"price":0.1,"limit":1e2}`), &legacy), "Float events should still decode")
+	testutil.AssertEqual(t, money.MustParseDecimal("0.1"), legacy.Price, "Legacy numbers should be read from their digits")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(100), *legacy.Limit, "Legacy exponents should decode")
+
+	testutil.AssertError(t, json.Unmarshal([]byte(`{"price":"ten"}`), &legacy), "Invalid strings should be rejected")
+}
+
+func TestDecimal_FormatSupportsPrecision(t *testing.T) {
+	price := money.MustParseDecimal("1234.5")
+
+	testutil.AssertEqual(t, "1234.50", fmt.Sprintf("%.2f", price), "Templates rely on %.2f")
+	testutil.AssertEqual(t, "1234.5", fmt.Sprintf("%v", price), "Default formatting should use String")
+}
+
+func TestMoney_CurrencyMismatchAndMinorUnits(t *testing.T) {
+	usd := money.New(money.MustParseDecimal("10.005"), "USD")
+	eur := money.New(money.NewDecimalFromInt(5), "EUR")
+
+	_, err := usd.Add(eur)
+	testutil.AssertTrue(t, errors.Is(err, money.ErrCurrencyMismatch), "Adding different currencies should fail")
+	_, err = usd.Cmp(eur)
+	testutil.AssertTrue(t, errors.Is(err, money.ErrCurrencyMismatch), "Comparing different currencies should fail")
+
+	total, err := usd.Add(money.New(money.NewDecimalFromInt(5), ""))
+	testutil.AssertNoError(t, err, "An empty currency should default to USD")
+	testutil.AssertEqual(t, "15.01 USD", total.String(), "Money should format in minor units")
+
+	testutil.AssertEqual(t, "10.00", usd.RoundToMinorUnit(money.RoundHalfEven).Amount.StringFixed(2), "Half-even should round ties to even cents")
+	testutil.AssertEqual(t, "1235 JPY", money.New(money.MustParseDecimal("1234.5"), "JPY").String(), "Yen has no minor unit")
+}
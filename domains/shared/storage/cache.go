@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -72,6 +73,8 @@ type Cache interface {
 	Del(ctx context.Context, keys ...string) error
 	Exists(ctx context.Context, keys ...string) (int64, error)
 	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	ExpireIfEqual(ctx context.Context, key, value string, expiration time.Duration) (bool, error)
+	DelIfEqual(ctx context.Context, key, value string) (bool, error)
 }
 
 // RedisCache implements Cache interface using Redis
@@ -109,8 +112,46 @@ func (c *RedisCache) SetNX(ctx context.Context, key string, value interface{}, e
 	return c.client.SetNX(ctx, key, value, expiration).Result()
 }
 
+// expireIfEqualScript extends a key's expiration only while it holds the
+// expected value, so a lease is never extended after another holder took it
+var expireIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// delIfEqualScript deletes a key only while it holds the expected value
+var delIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// ExpireIfEqual resets the expiration of a key that still holds value. It
+// reports whether the key was extended.
+func (c *RedisCache) ExpireIfEqual(ctx context.Context, key, value string, expiration time.Duration) (bool, error) {
+	extended, err := expireIfEqualScript.Run(ctx, c.client, []string{key}, value, expiration.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+	return extended == 1, nil
+}
+
+// DelIfEqual deletes a key that still holds value. It reports whether the key
+// was deleted.
+func (c *RedisCache) DelIfEqual(ctx context.Context, key, value string) (bool, error) {
+	deleted, err := delIfEqualScript.Run(ctx, c.client, []string{key}, value).Int64()
+	if err != nil {
+		return false, err
+	}
+	return deleted == 1, nil
+}
+
 // InMemoryCache provides a simple in-memory cache for testing
 type InMemoryCache struct {
+	mu   sync.Mutex
 	data map[string]cacheItem
 }
 
@@ -128,6 +169,14 @@ func NewInMemoryCache() *InMemoryCache {
 
 // Set stores a value in the cache
 func (c *InMemoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value, expiration)
+	return nil
+}
+
+func (c *InMemoryCache) set(key string, value interface{}, expiration time.Duration) {
 	var exp time.Time
 	if expiration > 0 {
 		exp = time.Now().Add(expiration)
@@ -137,11 +186,13 @@ func (c *InMemoryCache) Set(ctx context.Context, key string, value interface{},
 		value:      fmt.Sprintf("%v", value),
 		expiration: exp,
 	}
-	return nil
 }
 
 // Get retrieves a value from the cache
 func (c *InMemoryCache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	item, exists := c.data[key]
 	if !exists {
 		return "", redis.Nil
@@ -158,6 +209,9 @@ func (c *InMemoryCache) Get(ctx context.Context, key string) (string, error) {
 
 // Del deletes keys from the cache
 func (c *InMemoryCache) Del(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	for _, key := range keys {
 		delete(c.data, key)
 	}
@@ -166,6 +220,9 @@ func (c *InMemoryCache) Del(ctx context.Context, keys ...string) error {
 
 // Exists checks if keys exist in the cache
 func (c *InMemoryCache) Exists(ctx context.Context, keys ...string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	count := int64(0)
 	for _, key := range keys {
 		if item, exists := c.data[key]; exists {
@@ -182,9 +239,42 @@ func (c *InMemoryCache) Exists(ctx context.Context, keys ...string) (int64, erro
 
 // SetNX sets a value only if the key doesn't exist
 func (c *InMemoryCache) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
-	if _, exists := c.data[key]; exists {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Expired keys count as missing, as they do in Redis
+	if item, exists := c.data[key]; exists && (item.expiration.IsZero() || time.Now().Before(item.expiration)) {
+		return false, nil
+	}
+
+	c.set(key, value, expiration)
+	return true, nil
+}
+
+// ExpireIfEqual resets the expiration of a key that still holds value
+func (c *InMemoryCache) ExpireIfEqual(ctx context.Context, key, value string, expiration time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, exists := c.data[key]
+	if !exists || item.value != value || (!item.expiration.IsZero() && !time.Now().Before(item.expiration)) {
 		return false, nil
 	}
 
-	return true, c.Set(ctx, key, value, expiration)
-}
\ No newline at end of file
+	c.set(key, value, expiration)
+	return true, nil
+}
+
+// DelIfEqual deletes a key that still holds value
+func (c *InMemoryCache) DelIfEqual(ctx context.Context, key, value string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, exists := c.data[key]
+	if !exists || item.value != value || (!item.expiration.IsZero() && !time.Now().Before(item.expiration)) {
+		return false, nil
+	}
+
+	delete(c.data, key)
+	return true, nil
+}
@@ -1,6 +1,7 @@
 package testutil
 
 import (
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,22 +11,26 @@ import (
 
 // TestEventStore provides a simple in-memory event store for testing
 type TestEventStore struct {
-	events []events.Event
+	events      []events.Event
+	checkpoints map[string]*events.ProjectionCheckpoint
+	mu          sync.Mutex
 }
 
 func NewTestEventStore() *TestEventStore {
 	return &TestEventStore{
-		events: make([]events.Event, 0),
+		events:      make([]events.Event, 0),
+		checkpoints: make(map[string]*events.ProjectionCheckpoint),
 	}
 }
 
 func (s *TestEventStore) SaveEvent(evt *events.Event) error {
-	s.events = append(s.events, *evt)
-	return nil
+	return s.SaveEvents([]*events.Event{evt})
 }
 
 func (s *TestEventStore) SaveEvents(evts []*events.Event) error {
 	for _, evt := range evts {
+		// Event numbers are 1-based positions, like the events table sequence
+		evt.EventNumber = int64(len(s.events) + 1)
 		s.events = append(s.events, *evt)
 	}
 	return nil
@@ -95,8 +100,8 @@ func (s *TestEventStore) CreateEventFromDomain(domainEvent events.DomainEvent, u
 func (s *TestEventStore) GetAllEvents(fromEventNumber int64, limit int) ([]*events.Event, error) {
 	var result []*events.Event
 	count := 0
-	for i, evt := range s.events {
-		if int64(i+1) >= fromEventNumber {
+	for _, evt := range s.events {
+		if evt.EventNumber > fromEventNumber {
 			result = append(result, &evt)
 			count++
 			if limit > 0 && count >= limit {
@@ -107,6 +112,42 @@ func (s *TestEventStore) GetAllEvents(fromEventNumber int64, limit int) ([]*even
 	return result, nil
 }
 
+func (s *TestEventStore) GetProjectionCheckpoint(projectionName string) (*events.ProjectionCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if checkpoint, exists := s.checkpoints[projectionName]; exists {
+		copied := *checkpoint
+		return &copied, nil
+	}
+	return &events.ProjectionCheckpoint{
+		ProjectionName: projectionName,
+		Status:         "active",
+	}, nil
+}
+
+func (s *TestEventStore) SaveProjectionCheckpoint(checkpoint *events.ProjectionCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *checkpoint
+	s.checkpoints[checkpoint.ProjectionName] = &copied
+	return nil
+}
+
+func (s *TestEventStore) ListProjectionCheckpoints() ([]*events.ProjectionCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []*events.ProjectionCheckpoint
+	for _, checkpoint := range s.checkpoints {
+		copied := *checkpoint
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+func (s *TestEventStore) GetHeadEventNumber() (int64, error) {
+	return int64(len(s.events)), nil
+}
+
 func (s *TestEventStore) GetSnapshot(aggregateID string) (*events.Snapshot, error) {
 	// No snapshots in test store
 	return nil, nil
@@ -5,13 +5,14 @@ import (
 	"time"
 
 	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
 )
 
 // MockMarketDataProvider provides mock market data for testing
 type MockMarketDataProvider struct {
-	LastTradePrices map[string]float64
+	LastTradePrices map[string]money.Decimal
 	Volatilities    map[string]float64
-	ReferencePrices map[string]float64
+	ReferencePrices map[string]money.Decimal
 	IsMarketOpen    bool
 	MarketOpenTime  time.Time
 	MarketCloseTime time.Time
@@ -19,20 +20,20 @@ type MockMarketDataProvider struct {
 
 func NewMockMarketDataProvider() *MockMarketDataProvider {
 	return &MockMarketDataProvider{
-		LastTradePrices: make(map[string]float64),
+		LastTradePrices: make(map[string]money.Decimal),
 		Volatilities:    make(map[string]float64),
-		ReferencePrices: make(map[string]float64),
+		ReferencePrices: make(map[string]money.Decimal),
 		IsMarketOpen:    true,
 		MarketOpenTime:  time.Now().Add(-4 * time.Hour),
 		MarketCloseTime: time.Now().Add(4 * time.Hour),
 	}
 }
 
-func (m *MockMarketDataProvider) GetLastTradePrice(securityID string) (float64, error) {
+func (m *MockMarketDataProvider) GetLastTradePrice(securityID string) (money.Decimal, error) {
 	if price, ok := m.LastTradePrices[securityID]; ok {
 		return price, nil
 	}
-	return 100.0, nil // Default price
+	return money.NewDecimalFromInt(100), nil // Default price
 }
 
 func (m *MockMarketDataProvider) GetMarketHours() (open, close time.Time, isOpen bool) {
@@ -46,14 +47,14 @@ func (m *MockMarketDataProvider) GetVolatility(securityID string, period time.Du
 	return 0.15, nil // Default 15% volatility
 }
 
-func (m *MockMarketDataProvider) GetReferencePrice(securityID string) (float64, error) {
+func (m *MockMarketDataProvider) GetReferencePrice(securityID string) (money.Decimal, error) {
 	if price, ok := m.ReferencePrices[securityID]; ok {
 		return price, nil
 	}
-	return 100.0, nil // Default reference price
+	return money.NewDecimalFromInt(100), nil // Default reference price
 }
 
-func (m *MockMarketDataProvider) SetLastTradePrice(securityID string, price float64) {
+func (m *MockMarketDataProvider) SetLastTradePrice(securityID string, price money.Decimal) {
 	m.LastTradePrices[securityID] = price
 }
 
@@ -61,7 +62,7 @@ func (m *MockMarketDataProvider) SetVolatility(securityID string, volatility flo
 	m.Volatilities[securityID] = volatility
 }
 
-func (m *MockMarketDataProvider) SetReferencePrice(securityID string, price float64) {
+func (m *MockMarketDataProvider) SetReferencePrice(securityID string, price money.Decimal) {
 	m.ReferencePrices[securityID] = price
 }
 
@@ -74,8 +75,8 @@ type MatchResult struct {
 	SellerID     string
 	SecurityID   string
 	SharesTraded int64
-	TradePrice   float64
-	TotalAmount  float64
+	TradePrice   money.Decimal
+	TotalAmount  money.Money
 }
 
 // RiskAssessment represents trade risk evaluation
@@ -251,9 +252,9 @@ func (s *SpyEventBus) WasEventPublished(eventType string) bool {
 
 // StubMarketDataProvider provides predefined responses
 type StubMarketDataProvider struct {
-	LastTradePrice  float64
+	LastTradePrice  money.Decimal
 	Volatility      float64
-	ReferencePrice  float64
+	ReferencePrice  money.Decimal
 	IsOpen          bool
 	ShouldError     bool
 	ErrorMessage    string
@@ -261,17 +262,17 @@ type StubMarketDataProvider struct {
 
 func NewStubMarketDataProvider() *StubMarketDataProvider {
 	return &StubMarketDataProvider{
-		LastTradePrice: 100.0,
+		LastTradePrice: money.NewDecimalFromInt(100),
 		Volatility:     0.15,
-		ReferencePrice: 100.0,
+		ReferencePrice: money.NewDecimalFromInt(100),
 		IsOpen:         true,
 		ShouldError:    false,
 	}
 }
 
-func (s *StubMarketDataProvider) GetLastTradePrice(securityID string) (float64, error) {
+func (s *StubMarketDataProvider) GetLastTradePrice(securityID string) (money.Decimal, error) {
 	if s.ShouldError {
-		return 0, fmt.Errorf("%s", s.ErrorMessage)
+		return money.Zero, fmt.Errorf("%s", s.ErrorMessage)
 	}
 	return s.LastTradePrice, nil
 }
@@ -287,9 +288,9 @@ func (s *StubMarketDataProvider) GetVolatility(securityID string, period time.Du
 	return s.Volatility, nil
 }
 
-func (s *StubMarketDataProvider) GetReferencePrice(securityID string) (float64, error) {
+func (s *StubMarketDataProvider) GetReferencePrice(securityID string) (money.Decimal, error) {
 	if s.ShouldError {
-		return 0, fmt.Errorf("%s", s.ErrorMessage)
+		return money.Zero, fmt.Errorf("%s", s.ErrorMessage)
 	}
 	return s.ReferencePrice, nil
 }
\ No newline at end of file
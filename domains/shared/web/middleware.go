@@ -25,7 +25,8 @@ func CORSMiddleware(next http.Handler) http.Handler {
 		// Set CORS headers
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Consistency-Token")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Consistency-Token, X-Consistency-Stale")
 		
 		// Handle preflight requests
 		if r.Method == "OPTIONS" {
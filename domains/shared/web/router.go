@@ -2,10 +2,27 @@ package web
 
 import (
 	"database/sql"
+	"log"
 	"net/http"
 
 	"github.com/gorilla/mux"
 	"github.com/redis/go-redis/v9"
+
+	"securities-marketplace/domains/securities"
+	"securities-marketplace/domains/shared/audit"
+	"securities-marketplace/domains/shared/auth"
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/storage"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/calendar"
+	"securities-marketplace/domains/trading/execution"
+	"securities-marketplace/domains/trading/fees"
+	"securities-marketplace/domains/trading/listing"
+	"securities-marketplace/domains/trading/marketdata"
+	"securities-marketplace/domains/trading/risk"
+	"securities-marketplace/domains/users"
+	userhandlers "securities-marketplace/domains/users/handlers"
+	"securities-marketplace/domains/users/projections"
 )
 
 // NewRouter creates and configures the main application router
@@ -36,6 +53,18 @@ func NewRouter(db *sql.DB, redis *redis.Client) *mux.Router {
 
 // setupAPIRoutes configures API routes
 func setupAPIRoutes(router *mux.Router, db *sql.DB, redis *redis.Client) {
+	auditLog := audit.NewPostgresAuditLog(db)
+	eventStore := events.NewEventStore(db)
+	eventBus := events.NewEventBus(redis)
+	consistency := events.NewConsistencyWaiter(eventStore, events.DefaultConsistencyTimeout)
+
+	// The API settles trades and reports market hours on the same calendar
+	// as the worker
+	tradingCalendar, err := calendar.LoadFromEnv()
+	if err != nil {
+		log.Printf("Failed to load trading calendar, trading without one: %v", err)
+	}
+
 	// Authentication routes
 	authRouter := router.PathPrefix("/auth").Subrouter()
 	authRouter.HandleFunc("/login", LoginHandler(db)).Methods("POST")
@@ -45,13 +74,26 @@ func setupAPIRoutes(router *mux.Router, db *sql.DB, redis *redis.Client) {
 	// User routes
 	userRouter := router.PathPrefix("/users").Subrouter()
 	userRouter.Use(AuthenticationMiddleware)
+	userRouter.Use(audit.MutationMiddleware(auditLog, audit.CategoryAdmin, auth.AuditActorFromRequest))
+	userRouter.Use(consistency.Middleware(projections.UserProfileProjectionName, projections.ComplianceProjectionName))
 	userRouter.HandleFunc("", GetUsersHandler(db)).Methods("GET")
 	userRouter.HandleFunc("/{id}", GetUserHandler(db)).Methods("GET")
 	userRouter.HandleFunc("/{id}", UpdateUserHandler(db)).Methods("PUT")
 
+	// Profile and accreditation handlers register their own full paths
+	userService := users.NewUserService(users.NewEventSourcedUserRepository(eventStore), eventStore, eventBus)
+	userService.SetAuditLogger(auditLog)
+	accountRouter := router.NewRoute().Subrouter()
+	accountRouter.Use(AuthenticationMiddleware)
+	accountRouter.Use(audit.MutationMiddleware(auditLog, audit.CategoryCompliance, auth.AuditActorFromRequest))
+	accountRouter.Use(consistency.Middleware(projections.UserProfileProjectionName, projections.ComplianceProjectionName))
+	userhandlers.NewProfileHandler(userService, eventBus).RegisterRoutes(accountRouter)
+	userhandlers.NewAccreditationHandler(userService, eventBus).RegisterRoutes(accountRouter)
+
 	// Security routes
 	securityRouter := router.PathPrefix("/securities").Subrouter()
 	securityRouter.Use(AuthenticationMiddleware)
+	securityRouter.Use(audit.MutationMiddleware(auditLog, audit.CategoryAdmin, auth.AuditActorFromRequest))
 	securityRouter.HandleFunc("", GetSecuritiesHandler(db)).Methods("GET")
 	securityRouter.HandleFunc("/{id}", GetSecurityHandler(db)).Methods("GET")
 	securityRouter.HandleFunc("", CreateSecurityHandler(db)).Methods("POST")
@@ -59,31 +101,79 @@ func setupAPIRoutes(router *mux.Router, db *sql.DB, redis *redis.Client) {
 	// Trading routes
 	tradingRouter := router.PathPrefix("/trading").Subrouter()
 	tradingRouter.Use(AuthenticationMiddleware)
-	tradingRouter.HandleFunc("/listings", GetListingsHandler(db)).Methods("GET")
-	tradingRouter.HandleFunc("/listings", CreateListingHandler(db)).Methods("POST")
-	tradingRouter.HandleFunc("/bids", GetBidsHandler(db)).Methods("GET")
-	tradingRouter.HandleFunc("/bids", CreateBidHandler(db)).Methods("POST")
-	tradingRouter.HandleFunc("/trades", GetTradesHandler(db)).Methods("GET")
+	tradingRouter.Use(audit.MutationMiddleware(auditLog, audit.CategoryTrading, auth.AuditActorFromRequest))
+	// Queries served from read models wait for the client's consistency
+	// token; the negotiation, RFQ, book and session queries load aggregates
+	// from the event store and already see every write
+	tradingRouter.Handle("/listings", consistency.Middleware(listing.ListingsProjectionName)(GetListingsHandler(db))).Methods("GET")
+	tradingRouter.Handle("/bids", consistency.Middleware(bidding.BidsProjectionName)(GetBidsHandler(db))).Methods("GET")
+	tradingRouter.Handle("/trades", consistency.Middleware(execution.TradesProjectionName)(GetTradesHandler(db))).Methods("GET")
+	executionService := execution.NewExecutionService(execution.NewEventSourcedTradeRepository(eventStore), eventStore, eventBus)
+	executionService.SetAuditLogger(auditLog)
+	if tradingCalendar != nil {
+		executionService.SetTradingCalendar(tradingCalendar)
+	}
+	riskLimits := risk.NewPostgresLimitStore(db)
+	riskExposures := risk.NewTradeExposures(securities.NewEventSourcedSecurityRepository(eventStore), execution.NewEventSourcedTradeRepository(eventStore), tradingCalendar)
+	executionService.SetRiskEngine(risk.NewEngine(riskLimits, riskExposures, eventStore, eventBus))
+	feeSchedules := fees.NewPostgresScheduleStore(db)
+	executionService.SetFeeCalculator(fees.NewEngine(feeSchedules, fees.NewTradeHistory(eventStore)))
+	// Matching from the API checks the same price bands and reference
+	// prices as the worker's matching
+	marketDataProvider := marketdata.NewProvider(execution.NewEventSourcedTradeRepository(eventStore), marketdata.NewPostgresMarkStore(db), storage.NewRedisCache(redis), tradingCalendar)
+	executionService.SetMarketDataProvider(marketDataProvider)
+	executionService.SetPriceBands(execution.DefaultPriceBands())
+	// Order entry, negotiated and quoted trades go through the same leases
+	// as the worker's matching
+	executionService.SetMatchingSequencer(execution.NewMatchingSequencer(storage.NewRedisCache(redis), eventStore))
+	listingRepository := listing.NewEventSourcedListingRepository(eventStore)
+	listingService := listing.NewListingService(listingRepository, eventStore, eventBus)
+	listingService.SetEntryGate(executionService.SessionGate())
+	listingService.SetSequencer(executionService)
+	listing.NewHandler(listingService).RegisterRoutes(tradingRouter)
+	bidService := bidding.NewBidService(bidding.NewEventSourcedBidRepository(eventStore), listingRepository, eventStore, eventBus)
+	bidService.SetEntryGate(executionService.SessionGate())
+	bidService.SetSequencer(executionService)
+	bidding.NewHandler(bidService).RegisterRoutes(tradingRouter)
+	execution.NewNegotiationHandler(executionService).RegisterRoutes(tradingRouter)
+	execution.NewRFQHandler(executionService).RegisterRoutes(tradingRouter)
+	execution.NewDepthHandler(executionService).RegisterRoutes(tradingRouter)
+	sessionHandler := execution.NewSessionHandler(executionService)
+	sessionHandler.RegisterRoutes(tradingRouter)
 
 	// Market data routes
 	marketRouter := router.PathPrefix("/market").Subrouter()
 	marketRouter.HandleFunc("/data", GetMarketDataHandler(db, redis)).Methods("GET")
 	marketRouter.HandleFunc("/prices/{security_id}", GetPriceHistoryHandler(db)).Methods("GET")
+	marketDataHandler := marketdata.NewHandler(marketDataProvider)
+	marketDataHandler.RegisterRoutes(marketRouter)
 
 	// Admin routes
 	adminRouter := router.PathPrefix("/admin").Subrouter()
 	adminRouter.Use(AuthenticationMiddleware)
 	adminRouter.Use(AdminAuthorizationMiddleware)
+	adminRouter.Use(audit.Middleware(auditLog, audit.CategoryAdmin, auth.AuditActorFromRequest))
 	adminRouter.HandleFunc("/users", AdminGetUsersHandler(db)).Methods("GET")
 	adminRouter.HandleFunc("/securities", AdminGetSecuritiesHandler(db)).Methods("GET")
 	adminRouter.HandleFunc("/trades", AdminGetTradesHandler(db)).Methods("GET")
+	events.NewProjectionHandler(events.NewProjectionMonitor(eventStore, projections.ProjectionNames()...)).RegisterRoutes(adminRouter)
+	securityService := securities.NewSecurityService(securities.NewEventSourcedSecurityRepository(eventStore), eventStore, eventBus)
+	securityService.SetAuditLogger(auditLog)
+	securities.NewAuctionScheduleHandler(securityService).RegisterRoutes(adminRouter)
+	sessionHandler.RegisterAdminRoutes(adminRouter)
+	marketDataHandler.RegisterAdminRoutes(adminRouter)
+	execution.NewAdjustmentHandler(executionService).RegisterAdminRoutes(adminRouter)
+	fees.NewHandler(feeSchedules, execution.NewEventSourcedTradeRepository(eventStore)).RegisterAdminRoutes(adminRouter)
 
 	// Compliance routes
 	complianceRouter := router.PathPrefix("/compliance").Subrouter()
 	complianceRouter.Use(AuthenticationMiddleware)
 	complianceRouter.Use(ComplianceAuthorizationMiddleware)
+	complianceRouter.Use(audit.Middleware(auditLog, audit.CategoryCompliance, auth.AuditActorFromRequest))
 	complianceRouter.HandleFunc("/reports", GetComplianceReportsHandler(db)).Methods("GET")
 	complianceRouter.HandleFunc("/activities", GetSuspiciousActivitiesHandler(db)).Methods("GET")
+	audit.NewHandler(auditLog).RegisterRoutes(complianceRouter)
+	risk.NewHandler(riskLimits, risk.NewEventSourcedRiskProfileRepository(eventStore)).RegisterRoutes(complianceRouter)
 }
 
 // setupWebRoutes configures web routes for server-rendered HTML
@@ -151,9 +241,7 @@ func GetSecuritiesHandler(db *sql.DB) http.HandlerFunc               { return no
 func GetSecurityHandler(db *sql.DB) http.HandlerFunc                 { return notImplemented }
 func CreateSecurityHandler(db *sql.DB) http.HandlerFunc              { return notImplemented }
 func GetListingsHandler(db *sql.DB) http.HandlerFunc                 { return notImplemented }
-func CreateListingHandler(db *sql.DB) http.HandlerFunc               { return notImplemented }
 func GetBidsHandler(db *sql.DB) http.HandlerFunc                     { return notImplemented }
-func CreateBidHandler(db *sql.DB) http.HandlerFunc                   { return notImplemented }
 func GetTradesHandler(db *sql.DB) http.HandlerFunc                   { return notImplemented }
 func GetMarketDataHandler(db *sql.DB, redis *redis.Client) http.HandlerFunc { return notImplemented }
 func GetPriceHistoryHandler(db *sql.DB) http.HandlerFunc             { return notImplemented }
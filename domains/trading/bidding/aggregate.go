@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/orders"
 )
 
 // BidType represents the type of bid
@@ -34,7 +36,7 @@ const (
 type FillRecord struct {
 	TradeID      string    `json:"tradeId"`
 	SharesFilled int64     `json:"sharesFilled"`
-	FillPrice    float64   `json:"fillPrice"`
+	FillPrice    money.Decimal `json:"fillPrice"`
 	SellerID     string    `json:"sellerId"`
 	FilledAt     time.Time `json:"filledAt"`
 }
@@ -48,17 +50,24 @@ type BidAggregate struct {
 	BidderID        string    `json:"bidderId"`
 	SharesRequested int64     `json:"sharesRequested"`
 	SharesRemaining int64     `json:"sharesRemaining"`
-	BidPrice        float64   `json:"bidPrice"`
+	BidPrice        money.Decimal `json:"bidPrice"`
 	BidType         BidType   `json:"bidType"`
+	Conditions      orders.Conditions `json:"conditions"`
 	
 	// Status and lifecycle
 	Status    BidStatus `json:"status"`
 	PlacedAt  time.Time `json:"placedAt"`
 	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 	
+	// Stop orders are held off the book until the last trade price reaches
+	// the stop price
+	StopPrice      *money.Decimal `json:"stopPrice,omitempty"` // Stop-limit bids only; stop bids trigger at BidPrice
+	TriggeredAt    *time.Time     `json:"triggeredAt,omitempty"`
+	TriggeredPrice *money.Decimal `json:"triggeredPrice,omitempty"`
+	
 	// Fill tracking
 	SharesFilled    int64        `json:"sharesFilled"`
-	AverageFillPrice float64     `json:"averageFillPrice"`
+	AverageFillPrice money.Decimal `json:"averageFillPrice"`
 	FillRecords     []FillRecord `json:"fillRecords"`
 	
 	// Completion details
@@ -85,7 +94,14 @@ func NewBidAggregate(bidID string) *BidAggregate {
 }
 
 // PlaceBid places a new bid
-func (b *BidAggregate) PlaceBid(listingID, bidderID string, sharesRequested int64, bidPrice float64, bidType BidType, expiresAt *time.Time) error {
+func (b *BidAggregate) PlaceBid(listingID, bidderID string, sharesRequested int64, bidPrice money.Decimal, bidType BidType, expiresAt *time.Time) error {
+	return b.PlaceBidWithConditions(listingID, bidderID, sharesRequested, bidPrice, bidType, expiresAt, orders.Conditions{})
+}
+
+// PlaceBidWithConditions places a new bid that may only be filled as the
+// conditions allow and lives as long as their time in force. All-or-none bids
+// always carry the all-or-none condition.
+func (b *BidAggregate) PlaceBidWithConditions(listingID, bidderID string, sharesRequested int64, bidPrice money.Decimal, bidType BidType, expiresAt *time.Time, conditions orders.Conditions) error {
 	if b.Version > 0 {
 		return fmt.Errorf("bid already exists")
 	}
@@ -95,22 +111,91 @@ func (b *BidAggregate) PlaceBid(listingID, bidderID string, sharesRequested int6
 		return fmt.Errorf("shares requested must be greater than zero")
 	}
 
-	if bidPrice <= 0 {
+	if !bidPrice.IsPositive() {
 		return fmt.Errorf("bid price must be greater than zero")
 	}
 
+	if err := conditions.Validate(sharesRequested); err != nil {
+		return fmt.Errorf("invalid conditions: %w", err)
+	}
+
 	// Validate expiration
 	if expiresAt != nil && expiresAt.Before(time.Now()) {
 		return fmt.Errorf("expiration date cannot be in the past")
 	}
 
+	conditions, err := conditions.Resolve(expiresAt)
+	if err != nil {
+		return fmt.Errorf("invalid time in force: %w", err)
+	}
+
+	if bidType == BidTypeAllOrNone {
+		conditions.AllOrNone = true
+	}
+
 	event := NewBidPlaced(b.ID, listingID, bidderID, sharesRequested, bidPrice, string(bidType), expiresAt)
+	event.Conditions = conditions
+	b.AddEvent(event)
+	return b.ApplyEvent(event)
+}
+
+// PlaceStopBid places a stop bid that waits off the book until the last trade
+// price rises to the stop price. Without a limit price the bid is released as
+// a market order; with one it is released as a limit order at that price.
+func (b *BidAggregate) PlaceStopBid(listingID, bidderID string, sharesRequested int64, stopPrice money.Decimal, limitPrice *money.Decimal, expiresAt *time.Time) error {
+	if !stopPrice.IsPositive() {
+		return fmt.Errorf("stop price must be greater than zero")
+	}
+
+	if limitPrice == nil {
+		return b.PlaceBid(listingID, bidderID, sharesRequested, stopPrice, BidTypeStop, expiresAt)
+	}
+
+	if b.Version > 0 {
+		return fmt.Errorf("bid already exists")
+	}
+
+	if sharesRequested <= 0 {
+		return fmt.Errorf("shares requested must be greater than zero")
+	}
+
+	if !limitPrice.IsPositive() {
+		return fmt.Errorf("limit price must be greater than zero")
+	}
+
+	if expiresAt != nil && expiresAt.Before(time.Now()) {
+		return fmt.Errorf("expiration date cannot be in the past")
+	}
+
+	event := NewBidPlaced(b.ID, listingID, bidderID, sharesRequested, *limitPrice, string(BidTypeStopLimit), expiresAt)
+	event.StopPrice = &stopPrice
+	b.AddEvent(event)
+	return b.ApplyEvent(event)
+}
+
+// Trigger releases a stop bid to the order book once the last trade price
+// has reached its stop price
+func (b *BidAggregate) Trigger(lastTradePrice money.Decimal) error {
+	if !b.IsAwaitingTrigger() {
+		return fmt.Errorf("bid is not an untriggered stop bid")
+	}
+
+	if !b.ShouldTrigger(lastTradePrice) {
+		return fmt.Errorf("last trade price %s has not reached stop price %s", lastTradePrice, b.GetStopPrice())
+	}
+
+	releasedAs := BidTypeMarket
+	if b.BidType == BidTypeStopLimit {
+		releasedAs = BidTypeLimit
+	}
+
+	event := NewBidTriggered(b.ID, lastTradePrice, string(releasedAs))
 	b.AddEvent(event)
 	return b.ApplyEvent(event)
 }
 
 // ModifyBid modifies an existing bid
-func (b *BidAggregate) ModifyBid(newSharesRequested int64, newBidPrice float64, modifiedBy, reason string) error {
+func (b *BidAggregate) ModifyBid(newSharesRequested int64, newBidPrice money.Decimal, modifiedBy, reason string) error {
 	if b.Status != BidStatusActive && b.Status != BidStatusPartiallyFilled {
 		return fmt.Errorf("can only modify active or partially filled bids")
 	}
@@ -119,7 +204,7 @@ func (b *BidAggregate) ModifyBid(newSharesRequested int64, newBidPrice float64,
 		return fmt.Errorf("shares requested must be greater than zero")
 	}
 
-	if newBidPrice <= 0 {
+	if !newBidPrice.IsPositive() {
 		return fmt.Errorf("bid price must be greater than zero")
 	}
 
@@ -128,17 +213,25 @@ func (b *BidAggregate) ModifyBid(newSharesRequested int64, newBidPrice float64,
 		return fmt.Errorf("cannot reduce shares below filled amount (%d < %d)", newSharesRequested, b.SharesFilled)
 	}
 
+	if err := b.Conditions.Validate(newSharesRequested); err != nil {
+		return fmt.Errorf("invalid conditions: %w", err)
+	}
+
 	event := NewBidModified(b.ID, b.SharesRequested, newSharesRequested, b.BidPrice, newBidPrice, modifiedBy, reason)
 	b.AddEvent(event)
 	return b.ApplyEvent(event)
 }
 
 // PartiallyFill partially fills the bid
-func (b *BidAggregate) PartiallyFill(sharesFilled int64, fillPrice float64, tradeID, sellerID string) error {
+func (b *BidAggregate) PartiallyFill(sharesFilled int64, fillPrice money.Decimal, tradeID, sellerID string) error {
 	if b.Status != BidStatusActive && b.Status != BidStatusPartiallyFilled {
 		return fmt.Errorf("can only fill active or partially filled bids")
 	}
 
+	if b.IsAwaitingTrigger() {
+		return fmt.Errorf("cannot fill a stop bid before it is triggered")
+	}
+
 	if sharesFilled <= 0 {
 		return fmt.Errorf("shares filled must be greater than zero")
 	}
@@ -147,13 +240,17 @@ func (b *BidAggregate) PartiallyFill(sharesFilled int64, fillPrice float64, trad
 		return fmt.Errorf("cannot fill more shares than remaining (%d > %d)", sharesFilled, b.SharesRemaining)
 	}
 
-	if fillPrice <= 0 {
+	if !b.Conditions.AllowsFill(sharesFilled, b.SharesRemaining) {
+		return fmt.Errorf("fill of %d shares violates the bid's conditions", sharesFilled)
+	}
+
+	if !fillPrice.IsPositive() {
 		return fmt.Errorf("fill price must be greater than zero")
 	}
 
 	// For limit bids, check price constraint
-	if b.BidType == BidTypeLimit && fillPrice > b.BidPrice {
-		return fmt.Errorf("fill price %.2f exceeds bid limit of %.2f", fillPrice, b.BidPrice)
+	if (b.BidType == BidTypeLimit || b.BidType == BidTypeStopLimit) && fillPrice.GreaterThan(b.BidPrice) {
+		return fmt.Errorf("fill price %s exceeds bid limit of %s", fillPrice, b.BidPrice)
 	}
 
 	newSharesRemaining := b.SharesRemaining - sharesFilled
@@ -175,6 +272,37 @@ func (b *BidAggregate) PartiallyFill(sharesFilled int64, fillPrice float64, trad
 	return b.ApplyEvent(event)
 }
 
+// AdjustFill records that a trade which filled the bid was busted or
+// corrected. Shares taken off a fill go back to the bid's remaining shares,
+// and a bid that is still live bids for them again.
+func (b *BidAggregate) AdjustFill(tradeID string, sharesFilled int64, fillPrice money.Decimal, reason string) error {
+	record := b.fillRecord(tradeID)
+	if record == nil {
+		return fmt.Errorf("trade %s did not fill bid %s", tradeID, b.ID)
+	}
+
+	if sharesFilled < 0 || sharesFilled > record.SharesFilled {
+		return fmt.Errorf("adjusted fill of %d shares must be between 0 and %d", sharesFilled, record.SharesFilled)
+	}
+
+	if sharesFilled > 0 {
+		if !fillPrice.IsPositive() {
+			return fmt.Errorf("fill price must be greater than zero")
+		}
+		if (b.BidType == BidTypeLimit || b.BidType == BidTypeStopLimit) && fillPrice.GreaterThan(b.BidPrice) {
+			return fmt.Errorf("fill price %s exceeds bid limit of %s", fillPrice, b.BidPrice)
+		}
+	}
+
+	if reason == "" {
+		return fmt.Errorf("adjustment reason is required")
+	}
+
+	event := NewBidFillAdjusted(b.ID, tradeID, record.SharesFilled, sharesFilled, fillPrice, reason)
+	b.AddEvent(event)
+	return b.ApplyEvent(event)
+}
+
 // Withdraw withdraws the bid
 func (b *BidAggregate) Withdraw(reason, withdrawnBy string) error {
 	if b.Status != BidStatusActive && b.Status != BidStatusPartiallyFilled {
@@ -209,6 +337,30 @@ func (b *BidAggregate) Expire() error {
 	return b.ApplyEvent(event)
 }
 
+// ExpireAtClose expires a day bid once the market session it was placed in
+// has closed
+func (b *BidAggregate) ExpireAtClose(marketClose time.Time) error {
+	if b.Status != BidStatusActive && b.Status != BidStatusPartiallyFilled {
+		return fmt.Errorf("can only expire active or partially filled bids")
+	}
+
+	if b.Conditions.TimeInForce != orders.TimeInForceDAY {
+		return fmt.Errorf("only day bids expire at the market close")
+	}
+
+	if !b.PlacedAt.Before(marketClose) {
+		return fmt.Errorf("bid was placed after the market close")
+	}
+
+	if time.Now().Before(marketClose) {
+		return fmt.Errorf("market has not yet closed")
+	}
+
+	event := NewBidExpired(b.ID, marketClose)
+	b.AddEvent(event)
+	return b.ApplyEvent(event)
+}
+
 // Reject rejects the bid
 func (b *BidAggregate) Reject(reason, rejectedBy string) error {
 	if b.Status != BidStatusActive {
@@ -241,6 +393,10 @@ func (b *BidAggregate) ApplyEvent(event events.DomainEvent) error {
 		return b.applyBidExpired(e)
 	case *BidRejected:
 		return b.applyBidRejected(e)
+	case *BidTriggered:
+		return b.applyBidTriggered(e)
+	case *BidFillAdjusted:
+		return b.applyBidFillAdjusted(e)
 	default:
 		return fmt.Errorf("unknown event type: %T", event)
 	}
@@ -266,9 +422,17 @@ func (b *BidAggregate) applyBidPlaced(event *BidPlaced) error {
 	b.SharesRemaining = event.SharesRequested
 	b.BidPrice = event.BidPrice
 	b.BidType = BidType(event.BidType)
+	b.Conditions = event.Conditions
+	if b.BidType == BidTypeAllOrNone {
+		b.Conditions.AllOrNone = true
+	}
+	if b.Conditions.TimeInForce == "" {
+		b.Conditions.TimeInForce = orders.DefaultTimeInForce(event.ExpiresAt)
+	}
 	b.Status = BidStatusActive
 	b.PlacedAt = event.Timestamp
 	b.ExpiresAt = event.ExpiresAt
+	b.StopPrice = event.StopPrice
 	
 	b.IncrementVersion()
 	return nil
@@ -287,11 +451,6 @@ func (b *BidAggregate) applyBidPartiallyFilled(event *BidPartiallyFilled) error
 	b.SharesRemaining = event.SharesRemaining
 	b.SharesFilled += event.SharesFilled
 	
-	// Update average fill price
-	totalValue := b.AverageFillPrice * float64(b.SharesFilled-event.SharesFilled)
-	totalValue += event.FillPrice * float64(event.SharesFilled)
-	b.AverageFillPrice = totalValue / float64(b.SharesFilled)
-	
 	// Add fill record
 	fillRecord := FillRecord{
 		TradeID:      event.TradeID,
@@ -301,6 +460,9 @@ func (b *BidAggregate) applyBidPartiallyFilled(event *BidPartiallyFilled) error
 		FilledAt:     event.Timestamp,
 	}
 	b.FillRecords = append(b.FillRecords, fillRecord)
+	if err := b.updateAverageFillPrice(); err != nil {
+		return err
+	}
 	
 	b.Status = BidStatusPartiallyFilled
 	
@@ -344,6 +506,84 @@ func (b *BidAggregate) applyBidRejected(event *BidRejected) error {
 	return nil
 }
 
+func (b *BidAggregate) applyBidTriggered(event *BidTriggered) error {
+	triggerPrice := event.TriggerPrice
+	b.TriggeredAt = &event.Timestamp
+	b.TriggeredPrice = &triggerPrice
+	
+	b.IncrementVersion()
+	return nil
+}
+
+func (b *BidAggregate) applyBidFillAdjusted(event *BidFillAdjusted) error {
+	returned := event.PreviousShares - event.SharesFilled
+	b.SharesFilled -= returned
+	b.SharesRemaining += returned
+	if returned > 0 && (b.Status == BidStatusFilled || b.Status == BidStatusPartiallyFilled) {
+		b.FilledAt = nil
+		b.Status = BidStatusPartiallyFilled
+		if b.SharesFilled == 0 {
+			b.Status = BidStatusActive
+		}
+	}
+	
+	records := b.FillRecords[:0]
+	for _, record := range b.FillRecords {
+		if record.TradeID == event.TradeID {
+			if event.SharesFilled == 0 {
+				continue
+			}
+			record.SharesFilled = event.SharesFilled
+			record.FillPrice = event.FillPrice
+		}
+		records = append(records, record)
+	}
+	b.FillRecords = records
+	if err := b.updateAverageFillPrice(); err != nil {
+		return err
+	}
+	
+	b.IncrementVersion()
+	return nil
+}
+
+// updateAverageFillPrice averages over every fill so rounding does not
+// accumulate
+func (b *BidAggregate) updateAverageFillPrice() error {
+	if b.SharesFilled == 0 {
+		b.AverageFillPrice = money.Zero
+		return nil
+	}
+
+	totalValue := money.Zero
+	for _, record := range b.FillRecords {
+		value, err := record.FillPrice.MulInt(record.SharesFilled)
+		if err != nil {
+			return fmt.Errorf("failed to calculate average fill price: %w", err)
+		}
+		if totalValue, err = totalValue.Add(value); err != nil {
+			return fmt.Errorf("failed to calculate average fill price: %w", err)
+		}
+	}
+	averageFillPrice, err := totalValue.DivInt(b.SharesFilled, money.RoundHalfEven)
+	if err != nil {
+		return fmt.Errorf("failed to calculate average fill price: %w", err)
+	}
+	b.AverageFillPrice = averageFillPrice
+	return nil
+}
+
+// fillRecord returns the fill made by the trade, or nil if it did not fill
+// the bid
+func (b *BidAggregate) fillRecord(tradeID string) *FillRecord {
+	for i := range b.FillRecords {
+		if b.FillRecords[i].TradeID == tradeID {
+			return &b.FillRecords[i]
+		}
+	}
+	return nil
+}
+
 // Helper methods
 
 // IsActive returns true if the bid is active and can be filled
@@ -356,6 +596,10 @@ func (b *BidAggregate) IsActive() bool {
 		return false
 	}
 	
+	if b.IsAwaitingTrigger() {
+		return false
+	}
+	
 	if b.ExpiresAt != nil && time.Now().After(*b.ExpiresAt) {
 		return false
 	}
@@ -363,6 +607,31 @@ func (b *BidAggregate) IsActive() bool {
 	return true
 }
 
+// IsStop returns true for stop and stop-limit bids
+func (b *BidAggregate) IsStop() bool {
+	return b.BidType == BidTypeStop || b.BidType == BidTypeStopLimit
+}
+
+// IsAwaitingTrigger returns true if the bid is a stop bid that is still held
+// off the book
+func (b *BidAggregate) IsAwaitingTrigger() bool {
+	return b.IsStop() && b.TriggeredAt == nil
+}
+
+// GetStopPrice returns the last trade price at which a stop bid triggers
+func (b *BidAggregate) GetStopPrice() money.Decimal {
+	if b.StopPrice != nil {
+		return *b.StopPrice
+	}
+	return b.BidPrice
+}
+
+// ShouldTrigger returns true if the last trade price has reached the stop
+// price. Bids are buy orders, so they trigger at or above it.
+func (b *BidAggregate) ShouldTrigger(lastTradePrice money.Decimal) bool {
+	return b.IsAwaitingTrigger() && !lastTradePrice.LessThan(b.GetStopPrice())
+}
+
 // IsExpired returns true if the bid has expired
 func (b *BidAggregate) IsExpired() bool {
 	return b.ExpiresAt != nil && time.Now().After(*b.ExpiresAt)
@@ -391,7 +660,7 @@ func (b *BidAggregate) GetTimeRemaining() *time.Duration {
 }
 
 // CanBeFilled returns true if the bid can accept fills
-func (b *BidAggregate) CanBeFilled(fillPrice float64) bool {
+func (b *BidAggregate) CanBeFilled(fillPrice money.Decimal) bool {
 	if !b.IsActive() {
 		return false
 	}
@@ -399,11 +668,11 @@ func (b *BidAggregate) CanBeFilled(fillPrice float64) bool {
 	// Check price constraints based on bid type
 	switch b.BidType {
 	case BidTypeLimit:
-		return fillPrice <= b.BidPrice
-	case BidTypeMarket:
-		return true // Market bids accept any price
+		return !fillPrice.GreaterThan(b.BidPrice)
+	case BidTypeMarket, BidTypeStop:
+		return true // Market bids and triggered stop bids accept any price
 	default:
-		return fillPrice <= b.BidPrice
+		return !fillPrice.GreaterThan(b.BidPrice)
 	}
 }
 
@@ -416,11 +685,11 @@ func (b *BidAggregate) GetMaxFillShares() int64 {
 }
 
 // GetTotalValue returns the total value of the bid at the bid price
-func (b *BidAggregate) GetTotalValue() float64 {
-	return float64(b.SharesRequested) * b.BidPrice
+func (b *BidAggregate) GetTotalValue() (money.Decimal, error) {
+	return b.BidPrice.MulInt(b.SharesRequested)
 }
 
 // GetRemainingValue returns the remaining value of unfilled shares
-func (b *BidAggregate) GetRemainingValue() float64 {
-	return float64(b.SharesRemaining) * b.BidPrice
+func (b *BidAggregate) GetRemainingValue() (money.Decimal, error) {
+	return b.BidPrice.MulInt(b.SharesRemaining)
 }
\ No newline at end of file
@@ -0,0 +1,77 @@
+package bidding
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/orders"
+)
+
+// Bidding Domain Commands
+
+// PlaceBidCommand represents a command to bid on a listing. A bid with a
+// StopPrice is a stop bid: BidPrice is then its optional limit.
+type PlaceBidCommand struct {
+	BidID           string            `json:"bidId"`
+	ListingID       string            `json:"listingId"`
+	BidderID        string            `json:"bidderId"`
+	SharesRequested int64             `json:"sharesRequested"`
+	BidPrice        *money.Decimal    `json:"bidPrice,omitempty"`
+	StopPrice       *money.Decimal    `json:"stopPrice,omitempty"`
+	BidType         BidType           `json:"bidType"`
+	ExpiresAt       *time.Time        `json:"expiresAt,omitempty"`
+	Conditions      orders.Conditions `json:"conditions"`
+}
+
+// Validate validates the place bid command
+func (cmd *PlaceBidCommand) Validate() error {
+	if cmd.ListingID == "" {
+		return fmt.Errorf("listing ID is required")
+	}
+	if cmd.BidderID == "" {
+		return fmt.Errorf("bidder ID is required")
+	}
+	if cmd.StopPrice == nil && cmd.BidPrice == nil {
+		return fmt.Errorf("bid price is required")
+	}
+	return nil
+}
+
+// ModifyBidCommand represents a command to change a bid's size or price
+type ModifyBidCommand struct {
+	BidID              string        `json:"bidId"`
+	NewSharesRequested int64         `json:"newSharesRequested"`
+	NewBidPrice        money.Decimal `json:"newBidPrice"`
+	ModifiedBy         string        `json:"modifiedBy"`
+	Reason             string        `json:"reason"`
+}
+
+// Validate validates the modify bid command
+func (cmd *ModifyBidCommand) Validate() error {
+	if cmd.BidID == "" {
+		return fmt.Errorf("bid ID is required")
+	}
+	if cmd.ModifiedBy == "" {
+		return fmt.Errorf("modified by is required")
+	}
+	return nil
+}
+
+// WithdrawBidCommand represents a command to withdraw a bid
+type WithdrawBidCommand struct {
+	BidID       string `json:"bidId"`
+	Reason      string `json:"reason"`
+	WithdrawnBy string `json:"withdrawnBy"`
+}
+
+// Validate validates the withdraw bid command
+func (cmd *WithdrawBidCommand) Validate() error {
+	if cmd.BidID == "" {
+		return fmt.Errorf("bid ID is required")
+	}
+	if cmd.WithdrawnBy == "" {
+		return fmt.Errorf("withdrawn by is required")
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/orders"
 )
 
 // Bidding Domain Events
@@ -15,13 +17,15 @@ type BidPlaced struct {
 	ListingID       string  `json:"listingId"`
 	BidderID        string  `json:"bidderId"`
 	SharesRequested int64   `json:"sharesRequested"`
-	BidPrice        float64 `json:"bidPrice"`
+	BidPrice        money.Decimal `json:"bidPrice"`
 	BidType         string  `json:"bidType"`
+	StopPrice       *money.Decimal `json:"stopPrice,omitempty"` // Stop-limit bids only; stop bids trigger at BidPrice
+	Conditions      orders.Conditions `json:"conditions"`
 	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
 }
 
 // NewBidPlaced creates a new BidPlaced event
-func NewBidPlaced(bidID, listingID, bidderID string, sharesRequested int64, bidPrice float64, bidType string, expiresAt *time.Time) *BidPlaced {
+func NewBidPlaced(bidID, listingID, bidderID string, sharesRequested int64, bidPrice money.Decimal, bidType string, expiresAt *time.Time) *BidPlaced {
 	return &BidPlaced{
 		BaseEvent:       events.NewBaseEvent(bidID, "Bid"),
 		ListingID:       listingID,
@@ -57,13 +61,13 @@ type BidModified struct {
 	events.BaseEvent
 	OldSharesRequested int64   `json:"oldSharesRequested"`
 	NewSharesRequested int64   `json:"newSharesRequested"`
-	OldBidPrice        float64 `json:"oldBidPrice"`
-	NewBidPrice        float64 `json:"newBidPrice"`
+	OldBidPrice        money.Decimal `json:"oldBidPrice"`
+	NewBidPrice        money.Decimal `json:"newBidPrice"`
 	ModifiedBy         string  `json:"modifiedBy"`
 	Reason             string  `json:"reason"`
 }
 
-func NewBidModified(bidID string, oldSharesRequested, newSharesRequested int64, oldBidPrice, newBidPrice float64, modifiedBy, reason string) *BidModified {
+func NewBidModified(bidID string, oldSharesRequested, newSharesRequested int64, oldBidPrice, newBidPrice money.Decimal, modifiedBy, reason string) *BidModified {
 	return &BidModified{
 		BaseEvent:          events.NewBaseEvent(bidID, "Bid"),
 		OldSharesRequested: oldSharesRequested,
@@ -99,12 +103,12 @@ type BidPartiallyFilled struct {
 	events.BaseEvent
 	SharesFilled     int64   `json:"sharesFilled"`
 	SharesRemaining  int64   `json:"sharesRemaining"`
-	FillPrice        float64 `json:"fillPrice"`
+	FillPrice        money.Decimal `json:"fillPrice"`
 	TradeID          string  `json:"tradeId"`
 	SellerID         string  `json:"sellerId"`
 }
 
-func NewBidPartiallyFilled(bidID string, sharesFilled, sharesRemaining int64, fillPrice float64, tradeID, sellerID string) *BidPartiallyFilled {
+func NewBidPartiallyFilled(bidID string, sharesFilled, sharesRemaining int64, fillPrice money.Decimal, tradeID, sellerID string) *BidPartiallyFilled {
 	return &BidPartiallyFilled{
 		BaseEvent:       events.NewBaseEvent(bidID, "Bid"),
 		SharesFilled:    sharesFilled,
@@ -138,13 +142,13 @@ func (e *BidPartiallyFilled) GetMetadata() events.Metadata {
 type BidFilled struct {
 	events.BaseEvent
 	TotalSharesFilled int64     `json:"totalSharesFilled"`
-	FinalFillPrice    float64   `json:"finalFillPrice"`
+	FinalFillPrice    money.Decimal   `json:"finalFillPrice"`
 	FilledAt          time.Time `json:"filledAt"`
 	TradeID           string    `json:"tradeId"`
 	SellerID          string    `json:"sellerId"`
 }
 
-func NewBidFilled(bidID string, totalSharesFilled int64, finalFillPrice float64, filledAt time.Time, tradeID, sellerID string) *BidFilled {
+func NewBidFilled(bidID string, totalSharesFilled int64, finalFillPrice money.Decimal, filledAt time.Time, tradeID, sellerID string) *BidFilled {
 	return &BidFilled{
 		BaseEvent:         events.NewBaseEvent(bidID, "Bid"),
 		TotalSharesFilled: totalSharesFilled,
@@ -272,4 +276,84 @@ func (e *BidRejected) GetMetadata() events.Metadata {
 		EventVersion:  e.Version,
 		Timestamp:     e.Timestamp,
 	}
-}
\ No newline at end of file
+}
+
+// BidTriggered event is emitted when the last trade price reaches a stop
+// bid's stop price and the bid is released to the order book
+type BidTriggered struct {
+	events.BaseEvent
+	TriggerPrice money.Decimal `json:"triggerPrice"` // Last trade price that triggered the bid
+	ReleasedAs   string        `json:"releasedAs"`   // Market for stop bids, limit for stop-limit bids
+}
+
+func NewBidTriggered(bidID string, triggerPrice money.Decimal, releasedAs string) *BidTriggered {
+	return &BidTriggered{
+		BaseEvent:    events.NewBaseEvent(bidID, "Bid"),
+		TriggerPrice: triggerPrice,
+		ReleasedAs:   releasedAs,
+	}
+}
+
+func (e *BidTriggered) GetEventType() string     { return "BidTriggered" }
+func (e *BidTriggered) GetAggregateID() string   { return e.AggregateID }
+func (e *BidTriggered) GetAggregateType() string { return e.AggregateType }
+
+func (e *BidTriggered) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *BidTriggered) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// BidFillAdjusted event is emitted when a trade that filled the bid is busted
+// or corrected. A busted fill has no shares left.
+type BidFillAdjusted struct {
+	events.BaseEvent
+	TradeID        string        `json:"tradeId"`
+	PreviousShares int64         `json:"previousShares"`
+	SharesFilled   int64         `json:"sharesFilled"`
+	FillPrice      money.Decimal `json:"fillPrice"`
+	Reason         string        `json:"reason"`
+}
+
+func NewBidFillAdjusted(bidID, tradeID string, previousShares, sharesFilled int64, fillPrice money.Decimal, reason string) *BidFillAdjusted {
+	return &BidFillAdjusted{
+		BaseEvent:      events.NewBaseEvent(bidID, "Bid"),
+		TradeID:        tradeID,
+		PreviousShares: previousShares,
+		SharesFilled:   sharesFilled,
+		FillPrice:      fillPrice,
+		Reason:         reason,
+	}
+}
+
+func (e *BidFillAdjusted) GetEventType() string     { return "BidFillAdjusted" }
+func (e *BidFillAdjusted) GetAggregateID() string   { return e.AggregateID }
+func (e *BidFillAdjusted) GetAggregateType() string { return e.AggregateType }
+
+func (e *BidFillAdjusted) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *BidFillAdjusted) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// BidsProjectionName names the projection that maintains bids_projection.
+// Bid queries wait on it when they carry a consistency token.
+const BidsProjectionName = "bids_projection"
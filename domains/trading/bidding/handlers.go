@@ -0,0 +1,68 @@
+package bidding
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"securities-marketplace/domains/shared/auth"
+	"securities-marketplace/domains/shared/events"
+)
+
+// Handler exposes bid entry to buyers
+type Handler struct {
+	service *BidService
+	rbac    *auth.RBAC
+}
+
+// NewHandler creates a new bid handler
+func NewHandler(service *BidService) *Handler {
+	return &Handler{
+		service: service,
+		rbac:    auth.NewRBAC(),
+	}
+}
+
+// RegisterRoutes registers the handler routes. Callers are expected to mount
+// the router behind authentication.
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/bids", h.HandlePlace).Methods("POST")
+}
+
+// HandlePlace places a bid for the signed-in buyer. The response carries a
+// consistency token so the following read of /bids includes the new bid.
+func (h *Handler) HandlePlace(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromContext(r.Context())
+	if user == nil || user.UserID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	if !h.rbac.HasPermission(user.Roles, auth.PermissionTradeWrite) {
+		http.Error(w, "Insufficient permissions", http.StatusForbidden)
+		return
+	}
+
+	var cmd PlaceBidCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	cmd.BidderID = user.UserID
+
+	bid, err := h.service.PlaceBid(&cmd)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"bid":     bid,
+	}
+
+	events.SetConsistencyToken(w, bid.GetLastEventNumber())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
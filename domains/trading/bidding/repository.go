@@ -0,0 +1,88 @@
+package bidding
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"securities-marketplace/domains/shared/events"
+)
+
+// BidRepository defines the interface for bid persistence
+type BidRepository interface {
+	FindByID(bidID string) (*BidAggregate, error)
+}
+
+// EventSourcedBidRepository implements BidRepository using event sourcing
+type EventSourcedBidRepository struct {
+	eventStore events.EventStore
+}
+
+// NewEventSourcedBidRepository creates a new event-sourced bid repository
+func NewEventSourcedBidRepository(eventStore events.EventStore) *EventSourcedBidRepository {
+	return &EventSourcedBidRepository{
+		eventStore: eventStore,
+	}
+}
+
+// FindByID finds a bid by ID by replaying events
+func (r *EventSourcedBidRepository) FindByID(bidID string) (*BidAggregate, error) {
+	eventRecords, err := r.eventStore.GetEvents(bidID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	if len(eventRecords) == 0 {
+		return nil, fmt.Errorf("bid with id %s not found", bidID)
+	}
+
+	var domainEvents []events.DomainEvent
+	for _, eventRecord := range eventRecords {
+		domainEvent, err := r.convertEventRecordToDomainEvent(eventRecord)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert event %s: %w", eventRecord.EventType, err)
+		}
+		domainEvents = append(domainEvents, domainEvent)
+	}
+
+	bid := NewBidAggregate(bidID)
+	err = bid.LoadFromHistory(domainEvents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load from history: %w", err)
+	}
+
+	bid.SetLastEventNumber(eventRecords[len(eventRecords)-1].EventNumber)
+
+	return bid, nil
+}
+
+// convertEventRecordToDomainEvent converts a single event record to domain event
+func (r *EventSourcedBidRepository) convertEventRecordToDomainEvent(eventRecord *events.Event) (events.DomainEvent, error) {
+	var event events.DomainEvent
+	switch eventRecord.EventType {
+	case "BidPlaced":
+		event = &BidPlaced{}
+	case "BidModified":
+		event = &BidModified{}
+	case "BidPartiallyFilled":
+		event = &BidPartiallyFilled{}
+	case "BidFilled":
+		event = &BidFilled{}
+	case "BidWithdrawn":
+		event = &BidWithdrawn{}
+	case "BidExpired":
+		event = &BidExpired{}
+	case "BidRejected":
+		event = &BidRejected{}
+	case "BidTriggered":
+		event = &BidTriggered{}
+	case "BidFillAdjusted":
+		event = &BidFillAdjusted{}
+	default:
+		return nil, fmt.Errorf("unknown event type: %s", eventRecord.EventType)
+	}
+
+	if err := json.Unmarshal(eventRecord.EventData, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize %s: %w", eventRecord.EventType, err)
+	}
+	return event, nil
+}
@@ -0,0 +1,217 @@
+package bidding
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/trading/listing"
+	"securities-marketplace/domains/trading/orders"
+)
+
+// BidService provides application services for bids
+type BidService struct {
+	repository BidRepository
+	listings   listing.ListingRepository
+	eventStore events.EventStore
+	eventBus   events.EventBus
+	gate       orders.EntryGate
+	sequencer  orders.Sequencer
+}
+
+// NewBidService creates a new bid service
+func NewBidService(repository BidRepository, listings listing.ListingRepository, eventStore events.EventStore, eventBus events.EventBus) *BidService {
+	return &BidService{
+		repository: repository,
+		listings:   listings,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+	}
+}
+
+// SetEntryGate sets the check bids must pass before they are placed or
+// modified. Without one every bid is accepted.
+func (s *BidService) SetEntryGate(gate orders.EntryGate) {
+	s.gate = gate
+}
+
+// SetSequencer sets the sequencer bid changes run on, so they reach the
+// book in the same order as matching. Without one they run in place.
+func (s *BidService) SetSequencer(sequencer orders.Sequencer) {
+	s.sequencer = sequencer
+}
+
+// PlaceBid places a bid on a listing
+func (s *BidService) PlaceBid(cmd *PlaceBidCommand) (*BidAggregate, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid command: %w", err)
+	}
+
+	bidID := cmd.BidID
+	if bidID == "" {
+		bidID = uuid.New().String()
+	}
+
+	bid := NewBidAggregate(bidID)
+	err := s.sequence(cmd.ListingID, func(fence func() error) error {
+		if err := s.checkEntry(cmd.ListingID); err != nil {
+			return err
+		}
+
+		var err error
+		if cmd.StopPrice != nil {
+			err = bid.PlaceStopBid(cmd.ListingID, cmd.BidderID, cmd.SharesRequested, *cmd.StopPrice, cmd.BidPrice, cmd.ExpiresAt)
+		} else {
+			err = bid.PlaceBidWithConditions(cmd.ListingID, cmd.BidderID, cmd.SharesRequested, *cmd.BidPrice, cmd.BidType, cmd.ExpiresAt, cmd.Conditions)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to place bid: %w", err)
+		}
+
+		if err := s.saveAggregateEvents(bid, cmd.BidderID, fence); err != nil {
+			return fmt.Errorf("failed to save bid: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
+
+// ModifyBid changes a bid's size or price
+func (s *BidService) ModifyBid(cmd *ModifyBidCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return fmt.Errorf("invalid command: %w", err)
+	}
+
+	bid, err := s.repository.FindByID(cmd.BidID)
+	if err != nil {
+		return fmt.Errorf("failed to find bid: %w", err)
+	}
+
+	return s.sequence(bid.ListingID, func(fence func() error) error {
+		bid, err := s.repository.FindByID(cmd.BidID)
+		if err != nil {
+			return fmt.Errorf("failed to find bid: %w", err)
+		}
+		if err := s.checkEntry(bid.ListingID); err != nil {
+			return err
+		}
+
+		if err := bid.ModifyBid(cmd.NewSharesRequested, cmd.NewBidPrice, cmd.ModifiedBy, cmd.Reason); err != nil {
+			return fmt.Errorf("failed to modify bid: %w", err)
+		}
+		return s.saveAggregateEvents(bid, cmd.ModifiedBy, fence)
+	})
+}
+
+// WithdrawBid withdraws a bid. Withdrawal is allowed in every session
+// state.
+func (s *BidService) WithdrawBid(cmd *WithdrawBidCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return fmt.Errorf("invalid command: %w", err)
+	}
+
+	bid, err := s.repository.FindByID(cmd.BidID)
+	if err != nil {
+		return fmt.Errorf("failed to find bid: %w", err)
+	}
+
+	return s.sequence(bid.ListingID, func(fence func() error) error {
+		bid, err := s.repository.FindByID(cmd.BidID)
+		if err != nil {
+			return fmt.Errorf("failed to find bid: %w", err)
+		}
+
+		if err := bid.Withdraw(cmd.Reason, cmd.WithdrawnBy); err != nil {
+			return fmt.Errorf("failed to withdraw bid: %w", err)
+		}
+		return s.saveAggregateEvents(bid, cmd.WithdrawnBy, fence)
+	})
+}
+
+// GetBid retrieves a bid by ID
+func (s *BidService) GetBid(bidID string) (*BidAggregate, error) {
+	return s.repository.FindByID(bidID)
+}
+
+// sequence runs a command that changes bids on the listing's security on the
+// sequencer, or in place without one
+func (s *BidService) sequence(listingID string, command func(fence func() error) error) error {
+	if s.sequencer == nil {
+		return command(func() error { return nil })
+	}
+
+	listing, err := s.listings.FindByID(listingID)
+	if err != nil {
+		return fmt.Errorf("failed to find listing: %w", err)
+	}
+	return s.sequencer.SequenceOrders(listing.SecurityID, command)
+}
+
+// checkEntry checks the gate for the security of the listing bid on
+func (s *BidService) checkEntry(listingID string) error {
+	if s.gate == nil {
+		return nil
+	}
+
+	listing, err := s.listings.FindByID(listingID)
+	if err != nil {
+		return fmt.Errorf("failed to find listing: %w", err)
+	}
+	if err := s.gate.CheckOrderEntry(listing.SecurityID); err != nil {
+		return fmt.Errorf("order entry rejected: %w", err)
+	}
+	return nil
+}
+
+// saveAggregateEvents saves uncommitted events and publishes them. The fence
+// is checked right before saving.
+func (s *BidService) saveAggregateEvents(bid *BidAggregate, userID string, fence func() error) error {
+	uncommittedEvents := bid.GetUncommittedEvents()
+	if len(uncommittedEvents) == 0 {
+		return nil
+	}
+
+	var events []*events.Event
+	correlationID := uuid.New().String()
+
+	for i, domainEvent := range uncommittedEvents {
+		var causationID *string
+		if i > 0 {
+			prevEventID := events[i-1].EventID
+			causationID = &prevEventID
+		}
+
+		event, err := s.eventStore.CreateEventFromDomain(domainEvent, userID, correlationID, causationID)
+		if err != nil {
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+
+		event.AggregateVersion = bid.GetVersion() + i + 1
+		events = append(events, event)
+	}
+
+	if err := fence(); err != nil {
+		return err
+	}
+	if err := s.eventStore.SaveEvents(events); err != nil {
+		return fmt.Errorf("failed to save events: %w", err)
+	}
+
+	// Remember where this command landed in the global stream so callers can
+	// return it as a consistency token
+	bid.SetLastEventNumber(events[len(events)-1].EventNumber)
+
+	for _, domainEvent := range uncommittedEvents {
+		if err := s.eventBus.Publish(domainEvent); err != nil {
+			// Log error but don't fail the operation
+			fmt.Printf("Failed to publish event %s: %v\n", domainEvent.GetEventType(), err)
+		}
+	}
+
+	bid.MarkEventsAsCommitted()
+	return nil
+}
@@ -0,0 +1,177 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+)
+
+// dateLayout is how calendar dates are written in files and keyed in maps
+const dateLayout = "2006-01-02"
+
+// Calendar describes when a market trades and settles: the session hours
+// of a business day in the market's time zone, its weekend days, holidays
+// and early closes, and how many business days after the trade date trades
+// settle.
+type Calendar struct {
+	Name           string
+	Location       *time.Location
+	Open           time.Duration // Session open, as an offset from local midnight
+	Close          time.Duration // Session close, as an offset from local midnight
+	Weekend        map[time.Weekday]bool
+	Holidays       map[string]string        // Date to holiday name
+	EarlyCloses    map[string]time.Duration // Date to the session close that day
+	SettlementDays int                      // N in T+N
+}
+
+// Default returns a calendar that trades 09:30 to 16:00 UTC on weekdays,
+// with no holidays, and settles T+2
+func Default() *Calendar {
+	return &Calendar{
+		Name:           "default",
+		Location:       time.UTC,
+		Open:           9*time.Hour + 30*time.Minute,
+		Close:          16 * time.Hour,
+		Weekend:        map[time.Weekday]bool{time.Saturday: true, time.Sunday: true},
+		Holidays:       make(map[string]string),
+		EarlyCloses:    make(map[string]time.Duration),
+		SettlementDays: 2,
+	}
+}
+
+// Validate checks that the calendar has trading days and a usable session
+func (c *Calendar) Validate() error {
+	if c.Location == nil {
+		return fmt.Errorf("calendar has no time zone")
+	}
+	if c.Open < 0 || c.Close >= 24*time.Hour || c.Open >= c.Close {
+		return fmt.Errorf("session must open and close on the same day")
+	}
+	if len(c.Weekend) >= 7 {
+		return fmt.Errorf("calendar has no trading days")
+	}
+	for date, close := range c.EarlyCloses {
+		if close <= c.Open || close > c.Close {
+			return fmt.Errorf("early close on %s must be during the session", date)
+		}
+	}
+	if c.SettlementDays < 0 {
+		return fmt.Errorf("settlement days cannot be negative")
+	}
+	return nil
+}
+
+// IsBusinessDay returns true if the market trades on the local date of t
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	local := t.In(c.Location)
+	if c.Weekend[local.Weekday()] {
+		return false
+	}
+	_, holiday := c.Holidays[local.Format(dateLayout)]
+	return !holiday
+}
+
+// IsHoliday returns the name of the holiday on the local date of t, if any
+func (c *Calendar) IsHoliday(t time.Time) (string, bool) {
+	name, ok := c.Holidays[t.In(c.Location).Format(dateLayout)]
+	return name, ok
+}
+
+// Session returns the open and close of the session on the local date of t.
+// ok is false when the market does not trade that day.
+func (c *Calendar) Session(t time.Time) (open, close time.Time, ok bool) {
+	if !c.IsBusinessDay(t) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	day := c.midnight(t)
+	closesAt := c.Close
+	if early, ok := c.EarlyCloses[day.Format(dateLayout)]; ok {
+		closesAt = early
+	}
+	return day.Add(c.Open), day.Add(closesAt), true
+}
+
+// IsOpen returns true if the market is in session at t
+func (c *Calendar) IsOpen(t time.Time) bool {
+	open, close, ok := c.Session(t)
+	return ok && !t.Before(open) && t.Before(close)
+}
+
+// MarketHours returns the session in progress at now or, when the market is
+// closed, the last session before now
+func (c *Calendar) MarketHours(now time.Time) (open, close time.Time, isOpen bool) {
+	day := c.midnight(now)
+	for {
+		if open, close, ok := c.Session(day); ok && !now.Before(open) {
+			return open, close, now.Before(close)
+		}
+		day = c.previousDay(day)
+	}
+}
+
+// NextBusinessDay returns local midnight of the first business day after
+// the local date of t
+func (c *Calendar) NextBusinessDay(t time.Time) time.Time {
+	day := c.nextDay(c.midnight(t))
+	for !c.IsBusinessDay(day) {
+		day = c.nextDay(day)
+	}
+	return day
+}
+
+// TradeDate returns local midnight of the business day a trade made at t
+// belongs to. Trades after the close or on a day the market does not trade
+// count towards the next business day.
+func (c *Calendar) TradeDate(t time.Time) time.Time {
+	if _, close, ok := c.Session(t); ok && t.Before(close) {
+		return c.midnight(t)
+	}
+	return c.NextBusinessDay(t)
+}
+
+// SettlementDate returns the close of business on the day a trade made at t
+// settles, SettlementDays business days after its trade date
+func (c *Calendar) SettlementDate(t time.Time) time.Time {
+	day := c.TradeDate(t)
+	for i := 0; i < c.SettlementDays; i++ {
+		day = c.NextBusinessDay(day)
+	}
+	_, close, _ := c.Session(day)
+	return close
+}
+
+// SettlementDeadline returns the close of business on the settlement date,
+// rolled forward to the next business day if the date has since become a
+// holiday
+func (c *Calendar) SettlementDeadline(settlementDate time.Time) time.Time {
+	day := settlementDate
+	if !c.IsBusinessDay(day) {
+		day = c.NextBusinessDay(day)
+	}
+	_, close, _ := c.Session(day)
+	return close
+}
+
+// BusinessDaysBetween returns the number of business days after the local
+// date of from up to and including the local date of to
+func (c *Calendar) BusinessDaysBetween(from, to time.Time) int {
+	days := 0
+	end := c.midnight(to)
+	for day := c.NextBusinessDay(from); !day.After(end); day = c.NextBusinessDay(day) {
+		days++
+	}
+	return days
+}
+
+func (c *Calendar) midnight(t time.Time) time.Time {
+	local := t.In(c.Location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, c.Location)
+}
+
+func (c *Calendar) nextDay(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, c.Location)
+}
+
+func (c *Calendar) previousDay(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day()-1, 0, 0, 0, 0, c.Location)
+}
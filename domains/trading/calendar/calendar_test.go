@@ -0,0 +1,103 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/shared/testutil"
+)
+
+func testCalendar(t *testing.T) *Calendar {
+	cal, err := Parse([]byte(`{
+		"name": "test",
+		"timezone": "America/New_York",
+		"open": "09:30",
+		"close": "16:00",
+		"holidays": [{"date": "2026-12-25", "name": "Christmas Day"}],
+		"earlyCloses": [{"date": "2026-12-24", "close": "13:00"}]
+	}`))
+	testutil.AssertNoError(t, err, "Calendar should parse")
+	return cal
+}
+
+func newYork(t *testing.T, value string) time.Time {
+	location, _ := time.LoadLocation("America/New_York")
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", value, location)
+	testutil.AssertNoError(t, err, "Test time should parse")
+	return parsed
+}
+
+func TestCalendar_SettlesOnBusinessDays(t *testing.T) {
+	cal := testCalendar(t)
+
+	tests := []struct {
+		name      string
+		tradedAt  string
+		settlesOn string
+	}{
+		{"midweek", "2026-12-15 10:00", "2026-12-17 16:00"},
+		{"over the weekend", "2026-12-18 10:00", "2026-12-22 16:00"},
+		{"after the close", "2026-12-18 17:00", "2026-12-23 16:00"},
+		{"on a weekend", "2026-12-19 12:00", "2026-12-23 16:00"},
+		{"onto an early close", "2026-12-22 10:00", "2026-12-24 13:00"},
+		{"over a holiday", "2026-12-23 10:00", "2026-12-28 16:00"},
+		{"after an early close", "2026-12-24 14:00", "2026-12-30 16:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settlesAt := cal.SettlementDate(newYork(t, tt.tradedAt))
+			testutil.AssertTimeEqual(t, newYork(t, tt.settlesOn), settlesAt, "Trade should settle T+2 business days")
+		})
+	}
+}
+
+func TestCalendar_MarketHours(t *testing.T) {
+	cal := testCalendar(t)
+
+	testutil.AssertTrue(t, cal.IsOpen(newYork(t, "2026-12-24 12:59")), "Market should be open before an early close")
+	testutil.AssertFalse(t, cal.IsOpen(newYork(t, "2026-12-24 13:00")), "Market should close early")
+	testutil.AssertFalse(t, cal.IsOpen(newYork(t, "2026-12-25 12:00")), "Market should be closed on holidays")
+
+	open, close, isOpen := cal.MarketHours(newYork(t, "2026-12-25 12:00"))
+	testutil.AssertFalse(t, isOpen, "Market should be closed on holidays")
+	testutil.AssertTimeEqual(t, newYork(t, "2026-12-24 09:30"), open, "Last session should be reported while closed")
+	testutil.AssertTimeEqual(t, newYork(t, "2026-12-24 13:00"), close, "Last session should close early")
+
+	_, close, isOpen = cal.MarketHours(newYork(t, "2026-12-28 09:00"))
+	testutil.AssertFalse(t, isOpen, "Market should be closed before the open")
+	testutil.AssertTimeEqual(t, newYork(t, "2026-12-24 13:00"), close, "Sessions should skip weekends and holidays")
+}
+
+func TestCalendar_BusinessDaysAndDeadlines(t *testing.T) {
+	cal := testCalendar(t)
+
+	days := cal.BusinessDaysBetween(newYork(t, "2026-12-23 10:00"), newYork(t, "2026-12-29 16:00"))
+	testutil.AssertEqual(t, 3, days, "Weekends and holidays should not count")
+	testutil.AssertEqual(t, 0, cal.BusinessDaysBetween(newYork(t, "2026-12-29 10:00"), newYork(t, "2026-12-29 16:00")), "Same day should be zero")
+
+	deadline := cal.SettlementDeadline(newYork(t, "2026-12-25 16:00"))
+	testutil.AssertTimeEqual(t, newYork(t, "2026-12-28 16:00"), deadline, "Settlement on a holiday should roll forward")
+}
+
+func TestLoad_ReadsCalendarFiles(t *testing.T) {
+	cal, err := Load("../../../config/calendars/us-equities.json")
+	testutil.AssertNoError(t, err, "Bundled calendar should load")
+	name, ok := cal.IsHoliday(newYork(t, "2026-11-26 12:00"))
+	testutil.AssertTrue(t, ok, "Holidays should be loaded")
+	testutil.AssertEqual(t, "Thanksgiving Day", name, "Holiday names should be loaded")
+
+	_, err = Parse([]byte(`{"timezone": "UTC", "open": "16:00", "close": "09:30"}`))
+	testutil.AssertError(t, err, "Sessions must open before they close")
+	_, err = Parse([]byte(`{"timezone": "UTC", "open": "09:30", "close": "16:00", "weekend": ["Funday"]}`))
+	testutil.AssertError(t, err, "Unknown weekdays should be rejected")
+
+	t.Setenv("TRADING_CALENDAR", "")
+	cal, err = LoadFromEnv()
+	testutil.AssertNoError(t, err, "Missing calendar setting should not be an error")
+	testutil.AssertNil(t, cal, "No calendar should load when none is configured")
+	t.Setenv("TRADING_CALENDAR", "../../../config/calendars/us-equities.json")
+	cal, err = LoadFromEnv()
+	testutil.AssertNoError(t, err, "Configured calendar should load")
+	testutil.AssertEqual(t, "US equities", cal.Name, "Calendar named by TRADING_CALENDAR should load")
+}
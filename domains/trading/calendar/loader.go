@@ -0,0 +1,138 @@
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// calendarFile is the JSON layout of a calendar file. Times of day are
+// "15:04" in the calendar's time zone and dates are "2006-01-02".
+type calendarFile struct {
+	Name           string   `json:"name"`
+	Timezone       string   `json:"timezone"`
+	Open           string   `json:"open"`
+	Close          string   `json:"close"`
+	Weekend        []string `json:"weekend,omitempty"`        // Saturday and Sunday if omitted
+	SettlementDays *int     `json:"settlementDays,omitempty"` // 2 if omitted
+	Holidays       []struct {
+		Date string `json:"date"`
+		Name string `json:"name"`
+	} `json:"holidays"`
+	EarlyCloses []struct {
+		Date  string `json:"date"`
+		Close string `json:"close"`
+	} `json:"earlyCloses"`
+}
+
+// Load reads a calendar from a JSON file
+func Load(path string) (*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar file: %w", err)
+	}
+
+	calendar, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar file %s: %w", path, err)
+	}
+	return calendar, nil
+}
+
+// LoadFromEnv reads the calendar file named by the TRADING_CALENDAR
+// environment variable. It returns nil when the variable is not set.
+func LoadFromEnv() (*Calendar, error) {
+	path := os.Getenv("TRADING_CALENDAR")
+	if path == "" {
+		return nil, nil
+	}
+	return Load(path)
+}
+
+// Parse builds a calendar from its JSON description
+func Parse(data []byte) (*Calendar, error) {
+	var file calendarFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse calendar: %w", err)
+	}
+
+	location, err := time.LoadLocation(file.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: %w", file.Timezone, err)
+	}
+	open, err := parseTimeOfDay(file.Open)
+	if err != nil {
+		return nil, fmt.Errorf("invalid open: %w", err)
+	}
+	closeAt, err := parseTimeOfDay(file.Close)
+	if err != nil {
+		return nil, fmt.Errorf("invalid close: %w", err)
+	}
+
+	calendar := &Calendar{
+		Name:           file.Name,
+		Location:       location,
+		Open:           open,
+		Close:          closeAt,
+		Weekend:        map[time.Weekday]bool{time.Saturday: true, time.Sunday: true},
+		Holidays:       make(map[string]string),
+		EarlyCloses:    make(map[string]time.Duration),
+		SettlementDays: 2,
+	}
+
+	if file.Weekend != nil {
+		calendar.Weekend = make(map[time.Weekday]bool)
+		for _, name := range file.Weekend {
+			weekday, err := parseWeekday(name)
+			if err != nil {
+				return nil, err
+			}
+			calendar.Weekend[weekday] = true
+		}
+	}
+	if file.SettlementDays != nil {
+		calendar.SettlementDays = *file.SettlementDays
+	}
+
+	for _, holiday := range file.Holidays {
+		if _, err := time.Parse(dateLayout, holiday.Date); err != nil {
+			return nil, fmt.Errorf("invalid holiday date %q", holiday.Date)
+		}
+		calendar.Holidays[holiday.Date] = holiday.Name
+	}
+	for _, early := range file.EarlyCloses {
+		if _, err := time.Parse(dateLayout, early.Date); err != nil {
+			return nil, fmt.Errorf("invalid early close date %q", early.Date)
+		}
+		earlyClose, err := parseTimeOfDay(early.Close)
+		if err != nil {
+			return nil, fmt.Errorf("invalid early close on %s: %w", early.Date, err)
+		}
+		calendar.EarlyCloses[early.Date] = earlyClose
+	}
+
+	if err := calendar.Validate(); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// parseTimeOfDay parses "15:04" into an offset from midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("time of day must be in HH:MM format")
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func parseWeekday(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday %q", name)
+}
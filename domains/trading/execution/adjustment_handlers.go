@@ -0,0 +1,194 @@
+package execution
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"securities-marketplace/domains/shared/audit"
+	"securities-marketplace/domains/shared/auth"
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+)
+
+// AdjustmentHandler lets administrators bust and correct executed trades.
+// Every bust or correction needs the approval of two other administrators.
+type AdjustmentHandler struct {
+	service *ExecutionService
+}
+
+// NewAdjustmentHandler creates a new trade adjustment handler
+func NewAdjustmentHandler(service *ExecutionService) *AdjustmentHandler {
+	return &AdjustmentHandler{service: service}
+}
+
+// BustRequest is the body of a bust request
+type BustRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CorrectionRequest is the body of a correction request
+type CorrectionRequest struct {
+	TradePrice   money.Decimal `json:"tradePrice"`
+	SharesTraded int64         `json:"sharesTraded"`
+	Reason       string        `json:"reason"`
+}
+
+// RejectAdjustmentRequest is the body of an adjustment rejection
+type RejectAdjustmentRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RegisterAdminRoutes registers the adjustment routes. Callers are expected
+// to mount the router behind admin authorization.
+func (h *AdjustmentHandler) RegisterAdminRoutes(router *mux.Router) {
+	router.HandleFunc("/trades/adjustments", h.HandleListPending).Methods("GET")
+	router.HandleFunc("/trades/{id}/bust", h.HandleRequestBust).Methods("POST")
+	router.HandleFunc("/trades/{id}/correction", h.HandleRequestCorrection).Methods("POST")
+	router.HandleFunc("/trades/{id}/adjustment/approve", h.HandleApprove).Methods("POST")
+	router.HandleFunc("/trades/{id}/adjustment/reject", h.HandleReject).Methods("POST")
+}
+
+// HandleListPending returns the trades with a bust or correction awaiting
+// approval
+func (h *AdjustmentHandler) HandleListPending(w http.ResponseWriter, r *http.Request) {
+	trades, err := h.service.GetPendingAdjustments()
+	if err != nil {
+		http.Error(w, "Failed to load pending adjustments", http.StatusInternalServerError)
+		return
+	}
+
+	adjustments := make([]map[string]interface{}, 0, len(trades))
+	for _, trade := range trades {
+		adjustments = append(adjustments, adjustmentView(trade))
+	}
+	writeAdjustment(w, "adjustments", adjustments)
+}
+
+// HandleRequestBust asks for a trade to be busted
+func (h *AdjustmentHandler) HandleRequestBust(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := adjustmentAdminID(w, r)
+	if !ok {
+		return
+	}
+
+	var req BustRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	tradeID := mux.Vars(r)["id"]
+	if err := h.service.WithActor(audit.ActorFromContext(r.Context())).RequestTradeBust(tradeID, req.Reason, adminID); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	h.writeTrade(w, tradeID)
+}
+
+// HandleRequestCorrection asks for a trade's price or quantity to be corrected
+func (h *AdjustmentHandler) HandleRequestCorrection(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := adjustmentAdminID(w, r)
+	if !ok {
+		return
+	}
+
+	var req CorrectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	tradeID := mux.Vars(r)["id"]
+	if err := h.service.WithActor(audit.ActorFromContext(r.Context())).RequestTradeCorrection(tradeID, req.TradePrice, req.SharesTraded, req.Reason, adminID); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	h.writeTrade(w, tradeID)
+}
+
+// HandleApprove approves a trade's pending bust or correction
+func (h *AdjustmentHandler) HandleApprove(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := adjustmentAdminID(w, r)
+	if !ok {
+		return
+	}
+
+	trade, err := h.service.WithActor(audit.ActorFromContext(r.Context())).ApproveTradeAdjustment(mux.Vars(r)["id"], adminID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	events.SetConsistencyToken(w, trade.GetLastEventNumber())
+	writeAdjustment(w, "trade", adjustmentView(trade))
+}
+
+// HandleReject rejects a trade's pending bust or correction
+func (h *AdjustmentHandler) HandleReject(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := adjustmentAdminID(w, r)
+	if !ok {
+		return
+	}
+
+	var req RejectAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	tradeID := mux.Vars(r)["id"]
+	if err := h.service.WithActor(audit.ActorFromContext(r.Context())).RejectTradeAdjustment(tradeID, adminID, req.Reason); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	h.writeTrade(w, tradeID)
+}
+
+func (h *AdjustmentHandler) writeTrade(w http.ResponseWriter, tradeID string) {
+	trade, err := h.service.GetTrade(tradeID)
+	if err != nil {
+		http.Error(w, "Failed to load trade", http.StatusInternalServerError)
+		return
+	}
+	events.SetConsistencyToken(w, trade.GetLastEventNumber())
+	writeAdjustment(w, "trade", adjustmentView(trade))
+}
+
+// adjustmentAdminID returns the signed-in administrator. Approvals are
+// counted per administrator, so an anonymous request cannot adjust a trade.
+func adjustmentAdminID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	user := auth.GetUserFromContext(r.Context())
+	if user == nil || user.UserID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return "", false
+	}
+	return user.UserID, true
+}
+
+func adjustmentView(trade *TradeAggregate) map[string]interface{} {
+	return map[string]interface{}{
+		"tradeId":           trade.ID,
+		"securityId":        trade.SecurityID,
+		"buyerId":           trade.BuyerID,
+		"sellerId":          trade.SellerID,
+		"status":            trade.Status,
+		"tradePrice":        trade.TradePrice,
+		"sharesTraded":      trade.SharesTraded,
+		"totalAmount":       trade.TotalAmount,
+		"pendingAdjustment": trade.PendingAdjustment,
+		"bustedAt":          trade.BustedAt,
+		"bustReason":        trade.BustReason,
+		"correctedAt":       trade.CorrectedAt,
+	}
+}
+
+func writeAdjustment(w http.ResponseWriter, key string, value interface{}) {
+	response := map[string]interface{}{
+		"success": true,
+		key:       value,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
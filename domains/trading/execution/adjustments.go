@@ -0,0 +1,414 @@
+package execution
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+)
+
+// TradeAdjustmentType is what an adjustment does to an executed trade
+type TradeAdjustmentType string
+
+const (
+	TradeAdjustmentBust       TradeAdjustmentType = "bust"       // Void the trade
+	TradeAdjustmentCorrection TradeAdjustmentType = "correction" // Change its price or quantity
+)
+
+// RequiredAdjustmentApprovals is how many administrators, other than the one
+// who asked for it, must approve a bust or correction before it is applied
+const RequiredAdjustmentApprovals = 2
+
+// TradeAdjustment is a bust or correction awaiting approval
+type TradeAdjustment struct {
+	ID           string              `json:"id"`
+	Type         TradeAdjustmentType `json:"type"`
+	Reason       string              `json:"reason"`
+	RequestedBy  string              `json:"requestedBy"`
+	RequestedAt  time.Time           `json:"requestedAt"`
+	TradePrice   *money.Decimal      `json:"tradePrice,omitempty"`   // Corrections only
+	SharesTraded int64               `json:"sharesTraded,omitempty"` // Corrections only
+	ApprovedBy   []string            `json:"approvedBy"`
+}
+
+// RequestBust asks for the trade to be voided because it was executed in
+// error. The bust is applied once it has enough approvals.
+func (t *TradeAggregate) RequestBust(reason, requestedBy string) error {
+	if err := t.canRequestAdjustment(reason, requestedBy); err != nil {
+		return err
+	}
+
+	event := NewTradeAdjustmentRequested(t.ID, uuid.New().String(), TradeAdjustmentBust, reason, requestedBy, nil, 0, time.Now())
+	t.AddEvent(event)
+	return t.ApplyEvent(event)
+}
+
+// RequestCorrection asks for the trade's price or quantity to be corrected.
+// The quantity can only be lowered: the listing and bid may no longer have
+// the shares to trade more. The correction is applied once it has enough
+// approvals.
+func (t *TradeAggregate) RequestCorrection(tradePrice money.Decimal, sharesTraded int64, reason, requestedBy string) error {
+	if err := t.canRequestAdjustment(reason, requestedBy); err != nil {
+		return err
+	}
+
+	if !tradePrice.IsPositive() {
+		return fmt.Errorf("trade price must be greater than zero")
+	}
+
+	if sharesTraded <= 0 || sharesTraded > t.SharesTraded {
+		return fmt.Errorf("corrected quantity must be between 1 and %d", t.SharesTraded)
+	}
+
+	if tradePrice.Equal(t.TradePrice) && sharesTraded == t.SharesTraded {
+		return fmt.Errorf("correction does not change the trade")
+	}
+
+	if _, err := tradePrice.MulInt(sharesTraded); err != nil {
+		return fmt.Errorf("failed to value %d shares at %s: %w", sharesTraded, tradePrice, err)
+	}
+
+	event := NewTradeAdjustmentRequested(t.ID, uuid.New().String(), TradeAdjustmentCorrection, reason, requestedBy, &tradePrice, sharesTraded, time.Now())
+	t.AddEvent(event)
+	return t.ApplyEvent(event)
+}
+
+// ApproveAdjustment approves the pending bust or correction. Approvers must
+// differ from the requester, from each other and from the trade's parties.
+// It returns true when this approval applied the adjustment.
+func (t *TradeAggregate) ApproveAdjustment(approvedBy string) (bool, error) {
+	adjustment := t.PendingAdjustment
+	if adjustment == nil {
+		return false, fmt.Errorf("trade has no pending adjustment")
+	}
+
+	if approvedBy == "" {
+		return false, fmt.Errorf("approver is required")
+	}
+
+	if approvedBy == adjustment.RequestedBy {
+		return false, fmt.Errorf("an adjustment cannot be approved by its requester")
+	}
+
+	if slices.Contains(adjustment.ApprovedBy, approvedBy) {
+		return false, fmt.Errorf("%s has already approved this adjustment", approvedBy)
+	}
+
+	if approvedBy == t.BuyerID || approvedBy == t.SellerID {
+		return false, fmt.Errorf("a party to the trade cannot approve its adjustment")
+	}
+
+	now := time.Now()
+	approved := NewTradeAdjustmentApproved(t.ID, adjustment.ID, approvedBy, now)
+	t.AddEvent(approved)
+	if err := t.ApplyEvent(approved); err != nil {
+		return false, err
+	}
+
+	if len(adjustment.ApprovedBy) < RequiredAdjustmentApprovals {
+		return false, nil
+	}
+
+	var applied events.DomainEvent
+	if adjustment.Type == TradeAdjustmentBust {
+		applied = NewTradeBusted(t, adjustment, now)
+	} else {
+		totalAmount, err := adjustment.TradePrice.MulInt(adjustment.SharesTraded)
+		if err != nil {
+			return false, fmt.Errorf("failed to value corrected trade: %w", err)
+		}
+		applied = NewTradeCorrected(t, adjustment, totalAmount, now)
+	}
+	t.AddEvent(applied)
+	if err := t.ApplyEvent(applied); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RejectAdjustment drops the pending bust or correction and leaves the trade
+// as it was
+func (t *TradeAggregate) RejectAdjustment(rejectedBy, reason string) error {
+	if t.PendingAdjustment == nil {
+		return fmt.Errorf("trade has no pending adjustment")
+	}
+
+	if reason == "" {
+		return fmt.Errorf("rejection reason is required")
+	}
+
+	event := NewTradeAdjustmentRejected(t.ID, t.PendingAdjustment.ID, rejectedBy, reason, time.Now())
+	t.AddEvent(event)
+	return t.ApplyEvent(event)
+}
+
+func (t *TradeAggregate) canRequestAdjustment(reason, requestedBy string) error {
+	if t.Status == TradeStatusFailed || t.Status == TradeStatusCancelled || t.Status == TradeStatusBusted {
+		return fmt.Errorf("cannot adjust a %s trade", t.Status)
+	}
+
+	if t.PendingAdjustment != nil {
+		return fmt.Errorf("trade already has a pending %s", t.PendingAdjustment.Type)
+	}
+
+	if reason == "" {
+		return fmt.Errorf("adjustment reason is required")
+	}
+
+	if requestedBy == "" {
+		return fmt.Errorf("requester is required")
+	}
+	return nil
+}
+
+func (t *TradeAggregate) applyTradeAdjustmentRequested(event *TradeAdjustmentRequested) error {
+	t.PendingAdjustment = &TradeAdjustment{
+		ID:           event.AdjustmentID,
+		Type:         event.AdjustmentType,
+		Reason:       event.Reason,
+		RequestedBy:  event.RequestedBy,
+		RequestedAt:  event.RequestedAt,
+		TradePrice:   event.TradePrice,
+		SharesTraded: event.SharesTraded,
+	}
+
+	t.IncrementVersion()
+	return nil
+}
+
+func (t *TradeAggregate) applyTradeAdjustmentApproved(event *TradeAdjustmentApproved) error {
+	if t.PendingAdjustment == nil || t.PendingAdjustment.ID != event.AdjustmentID {
+		return fmt.Errorf("adjustment %s is not pending", event.AdjustmentID)
+	}
+	t.PendingAdjustment.ApprovedBy = append(t.PendingAdjustment.ApprovedBy, event.ApprovedBy)
+
+	t.IncrementVersion()
+	return nil
+}
+
+func (t *TradeAggregate) applyTradeAdjustmentRejected(event *TradeAdjustmentRejected) error {
+	t.PendingAdjustment = nil
+
+	t.IncrementVersion()
+	return nil
+}
+
+func (t *TradeAggregate) applyTradeBusted(event *TradeBusted) error {
+	t.Status = TradeStatusBusted
+	t.BustedAt = &event.BustedAt
+	t.BustReason = event.Reason
+	t.PendingAdjustment = nil
+
+	t.IncrementVersion()
+	return nil
+}
+
+func (t *TradeAggregate) applyTradeCorrected(event *TradeCorrected) error {
+	t.TradePrice = event.TradePrice
+	t.SharesTraded = event.SharesTraded
+	t.TotalAmount = money.New(event.TotalAmount, event.Currency)
+	if t.TransferInfo != nil {
+		t.TransferInfo.SharesCount = event.SharesTraded
+	}
+	t.CorrectedAt = &event.CorrectedAt
+	t.PendingAdjustment = nil
+
+	t.IncrementVersion()
+	return nil
+}
+
+// sequenceTrade runs an adjustment command on the actor of the trade's
+// security, so an adjustment never races matching on the orders it adjusts
+func (s *ExecutionService) sequenceTrade(tradeID string, command func(fenced *ExecutionService) (interface{}, error)) (interface{}, error) {
+	trade, err := s.repository.FindByID(tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trade: %w", err)
+	}
+	return s.sequence(trade.SecurityID, command)
+}
+
+// RequestTradeBust asks for a trade executed in error to be voided
+func (s *ExecutionService) RequestTradeBust(tradeID, reason, requestedBy string) error {
+	_, err := s.sequenceTrade(tradeID, func(fenced *ExecutionService) (interface{}, error) {
+		return nil, fenced.requestTradeBust(tradeID, reason, requestedBy)
+	})
+	return err
+}
+
+// requestTradeBust requests a bust on the actor of the trade's security
+func (s *ExecutionService) requestTradeBust(tradeID, reason, requestedBy string) error {
+	trade, err := s.repository.FindByID(tradeID)
+	if err != nil {
+		return fmt.Errorf("failed to find trade: %w", err)
+	}
+
+	if err := trade.RequestBust(reason, requestedBy); err != nil {
+		return fmt.Errorf("failed to request bust: %w", err)
+	}
+
+	return s.saveAggregateEvents(trade, requestedBy)
+}
+
+// RequestTradeCorrection asks for a trade's price or quantity to be corrected
+func (s *ExecutionService) RequestTradeCorrection(tradeID string, tradePrice money.Decimal, sharesTraded int64, reason, requestedBy string) error {
+	_, err := s.sequenceTrade(tradeID, func(fenced *ExecutionService) (interface{}, error) {
+		return nil, fenced.requestTradeCorrection(tradeID, tradePrice, sharesTraded, reason, requestedBy)
+	})
+	return err
+}
+
+// requestTradeCorrection requests a correction on the actor of the trade's
+// security
+func (s *ExecutionService) requestTradeCorrection(tradeID string, tradePrice money.Decimal, sharesTraded int64, reason, requestedBy string) error {
+	trade, err := s.repository.FindByID(tradeID)
+	if err != nil {
+		return fmt.Errorf("failed to find trade: %w", err)
+	}
+
+	if err := trade.RequestCorrection(tradePrice, sharesTraded, reason, requestedBy); err != nil {
+		return fmt.Errorf("failed to request correction: %w", err)
+	}
+
+	return s.saveAggregateEvents(trade, requestedBy)
+}
+
+// ApproveTradeAdjustment approves a trade's pending bust or correction. The
+// approval that applies it also adjusts the listing and bid the trade filled,
+// and returns shares already transferred to the seller, in the same batch.
+func (s *ExecutionService) ApproveTradeAdjustment(tradeID, approvedBy string) (*TradeAggregate, error) {
+	result, err := s.sequenceTrade(tradeID, func(fenced *ExecutionService) (interface{}, error) {
+		return fenced.approveTradeAdjustment(tradeID, approvedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*TradeAggregate), nil
+}
+
+// approveTradeAdjustment approves on the actor of the trade's security
+func (s *ExecutionService) approveTradeAdjustment(tradeID, approvedBy string) (*TradeAggregate, error) {
+	trade, err := s.repository.FindByID(tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trade: %w", err)
+	}
+
+	adjustment := trade.PendingAdjustment
+	previousShares := trade.SharesTraded
+	transferred := trade.TransferInfo != nil
+
+	applied, err := trade.ApproveAdjustment(approvedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve adjustment: %w", err)
+	}
+
+	var compensated []events.Aggregate
+	if applied {
+		compensated, err = s.compensateAdjustment(trade, adjustment.Reason, previousShares, transferred)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.saveAggregateEvents(trade, approvedBy, compensated...); err != nil {
+		return nil, err
+	}
+	return trade, nil
+}
+
+// RejectTradeAdjustment rejects a trade's pending bust or correction
+func (s *ExecutionService) RejectTradeAdjustment(tradeID, rejectedBy, reason string) error {
+	_, err := s.sequenceTrade(tradeID, func(fenced *ExecutionService) (interface{}, error) {
+		return nil, fenced.rejectTradeAdjustment(tradeID, rejectedBy, reason)
+	})
+	return err
+}
+
+// rejectTradeAdjustment rejects on the actor of the trade's security
+func (s *ExecutionService) rejectTradeAdjustment(tradeID, rejectedBy, reason string) error {
+	trade, err := s.repository.FindByID(tradeID)
+	if err != nil {
+		return fmt.Errorf("failed to find trade: %w", err)
+	}
+
+	if err := trade.RejectAdjustment(rejectedBy, reason); err != nil {
+		return fmt.Errorf("failed to reject adjustment: %w", err)
+	}
+
+	return s.saveAggregateEvents(trade, rejectedBy)
+}
+
+// GetPendingAdjustments returns the trades with a bust or correction awaiting
+// approval
+func (s *ExecutionService) GetPendingAdjustments() ([]*TradeAggregate, error) {
+	requests, err := s.eventStore.GetEventsByType("TradeAdjustmentRequested", 1000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get adjustment requests: %w", err)
+	}
+
+	var pending []*TradeAggregate
+	seen := make(map[string]bool)
+	for _, request := range requests {
+		if seen[request.AggregateID] {
+			continue
+		}
+		seen[request.AggregateID] = true
+
+		trade, err := s.repository.FindByID(request.AggregateID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find trade: %w", err)
+		}
+		if trade.PendingAdjustment != nil {
+			pending = append(pending, trade)
+		}
+	}
+	return pending, nil
+}
+
+// compensateAdjustment brings the listing, bid and share ownership in line
+// with an applied bust or correction, and returns the aggregates it changed
+func (s *ExecutionService) compensateAdjustment(trade *TradeAggregate, reason string, previousShares int64, transferred bool) ([]events.Aggregate, error) {
+	shares := trade.SharesTraded
+	if trade.Status == TradeStatusBusted {
+		shares = 0
+	}
+
+	var changed []events.Aggregate
+	if trade.ListingID != nil && *trade.ListingID != "" {
+		l, err := s.listings.FindByID(*trade.ListingID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find listing: %w", err)
+		}
+		if err := l.AdjustSale(trade.ID, shares, trade.TradePrice, reason); err != nil {
+			return nil, fmt.Errorf("failed to adjust listing sale: %w", err)
+		}
+		changed = append(changed, l)
+	}
+
+	if trade.BidID != nil {
+		b, err := s.bids.FindByID(*trade.BidID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find bid: %w", err)
+		}
+		if err := b.AdjustFill(trade.ID, shares, trade.TradePrice, reason); err != nil {
+			return nil, fmt.Errorf("failed to adjust bid fill: %w", err)
+		}
+		changed = append(changed, b)
+	}
+
+	if transferred && shares < previousShares {
+		security, err := s.securities.FindByID(trade.SecurityID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find security: %w", err)
+		}
+		if err := security.ReverseOwnershipTransfer(trade.BuyerID, trade.SellerID, previousShares-shares, trade.ID, reason); err != nil {
+			return nil, fmt.Errorf("failed to return shares to the seller: %w", err)
+		}
+		changed = append(changed, security)
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,135 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/securities"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/testutil"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/listing"
+)
+
+func newMatchedTrade() *TradeAggregate {
+	trade := NewTradeAggregate("trade-1")
+	trade.MatchTrade("listing-1", nil, "buyer-1", "seller-1", "TEST-001", 100, money.NewDecimalFromInt(50), usd("5000"), time.Now().Add(48*time.Hour), "price_time")
+	return trade
+}
+
+func TestTradeAggregate_AdjustmentNeedsTwoIndependentApprovals(t *testing.T) {
+	// Arrange
+	trade := newMatchedTrade()
+
+	// Act & Assert
+	testutil.AssertError(t, trade.RequestBust("", "admin-1"), "Busts should need a reason")
+	testutil.AssertNoError(t, trade.RequestBust("fat finger", "admin-1"), "Bust should be requested")
+	testutil.AssertError(t, trade.RequestCorrection(money.NewDecimalFromInt(49), 100, "wrong price", "admin-1"), "Only one adjustment should be pending")
+
+	_, err := trade.ApproveAdjustment("admin-1")
+	testutil.AssertError(t, err, "Requester should not approve their own bust")
+	_, err = trade.ApproveAdjustment("buyer-1")
+	testutil.AssertError(t, err, "Parties to the trade should not approve")
+
+	applied, err := trade.ApproveAdjustment("admin-2")
+	testutil.AssertNoError(t, err, "First approval should be recorded")
+	testutil.AssertFalse(t, applied, "One approval should not apply the bust")
+	_, err = trade.ApproveAdjustment("admin-2")
+	testutil.AssertError(t, err, "The same administrator should not approve twice")
+
+	testutil.AssertNoError(t, trade.RejectAdjustment("admin-3", "trade was intended"), "Bust should be rejected")
+	testutil.AssertTrue(t, trade.PendingAdjustment == nil, "Rejection should drop the bust")
+	testutil.AssertEqual(t, TradeStatusMatched, trade.Status, "Rejection should leave the trade standing")
+
+	testutil.AssertError(t, trade.RequestCorrection(money.NewDecimalFromInt(50), 150, "wrong size", "admin-1"), "Corrections should not raise the quantity")
+	testutil.AssertNoError(t, trade.RequestCorrection(money.NewDecimalFromInt(49), 80, "wrong size", "admin-1"), "Correction should be requested")
+	trade.ApproveAdjustment("admin-2")
+	applied, err = trade.ApproveAdjustment("admin-3")
+	testutil.AssertNoError(t, err, "Second approval should be recorded")
+	testutil.AssertTrue(t, applied, "Second approval should apply the correction")
+	testutil.AssertEqual(t, int64(80), trade.SharesTraded, "Quantity should be corrected")
+	testutil.AssertEqual(t, "3920", trade.TotalAmount.Amount.String(), "Total should follow the corrected price and quantity")
+	testutil.AssertEqual(t, "TradeCorrected", trade.GetUncommittedEvents()[len(trade.GetUncommittedEvents())-1].GetEventType(), "Correction should be emitted")
+}
+
+func TestExecutionService_BustAndCorrectionCompensateListingBidAndOwnership(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetInvestorVerifier(testInvestorVerifier{})
+
+	security := securities.NewSecurityAggregate("TEST-001")
+	security.ListSecurity("seller-1", securities.SecurityTypeStock, "Test", "TST", 1000, nil, "", nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, security), "Security should be saved")
+
+	offer := listing.NewListingAggregate("listing-1")
+	offer.CreateListing("TEST-001", "seller-1", 100, listing.ListingTypeFixed, nil, nil, decimalPtr("50.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, offer), "Listing should be saved")
+
+	bid := bidding.NewBidAggregate("bid-1")
+	bid.PlaceBid("listing-1", "buyer-1", 100, money.NewDecimalFromInt(50), bidding.BidTypeLimit, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, bid), "Bid should be saved")
+
+	trades, err := service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertLengthEqual(t, 1, trades, "Listing and bid should trade")
+	tradeID := trades[0].ID
+
+	testutil.AssertNoError(t, service.ConfirmTrade(tradeID, "buyer-1"), "Buyer should confirm")
+	testutil.AssertNoError(t, service.ConfirmTrade(tradeID, "seller-1"), "Seller should confirm")
+	testutil.AssertNoError(t, service.InitiateSettlement(tradeID, "escrow-1", "system"), "Settlement should start")
+	testutil.AssertNoError(t, service.RecordPayment(tradeID, trades[0].TotalAmount, "wire", "tx-1"), "Payment should be recorded")
+	testutil.AssertNoError(t, service.RecordShareTransfer(tradeID, 100, "seller-1", "buyer-1", "book_entry", "hash"), "Transfer should be recorded")
+
+	securityRepository := securities.NewEventSourcedSecurityRepository(setup.EventStore)
+	security, _ = securityRepository.FindByID("TEST-001")
+	security.TransferOwnership("seller-1", "buyer-1", 100, tradeID)
+	testutil.AssertNoError(t, service.saveStandaloneEvents(security, "system"), "Ownership should move to the buyer")
+
+	listings := listing.NewEventSourcedListingRepository(setup.EventStore)
+	bids := bidding.NewEventSourcedBidRepository(setup.EventStore)
+
+	// Act & Assert
+	testutil.AssertNoError(t, service.RequestTradeCorrection(tradeID, money.NewDecimalFromInt(48), 60, "booked size was wrong", "admin-1"), "Correction should be requested")
+	_, err = service.ApproveTradeAdjustment(tradeID, "admin-2")
+	testutil.AssertNoError(t, err, "First approval should be recorded")
+	corrected, err := service.ApproveTradeAdjustment(tradeID, "admin-3")
+	testutil.AssertNoError(t, err, "Second approval should apply the correction")
+	testutil.AssertEqual(t, int64(60), corrected.SharesTraded, "Trade should be corrected")
+
+	reloaded, err := service.GetTrade(tradeID)
+	testutil.AssertNoError(t, err, "Corrected trade should reload")
+	testutil.AssertEqual(t, "48", reloaded.TradePrice.String(), "Correction should survive a reload")
+	filledListing, _ := listings.FindByID("listing-1")
+	testutil.AssertEqual(t, int64(60), filledListing.TotalSharesSold, "Listing should only have sold the corrected quantity")
+	testutil.AssertEqual(t, int64(0), filledListing.SharesRemaining, "Corrected shares should not be offered again")
+	filledBid, _ := bids.FindByID("bid-1")
+	testutil.AssertEqual(t, int64(60), filledBid.SharesFilled, "Bid should only be filled by the corrected quantity")
+	testutil.AssertEqual(t, "48", filledBid.AverageFillPrice.String(), "Bid should be filled at the corrected price")
+	testutil.AssertEqual(t, int64(40), filledBid.SharesRemaining, "Corrected shares should be bid for again")
+	testutil.AssertEqual(t, bidding.BidStatusPartiallyFilled, filledBid.Status, "Corrected bid should no longer be filled")
+	testutil.AssertEqual(t, 60.0, filledBid.GetFillPercentage(), "Fill percentage should follow the correction")
+	security, _ = securityRepository.FindByID("TEST-001")
+	testutil.AssertEqual(t, int64(60), security.GetSharesOwned("buyer-1"), "Excess shares should go back to the seller")
+	testutil.AssertEqual(t, int64(940), security.GetSharesOwned("seller-1"), "Seller should hold the returned shares")
+
+	testutil.AssertNoError(t, service.RequestTradeBust(tradeID, "erroneous execution", "admin-2"), "Bust should be requested")
+	service.ApproveTradeAdjustment(tradeID, "admin-1")
+	_, err = service.ApproveTradeAdjustment(tradeID, "admin-3")
+	testutil.AssertNoError(t, err, "Second approval should apply the bust")
+
+	busted, _ := service.GetTrade(tradeID)
+	testutil.AssertEqual(t, TradeStatusBusted, busted.Status, "Trade should be busted")
+	testutil.AssertError(t, service.CancelTrade(tradeID, "too late", "admin-1"), "Busted trades should not be cancelled")
+	filledListing, _ = listings.FindByID("listing-1")
+	testutil.AssertEqual(t, int64(0), filledListing.TotalSharesSold, "Bust should reverse the sale")
+	filledBid, _ = bids.FindByID("bid-1")
+	testutil.AssertEqual(t, int64(0), filledBid.SharesFilled, "Bust should reverse the fill")
+	testutil.AssertLengthEqual(t, 0, filledBid.FillRecords, "Bust should drop the fill record")
+	testutil.AssertEqual(t, int64(100), filledBid.SharesRemaining, "Busted shares should be bid for again")
+	testutil.AssertEqual(t, bidding.BidStatusActive, filledBid.Status, "Busted bid should be active again")
+	security, _ = securityRepository.FindByID("TEST-001")
+	testutil.AssertEqual(t, int64(1000), security.GetSharesOwned("seller-1"), "Bust should return every share to the seller")
+	testutil.AssertEqual(t, int64(0), security.GetSharesOwned("buyer-1"), "Buyer should hold nothing after the bust")
+	testutil.AssertEqual(t, 1, len(setup.EventBus.GetEventsByType("TradeBusted")), "Bust should be published")
+}
@@ -5,6 +5,20 @@ import (
 	"math"
 	"sort"
 	"time"
+
+	"securities-marketplace/domains/shared/money"
+)
+
+var (
+	// samePriceTolerance treats prices less than a cent apart as one level
+	samePriceTolerance = money.MustParseDecimal("0.01")
+
+	// bulkDiscountFactor applies the 0.5% bulk trade discount
+	bulkDiscountFactor = money.MustParseDecimal("0.995")
+
+	// negotiationPriceRange is how far from the reference price (10%)
+	// negotiated orders may be
+	negotiationPriceRange = money.MustParseDecimal("0.1")
 )
 
 // AdvancedMatchingEngine extends the basic matching engine with sophisticated algorithms
@@ -12,14 +26,15 @@ type AdvancedMatchingEngine struct {
 	*OrderMatchingEngine
 	marketDataProvider MarketDataProvider
 	riskEngine         RiskEngine
+	allocationPolicy   AllocationPolicy
 }
 
 // MarketDataProvider interface for getting market data
 type MarketDataProvider interface {
-	GetLastTradePrice(securityID string) (float64, error)
+	GetLastTradePrice(securityID string) (money.Decimal, error)
 	GetMarketHours() (open, close time.Time, isOpen bool)
 	GetVolatility(securityID string, period time.Duration) (float64, error)
-	GetReferencePrice(securityID string) (float64, error)
+	GetReferencePrice(securityID string) (money.Decimal, error)
 }
 
 // RiskEngine interface for risk assessment
@@ -29,6 +44,12 @@ type RiskEngine interface {
 	ValidateCounterparty(buyerID, sellerID string) error
 }
 
+// PostTradeRiskChecker is implemented by risk engines that check limits again
+// once a trade has executed
+type PostTradeRiskChecker interface {
+	CheckExecutedTrade(trade *MatchResult) error
+}
+
 // RiskAssessment represents trade risk evaluation
 type RiskAssessment struct {
 	RiskLevel      string  `json:"riskLevel"`      // low, medium, high, extreme
@@ -44,13 +65,21 @@ func NewAdvancedMatchingEngine(
 	marketData MarketDataProvider,
 	riskEngine RiskEngine,
 ) *AdvancedMatchingEngine {
+	basic.SetMarketDataProvider(marketData)
 	return &AdvancedMatchingEngine{
 		OrderMatchingEngine: basic,
 		marketDataProvider:  marketData,
 		riskEngine:         riskEngine,
+		allocationPolicy:   ProRataFIFO{},
 	}
 }
 
+// SetAllocationPolicy sets how pro-rata auctions divide the larger side's
+// shares among its orders
+func (e *AdvancedMatchingEngine) SetAllocationPolicy(policy AllocationPolicy) {
+	e.allocationPolicy = policy
+}
+
 // MatchOrdersWithRisk performs order matching with risk assessment
 func (e *AdvancedMatchingEngine) MatchOrdersWithRisk(securityID string, algorithm MatchingAlgorithm) ([]*MatchResult, []*RiskAssessment, error) {
 	// Get basic matches
@@ -82,8 +111,16 @@ func (e *AdvancedMatchingEngine) MatchOrdersWithRisk(securityID string, algorith
 	return validMatches, riskAssessments, nil
 }
 
-// MatchWithProRata implements pro-rata allocation for uniform price auctions
-func (e *AdvancedMatchingEngine) MatchWithProRata(orderBook *OrderBook, clearingPrice float64) ([]*MatchResult, error) {
+// MatchWithProRata implements pro-rata allocation for uniform price auctions.
+// The smaller side trades in full and the allocation policy divides its
+// quantity among the larger side's orders in time priority. Each allocation
+// is a separate execution, so orders whose conditions an allocation does not
+// meet are passed over.
+func (e *AdvancedMatchingEngine) MatchWithProRata(orderBook *OrderBook, clearingPrice money.Decimal) ([]*MatchResult, error) {
+	if orderBook.breaches(clearingPrice) {
+		return []*MatchResult{}, nil
+	}
+
 	sellOrders := orderBook.GetSellOrders()
 	buyOrders := orderBook.GetBuyOrders()
 
@@ -92,13 +129,13 @@ func (e *AdvancedMatchingEngine) MatchWithProRata(orderBook *OrderBook, clearing
 	var eligibleBuys []*OrderBookEntry
 
 	for _, order := range sellOrders {
-		if order.Price == nil || *order.Price <= clearingPrice {
+		if order.Price == nil || !order.Price.GreaterThan(clearingPrice) {
 			eligibleSells = append(eligibleSells, order)
 		}
 	}
 
 	for _, order := range buyOrders {
-		if order.Price == nil || *order.Price >= clearingPrice {
+		if order.Price == nil || !order.Price.LessThan(clearingPrice) {
 			eligibleBuys = append(eligibleBuys, order)
 		}
 	}
@@ -118,20 +155,30 @@ func (e *AdvancedMatchingEngine) MatchWithProRata(orderBook *OrderBook, clearing
 
 	// Determine tradeable quantity
 	tradeableQuantity := min(totalSupply, totalDemand)
-	
+
+	policy := e.allocationPolicy
+	if policy == nil {
+		policy = ProRataFIFO{}
+	}
+
 	var matches []*MatchResult
 
 	// If supply exceeds demand, allocate proportionally among sellers
 	if totalSupply > totalDemand {
 		// Allocate full demand, pro-rata among sellers
-		for _, sellOrder := range eligibleSells {
-			allocation := float64(sellOrder.Quantity) / float64(totalSupply) * float64(tradeableQuantity)
-			allocatedShares := int64(math.Floor(allocation))
+		sortByTime(eligibleSells)
+		allocations := policy.Allocate(eligibleSells, tradeableQuantity)
+		for i, sellOrder := range eligibleSells {
+			allocatedShares := allocations[i]
 
 			if allocatedShares > 0 {
 				// Find matching buy orders
-				buyMatches := e.allocateToBuyers(eligibleBuys, allocatedShares)
+				buyMatches := e.allocateToBuyers(orderBook, sellOrder, eligibleBuys, allocatedShares)
 				for _, buyMatch := range buyMatches {
+					totalAmount, err := tradeAmount(clearingPrice, buyMatch.Quantity, orderBook.Currency)
+					if err != nil {
+						return nil, err
+					}
 					match := &MatchResult{
 						TradeID:           e.generateTradeID(),
 						ListingID:         sellOrder.ListingID,
@@ -141,7 +188,7 @@ func (e *AdvancedMatchingEngine) MatchWithProRata(orderBook *OrderBook, clearing
 						SecurityID:        orderBook.SecurityID,
 						SharesTraded:      buyMatch.Quantity,
 						TradePrice:        clearingPrice,
-						TotalAmount:       float64(buyMatch.Quantity) * clearingPrice,
+						TotalAmount:       totalAmount,
 						SettlementDate:    e.calculateSettlementDate(),
 						MatchingAlgorithm: string(UniformPriceAuction),
 					}
@@ -151,14 +198,19 @@ func (e *AdvancedMatchingEngine) MatchWithProRata(orderBook *OrderBook, clearing
 		}
 	} else {
 		// Allocate full supply, pro-rata among buyers
-		for _, buyOrder := range eligibleBuys {
-			allocation := float64(buyOrder.Quantity) / float64(totalDemand) * float64(tradeableQuantity)
-			allocatedShares := int64(math.Floor(allocation))
+		sortByTime(eligibleBuys)
+		allocations := policy.Allocate(eligibleBuys, tradeableQuantity)
+		for i, buyOrder := range eligibleBuys {
+			allocatedShares := allocations[i]
 
 			if allocatedShares > 0 {
 				// Find matching sell orders
-				sellMatches := e.allocateToSellers(eligibleSells, allocatedShares)
+				sellMatches := e.allocateToSellers(orderBook, buyOrder, eligibleSells, allocatedShares)
 				for _, sellMatch := range sellMatches {
+					totalAmount, err := tradeAmount(clearingPrice, sellMatch.Quantity, orderBook.Currency)
+					if err != nil {
+						return nil, err
+					}
 					match := &MatchResult{
 						TradeID:           e.generateTradeID(),
 						ListingID:         sellMatch.ListingID,
@@ -168,7 +220,7 @@ func (e *AdvancedMatchingEngine) MatchWithProRata(orderBook *OrderBook, clearing
 						SecurityID:        orderBook.SecurityID,
 						SharesTraded:      sellMatch.Quantity,
 						TradePrice:        clearingPrice,
-						TotalAmount:       float64(sellMatch.Quantity) * clearingPrice,
+						TotalAmount:       totalAmount,
 						SettlementDate:    e.calculateSettlementDate(),
 						MatchingAlgorithm: string(UniformPriceAuction),
 					}
@@ -198,13 +250,12 @@ func (e *AdvancedMatchingEngine) MatchWithTimeWeightedPriority(orderBook *OrderB
 			return false
 		}
 
-		priceDiff := *sellOrders[i].Price - *sellOrders[j].Price
-		if math.Abs(priceDiff) < 0.01 { // Same price level
+		if samePriceLevel(*sellOrders[i].Price, *sellOrders[j].Price) {
 			// Apply time weighting
 			timeWeight := e.calculateTimeWeight(sellOrders[i].Timestamp)
 			return timeWeight > e.calculateTimeWeight(sellOrders[j].Timestamp)
 		}
-		return *sellOrders[i].Price < *sellOrders[j].Price
+		return sellOrders[i].Price.LessThan(*sellOrders[j].Price)
 	})
 
 	sort.Slice(buyOrders, func(i, j int) bool {
@@ -218,18 +269,23 @@ func (e *AdvancedMatchingEngine) MatchWithTimeWeightedPriority(orderBook *OrderB
 			return false
 		}
 
-		priceDiff := *buyOrders[i].Price - *buyOrders[j].Price
-		if math.Abs(priceDiff) < 0.01 { // Same price level
+		if samePriceLevel(*buyOrders[i].Price, *buyOrders[j].Price) {
 			timeWeight := e.calculateTimeWeight(buyOrders[i].Timestamp)
 			return timeWeight > e.calculateTimeWeight(buyOrders[j].Timestamp)
 		}
-		return *buyOrders[i].Price > *buyOrders[j].Price
+		return buyOrders[i].Price.GreaterThan(*buyOrders[j].Price)
 	})
 
 	// Use standard matching logic with the time-weighted sorted orders
 	return e.matchPriceTimePriority(orderBook)
 }
 
+// samePriceLevel reports whether two prices are within samePriceTolerance
+func samePriceLevel(a, b money.Decimal) bool {
+	diff, err := a.Sub(b)
+	return err == nil && diff.Abs().LessThan(samePriceTolerance)
+}
+
 // MatchBulkOrders handles large orders with special treatment
 func (e *AdvancedMatchingEngine) MatchBulkOrders(orderBook *OrderBook, minBulkSize int64) ([]*MatchResult, error) {
 	var bulkMatches []*MatchResult
@@ -260,6 +316,7 @@ func (e *AdvancedMatchingEngine) MatchBulkOrders(orderBook *OrderBook, minBulkSi
 
 	// Match bulk orders first with preferred pricing
 	bulkOrderBook := NewOrderBook(orderBook.SecurityID)
+	bulkOrderBook.Band = orderBook.Band
 	for _, order := range bulkSells {
 		bulkOrderBook.AddSellOrder(order)
 	}
@@ -277,6 +334,7 @@ func (e *AdvancedMatchingEngine) MatchBulkOrders(orderBook *OrderBook, minBulkSi
 
 	// Match remaining regular orders
 	regularOrderBook := NewOrderBook(orderBook.SecurityID)
+	regularOrderBook.Band = orderBook.Band
 	for _, order := range regularSells {
 		regularOrderBook.AddSellOrder(order)
 	}
@@ -284,7 +342,7 @@ func (e *AdvancedMatchingEngine) MatchBulkOrders(orderBook *OrderBook, minBulkSi
 		regularOrderBook.AddBuyOrder(order)
 	}
 
-	if len(regularSells) > 0 && len(regularBuys) > 0 {
+	if len(regularSells) > 0 && len(regularBuys) > 0 && bulkOrderBook.Halt == nil {
 		matches, err := e.matchPriceTimePriority(regularOrderBook)
 		if err != nil {
 			return nil, err
@@ -293,6 +351,13 @@ func (e *AdvancedMatchingEngine) MatchBulkOrders(orderBook *OrderBook, minBulkSi
 	}
 
 	// Combine results
+	orderBook.Prevented = append(orderBook.Prevented, bulkOrderBook.Prevented...)
+	orderBook.Prevented = append(orderBook.Prevented, regularOrderBook.Prevented...)
+	if bulkOrderBook.Halt != nil {
+		orderBook.Halt = bulkOrderBook.Halt
+	} else {
+		orderBook.Halt = regularOrderBook.Halt
+	}
 	allMatches := append(bulkMatches, regularMatches...)
 	return allMatches, nil
 }
@@ -309,7 +374,7 @@ func (e *AdvancedMatchingEngine) MatchNegotiatedAdvanced(orderBook *OrderBook) (
 		// Get reference price for fair value assessment
 		referencePrice, err := e.marketDataProvider.GetReferencePrice(sellOrder.SecurityID)
 		if err != nil {
-			referencePrice = 100.0 // Fallback
+			referencePrice = money.NewDecimalFromInt(100) // Fallback
 		}
 
 		// Find compatible buy orders
@@ -317,11 +382,30 @@ func (e *AdvancedMatchingEngine) MatchNegotiatedAdvanced(orderBook *OrderBook) (
 		
 		for _, buyOrder := range compatibleBuys {
 			// Calculate negotiated price based on multiple factors
-			negotiatedPrice := e.calculateNegotiatedPrice(sellOrder, buyOrder, referencePrice)
+			negotiatedPrice, err := e.calculateNegotiatedPrice(sellOrder, buyOrder, referencePrice)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate negotiated price: %w", err)
+			}
 			
 			// Check if both parties would accept this price
 			if e.wouldAcceptPrice(sellOrder, negotiatedPrice) && e.wouldAcceptPrice(buyOrder, negotiatedPrice) {
-				quantity := min(sellOrder.Quantity, buyOrder.Quantity)
+				if e.preventSelfTrade(orderBook, sellOrder, buyOrder) {
+					if sellOrder.Quantity == 0 {
+						break
+					}
+					continue
+				}
+				if orderBook.breaches(negotiatedPrice) {
+					return matches, nil
+				}
+				quantity := fillQuantity(sellOrder, buyOrder, sellOrder.Quantity)
+				if quantity == 0 {
+					continue
+				}
+				totalAmount, err := tradeAmount(negotiatedPrice, quantity, orderBook.Currency)
+				if err != nil {
+					return nil, err
+				}
 				
 				match := &MatchResult{
 					TradeID:           e.generateTradeID(),
@@ -332,7 +416,7 @@ func (e *AdvancedMatchingEngine) MatchNegotiatedAdvanced(orderBook *OrderBook) (
 					SecurityID:        orderBook.SecurityID,
 					SharesTraded:      quantity,
 					TradePrice:        negotiatedPrice,
-					TotalAmount:       float64(quantity) * negotiatedPrice,
+					TotalAmount:       totalAmount,
 					SettlementDate:    e.calculateSettlementDate(),
 					MatchingAlgorithm: string(NegotiatedTrading),
 				}
@@ -365,44 +449,54 @@ func (e *AdvancedMatchingEngine) calculateTimeWeight(timestamp time.Time) float6
 	return math.Min(2.0, 1.0+math.Log10(hoursSinceOrder))
 }
 
-func (e *AdvancedMatchingEngine) allocateToBuyers(buyers []*OrderBookEntry, totalShares int64) []*OrderBookEntry {
+func (e *AdvancedMatchingEngine) allocateToBuyers(orderBook *OrderBook, seller *OrderBookEntry, buyers []*OrderBookEntry, totalShares int64) []*OrderBookEntry {
 	// Simple FIFO allocation for now - could be enhanced with pro-rata
 	var allocations []*OrderBookEntry
 	remaining := totalShares
 
 	for _, buyer := range buyers {
-		if remaining <= 0 {
+		if remaining <= 0 || seller.Quantity == 0 {
 			break
 		}
+		if buyer.Quantity == 0 || e.preventSelfTrade(orderBook, seller, buyer) {
+			continue
+		}
 		
-		allocation := min(buyer.Quantity, remaining)
+		allocation := fillQuantity(seller, buyer, remaining)
 		if allocation > 0 {
 			buyerCopy := *buyer
 			buyerCopy.Quantity = allocation
 			allocations = append(allocations, &buyerCopy)
 			remaining -= allocation
+			seller.Quantity -= allocation
+			buyer.Quantity -= allocation
 		}
 	}
 
 	return allocations
 }
 
-func (e *AdvancedMatchingEngine) allocateToSellers(sellers []*OrderBookEntry, totalShares int64) []*OrderBookEntry {
+func (e *AdvancedMatchingEngine) allocateToSellers(orderBook *OrderBook, buyer *OrderBookEntry, sellers []*OrderBookEntry, totalShares int64) []*OrderBookEntry {
 	// Simple FIFO allocation for now - could be enhanced with pro-rata
 	var allocations []*OrderBookEntry
 	remaining := totalShares
 
 	for _, seller := range sellers {
-		if remaining <= 0 {
+		if remaining <= 0 || buyer.Quantity == 0 {
 			break
 		}
+		if seller.Quantity == 0 || e.preventSelfTrade(orderBook, seller, buyer) {
+			continue
+		}
 		
-		allocation := min(seller.Quantity, remaining)
+		allocation := fillQuantity(seller, buyer, remaining)
 		if allocation > 0 {
 			sellerCopy := *seller
 			sellerCopy.Quantity = allocation
 			allocations = append(allocations, &sellerCopy)
 			remaining -= allocation
+			seller.Quantity -= allocation
+			buyer.Quantity -= allocation
 		}
 	}
 
@@ -419,16 +513,19 @@ func (e *AdvancedMatchingEngine) matchBulkWithDiscounts(orderBook *OrderBook) ([
 	// Apply 0.5% discount to bulk trades
 	for _, match := range matches {
 		if match.SharesTraded >= 10000 { // Bulk threshold
-			discount := 0.005 // 0.5%
-			match.TradePrice *= (1 - discount)
-			match.TotalAmount = float64(match.SharesTraded) * match.TradePrice
+			if match.TradePrice, err = match.TradePrice.Mul(bulkDiscountFactor, money.RoundHalfEven); err != nil {
+				return nil, fmt.Errorf("failed to discount bulk trade: %w", err)
+			}
+			if match.TotalAmount, err = tradeAmount(match.TradePrice, match.SharesTraded, match.TotalAmount.Currency); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return matches, nil
 }
 
-func (e *AdvancedMatchingEngine) findCompatibleOrders(order *OrderBookEntry, candidates []*OrderBookEntry, referencePrice float64) []*OrderBookEntry {
+func (e *AdvancedMatchingEngine) findCompatibleOrders(order *OrderBookEntry, candidates []*OrderBookEntry, referencePrice money.Decimal) []*OrderBookEntry {
 	var compatible []*OrderBookEntry
 	
 	for _, candidate := range candidates {
@@ -438,11 +535,14 @@ func (e *AdvancedMatchingEngine) findCompatibleOrders(order *OrderBookEntry, can
 		}
 		
 		// Additional checks for negotiated trading
-		priceRange := referencePrice * 0.1 // 10% range
+		priceRange, err := referencePrice.Mul(negotiationPriceRange, money.RoundHalfEven)
+		if err != nil {
+			continue
+		}
 		
 		if order.Price != nil && candidate.Price != nil {
 			// Both have prices - check if they're within reasonable range
-			if math.Abs(*order.Price-*candidate.Price) <= priceRange {
+			if diff, err := order.Price.Sub(*candidate.Price); err == nil && !diff.Abs().GreaterThan(priceRange) {
 				compatible = append(compatible, candidate)
 			}
 		} else {
@@ -454,30 +554,46 @@ func (e *AdvancedMatchingEngine) findCompatibleOrders(order *OrderBookEntry, can
 	return compatible
 }
 
-func (e *AdvancedMatchingEngine) calculateNegotiatedPrice(sellOrder, buyOrder *OrderBookEntry, referencePrice float64) float64 {
+func (e *AdvancedMatchingEngine) calculateNegotiatedPrice(sellOrder, buyOrder *OrderBookEntry, referencePrice money.Decimal) (money.Decimal, error) {
 	// Sophisticated price calculation considering multiple factors
 	
-	var sellPrice, buyPrice float64
+	var sellPrice, buyPrice money.Decimal
+	var err error
 	
 	if sellOrder.Price != nil {
 		sellPrice = *sellOrder.Price
-	} else {
-		sellPrice = referencePrice * 1.02 // 2% above reference for market sells
+	} else if sellPrice, err = referencePrice.Mul(money.MustParseDecimal("1.02"), money.RoundHalfEven); err != nil { // 2% above reference for market sells
+		return money.Zero, err
 	}
 	
 	if buyOrder.Price != nil {
 		buyPrice = *buyOrder.Price
-	} else {
-		buyPrice = referencePrice * 0.98 // 2% below reference for market buys
+	} else if buyPrice, err = referencePrice.Mul(money.MustParseDecimal("0.98"), money.RoundHalfEven); err != nil { // 2% below reference for market buys
+		return money.Zero, err
 	}
 	
 	// Weight based on order sizes
-	sellWeight := float64(sellOrder.Quantity)
-	buyWeight := float64(buyOrder.Quantity)
-	totalWeight := sellWeight + buyWeight
+	totalWeight := sellOrder.Quantity + buyOrder.Quantity
 	
 	// Weighted average with slight bias toward the larger order
-	weightedPrice := (sellPrice*sellWeight + buyPrice*buyWeight) / totalWeight
+	weightedPrice := sellPrice
+	if totalWeight > 0 {
+		sellValue, err := sellPrice.MulInt(sellOrder.Quantity)
+		if err != nil {
+			return money.Zero, err
+		}
+		buyValue, err := buyPrice.MulInt(buyOrder.Quantity)
+		if err != nil {
+			return money.Zero, err
+		}
+		totalValue, err := sellValue.Add(buyValue)
+		if err != nil {
+			return money.Zero, err
+		}
+		if weightedPrice, err = totalValue.DivInt(totalWeight, money.RoundHalfEven); err != nil {
+			return money.Zero, err
+		}
+	}
 	
 	// Apply time pressure factor (older orders get slight price preference)
 	sellAge := time.Since(sellOrder.Timestamp).Hours()
@@ -485,16 +601,16 @@ func (e *AdvancedMatchingEngine) calculateNegotiatedPrice(sellOrder, buyOrder *O
 	
 	if sellAge > buyAge {
 		// Favor seller slightly
-		weightedPrice = weightedPrice * 1.001
+		return weightedPrice.Mul(money.MustParseDecimal("1.001"), money.RoundHalfEven)
 	} else if buyAge > sellAge {
 		// Favor buyer slightly
-		weightedPrice = weightedPrice * 0.999
+		return weightedPrice.Mul(money.MustParseDecimal("0.999"), money.RoundHalfEven)
 	}
 	
-	return weightedPrice
+	return weightedPrice, nil
 }
 
-func (e *AdvancedMatchingEngine) wouldAcceptPrice(order *OrderBookEntry, price float64) bool {
+func (e *AdvancedMatchingEngine) wouldAcceptPrice(order *OrderBookEntry, price money.Decimal) bool {
 	if order.Price == nil {
 		// Market orders accept any reasonable price
 		return true
@@ -502,9 +618,9 @@ func (e *AdvancedMatchingEngine) wouldAcceptPrice(order *OrderBookEntry, price f
 	
 	if order.OrderType == "sell" {
 		// Seller accepts if price >= their ask
-		return price >= *order.Price
+		return !price.LessThan(*order.Price)
 	} else {
 		// Buyer accepts if price <= their bid
-		return price <= *order.Price
+		return !price.GreaterThan(*order.Price)
 	}
 }
\ No newline at end of file
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/calendar"
 )
 
 // TradeStatus represents the current status of a trade
@@ -20,6 +22,7 @@ const (
 	TradeStatusSettled              TradeStatus = "settled"
 	TradeStatusFailed               TradeStatus = "failed"
 	TradeStatusCancelled            TradeStatus = "cancelled"
+	TradeStatusBusted               TradeStatus = "busted"
 )
 
 // SettlementStage represents the current stage of settlement
@@ -38,8 +41,7 @@ const (
 
 // PaymentInfo holds payment details
 type PaymentInfo struct {
-	Amount        float64   `json:"amount"`
-	Currency      string    `json:"currency"`
+	Amount        money.Money `json:"amount"`
 	PaymentMethod string    `json:"paymentMethod"`
 	TransactionID string    `json:"transactionId"`
 	ReceivedAt    time.Time `json:"receivedAt"`
@@ -68,10 +70,11 @@ type TradeAggregate struct {
 	
 	// Trade details
 	SharesTraded    int64   `json:"sharesTraded"`
-	TradePrice      float64 `json:"tradePrice"`
-	TotalAmount     float64 `json:"totalAmount"`
-	Fees            float64 `json:"fees"`
-	Taxes           float64 `json:"taxes"`
+	TradePrice      money.Decimal `json:"tradePrice"`
+	TotalAmount     money.Money   `json:"totalAmount"`
+	Fees            money.Money   `json:"fees"`
+	FeeBreakdown    *FeeBreakdown `json:"feeBreakdown,omitempty"`
+	Taxes           money.Money   `json:"taxes"`
 	
 	// Settlement information
 	SettlementDate  time.Time `json:"settlementDate"`
@@ -103,6 +106,12 @@ type TradeAggregate struct {
 	CancellationReason string `json:"cancellationReason,omitempty"`
 	CancelledBy        string `json:"cancelledBy,omitempty"`
 	RecoveryAction     string `json:"recoveryAction,omitempty"`
+	
+	// Busts and corrections
+	PendingAdjustment *TradeAdjustment `json:"pendingAdjustment,omitempty"`
+	BustedAt          *time.Time       `json:"bustedAt,omitempty"`
+	BustReason        string           `json:"bustReason,omitempty"`
+	CorrectedAt       *time.Time       `json:"correctedAt,omitempty"`
 }
 
 // NewTradeAggregate creates a new trade aggregate
@@ -113,13 +122,13 @@ func NewTradeAggregate(tradeID string) *TradeAggregate {
 		SettlementStage:   SettlementStageNone,
 		BuyerConfirmed:    false,
 		SellerConfirmed:   false,
-		Fees:              0,
-		Taxes:             0,
+		Fees:              money.ZeroIn(money.DefaultCurrency),
+		Taxes:             money.ZeroIn(money.DefaultCurrency),
 	}
 }
 
 // MatchTrade creates a new trade match
-func (t *TradeAggregate) MatchTrade(listingID string, bidID *string, buyerID, sellerID, securityID string, sharesTraded int64, tradePrice, totalAmount float64, settlementDate time.Time, matchingAlgorithm string) error {
+func (t *TradeAggregate) MatchTrade(listingID string, bidID *string, buyerID, sellerID, securityID string, sharesTraded int64, tradePrice money.Decimal, totalAmount money.Money, settlementDate time.Time, matchingAlgorithm string) error {
 	if t.Version > 0 {
 		return fmt.Errorf("trade already exists")
 	}
@@ -129,14 +138,22 @@ func (t *TradeAggregate) MatchTrade(listingID string, bidID *string, buyerID, se
 		return fmt.Errorf("shares traded must be greater than zero")
 	}
 
-	if tradePrice <= 0 {
+	if !tradePrice.IsPositive() {
 		return fmt.Errorf("trade price must be greater than zero")
 	}
 
-	if totalAmount <= 0 {
+	if !totalAmount.IsPositive() {
 		return fmt.Errorf("total amount must be greater than zero")
 	}
 
+	expectedAmount, err := tradePrice.MulInt(sharesTraded)
+	if err != nil {
+		return fmt.Errorf("failed to value %d shares at %s: %w", sharesTraded, tradePrice, err)
+	}
+	if !totalAmount.Amount.Equal(expectedAmount) {
+		return fmt.Errorf("total amount %s does not equal %d shares at %s", totalAmount, sharesTraded, tradePrice)
+	}
+
 	if settlementDate.Before(time.Now()) {
 		return fmt.Errorf("settlement date cannot be in the past")
 	}
@@ -185,20 +202,24 @@ func (t *TradeAggregate) InitiateSettlement(escrowAccountID, initiatedBy string)
 }
 
 // ReceivePayment records payment received in escrow
-func (t *TradeAggregate) ReceivePayment(amount float64, currency, paymentMethod, transactionID string) error {
+func (t *TradeAggregate) ReceivePayment(amount money.Money, paymentMethod, transactionID string) error {
 	if t.Status != TradeStatusSettlementInitiated {
 		return fmt.Errorf("can only receive payment for trades with initiated settlement")
 	}
 
-	if amount <= 0 {
+	if !amount.IsPositive() {
 		return fmt.Errorf("payment amount must be greater than zero")
 	}
 
-	if amount < t.TotalAmount {
-		return fmt.Errorf("payment amount %.2f is less than required %.2f", amount, t.TotalAmount)
+	cmp, err := amount.Cmp(t.TotalAmount)
+	if err != nil {
+		return fmt.Errorf("payment must be made in the trade currency: %w", err)
+	}
+	if cmp < 0 {
+		return fmt.Errorf("payment amount %s is less than required %s", amount, t.TotalAmount)
 	}
 
-	event := NewPaymentReceived(t.ID, amount, currency, paymentMethod, transactionID, time.Now())
+	event := NewPaymentReceived(t.ID, amount, paymentMethod, transactionID, time.Now())
 	t.AddEvent(event)
 	return t.ApplyEvent(event)
 }
@@ -226,16 +247,32 @@ func (t *TradeAggregate) TransferShares(sharesCount int64, fromOwner, toOwner, t
 	return t.ApplyEvent(event)
 }
 
-// SettleTrade completes the trade settlement
-func (t *TradeAggregate) SettleTrade(finalAmount, fees, taxes float64, settlementMethod string) error {
+// SettleTrade completes the trade settlement. The fees charged are the total
+// of the breakdown; a nil breakdown charges none.
+func (t *TradeAggregate) SettleTrade(finalAmount money.Money, fees *FeeBreakdown, taxes money.Money, settlementMethod string) error {
 	if t.Status != TradeStatusSharesTransferred {
 		return fmt.Errorf("can only settle after shares are transferred")
 	}
 
-	if finalAmount <= 0 {
+	if !finalAmount.IsPositive() {
 		return fmt.Errorf("final amount must be greater than zero")
 	}
 
+	for _, amount := range []money.Money{finalAmount, taxes} {
+		if amount.Currency != t.TotalAmount.Currency {
+			return fmt.Errorf("settlement amounts must be in the trade currency %s, got %s", t.TotalAmount.Currency, amount.Currency)
+		}
+	}
+
+	if fees == nil {
+		fees = &FeeBreakdown{Items: []FeeItem{}}
+	}
+	for _, item := range fees.Items {
+		if item.Amount.IsNegative() {
+			return fmt.Errorf("%s fee of %s cannot be negative", item.Type, item.PayerID)
+		}
+	}
+
 	event := NewTradeSettled(t.ID, time.Now(), finalAmount, fees, taxes, settlementMethod)
 	t.AddEvent(event)
 	return t.ApplyEvent(event)
@@ -243,7 +280,7 @@ func (t *TradeAggregate) SettleTrade(finalAmount, fees, taxes float64, settlemen
 
 // FailTrade marks the trade as failed
 func (t *TradeAggregate) FailTrade(failureReason, failureStage, recoveryAction string) error {
-	if t.Status == TradeStatusSettled || t.Status == TradeStatusCancelled {
+	if t.Status == TradeStatusSettled || t.Status == TradeStatusCancelled || t.Status == TradeStatusBusted {
 		return fmt.Errorf("cannot fail already completed or cancelled trade")
 	}
 
@@ -258,8 +295,8 @@ func (t *TradeAggregate) FailTrade(failureReason, failureStage, recoveryAction s
 
 // CancelTrade cancels the trade before settlement
 func (t *TradeAggregate) CancelTrade(cancellationReason, cancelledBy string) error {
-	if t.Status == TradeStatusSettled || t.Status == TradeStatusFailed {
-		return fmt.Errorf("cannot cancel already completed or failed trade")
+	if t.Status == TradeStatusSettled || t.Status == TradeStatusFailed || t.Status == TradeStatusBusted {
+		return fmt.Errorf("cannot cancel already completed, failed or busted trade")
 	}
 
 	if t.Status == TradeStatusPaymentReceived || t.Status == TradeStatusSharesTransferred {
@@ -294,6 +331,16 @@ func (t *TradeAggregate) ApplyEvent(event events.DomainEvent) error {
 		return t.applyTradeFailed(e)
 	case *TradeCancelled:
 		return t.applyTradeCancelled(e)
+	case *TradeAdjustmentRequested:
+		return t.applyTradeAdjustmentRequested(e)
+	case *TradeAdjustmentApproved:
+		return t.applyTradeAdjustmentApproved(e)
+	case *TradeAdjustmentRejected:
+		return t.applyTradeAdjustmentRejected(e)
+	case *TradeBusted:
+		return t.applyTradeBusted(e)
+	case *TradeCorrected:
+		return t.applyTradeCorrected(e)
 	default:
 		return fmt.Errorf("unknown event type: %T", event)
 	}
@@ -320,7 +367,9 @@ func (t *TradeAggregate) applyTradeMatched(event *TradeMatched) error {
 	t.SecurityID = event.SecurityID
 	t.SharesTraded = event.SharesTraded
 	t.TradePrice = event.TradePrice
-	t.TotalAmount = event.TotalAmount
+	t.TotalAmount = money.New(event.TotalAmount, event.Currency)
+	t.Fees = money.ZeroIn(t.TotalAmount.Currency)
+	t.Taxes = money.ZeroIn(t.TotalAmount.Currency)
 	t.SettlementDate = event.SettlementDate
 	t.MatchingAlgorithm = event.MatchingAlgorithm
 	t.Status = TradeStatusMatched
@@ -358,8 +407,7 @@ func (t *TradeAggregate) applyPaymentReceived(event *PaymentReceived) error {
 	t.Status = TradeStatusPaymentReceived
 	t.SettlementStage = SettlementStagePaymentReceived
 	t.PaymentInfo = &PaymentInfo{
-		Amount:        event.Amount,
-		Currency:      event.Currency,
+		Amount:        money.New(event.Amount, event.Currency),
 		PaymentMethod: event.PaymentMethod,
 		TransactionID: event.TransactionID,
 		ReceivedAt:    event.ReceivedAt,
@@ -389,12 +437,15 @@ func (t *TradeAggregate) applyTradeSettled(event *TradeSettled) error {
 	t.Status = TradeStatusSettled
 	t.SettlementStage = SettlementStageCompleted
 	t.SettledAt = &event.SettledAt
-	t.Fees = event.Fees
-	t.Taxes = event.Taxes
-	// Final amount might differ from total amount due to fees/taxes
-	if event.FinalAmount != t.TotalAmount {
-		t.TotalAmount = event.FinalAmount
+	currency := event.Currency
+	if currency == "" {
+		currency = t.TotalAmount.Currency
 	}
+	t.Fees = money.New(event.Fees, currency)
+	t.FeeBreakdown = event.FeeBreakdown
+	t.Taxes = money.New(event.Taxes, currency)
+	// Final amount might differ from total amount due to fees/taxes
+	t.TotalAmount = money.New(event.FinalAmount, currency)
 	
 	t.IncrementVersion()
 	return nil
@@ -426,7 +477,13 @@ func (t *TradeAggregate) applyTradeCancelled(event *TradeCancelled) error {
 
 // IsCompleted returns true if the trade is in a final state
 func (t *TradeAggregate) IsCompleted() bool {
-	return t.Status == TradeStatusSettled || t.Status == TradeStatusFailed || t.Status == TradeStatusCancelled
+	return t.Status == TradeStatusSettled || t.IsVoid()
+}
+
+// IsVoid returns true if the trade failed, was cancelled or was busted, and so
+// never stood
+func (t *TradeAggregate) IsVoid() bool {
+	return t.Status == TradeStatusFailed || t.Status == TradeStatusCancelled || t.Status == TradeStatusBusted
 }
 
 // IsSettled returns true if the trade is successfully settled
@@ -441,34 +498,37 @@ func (t *TradeAggregate) CanBeConfirmed() bool {
 
 // CanBeCancelled returns true if the trade can be cancelled
 func (t *TradeAggregate) CanBeCancelled() bool {
-	return t.Status != TradeStatusSettled && t.Status != TradeStatusFailed && 
+	return t.Status != TradeStatusSettled && t.Status != TradeStatusFailed && t.Status != TradeStatusBusted &&
 		   t.Status != TradeStatusPaymentReceived && t.Status != TradeStatusSharesTransferred
 }
 
 // GetNetAmount returns the net amount after fees and taxes
-func (t *TradeAggregate) GetNetAmount() float64 {
-	return t.TotalAmount - t.Fees - t.Taxes
+func (t *TradeAggregate) GetNetAmount() (money.Money, error) {
+	net, err := t.TotalAmount.Amount.Sub(t.Fees.Amount)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if net, err = net.Sub(t.Taxes.Amount); err != nil {
+		return money.Money{}, err
+	}
+	return money.New(net, t.TotalAmount.Currency), nil
 }
 
-// GetDaysToSettlement returns the number of days until settlement
-func (t *TradeAggregate) GetDaysToSettlement() int {
+// GetDaysToSettlement returns the number of business days until settlement
+func (t *TradeAggregate) GetDaysToSettlement(cal *calendar.Calendar) int {
 	if t.IsCompleted() {
 		return 0
 	}
-	
-	days := int(time.Until(t.SettlementDate).Hours() / 24)
-	if days < 0 {
-		return 0
-	}
-	return days
+	return cal.BusinessDaysBetween(time.Now(), t.SettlementDate)
 }
 
-// IsOverdue returns true if the settlement date has passed
-func (t *TradeAggregate) IsOverdue() bool {
+// IsOverdue returns true if business closed on the settlement date without
+// the trade settling
+func (t *TradeAggregate) IsOverdue(cal *calendar.Calendar) bool {
 	if t.IsCompleted() {
 		return false
 	}
-	return time.Now().After(t.SettlementDate)
+	return time.Now().After(cal.SettlementDeadline(t.SettlementDate))
 }
 
 // GetProgressPercentage returns the settlement progress as a percentage
@@ -488,7 +548,7 @@ func (t *TradeAggregate) GetProgressPercentage() float64 {
 		return 90.0
 	case TradeStatusSettled:
 		return 100.0
-	case TradeStatusFailed, TradeStatusCancelled:
+	case TradeStatusFailed, TradeStatusCancelled, TradeStatusBusted:
 		return 0.0
 	default:
 		return 0.0
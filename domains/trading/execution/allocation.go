@@ -0,0 +1,161 @@
+package execution
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// AllocationPolicy divides the shares the larger side of a pro-rata auction
+// trades among that side's orders. Orders are given in time priority and an
+// allocation is returned for each, in the same order. No allocation exceeds
+// its order's quantity, and the allocations add up to the quantity whenever
+// the orders hold that many shares.
+type AllocationPolicy interface {
+	Allocate(orders []*OrderBookEntry, quantity int64) []int64
+}
+
+// ProRataFIFO allocates in proportion to order size, rounding down, and
+// hands the shares left over by rounding to orders in time priority
+type ProRataFIFO struct{}
+
+// Allocate implements AllocationPolicy
+func (ProRataFIFO) Allocate(orders []*OrderBookEntry, quantity int64) []int64 {
+	capacities := orderQuantities(orders)
+	allocations := proRata(capacities, quantity)
+	allocateInTimePriority(capacities, allocations, quantity-sum(allocations), 0)
+	return allocations
+}
+
+// ProRataMinimumLot allocates pro rata but drops allocations smaller than the
+// minimum lot. The shares left over go to orders in time priority, again in
+// pieces of at least a lot where the orders leave room for one.
+type ProRataMinimumLot struct {
+	MinimumLot int64
+}
+
+// Allocate implements AllocationPolicy
+func (p ProRataMinimumLot) Allocate(orders []*OrderBookEntry, quantity int64) []int64 {
+	capacities := orderQuantities(orders)
+	allocations := proRata(capacities, quantity)
+	for i, allocation := range allocations {
+		if allocation < p.MinimumLot {
+			allocations[i] = 0
+		}
+	}
+
+	remaining := allocateInTimePriority(capacities, allocations, quantity-sum(allocations), p.MinimumLot)
+	allocateInTimePriority(capacities, allocations, remaining, 0)
+	return allocations
+}
+
+// SizePriority fills the largest orders first, and orders of the same size
+// in time priority
+type SizePriority struct{}
+
+// Allocate implements AllocationPolicy
+func (SizePriority) Allocate(orders []*OrderBookEntry, quantity int64) []int64 {
+	bySize := make([]int, len(orders))
+	for i := range bySize {
+		bySize[i] = i
+	}
+	sort.SliceStable(bySize, func(i, j int) bool {
+		return orders[bySize[i]].Quantity > orders[bySize[j]].Quantity
+	})
+
+	allocations := make([]int64, len(orders))
+	for _, i := range bySize {
+		allocations[i] = min(quantity, orders[i].Quantity)
+		quantity -= allocations[i]
+	}
+	return allocations
+}
+
+// TopOrderProRata fills the first order in time priority, up to MaxShares if
+// set, and allocates the rest pro rata with the remainder in time priority.
+// The top order takes part in the pro-rata allocation with whatever it has
+// left.
+type TopOrderProRata struct {
+	MaxShares int64
+}
+
+// Allocate implements AllocationPolicy
+func (p TopOrderProRata) Allocate(orders []*OrderBookEntry, quantity int64) []int64 {
+	capacities := orderQuantities(orders)
+	if len(capacities) == 0 {
+		return []int64{}
+	}
+
+	top := min(quantity, capacities[0])
+	if p.MaxShares > 0 {
+		top = min(top, p.MaxShares)
+	}
+	capacities[0] -= top
+
+	allocations := proRata(capacities, quantity-top)
+	allocateInTimePriority(capacities, allocations, quantity-top-sum(allocations), 0)
+	allocations[0] += top
+	return allocations
+}
+
+// proRata allocates each order its share of the quantity in proportion to
+// its capacity, rounded down. Nothing is allocated beyond the orders'
+// capacity.
+func proRata(capacities []int64, quantity int64) []int64 {
+	allocations := make([]int64, len(capacities))
+	total := sum(capacities)
+	if total == 0 || quantity <= 0 {
+		return allocations
+	}
+	quantity = min(quantity, total)
+
+	for i, capacity := range capacities {
+		// capacity * quantity / total in 128 bits; the quotient is at most
+		// quantity, so it cannot overflow
+		hi, lo := bits.Mul64(uint64(capacity), uint64(quantity))
+		share, _ := bits.Div64(hi, lo, uint64(total))
+		allocations[i] = int64(share)
+	}
+	return allocations
+}
+
+// allocateInTimePriority gives the remaining shares to orders in time
+// priority, each up to its capacity. Orders with no allocation yet are
+// passed over when they would get fewer than minimum shares. It returns the
+// shares it could not allocate.
+func allocateInTimePriority(capacities, allocations []int64, remaining, minimum int64) int64 {
+	for i, capacity := range capacities {
+		if remaining <= 0 {
+			break
+		}
+		extra := min(remaining, capacity-allocations[i])
+		if extra <= 0 || (allocations[i] == 0 && extra < minimum) {
+			continue
+		}
+		allocations[i] += extra
+		remaining -= extra
+	}
+	return remaining
+}
+
+// sortByTime puts orders in time priority, oldest first
+func sortByTime(orders []*OrderBookEntry) {
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].Timestamp.Before(orders[j].Timestamp)
+	})
+}
+
+func orderQuantities(orders []*OrderBookEntry) []int64 {
+	quantities := make([]int64, len(orders))
+	for i, order := range orders {
+		quantities[i] = order.Quantity
+	}
+	return quantities
+}
+
+func sum(values []int64) int64 {
+	var total int64
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
@@ -0,0 +1,126 @@
+package execution
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/testutil"
+)
+
+var allocationPolicies = map[string]AllocationPolicy{
+	"pro-rata FIFO":      ProRataFIFO{},
+	"minimum lot":        ProRataMinimumLot{MinimumLot: 10},
+	"size priority":      SizePriority{},
+	"top order pro-rata": TopOrderProRata{MaxShares: 25},
+}
+
+// randomAuctionBook builds a book of limit and market orders without
+// conditions, each for a different user. The same seed always builds the
+// same book.
+func randomAuctionBook(seed int64) *OrderBook {
+	rng := rand.New(rand.NewSource(seed))
+	var entries []*OrderBookEntry
+
+	for i := 0; i < 1+rng.Intn(10); i++ {
+		for _, side := range []string{"sell", "buy"} {
+			var price *money.Decimal
+			if rng.Intn(10) > 0 {
+				price = decimalPtr(fmt.Sprintf("%d", 9+rng.Intn(3)))
+			}
+			at := testutil.TestTime.Add(time.Duration(rng.Intn(60)) * time.Second)
+			entries = append(entries, newBookEntry(side, fmt.Sprintf("%s-%d", side, i), 1+rng.Int63n(200), price, at))
+		}
+	}
+
+	return newAuctionBook(entries...)
+}
+
+func TestAllocationPolicies_DivideQuantity(t *testing.T) {
+	// Arrange
+	orders := []*OrderBookEntry{
+		newBookEntry("sell", "ask-1", 30, nil, testutil.TestTime),
+		newBookEntry("sell", "ask-2", 50, nil, testutil.TestTime.Add(time.Second)),
+		newBookEntry("sell", "ask-3", 20, nil, testutil.TestTime.Add(2*time.Second)),
+	}
+
+	// Act & Assert
+	testutil.AssertEqual(t, []int64{4, 5, 2}, ProRataFIFO{}.Allocate(orders, 11), "Rounding remainder should go to the oldest order")
+	testutil.AssertEqual(t, []int64{6, 5, 0}, ProRataMinimumLot{MinimumLot: 3}.Allocate(orders, 11), "Allocations under a lot should be dropped and reallocated")
+	testutil.AssertEqual(t, []int64{0, 11, 0}, SizePriority{}.Allocate(orders, 11), "Largest order should be filled first")
+	testutil.AssertEqual(t, []int64{7, 3, 1}, TopOrderProRata{MaxShares: 5}.Allocate(orders, 11), "Top order should be filled up to its cap before pro-rata")
+}
+
+func TestAllocationPolicies_AllocateExactlyWithoutOverfilling(t *testing.T) {
+	for name, policy := range allocationPolicies {
+		for seed := int64(1); seed <= 500; seed++ {
+			rng := rand.New(rand.NewSource(seed))
+			orders := make([]*OrderBookEntry, 1+rng.Intn(10))
+			var total int64
+			for i := range orders {
+				orders[i] = newBookEntry("sell", fmt.Sprintf("ask-%d", i), 1+rng.Int63n(200), nil, testutil.TestTime)
+				total += orders[i].Quantity
+			}
+			quantity := rng.Int63n(total + 50)
+
+			allocations := policy.Allocate(orders, quantity)
+
+			testutil.AssertEqual(t, min(quantity, total), sum(allocations), fmt.Sprintf("%s seed %d should allocate every share it can", name, seed))
+			for i, allocation := range allocations {
+				if allocation < 0 || allocation > orders[i].Quantity {
+					t.Fatalf("%s seed %d: allocated %d shares to an order for %d", name, seed, allocation, orders[i].Quantity)
+				}
+			}
+		}
+	}
+}
+
+func TestMatchWithProRata_TradesTradeableQuantityWithoutOverfilling(t *testing.T) {
+	for name, policy := range allocationPolicies {
+		engine := &AdvancedMatchingEngine{OrderMatchingEngine: &OrderMatchingEngine{}}
+		engine.SetAllocationPolicy(policy)
+
+		for seed := int64(1); seed <= 500; seed++ {
+			clearing, err := CalculateAuctionClearing(randomAuctionBook(seed), nil)
+			if err != nil || clearing == nil {
+				continue
+			}
+
+			quantities := make(map[string]int64)
+			var supply, demand int64
+			book := randomAuctionBook(seed)
+			for _, order := range book.GetSellOrders() {
+				quantities[order.OrderID()] = order.Quantity
+				if order.Price == nil || !order.Price.GreaterThan(clearing.Price) {
+					supply += order.Quantity
+				}
+			}
+			for _, order := range book.GetBuyOrders() {
+				quantities[order.OrderID()] = order.Quantity
+				if order.Price == nil || !order.Price.LessThan(clearing.Price) {
+					demand += order.Quantity
+				}
+			}
+
+			matches, err := engine.MatchWithProRata(randomAuctionBook(seed), clearing.Price)
+			testutil.AssertNoError(t, err, "Pro-rata matching should succeed")
+
+			var traded int64
+			filled := make(map[string]int64)
+			for _, match := range matches {
+				traded += match.SharesTraded
+				filled[match.ListingID] += match.SharesTraded
+				filled[*match.BidID] += match.SharesTraded
+			}
+
+			testutil.AssertEqual(t, min(supply, demand), traded, fmt.Sprintf("%s seed %d should trade the tradeable quantity", name, seed))
+			for orderID, shares := range filled {
+				if shares > quantities[orderID] {
+					t.Fatalf("%s seed %d: order %s filled %d of %d shares", name, seed, orderID, shares, quantities[orderID])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package execution
+
+import (
+	"fmt"
+	"sort"
+
+	"securities-marketplace/domains/shared/money"
+)
+
+// AuctionClearing is the price a call auction clears at and the order
+// interest around it
+type AuctionClearing struct {
+	SecurityID       string         `json:"securityId"`
+	Price            money.Decimal  `json:"price"`
+	ExecutableVolume int64          `json:"executableVolume"`
+	Demand           int64          `json:"demand"`    // Buy quantity willing to trade at Price
+	Supply           int64          `json:"supply"`    // Sell quantity willing to trade at Price
+	Imbalance        int64          `json:"imbalance"` // Demand - Supply; positive means surplus buy interest
+	ReferencePrice   *money.Decimal `json:"referencePrice,omitempty"`
+}
+
+// ImbalanceSide returns "buy" or "sell" for the side left with unexecuted
+// interest at the clearing price, or "" when the auction is balanced
+func (c *AuctionClearing) ImbalanceSide() string {
+	switch {
+	case c.Imbalance > 0:
+		return "buy"
+	case c.Imbalance < 0:
+		return "sell"
+	default:
+		return ""
+	}
+}
+
+// auctionLevel is the cumulative interest at one candidate price
+type auctionLevel struct {
+	price  money.Decimal
+	demand int64
+	supply int64
+}
+
+func (l auctionLevel) volume() int64 {
+	return min(l.demand, l.supply)
+}
+
+func (l auctionLevel) imbalance() int64 {
+	return l.demand - l.supply
+}
+
+// CalculateAuctionClearing finds the price that maximizes executed volume for
+// a call auction. Candidate prices are the limit prices in the book plus the
+// reference price when it lies between them. Ties are broken by:
+//
+//  1. the smallest imbalance between demand and supply
+//  2. market pressure: the highest price if every remaining candidate has
+//     surplus demand, the lowest if every one has surplus supply
+//  3. the price closest to the reference price
+//
+// If a tie remains, or there is no reference price, the lowest remaining
+// price is used. A nil clearing is returned when the book does not cross.
+func CalculateAuctionClearing(orderBook *OrderBook, referencePrice *money.Decimal) (*AuctionClearing, error) {
+	levels, err := auctionLevels(orderBook, referencePrice)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Maximum executable volume
+	var maxVolume int64
+	for _, level := range levels {
+		maxVolume = max(maxVolume, level.volume())
+	}
+	if maxVolume == 0 {
+		return nil, nil
+	}
+	candidates := filterLevels(levels, func(l auctionLevel) bool { return l.volume() == maxVolume })
+
+	// 2. Minimum imbalance
+	minImbalance := absInt64(candidates[0].imbalance())
+	for _, level := range candidates {
+		minImbalance = min(minImbalance, absInt64(level.imbalance()))
+	}
+	candidates = filterLevels(candidates, func(l auctionLevel) bool { return absInt64(l.imbalance()) == minImbalance })
+
+	// 3. Market pressure
+	chosen := candidates[0]
+	switch {
+	case len(candidates) == 1:
+	case allLevels(candidates, func(l auctionLevel) bool { return l.imbalance() > 0 }):
+		chosen = candidates[len(candidates)-1]
+	case allLevels(candidates, func(l auctionLevel) bool { return l.imbalance() < 0 }):
+		chosen = candidates[0]
+	case referencePrice != nil:
+		// 4. Closest to the reference price
+		closest := priceDistance(candidates[0].price, *referencePrice)
+		for _, level := range candidates[1:] {
+			if distance := priceDistance(level.price, *referencePrice); distance.LessThan(closest) {
+				chosen, closest = level, distance
+			}
+		}
+	}
+
+	return &AuctionClearing{
+		SecurityID:       orderBook.SecurityID,
+		Price:            chosen.price,
+		ExecutableVolume: chosen.volume(),
+		Demand:           chosen.demand,
+		Supply:           chosen.supply,
+		Imbalance:        chosen.imbalance(),
+		ReferencePrice:   referencePrice,
+	}, nil
+}
+
+// auctionLevels builds the cumulative demand and supply curves, ordered by
+// ascending price. Market orders count towards every price.
+func auctionLevels(orderBook *OrderBook, referencePrice *money.Decimal) ([]auctionLevel, error) {
+	var marketDemand, marketSupply int64
+	var buys, sells []*OrderBookEntry
+	prices := make(map[money.Decimal]bool)
+
+	for _, order := range orderBook.GetBuyOrders() {
+		if order.Quantity <= 0 {
+			continue
+		}
+		if order.Price == nil {
+			marketDemand += order.Quantity
+			continue
+		}
+		buys = append(buys, order)
+		prices[*order.Price] = true
+	}
+	for _, order := range orderBook.GetSellOrders() {
+		if order.Quantity <= 0 {
+			continue
+		}
+		if order.Price == nil {
+			marketSupply += order.Quantity
+			continue
+		}
+		sells = append(sells, order)
+		prices[*order.Price] = true
+	}
+
+	candidates := make([]money.Decimal, 0, len(prices)+1)
+	for price := range prices {
+		candidates = append(candidates, price)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].LessThan(candidates[j]) })
+
+	if referencePrice != nil && !prices[*referencePrice] {
+		switch {
+		case len(candidates) == 0:
+			candidates = append(candidates, *referencePrice)
+		case candidates[0].LessThan(*referencePrice) && referencePrice.LessThan(candidates[len(candidates)-1]):
+			index := sort.Search(len(candidates), func(i int) bool { return referencePrice.LessThan(candidates[i]) })
+			candidates = append(candidates[:index], append([]money.Decimal{*referencePrice}, candidates[index:]...)...)
+		}
+	}
+
+	if len(candidates) == 0 {
+		if marketDemand > 0 && marketSupply > 0 {
+			return nil, fmt.Errorf("only market orders in the book and no reference price for security %s", orderBook.SecurityID)
+		}
+		return nil, nil
+	}
+
+	// Supply at a price is every sell limited at or below it; demand is every
+	// buy limited at or above it
+	sort.Slice(sells, func(i, j int) bool { return sells[i].Price.LessThan(*sells[j].Price) })
+	sort.Slice(buys, func(i, j int) bool { return buys[i].Price.GreaterThan(*buys[j].Price) })
+
+	levels := make([]auctionLevel, len(candidates))
+	supply, next := marketSupply, 0
+	for i, price := range candidates {
+		for next < len(sells) && !sells[next].Price.GreaterThan(price) {
+			supply += sells[next].Quantity
+			next++
+		}
+		levels[i] = auctionLevel{price: price, supply: supply}
+	}
+	demand, next := marketDemand, 0
+	for i := len(candidates) - 1; i >= 0; i-- {
+		for next < len(buys) && !buys[next].Price.LessThan(candidates[i]) {
+			demand += buys[next].Quantity
+			next++
+		}
+		levels[i].demand = demand
+	}
+
+	return levels, nil
+}
+
+func filterLevels(levels []auctionLevel, keep func(auctionLevel) bool) []auctionLevel {
+	var filtered []auctionLevel
+	for _, level := range levels {
+		if keep(level) {
+			filtered = append(filtered, level)
+		}
+	}
+	return filtered
+}
+
+func allLevels(levels []auctionLevel, test func(auctionLevel) bool) bool {
+	for _, level := range levels {
+		if !test(level) {
+			return false
+		}
+	}
+	return true
+}
+
+func absInt64(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// priceDistance returns how far apart two prices are. Prices are positive, so
+// their difference always fits in a decimal.
+func priceDistance(a, b money.Decimal) money.Decimal {
+	diff, _ := a.Sub(b)
+	return diff.Abs()
+}
@@ -0,0 +1,160 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"time"
+
+	"securities-marketplace/domains/securities"
+)
+
+// AuctionScheduleSource provides the call auction schedule of every security
+// traded in periodic auctions
+type AuctionScheduleSource interface {
+	GetAuctionSchedules() (map[string]securities.AuctionSchedule, error)
+}
+
+// AuctionMarket is the part of the execution service the auction scheduler
+// drives
+type AuctionMarket interface {
+	PublishIndicativeAuctionPrice(securityID string) (*AuctionClearing, error)
+	RunMatching(securityID string, algorithm MatchingAlgorithm) ([]*TradeAggregate, error)
+}
+
+// scheduledAuction is the next auction of one security
+type scheduledAuction struct {
+	schedule    securities.AuctionSchedule
+	scheduledAt time.Time // Nominal auction time
+	endsAt      time.Time // scheduledAt plus random jitter
+}
+
+// AuctionScheduler runs periodic call auctions. Between auctions orders are
+// only collected. During the indicative phase the indicative price is
+// published on every tick, and once the randomized end time passes the
+// auction executes at a uniform price.
+type AuctionScheduler struct {
+	market          AuctionMarket
+	schedules       AuctionScheduleSource
+	tickInterval    time.Duration
+	refreshInterval time.Duration
+	jitter          func(max time.Duration) time.Duration
+
+	auctions      map[string]*scheduledAuction
+	lastRefreshed time.Time
+}
+
+// NewAuctionScheduler creates a new auction scheduler
+func NewAuctionScheduler(market AuctionMarket, schedules AuctionScheduleSource) *AuctionScheduler {
+	return &AuctionScheduler{
+		market:          market,
+		schedules:       schedules,
+		tickInterval:    5 * time.Second,
+		refreshInterval: time.Minute,
+		jitter:          randomJitter,
+		auctions:        make(map[string]*scheduledAuction),
+	}
+}
+
+// Run ticks the scheduler until the context is cancelled
+func (s *AuctionScheduler) Run(ctx context.Context) {
+	log.Println("Starting auction scheduler")
+
+	ticker := time.NewTicker(s.tickInterval)
+	defer ticker.Stop()
+
+	s.Tick(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Auction scheduler stopped")
+			return
+		case now := <-ticker.C:
+			s.Tick(now)
+		}
+	}
+}
+
+// Tick advances every scheduled auction to the given time, reloading the
+// schedules first when they are due for a refresh
+func (s *AuctionScheduler) Tick(now time.Time) {
+	if s.lastRefreshed.IsZero() || now.Sub(s.lastRefreshed) >= s.refreshInterval {
+		if err := s.refresh(now); err != nil {
+			log.Printf("Failed to refresh auction schedules: %v", err)
+		}
+	}
+
+	for securityID, auction := range s.auctions {
+		switch {
+		case !now.Before(auction.endsAt):
+			s.runAuction(securityID)
+			if err := s.scheduleNext(securityID, auction.schedule, now); err != nil {
+				log.Printf("Failed to schedule next auction for %s: %v", securityID, err)
+				delete(s.auctions, securityID)
+			}
+		case !now.Before(auction.scheduledAt.Add(-auction.schedule.IndicativePhase())):
+			if _, err := s.market.PublishIndicativeAuctionPrice(securityID); err != nil && !errors.Is(err, ErrSecurityLeased) {
+				log.Printf("Failed to publish indicative auction price for %s: %v", securityID, err)
+			}
+		}
+	}
+}
+
+// refresh picks up new, changed and cleared schedules. Auctions whose
+// schedule is unchanged keep their end time.
+func (s *AuctionScheduler) refresh(now time.Time) error {
+	schedules, err := s.schedules.GetAuctionSchedules()
+	if err != nil {
+		return err
+	}
+	s.lastRefreshed = now
+
+	for securityID := range s.auctions {
+		if _, ok := schedules[securityID]; !ok {
+			delete(s.auctions, securityID)
+		}
+	}
+	for securityID, schedule := range schedules {
+		if current, ok := s.auctions[securityID]; ok && current.schedule == schedule {
+			continue
+		}
+		if err := s.scheduleNext(securityID, schedule, now); err != nil {
+			log.Printf("Failed to schedule auction for %s: %v", securityID, err)
+		}
+	}
+	return nil
+}
+
+func (s *AuctionScheduler) scheduleNext(securityID string, schedule securities.AuctionSchedule, after time.Time) error {
+	scheduledAt, err := schedule.NextAuction(after)
+	if err != nil {
+		return err
+	}
+	s.auctions[securityID] = &scheduledAuction{
+		schedule:    schedule,
+		scheduledAt: scheduledAt,
+		endsAt:      scheduledAt.Add(s.jitter(schedule.MaxJitter())),
+	}
+	return nil
+}
+
+func (s *AuctionScheduler) runAuction(securityID string) {
+	trades, err := s.market.RunMatching(securityID, UniformPriceAuction)
+	if errors.Is(err, ErrSecurityLeased) {
+		// Another worker holds the security and runs its auction
+		return
+	}
+	if err != nil {
+		log.Printf("Call auction for %s failed: %v", securityID, err)
+		return
+	}
+	log.Printf("Call auction for %s executed %d trades", securityID, len(trades))
+}
+
+func randomJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max) + 1))
+}
@@ -0,0 +1,189 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/securities"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/testutil"
+)
+
+func newAuctionBook(entries ...*OrderBookEntry) *OrderBook {
+	book := NewOrderBook("TEST-001")
+	for _, entry := range entries {
+		if entry.OrderType == "sell" {
+			book.AddSellOrder(entry)
+		} else {
+			book.AddBuyOrder(entry)
+		}
+	}
+	return book
+}
+
+func TestCalculateAuctionClearing_MaximizesVolume(t *testing.T) {
+	book := newAuctionBook(
+		newBookEntry("sell", "ask-1", 100, decimalPtr("10"), testutil.TestTime),
+		newBookEntry("sell", "ask-2", 100, decimalPtr("11"), testutil.TestTime),
+		newBookEntry("buy", "bid-1", 150, decimalPtr("11"), testutil.TestTime),
+		newBookEntry("buy", "bid-2", 50, decimalPtr("10"), testutil.TestTime),
+	)
+
+	clearing, err := CalculateAuctionClearing(book, nil)
+
+	testutil.AssertNoError(t, err, "Clearing should succeed")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(11), clearing.Price, "Price with the most volume should win")
+	testutil.AssertEqual(t, int64(150), clearing.ExecutableVolume, "Executable volume should be reported")
+	testutil.AssertEqual(t, int64(-50), clearing.Imbalance, "Surplus supply should be reported")
+	testutil.AssertEqual(t, "sell", clearing.ImbalanceSide(), "Imbalance side should be sell")
+}
+
+func TestCalculateAuctionClearing_TieBreaks(t *testing.T) {
+	// Equal volume, the second price leaves no imbalance
+	book := newAuctionBook(
+		newBookEntry("sell", "ask-1", 100, decimalPtr("10"), testutil.TestTime),
+		newBookEntry("buy", "bid-1", 100, decimalPtr("11"), testutil.TestTime),
+		newBookEntry("buy", "bid-2", 50, decimalPtr("10"), testutil.TestTime),
+	)
+	clearing, _ := CalculateAuctionClearing(book, nil)
+	testutil.AssertEqual(t, money.NewDecimalFromInt(11), clearing.Price, "Minimum imbalance should break volume ties")
+
+	// Surplus demand at every candidate pushes the price up
+	book = newAuctionBook(
+		newBookEntry("sell", "ask-1", 100, decimalPtr("10"), testutil.TestTime),
+		newBookEntry("buy", "bid-1", 200, decimalPtr("11"), testutil.TestTime),
+	)
+	clearing, _ = CalculateAuctionClearing(book, nil)
+	testutil.AssertEqual(t, money.NewDecimalFromInt(11), clearing.Price, "Buy pressure should choose the highest price")
+
+	// Surplus supply at every candidate pushes the price down
+	book = newAuctionBook(
+		newBookEntry("sell", "ask-1", 200, decimalPtr("10"), testutil.TestTime),
+		newBookEntry("buy", "bid-1", 100, decimalPtr("11"), testutil.TestTime),
+	)
+	clearing, _ = CalculateAuctionClearing(book, nil)
+	testutil.AssertEqual(t, money.NewDecimalFromInt(10), clearing.Price, "Sell pressure should choose the lowest price")
+
+	// Balanced at every candidate: the reference price decides
+	book = newAuctionBook(
+		newBookEntry("sell", "ask-1", 100, decimalPtr("10"), testutil.TestTime),
+		newBookEntry("buy", "bid-1", 100, decimalPtr("12"), testutil.TestTime),
+	)
+	clearing, _ = CalculateAuctionClearing(book, decimalPtr("11.5"))
+	testutil.AssertEqual(t, money.MustParseDecimal("11.5"), clearing.Price, "A reference price inside the range should be used")
+	clearing, _ = CalculateAuctionClearing(book, decimalPtr("20"))
+	testutil.AssertEqual(t, money.NewDecimalFromInt(12), clearing.Price, "The price closest to the reference should be used")
+	clearing, _ = CalculateAuctionClearing(book, nil)
+	testutil.AssertEqual(t, money.NewDecimalFromInt(10), clearing.Price, "Without a reference the lowest price should be used")
+}
+
+func TestCalculateAuctionClearing_NoCross(t *testing.T) {
+	book := newAuctionBook(
+		newBookEntry("sell", "ask-1", 100, decimalPtr("12"), testutil.TestTime),
+		newBookEntry("buy", "bid-1", 100, decimalPtr("11"), testutil.TestTime),
+	)
+	clearing, err := CalculateAuctionClearing(book, nil)
+	testutil.AssertNoError(t, err, "A book that does not cross is not an error")
+	testutil.AssertNil(t, clearing, "No clearing price should be found")
+
+	book = newAuctionBook(
+		newBookEntry("sell", "ask-1", 100, nil, testutil.TestTime),
+		newBookEntry("buy", "bid-1", 100, nil, testutil.TestTime),
+	)
+	_, err = CalculateAuctionClearing(book, nil)
+	testutil.AssertError(t, err, "Market orders alone need a reference price")
+	clearing, err = CalculateAuctionClearing(book, decimalPtr("25"))
+	testutil.AssertNoError(t, err, "The reference price should clear market orders")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(25), clearing.Price, "Market orders should trade at the reference price")
+}
+
+func TestMatchUniformPriceAuction_ExecutesAtClearingPrice(t *testing.T) {
+	setup := testutil.NewTestSetup()
+	engine := NewOrderMatchingEngine(setup.EventStore, setup.EventBus)
+
+	book := newAuctionBook(
+		newBookEntry("sell", "ask-1", 100, decimalPtr("10"), testutil.TestTime),
+		newBookEntry("sell", "ask-2", 100, decimalPtr("11"), testutil.TestTime),
+		newBookEntry("buy", "bid-1", 150, decimalPtr("11"), testutil.TestTime),
+		newBookEntry("buy", "bid-2", 50, decimalPtr("10"), testutil.TestTime),
+	)
+
+	matches, err := engine.matchUniformPriceAuction(book)
+
+	testutil.AssertNoError(t, err, "Auction should succeed")
+	var traded int64
+	for _, match := range matches {
+		testutil.AssertEqual(t, money.NewDecimalFromInt(11), match.TradePrice, "Every trade should be at the clearing price")
+		testutil.AssertEqual(t, "bid-1", *match.BidID, "Only bids at or above the clearing price should trade")
+		traded += match.SharesTraded
+	}
+	testutil.AssertEqual(t, int64(150), traded, "Executed volume should equal the clearing volume")
+
+	published := setup.EventBus.GetEventsByType("AuctionIndicativePriceCalculated")
+	testutil.AssertLengthEqual(t, 1, published, "Indicative price should be published")
+	indicative := published[0].(*AuctionIndicativePriceCalculated)
+	testutil.AssertEqual(t, int64(-50), indicative.Imbalance, "Indicative imbalance should be published")
+}
+
+type fakeAuctionMarket struct {
+	indicative []string
+	auctions   []string
+}
+
+func (m *fakeAuctionMarket) PublishIndicativeAuctionPrice(securityID string) (*AuctionClearing, error) {
+	m.indicative = append(m.indicative, securityID)
+	return nil, nil
+}
+
+func (m *fakeAuctionMarket) RunMatching(securityID string, algorithm MatchingAlgorithm) ([]*TradeAggregate, error) {
+	if algorithm == UniformPriceAuction {
+		m.auctions = append(m.auctions, securityID)
+	}
+	return nil, nil
+}
+
+type fakeAuctionSchedules map[string]securities.AuctionSchedule
+
+func (s fakeAuctionSchedules) GetAuctionSchedules() (map[string]securities.AuctionSchedule, error) {
+	return s, nil
+}
+
+func TestAuctionScheduler_RunsPhasesAndJitteredAuctions(t *testing.T) {
+	market := &fakeAuctionMarket{}
+	schedules := fakeAuctionSchedules{
+		"TEST-001": {Frequency: securities.AuctionFrequencyInterval, IntervalMinutes: 15, IndicativeMinutes: 2, MaxJitterSeconds: 30},
+	}
+	scheduler := NewAuctionScheduler(market, schedules)
+	scheduler.jitter = func(max time.Duration) time.Duration { return max }
+
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	scheduler.Tick(start)
+	scheduler.Tick(start.Add(10 * time.Minute))
+	testutil.AssertLengthEqual(t, 0, market.indicative, "Orders should only be collected before the indicative phase")
+
+	scheduler.Tick(start.Add(13*time.Minute + 30*time.Second))
+	scheduler.Tick(start.Add(15*time.Minute + 10*time.Second))
+	testutil.AssertLengthEqual(t, 2, market.indicative, "Indicative price should be published until the auction ends")
+	testutil.AssertLengthEqual(t, 0, market.auctions, "Auction should not end before its jitter")
+
+	scheduler.Tick(start.Add(15*time.Minute + 30*time.Second))
+	testutil.AssertLengthEqual(t, 1, market.auctions, "Auction should run once the jittered end passes")
+	testutil.AssertEqual(t, start.Add(30*time.Minute), scheduler.auctions["TEST-001"].scheduledAt, "Next auction should be scheduled")
+
+	delete(schedules, "TEST-001")
+	scheduler.Tick(start.Add(29 * time.Minute))
+	testutil.AssertLengthEqual(t, 0, scheduler.auctions, "Cleared schedules should stop auctions")
+}
+
+func TestAuctionSchedule_NextAuction(t *testing.T) {
+	daily := securities.AuctionSchedule{Frequency: securities.AuctionFrequencyDaily, TimeOfDay: "16:00", Timezone: "America/New_York"}
+	testutil.AssertNoError(t, daily.Validate(), "Daily schedule should be valid")
+
+	location, _ := time.LoadLocation("America/New_York")
+	next, err := daily.NextAuction(time.Date(2024, 3, 1, 16, 0, 0, 0, location))
+	testutil.AssertNoError(t, err, "Next auction should be found")
+	testutil.AssertEqual(t, time.Date(2024, 3, 2, 16, 0, 0, 0, location), next, "An auction at the current time should roll to tomorrow")
+
+	invalid := securities.AuctionSchedule{Frequency: securities.AuctionFrequencyInterval, IntervalMinutes: 5, IndicativeMinutes: 5}
+	testutil.AssertError(t, invalid.Validate(), "Indicative phase must leave time to collect orders")
+}
@@ -0,0 +1,254 @@
+package execution
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/testutil"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/listing"
+	"securities-marketplace/domains/trading/orders"
+)
+
+// randomConditions picks no conditions, all-or-none, fill-or-kill,
+// immediate-or-cancel or a minimum quantity for an order
+func randomConditions(rng *rand.Rand, quantity int64) orders.Conditions {
+	switch rng.Intn(6) {
+	case 0:
+		return orders.Conditions{AllOrNone: true}
+	case 1:
+		return orders.Conditions{FillOrKill: true}
+	case 2:
+		return orders.Conditions{MinQuantity: 1 + rng.Int63n(quantity)}
+	case 3:
+		return orders.Conditions{TimeInForce: orders.TimeInForceIOC}
+	default:
+		return orders.Conditions{}
+	}
+}
+
+// randomConditionalBook builds a book of limit and market orders with random
+// conditions. The same seed always builds the same book.
+func randomConditionalBook(seed int64) *OrderBook {
+	rng := rand.New(rand.NewSource(seed))
+	var entries []*OrderBookEntry
+
+	for i := 0; i < 2+rng.Intn(8); i++ {
+		for _, side := range []string{"sell", "buy"} {
+			quantity := 1 + rng.Int63n(200)
+			var price *money.Decimal
+			if rng.Intn(10) > 0 {
+				price = decimalPtr(fmt.Sprintf("%d", 9+rng.Intn(3)))
+			}
+			entry := newBookEntry(side, fmt.Sprintf("%s-%d", side, i), quantity, price, testutil.TestTime.Add(time.Duration(rng.Intn(60))*time.Second))
+			entry.Conditions = randomConditions(rng, quantity)
+			entries = append(entries, entry)
+		}
+	}
+
+	return newAuctionBook(entries...)
+}
+
+// assertConditionsHold replays the matches against the original orders and
+// checks every execution was one the order's conditions allow
+func assertConditionsHold(t *testing.T, book *OrderBook, matches []*MatchResult, context string) {
+	t.Helper()
+
+	remaining := make(map[string]int64)
+	conditions := make(map[string]orders.Conditions)
+	for _, entry := range append(book.GetSellOrders(), book.GetBuyOrders()...) {
+		remaining[entry.OrderID()] = entry.Quantity
+		conditions[entry.OrderID()] = entry.Conditions
+	}
+
+	for _, match := range matches {
+		for _, orderID := range []string{match.ListingID, *match.BidID} {
+			if !conditions[orderID].AllowsFill(match.SharesTraded, remaining[orderID]) {
+				t.Fatalf("%s: fill of %d shares violates %+v of %s with %d remaining", context, match.SharesTraded, conditions[orderID], orderID, remaining[orderID])
+			}
+			remaining[orderID] -= match.SharesTraded
+		}
+	}
+}
+
+func TestMatching_NeverViolatesOrderConditions(t *testing.T) {
+	engine := &AdvancedMatchingEngine{OrderMatchingEngine: &OrderMatchingEngine{}}
+
+	for seed := int64(1); seed <= 500; seed++ {
+		matches, err := engine.matchPriceTimePriority(randomConditionalBook(seed))
+		testutil.AssertNoError(t, err, "Price-time matching should succeed")
+		assertConditionsHold(t, randomConditionalBook(seed), matches, fmt.Sprintf("price-time seed %d", seed))
+
+		if matches, err := engine.matchUniformPriceAuction(randomConditionalBook(seed)); err == nil {
+			assertConditionsHold(t, randomConditionalBook(seed), matches, fmt.Sprintf("auction seed %d", seed))
+		}
+
+		clearing, err := CalculateAuctionClearing(randomConditionalBook(seed), nil)
+		if err != nil || clearing == nil {
+			continue
+		}
+		matches, err = engine.MatchWithProRata(randomConditionalBook(seed), clearing.Price)
+		testutil.AssertNoError(t, err, "Pro-rata matching should succeed")
+		assertConditionsHold(t, randomConditionalBook(seed), matches, fmt.Sprintf("pro-rata seed %d", seed))
+	}
+}
+
+func TestMatching_KillsOnlyUnfilledImmediateOrders(t *testing.T) {
+	engine := &OrderMatchingEngine{}
+
+	for seed := int64(1); seed <= 500; seed++ {
+		book := randomConditionalBook(seed)
+		matches, err := engine.matchPriceTimePriority(randomConditionalBook(seed))
+		testutil.AssertNoError(t, err, "Price-time matching should succeed")
+
+		filled := make(map[string]int64)
+		for _, match := range matches {
+			filled[match.ListingID] += match.SharesTraded
+			filled[*match.BidID] += match.SharesTraded
+		}
+		killed := make(map[string]bool)
+		for _, entry := range unfilledImmediateOrders(book, matches) {
+			killed[entry.OrderID()] = true
+		}
+
+		for _, entry := range append(book.GetSellOrders(), book.GetBuyOrders()...) {
+			if entry.Conditions.FillOrKill && filled[entry.OrderID()] != 0 && filled[entry.OrderID()] != entry.Quantity {
+				t.Fatalf("seed %d: fill-or-kill order %s was part-filled", seed, entry.OrderID())
+			}
+			immediate := entry.Conditions.FillOrKill || entry.Conditions.TimeInForce == orders.TimeInForceIOC
+			shouldKill := immediate && filled[entry.OrderID()] < entry.Quantity
+			if killed[entry.OrderID()] != shouldKill {
+				t.Fatalf("seed %d: order %s killed=%v, want %v", seed, entry.OrderID(), killed[entry.OrderID()], shouldKill)
+			}
+		}
+	}
+}
+
+func TestMatching_SkippedAllOrNoneOrdersKeepPriority(t *testing.T) {
+	engine := &OrderMatchingEngine{}
+	allOrNone := newBookEntry("buy", "bid-aon", 100, decimalPtr("11"), testutil.TestTime)
+	allOrNone.Conditions = orders.Conditions{AllOrNone: true}
+
+	book := newAuctionBook(
+		newBookEntry("sell", "ask-1", 60, decimalPtr("10"), testutil.TestTime),
+		newBookEntry("sell", "ask-2", 100, decimalPtr("10"), testutil.TestTime.Add(time.Second)),
+		allOrNone,
+		newBookEntry("buy", "bid-later", 50, decimalPtr("11"), testutil.TestTime.Add(time.Second)),
+	)
+
+	matches, err := engine.matchPriceTimePriority(book)
+
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertLengthEqual(t, 2, matches, "Should have two matches")
+	testutil.AssertEqual(t, "bid-later", *matches[0].BidID, "Later bid should trade past the all-or-none bid")
+	testutil.AssertEqual(t, "bid-aon", *matches[1].BidID, "All-or-none bid should fill in one execution once it can")
+	testutil.AssertEqual(t, int64(100), matches[1].SharesTraded, "All-or-none bid should fill completely")
+}
+
+func TestExecutionService_RunMatchingKillsUnfilledFillOrKillBids(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetInvestorVerifier(testInvestorVerifier{})
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	offer := listing.NewListingAggregate("listing-1")
+	offer.CreateListing("TEST-001", "seller-1", 50, listing.ListingTypeFixed, nil, nil, decimalPtr("50.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, offer), "Listing should be saved")
+
+	bid := bidding.NewBidAggregate("bid-fok")
+	err := bid.PlaceBidWithConditions("listing-1", "buyer-1", 80, money.NewDecimalFromInt(50), bidding.BidTypeLimit, nil, orders.Conditions{FillOrKill: true})
+	testutil.AssertNoError(t, err, "Fill-or-kill bid should be placed")
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, bid), "Bid should be saved")
+
+	// Act
+	trades, err := service.RunMatching("TEST-001", PriceTimePriority)
+
+	// Assert
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertLengthEqual(t, 0, trades, "Fill-or-kill bid should not part-fill")
+
+	killed, _ := bidding.NewEventSourcedBidRepository(setup.EventStore).FindByID("bid-fok")
+	testutil.AssertEqual(t, bidding.BidStatusWithdrawn, killed.Status, "Unfilled fill-or-kill bid should be cancelled")
+
+	book, _ := service.GetOrderBook("TEST-001")
+	_, resting := book.Get("bid-fok")
+	testutil.AssertFalse(t, resting, "Killed bid should leave the book")
+	testutil.AssertError(t, killed.PartiallyFill(10, money.NewDecimalFromInt(50), "trade-1", "seller-1"), "Killed bids cannot be filled")
+}
+
+func TestExecutionService_RunMatchingCancelsImmediateOrCancelRemainder(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetInvestorVerifier(testInvestorVerifier{})
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	offer := listing.NewListingAggregate("listing-1")
+	offer.CreateListing("TEST-001", "seller-1", 100, listing.ListingTypeFixed, nil, nil, decimalPtr("50.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, offer), "Listing should be saved")
+
+	bid := bidding.NewBidAggregate("bid-ioc")
+	err := bid.PlaceBidWithConditions("listing-1", "buyer-1", 150, money.NewDecimalFromInt(50), bidding.BidTypeLimit, nil, orders.Conditions{TimeInForce: orders.TimeInForceIOC})
+	testutil.AssertNoError(t, err, "Immediate-or-cancel bid should be placed")
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, bid), "Bid should be saved")
+
+	// Act
+	trades, err := service.RunMatching("TEST-001", PriceTimePriority)
+
+	// Assert
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertLengthEqual(t, 1, trades, "Available shares should trade")
+	testutil.AssertEqual(t, int64(100), trades[0].SharesTraded, "Whole listing should trade")
+
+	cancelled, _ := bidding.NewEventSourcedBidRepository(setup.EventStore).FindByID("bid-ioc")
+	testutil.AssertEqual(t, bidding.BidStatusWithdrawn, cancelled.Status, "Remainder should be cancelled")
+	testutil.AssertEqual(t, int64(100), cancelled.SharesFilled, "Fills should be kept")
+}
+
+func TestExecutionService_ExpireOrdersByTimeInForce(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+
+	gtc := listing.NewListingAggregate("listing-gtc")
+	gtc.CreateListing("TEST-001", "seller-1", 100, listing.ListingTypeFixed, nil, nil, decimalPtr("60.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, gtc), "Good-till-cancelled listing should be saved")
+
+	day := listing.NewListingAggregate("listing-day")
+	err := day.CreateListingWithConditions("TEST-001", "seller-2", 100, listing.ListingTypeFixed, nil, nil, decimalPtr("61.00"), nil, false, nil, orders.Conditions{TimeInForce: orders.TimeInForceDAY})
+	testutil.AssertNoError(t, err, "Day listing should be created")
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, day), "Day listing should be saved")
+
+	deadline := time.Now().Add(-time.Minute)
+	saveDomainEvents(t, setup.EventStore,
+		bidding.NewBidPlaced("bid-gtd", "listing-gtc", "buyer-1", 10, money.NewDecimalFromInt(50), string(bidding.BidTypeLimit), &deadline),
+	)
+
+	// Act & Assert: without market hours only the deadline applies
+	expired, err := service.ExpireOrders()
+	testutil.AssertNoError(t, err, "Expiry should succeed")
+	testutil.AssertEqual(t, 1, expired, "Good-till-date bid should expire at its deadline")
+
+	marketClose := time.Now().Add(time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+	service.SetMarketDataProvider(&testMarketData{closesAt: marketClose})
+
+	expired, err = service.ExpireOrders()
+	testutil.AssertNoError(t, err, "Expiry should succeed")
+	testutil.AssertEqual(t, 1, expired, "Day listing should expire at the market close")
+
+	listings := listing.NewEventSourcedListingRepository(setup.EventStore)
+	expiredDay, _ := listings.FindByID("listing-day")
+	testutil.AssertEqual(t, listing.ListingStatusExpired, expiredDay.Status, "Day listing should be expired")
+	stillActive, _ := listings.FindByID("listing-gtc")
+	testutil.AssertEqual(t, listing.ListingStatusActive, stillActive.Status, "Good-till-cancelled listing should stay active")
+	expiredBid, _ := bidding.NewEventSourcedBidRepository(setup.EventStore).FindByID("bid-gtd")
+	testutil.AssertEqual(t, bidding.BidStatusExpired, expiredBid.Status, "Good-till-date bid should be expired")
+
+	book, _ := service.GetOrderBook("TEST-001")
+	testutil.AssertEqual(t, 1, book.Len(), "Only the good-till-cancelled listing should rest in the book")
+}
@@ -0,0 +1,161 @@
+package execution
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+)
+
+// MaxDepthLevels caps the price levels a depth request returns per side
+const MaxDepthLevels = 50
+
+// TopOfBook is the best bid and ask of a security and the spread between them
+type TopOfBook struct {
+	SecurityID string         `json:"securityId"`
+	BestBid    *DepthLevel    `json:"bestBid,omitempty"`
+	BestAsk    *DepthLevel    `json:"bestAsk,omitempty"`
+	Spread     *money.Decimal `json:"spread,omitempty"`
+	MidPrice   *money.Decimal `json:"midPrice,omitempty"`
+	AsOf       time.Time      `json:"asOf"`
+}
+
+// DepthOrder is one displayed order in the book. Participant is left empty
+// when the book is anonymized.
+type DepthOrder struct {
+	Side        string        `json:"side"` // "buy" or "sell"
+	Price       money.Decimal `json:"price"`
+	Quantity    int64         `json:"quantity"` // Displayed shares only
+	Timestamp   time.Time     `json:"timestamp"`
+	Participant string        `json:"participant,omitempty"`
+	Own         bool          `json:"own"` // Placed by the viewer
+}
+
+// IndicativeAuction is the price a call auction would clear at if it ran now
+type IndicativeAuction struct {
+	Price            money.Decimal `json:"price"`
+	ExecutableVolume int64         `json:"executableVolume"`
+	Imbalance        int64         `json:"imbalance"`
+	ImbalanceSide    string        `json:"imbalanceSide,omitempty"`
+}
+
+// BookDepth is the aggregated depth (L2) of a security's book. Iceberg
+// reserves are never shown, neither in the levels nor in the orders.
+type BookDepth struct {
+	TopOfBook
+	Bids              []DepthLevel       `json:"bids"`
+	Asks              []DepthLevel       `json:"asks"`
+	Orders            []DepthOrder       `json:"orders,omitempty"`
+	IndicativeAuction *IndicativeAuction `json:"indicativeAuction,omitempty"`
+}
+
+// DepthOptions selects what a depth request returns
+type DepthOptions struct {
+	Levels    int    // Price levels per side, up to MaxDepthLevels
+	Orders    bool   // Include the orders resting in those levels
+	Anonymize bool   // Hide who placed each order, except from the viewer
+	ViewerID  string // Marks the viewer's own orders
+}
+
+// GetTopOfBook returns the best bid and ask of a security
+func (s *ExecutionService) GetTopOfBook(securityID string) (*TopOfBook, error) {
+	book, err := s.GetOrderBook(securityID)
+	if err != nil {
+		return nil, err
+	}
+	return topOfBook(book), nil
+}
+
+// GetTopsOfBook returns the best bid and ask of every security with a book
+func (s *ExecutionService) GetTopsOfBook() ([]*TopOfBook, error) {
+	books := s.matchingEngine.OrderBooks()
+	if err := books.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync order books: %w", err)
+	}
+
+	securityIDs := books.Securities()
+	tops := make([]*TopOfBook, 0, len(securityIDs))
+	for _, securityID := range securityIDs {
+		tops = append(tops, topOfBook(books.Book(securityID)))
+	}
+	return tops, nil
+}
+
+// GetBookDepth returns the aggregated depth of a security's book, and the
+// indicative auction price when the book crosses
+func (s *ExecutionService) GetBookDepth(securityID string, options DepthOptions) (*BookDepth, error) {
+	if options.Levels <= 0 || options.Levels > MaxDepthLevels {
+		return nil, fmt.Errorf("levels must be between 1 and %d", MaxDepthLevels)
+	}
+
+	book, err := s.GetOrderBook(securityID)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := &BookDepth{TopOfBook: *topOfBook(book)}
+	depth.Bids, depth.Asks = book.Depth(options.Levels)
+
+	if options.Orders {
+		bids, asks := book.LevelOrders(options.Levels)
+		depth.Orders = make([]DepthOrder, 0, len(bids)+len(asks))
+		for _, entry := range append(bids, asks...) {
+			depth.Orders = append(depth.Orders, depthOrder(entry, options))
+		}
+	}
+
+	if bid, ask := depth.BestBid, depth.BestAsk; bid != nil && ask != nil && !bid.Price.LessThan(ask.Price) {
+		clearing, err := s.matchingEngine.CalculateIndicativeAuctionPrice(securityID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate indicative auction price: %w", err)
+		}
+		if clearing != nil {
+			depth.IndicativeAuction = &IndicativeAuction{
+				Price:            clearing.Price,
+				ExecutableVolume: clearing.ExecutableVolume,
+				Imbalance:        clearing.Imbalance,
+				ImbalanceSide:    clearing.ImbalanceSide(),
+			}
+		}
+	}
+
+	return depth, nil
+}
+
+func topOfBook(book *LimitOrderBook) *TopOfBook {
+	top := &TopOfBook{SecurityID: book.SecurityID, AsOf: time.Now()}
+	if bid, ok := book.BestBid(); ok {
+		top.BestBid = &bid
+	}
+	if ask, ok := book.BestAsk(); ok {
+		top.BestAsk = &ask
+	}
+
+	if top.BestBid != nil && top.BestAsk != nil {
+		if spread, err := top.BestAsk.Price.Sub(top.BestBid.Price); err == nil {
+			top.Spread = &spread
+		}
+		if sum, err := top.BestBid.Price.Add(top.BestAsk.Price); err == nil {
+			if mid, err := sum.DivInt(2, money.RoundHalfEven); err == nil {
+				top.MidPrice = &mid
+			}
+		}
+	}
+	return top
+}
+
+func depthOrder(entry *OrderBookEntry, options DepthOptions) DepthOrder {
+	order := DepthOrder{
+		Side:      entry.OrderType,
+		Quantity:  entry.Displayed(),
+		Timestamp: entry.Timestamp,
+		Own:       options.ViewerID != "" && entry.UserID == options.ViewerID,
+	}
+	if entry.Price != nil {
+		order.Price = *entry.Price
+	}
+	if !options.Anonymize || order.Own {
+		order.Participant = entry.UserID
+	}
+	return order
+}
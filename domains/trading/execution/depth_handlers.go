@@ -0,0 +1,119 @@
+package execution
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"securities-marketplace/domains/shared/auth"
+)
+
+// defaultDepthLevels is how many price levels a depth request returns per
+// side when it does not ask for a number
+const defaultDepthLevels = 10
+
+// DepthHandler exposes the order book of each security to users with market
+// data read permission. Orders are anonymized unless anonymization is turned
+// off; compliance always sees who placed them.
+type DepthHandler struct {
+	service   *ExecutionService
+	rbac      *auth.RBAC
+	anonymize bool
+}
+
+// NewDepthHandler creates a new depth handler that anonymizes orders
+func NewDepthHandler(service *ExecutionService) *DepthHandler {
+	return &DepthHandler{
+		service:   service,
+		rbac:      auth.NewRBAC(),
+		anonymize: true,
+	}
+}
+
+// SetAnonymize sets whether orders hide who placed them
+func (h *DepthHandler) SetAnonymize(anonymize bool) {
+	h.anonymize = anonymize
+}
+
+// RegisterRoutes registers the handler routes. Callers are expected to mount
+// the router behind authentication.
+func (h *DepthHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/book/{id}", h.HandleDepth).Methods("GET")
+	router.HandleFunc("/book/{id}/top", h.HandleTopOfBook).Methods("GET")
+}
+
+// HandleDepth returns the aggregated depth of a security's book. levels sets
+// the price levels per side and orders=true adds the orders in them.
+func (h *DepthHandler) HandleDepth(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireReader(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	options := DepthOptions{
+		Levels:    defaultDepthLevels,
+		Orders:    query.Get("orders") == "true",
+		Anonymize: h.anonymize && !h.rbac.HasPermission(user.Roles, auth.PermissionComplianceRead),
+		ViewerID:  user.UserID,
+	}
+	if value := query.Get("levels"); value != "" {
+		levels, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "levels must be a number", http.StatusBadRequest)
+			return
+		}
+		options.Levels = levels
+	}
+	if options.Levels <= 0 || options.Levels > MaxDepthLevels {
+		http.Error(w, "levels must be between 1 and 50", http.StatusBadRequest)
+		return
+	}
+
+	depth, err := h.service.GetBookDepth(mux.Vars(r)["id"], options)
+	if err != nil {
+		http.Error(w, "Failed to load order book", http.StatusInternalServerError)
+		return
+	}
+	writeDepth(w, "book", depth)
+}
+
+// HandleTopOfBook returns the best bid and ask of a security
+func (h *DepthHandler) HandleTopOfBook(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireReader(w, r); !ok {
+		return
+	}
+
+	top, err := h.service.GetTopOfBook(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Failed to load order book", http.StatusInternalServerError)
+		return
+	}
+	writeDepth(w, "top", top)
+}
+
+// requireReader returns the authenticated user if they may read market data
+func (h *DepthHandler) requireReader(w http.ResponseWriter, r *http.Request) (*auth.UserContext, bool) {
+	user := auth.GetUserFromContext(r.Context())
+	if user == nil || user.UserID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return nil, false
+	}
+	if !h.rbac.HasPermission(user.Roles, auth.PermissionMarketDataRead) {
+		http.Error(w, "Insufficient permissions", http.StatusForbidden)
+		return nil, false
+	}
+	return user, true
+}
+
+func writeDepth(w http.ResponseWriter, key string, value interface{}) {
+	response := map[string]interface{}{
+		"success": true,
+		key:       value,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,57 @@
+package execution
+
+import (
+	"fmt"
+
+	"securities-marketplace/domains/users"
+)
+
+// InvestorVerifier reports the investor status the matching engine needs to
+// honour listing restrictions
+type InvestorVerifier interface {
+	IsAccredited(userID string) (bool, error)
+}
+
+// UserInvestorVerifier verifies investors against their user aggregates
+type UserInvestorVerifier struct {
+	repository users.UserRepository
+}
+
+// NewUserInvestorVerifier creates a new user-backed investor verifier
+func NewUserInvestorVerifier(repository users.UserRepository) *UserInvestorVerifier {
+	return &UserInvestorVerifier{repository: repository}
+}
+
+// IsAccredited returns true if the user holds a current accreditation
+func (v *UserInvestorVerifier) IsAccredited(userID string) (bool, error) {
+	user, err := v.repository.FindByID(userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find user: %w", err)
+	}
+	return user.IsAccredited(), nil
+}
+
+// OwnershipResolver groups accounts under the beneficial owner or broker that
+// controls them, so the matching engine can stop an owner trading with itself
+type OwnershipResolver interface {
+	BeneficialOwner(userID string) (string, error)
+}
+
+// UserOwnershipResolver resolves owners from the links on user aggregates
+type UserOwnershipResolver struct {
+	repository users.UserRepository
+}
+
+// NewUserOwnershipResolver creates a new user-backed ownership resolver
+func NewUserOwnershipResolver(repository users.UserRepository) *UserOwnershipResolver {
+	return &UserOwnershipResolver{repository: repository}
+}
+
+// BeneficialOwner returns the owner the user's account trades for
+func (r *UserOwnershipResolver) BeneficialOwner(userID string) (string, error) {
+	user, err := r.repository.FindByID(userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find user: %w", err)
+	}
+	return user.GetBeneficialOwnerID(), nil
+}
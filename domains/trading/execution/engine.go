@@ -3,9 +3,20 @@ package execution
 import (
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
+
+	"securities-marketplace/domains/securities"
 	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/calendar"
+	"securities-marketplace/domains/trading/listing"
+	"securities-marketplace/domains/trading/orders"
+	"securities-marketplace/domains/trading/session"
+	"securities-marketplace/domains/users"
 )
 
 // MatchingAlgorithm represents different matching algorithms
@@ -15,6 +26,7 @@ const (
 	PriceTimePriority  MatchingAlgorithm = "price_time_priority"
 	UniformPriceAuction MatchingAlgorithm = "uniform_price_auction"
 	NegotiatedTrading  MatchingAlgorithm = "negotiated_trading"
+	RequestForQuote    MatchingAlgorithm = "request_for_quote" // Accepted quotes, never run against the book
 )
 
 // OrderBookEntry represents an entry in the order book
@@ -25,10 +37,13 @@ type OrderBookEntry struct {
 	SecurityID    string
 	OrderType     string // "sell" or "buy"
 	Quantity      int64
-	Price         *float64 // nil for market orders
+	Price         *money.Decimal // nil for market orders
 	Timestamp     time.Time
 	IsAccredited  bool
 	ExpiresAt     *time.Time
+	Conditions    orders.Conditions
+	Hidden        int64 // Shares of an iceberg listing held back from the displayed book
+	OwnerID       string // Beneficial owner the order trades for, for self-trade prevention
 }
 
 // MatchResult represents the result of a matching operation
@@ -40,33 +55,176 @@ type MatchResult struct {
 	SellerID         string
 	SecurityID       string
 	SharesTraded     int64
-	TradePrice       float64
-	TotalAmount      float64
+	TradePrice       money.Decimal
+	TotalAmount      money.Money
 	SettlementDate   time.Time
 	MatchingAlgorithm string
 }
 
+// MatchingRound is the outcome of one matching run over a security's book
+type MatchingRound struct {
+	Matches []*MatchResult
+	Killed    []*OrderBookEntry // Fill-or-kill and immediate-or-cancel orders whose remainder must be cancelled
+	Prevented []*SelfTradePrevention // Orders cancelled or decremented instead of trading with their own owner
+	Halt      *VolatilityHalt        // Set when a trade would fall outside the price band; matching stopped before it
+}
+
 // OrderMatchingEngine handles order matching for securities trading
 type OrderMatchingEngine struct {
 	eventStore events.EventStore
 	eventBus   events.EventBus
+	books      *OrderBookManager
+	listings   listing.ListingRepository
+	bids       bidding.BidRepository
+	investors  InvestorVerifier
+	owners     OwnershipResolver
+	marketData MarketDataProvider
+	securities securities.SecurityRepository
+	bands      *PriceBands
+	calendar   *calendar.Calendar // Trading calendar; nil until set, when market hours are not checked
+	sessions   *session.Gate
+
+	selfTradeMode SelfTradePreventionMode
+
+	unbanded *sync.Map // Securities already reported as matching without a price band
 }
 
 // NewOrderMatchingEngine creates a new order matching engine
 func NewOrderMatchingEngine(eventStore events.EventStore, eventBus events.EventBus) *OrderMatchingEngine {
+	userRepository := users.NewEventSourcedUserRepository(eventStore)
 	return &OrderMatchingEngine{
-		eventStore: eventStore,
-		eventBus:   eventBus,
+		eventStore:    eventStore,
+		eventBus:      eventBus,
+		books:         NewOrderBookManager(eventStore),
+		listings:      listing.NewEventSourcedListingRepository(eventStore),
+		bids:          bidding.NewEventSourcedBidRepository(eventStore),
+		investors:     NewUserInvestorVerifier(userRepository),
+		owners:        NewUserOwnershipResolver(userRepository),
+		securities:    securities.NewEventSourcedSecurityRepository(eventStore),
+		sessions:      session.NewGate(session.NewEventSourcedSessionRepository(eventStore)),
+		selfTradeMode: SelfTradeCancelNewest,
+		unbanded:      &sync.Map{},
+	}
+}
+
+// SetInvestorVerifier replaces the verifier used to check buyer accreditation
+func (e *OrderMatchingEngine) SetInvestorVerifier(verifier InvestorVerifier) {
+	e.investors = verifier
+}
+
+// SetOwnershipResolver replaces the resolver that groups accounts by
+// beneficial owner for self-trade prevention
+func (e *OrderMatchingEngine) SetOwnershipResolver(resolver OwnershipResolver) {
+	e.owners = resolver
+}
+
+// SetSelfTradePrevention sets how the engine resolves orders of the same
+// beneficial owner that would trade with each other in the book
+func (e *OrderMatchingEngine) SetSelfTradePrevention(mode SelfTradePreventionMode) {
+	e.selfTradeMode = mode
+}
+
+// SetMarketDataProvider sets the source of reference prices for auctions
+func (e *OrderMatchingEngine) SetMarketDataProvider(provider MarketDataProvider) {
+	e.marketData = provider
+}
+
+// SetPriceBands enables price bands. Every trade made by a matching run must
+// fall inside the security's band, built from the market data provider's
+// reference price and volatility; a trade outside it stops the run and halts
+// the security. Negotiated and quoted trades are agreed bilaterally and are
+// not checked. Bands need a market data provider.
+func (e *OrderMatchingEngine) SetPriceBands(bands PriceBands) {
+	e.bands = &bands
+}
+
+// SetTradingCalendar sets the calendar that decides settlement dates and
+// market hours. Once set, matching runs only while the market is open; until
+// then the default calendar settles trades T+2 on weekdays and market hours
+// are not checked.
+func (e *OrderMatchingEngine) SetTradingCalendar(cal *calendar.Calendar) {
+	e.calendar = cal
+}
+
+// MarketHours returns the current or last trading session and whether the
+// market is open now. known is false when no trading calendar is set and no
+// market data provider reports market hours.
+func (e *OrderMatchingEngine) MarketHours(now time.Time) (open, close time.Time, isOpen, known bool) {
+	if e.calendar != nil {
+		open, close, isOpen = e.calendar.MarketHours(now)
+		return open, close, isOpen, true
 	}
+	if e.marketData != nil {
+		open, close, isOpen = e.marketData.GetMarketHours()
+		return open, close, isOpen, true
+	}
+	return time.Time{}, time.Time{}, false, false
 }
 
 // MatchOrders attempts to match buy and sell orders for a security
 func (e *OrderMatchingEngine) MatchOrders(securityID string, algorithm MatchingAlgorithm) ([]*MatchResult, error) {
+	round, err := e.RunMatchingRound(securityID, algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return round.Matches, nil
+}
+
+// RunMatchingRound matches the book for a security and reports the
+// fill-or-kill and immediate-or-cancel orders the run did not completely fill,
+// which must be cancelled rather than left in the book, and the orders
+// prevented from trading with their own beneficial owner. Halted securities
+// do not match until their reopening auction, and during the opening and
+// closing auctions only call auctions run.
+func (e *OrderMatchingEngine) RunMatchingRound(securityID string, algorithm MatchingAlgorithm) (*MatchingRound, error) {
+	if err := e.checkMarketOpen(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := e.checkSession(securityID, algorithm); err != nil {
+		return nil, err
+	}
+	if err := e.checkTradingHalt(securityID); err != nil {
+		return nil, err
+	}
+	return e.runMatchingRound(securityID, algorithm, e.priceBand(securityID))
+}
+
+// RunReopeningAuction runs the call auction that reopens a halted security.
+// It is not limited by the price band: it discovers the price trading
+// resumes at.
+func (e *OrderMatchingEngine) RunReopeningAuction(securityID string) (*MatchingRound, error) {
+	if err := e.checkMarketOpen(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := e.checkSession(securityID, UniformPriceAuction); err != nil {
+		return nil, err
+	}
+	return e.runMatchingRound(securityID, UniformPriceAuction, nil)
+}
+
+// UncrossAuction runs the call auction that ends an opening or closing
+// auction. The session rather than the calendar decides when it may run: the
+// closing auction uncrosses at the close.
+func (e *OrderMatchingEngine) UncrossAuction(securityID string) (*MatchingRound, error) {
+	if err := e.checkSession(securityID, UniformPriceAuction); err != nil {
+		return nil, err
+	}
+	if err := e.checkTradingHalt(securityID); err != nil {
+		return nil, err
+	}
+	return e.runMatchingRound(securityID, UniformPriceAuction, e.priceBand(securityID))
+}
+
+func (e *OrderMatchingEngine) runMatchingRound(securityID string, algorithm MatchingAlgorithm, band *PriceBand) (*MatchingRound, error) {
 	// Get current order book for the security
 	orderBook, err := e.buildOrderBook(securityID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build order book: %w", err)
 	}
+	orderBook.Band = band
+	if orderBook.Currency, err = e.currency(securityID); err != nil {
+		return nil, err
+	}
 
 	// Apply matching algorithm
 	var matches []*MatchResult
@@ -85,371 +243,792 @@ func (e *OrderMatchingEngine) MatchOrders(securityID string, algorithm MatchingA
 		return nil, fmt.Errorf("matching failed: %w", err)
 	}
 
-	return matches, nil
+	return &MatchingRound{
+		Matches:   matches,
+		Killed:    unfilledImmediateOrders(e.books.Book(securityID).Snapshot(), matches),
+		Prevented: orderBook.Prevented,
+		Halt:      orderBook.Halt,
+	}, nil
 }
 
-// MatchSpecificOrders attempts to match a specific bid against a specific listing
-func (e *OrderMatchingEngine) MatchSpecificOrders(listingID, bidID string) (*MatchResult, error) {
-	// This would be used for direct negotiations or manual matching
-	// Implementation would load the specific listing and bid, validate compatibility,
-	// and create a match result if valid
-	
-	// For now, return a placeholder implementation
-	return nil, fmt.Errorf("specific order matching not yet implemented")
+// unfilledImmediateOrders returns the fill-or-kill and immediate-or-cancel
+// orders in a book that the matches leave shares of. The book must be from
+// before the matches were executed.
+func unfilledImmediateOrders(orderBook *OrderBook, matches []*MatchResult) []*OrderBookEntry {
+	filled := make(map[string]int64)
+	for _, match := range matches {
+		filled[match.ListingID] += match.SharesTraded
+		if match.BidID != nil {
+			filled[*match.BidID] += match.SharesTraded
+		}
+	}
+
+	var killed []*OrderBookEntry
+	for _, entry := range append(orderBook.GetSellOrders(), orderBook.GetBuyOrders()...) {
+		if entry.Conditions.IsImmediate() && filled[entry.OrderID()] < entry.Quantity {
+			killed = append(killed, entry)
+		}
+	}
+	return killed
 }
 
-// buildOrderBook constructs the current order book for a security
-func (e *OrderMatchingEngine) buildOrderBook(securityID string) (*OrderBook, error) {
-	orderBook := NewOrderBook(securityID)
+// MatchSpecificOrders matches a bid against the listing it was placed on. The
+// trade is for as many shares as both sides still have, at the listing's
+// asking price when it has one and the bid price otherwise.
+func (e *OrderMatchingEngine) MatchSpecificOrders(listingID, bidID string) (*MatchResult, error) {
+	b, err := e.bids.FindByID(bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bid: %w", err)
+	}
+	if b.ListingID != listingID {
+		return nil, fmt.Errorf("bid %s was not placed on listing %s", bidID, listingID)
+	}
+	if !b.IsActive() {
+		return nil, fmt.Errorf("bid %s is not active", bidID)
+	}
 
-	// Get all active listings for the security
-	listings, err := e.getActiveListings(securityID)
+	l, err := e.tradableListing(listingID, b.BidderID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get active listings: %w", err)
+		return nil, err
 	}
 
-	// Add sell orders from listings
-	for _, listing := range listings {
-		entry := &OrderBookEntry{
-			ListingID:    listing.ListingID,
-			UserID:       listing.SellerID,
-			SecurityID:   securityID,
-			OrderType:    "sell",
-			Quantity:     listing.SharesRemaining,
-			Price:        listing.CurrentPrice,
-			Timestamp:    listing.CreatedAt,
-			IsAccredited: true, // Assume sellers are accredited
-			ExpiresAt:    listing.ExpiresAt,
+	price := b.BidPrice
+	if l.CurrentPrice != nil {
+		if b.BidPrice.LessThan(*l.CurrentPrice) {
+			return nil, fmt.Errorf("bid price %s is below the asking price %s", b.BidPrice, *l.CurrentPrice)
 		}
-		orderBook.AddSellOrder(entry)
+		price = *l.CurrentPrice
 	}
 
-	// Get all active bids for the security
-	bids, err := e.getActiveBids(securityID)
+	shares := min(l.SharesRemaining, b.SharesRemaining)
+	if !l.Conditions.AllowsFill(shares, l.SharesRemaining) || !b.Conditions.AllowsFill(shares, b.SharesRemaining) {
+		return nil, fmt.Errorf("a trade of %d shares does not meet the conditions of listing %s and bid %s", shares, listingID, bidID)
+	}
+	return e.newMatch(l, &bidID, b.BidderID, shares, price, NegotiatedTrading)
+}
+
+// NegotiatedMatch builds the match for terms agreed in a negotiation. No bid
+// is involved: the buyer trades directly against the listing.
+func (e *OrderMatchingEngine) NegotiatedMatch(listingID, buyerID string, shares int64, price money.Decimal) (*MatchResult, error) {
+	l, err := e.tradableListing(listingID, buyerID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get active bids: %w", err)
+		return nil, err
+	}
+	if shares > l.SharesRemaining {
+		return nil, fmt.Errorf("listing %s only has %d shares remaining", listingID, l.SharesRemaining)
+	}
+	if !l.Conditions.AllowsFill(shares, l.SharesRemaining) {
+		return nil, fmt.Errorf("a trade of %d shares does not meet the conditions of listing %s", shares, listingID)
 	}
 
-	// Add buy orders from bids
-	for _, bid := range bids {
-		entry := &OrderBookEntry{
-			BidID:        &bid.BidID,
-			UserID:       bid.BidderID,
-			SecurityID:   securityID,
-			OrderType:    "buy",
-			Quantity:     bid.SharesRemaining,
-			Price:        &bid.BidPrice,
-			Timestamp:    bid.PlacedAt,
-			IsAccredited: bid.IsAccredited,
-			ExpiresAt:    bid.ExpiresAt,
-		}
-		orderBook.AddBuyOrder(entry)
+	return e.newMatch(l, nil, buyerID, shares, price, NegotiatedTrading)
+}
+
+// QuoteMatch builds the match for an accepted quote. Quotes trade directly
+// between the holder and the requester, outside the order book, so there is
+// no listing or bid to fill.
+func (e *OrderMatchingEngine) QuoteMatch(securityID, buyerID, sellerID string, shares int64, price money.Decimal) (*MatchResult, error) {
+	if err := e.checkTradingHalt(securityID); err != nil {
+		return nil, err
+	}
+	if err := e.checkSession(securityID, RequestForQuote); err != nil {
+		return nil, err
+	}
+	if err := e.checkSelfTrade(securityID, "", buyerID, sellerID); err != nil {
+		return nil, err
+	}
+	currency, err := e.currency(securityID)
+	if err != nil {
+		return nil, err
+	}
+	totalAmount, err := tradeAmount(price, shares, currency)
+	if err != nil {
+		return nil, err
 	}
 
-	return orderBook, nil
+	return &MatchResult{
+		TradeID:           e.generateTradeID(),
+		BuyerID:           buyerID,
+		SellerID:          sellerID,
+		SecurityID:        securityID,
+		SharesTraded:      shares,
+		TradePrice:        price,
+		TotalAmount:       totalAmount,
+		SettlementDate:    e.calculateSettlementDate(),
+		MatchingAlgorithm: string(RequestForQuote),
+	}, nil
 }
 
-// matchPriceTimePriority implements price-time priority matching
-func (e *OrderMatchingEngine) matchPriceTimePriority(orderBook *OrderBook) ([]*MatchResult, error) {
-	var matches []*MatchResult
+// tradableListing loads a listing and checks that the buyer can trade
+// against it
+func (e *OrderMatchingEngine) tradableListing(listingID, buyerID string) (*listing.ListingAggregate, error) {
+	l, err := e.listings.FindByID(listingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find listing: %w", err)
+	}
+	if !l.IsActive() {
+		return nil, fmt.Errorf("listing %s is not active", listingID)
+	}
+	if err := e.checkTradingHalt(l.SecurityID); err != nil {
+		return nil, err
+	}
+	if err := e.checkSession(l.SecurityID, NegotiatedTrading); err != nil {
+		return nil, err
+	}
 
-	// Sort sell orders by price (ascending), then by time (ascending)
-	sellOrders := orderBook.GetSellOrders()
-	sort.Slice(sellOrders, func(i, j int) bool {
-		if sellOrders[i].Price == nil && sellOrders[j].Price == nil {
-			return sellOrders[i].Timestamp.Before(sellOrders[j].Timestamp)
+	requiresAccreditation, eligible := listingRestrictions(l)
+	if !eligible {
+		return nil, fmt.Errorf("listing %s has restrictions that cannot be verified", listingID)
+	}
+
+	isAccredited, err := e.investors.IsAccredited(buyerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify buyer: %w", err)
+	}
+	if !l.CanAcceptBid(buyerID, isAccredited) || (requiresAccreditation && !isAccredited) {
+		return nil, fmt.Errorf("buyer %s cannot trade on listing %s", buyerID, listingID)
+	}
+
+	if err := e.checkSelfTrade(l.SecurityID, listingID, buyerID, l.SellerID); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// checkMarketOpen returns an error if a trading calendar is set and the
+// market is closed at now
+func (e *OrderMatchingEngine) checkMarketOpen(now time.Time) error {
+	if e.calendar != nil && !e.calendar.IsOpen(now) {
+		return fmt.Errorf("market is closed")
+	}
+	return nil
+}
+
+// checkTradingHalt returns an error if trading in the security is halted,
+// or if the security cannot be loaded to tell
+func (e *OrderMatchingEngine) checkTradingHalt(securityID string) error {
+	security, err := e.securities.FindByID(securityID)
+	if err != nil {
+		return fmt.Errorf("failed to check trading halt: %w", err)
+	}
+	if security.IsHalted() {
+		return fmt.Errorf("trading in %s is halted until %s", securityID, security.HaltedUntil.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// checkSession returns an error if the security's trading session does not
+// allow the matching algorithm. Only call auctions run during the opening and
+// closing auctions.
+func (e *OrderMatchingEngine) checkSession(securityID string, algorithm MatchingAlgorithm) error {
+	if e.sessions == nil {
+		return nil
+	}
+	return e.sessions.CheckMatching(securityID, algorithm == UniformPriceAuction)
+}
+
+// priceBand returns the band matching runs on the security must trade in, or
+// nil when price bands are off or the band cannot be built
+func (e *OrderMatchingEngine) priceBand(securityID string) *PriceBand {
+	if e.bands == nil || e.marketData == nil {
+		return nil
+	}
+
+	config := e.bands.Default
+	if security, err := e.securities.FindByID(securityID); err == nil {
+		config = e.bands.For(security.SecurityType)
+	}
+
+	band, err := newPriceBand(securityID, config, e.marketData)
+	if err != nil {
+		// Newly listed securities have no reference price until they trade,
+		// so report each security once rather than on every round
+		if _, reported := e.unbanded.LoadOrStore(securityID, true); !reported {
+			fmt.Printf("Matching %s without a price band: %v\n", securityID, err)
 		}
-		if sellOrders[i].Price == nil {
-			return true // Market orders have priority
+		return nil
+	}
+	e.unbanded.Delete(securityID)
+	return band
+}
+
+func (e *OrderMatchingEngine) newMatch(l *listing.ListingAggregate, bidID *string, buyerID string, shares int64, price money.Decimal, algorithm MatchingAlgorithm) (*MatchResult, error) {
+	currency, err := e.currency(l.SecurityID)
+	if err != nil {
+		return nil, err
+	}
+	totalAmount, err := tradeAmount(price, shares, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MatchResult{
+		TradeID:           e.generateTradeID(),
+		ListingID:         l.ID,
+		BidID:             bidID,
+		BuyerID:           buyerID,
+		SellerID:          l.SellerID,
+		SecurityID:        l.SecurityID,
+		SharesTraded:      shares,
+		TradePrice:        price,
+		TotalAmount:       totalAmount,
+		SettlementDate:    e.calculateSettlementDate(),
+		MatchingAlgorithm: string(algorithm),
+	}, nil
+}
+
+// currency returns the currency the security trades in
+func (e *OrderMatchingEngine) currency(securityID string) (string, error) {
+	security, err := e.securities.FindByID(securityID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find security currency: %w", err)
+	}
+	return security.Currency, nil
+}
+
+// tradeAmount returns the value of the shares at the price
+func tradeAmount(price money.Decimal, shares int64, currency string) (money.Money, error) {
+	amount, err := price.MulInt(shares)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("failed to value %d shares at %s: %w", shares, price, err)
+	}
+	return money.New(amount, currency), nil
+}
+
+// buildOrderBook returns the crossing part of the live order book for a
+// security. The book is brought up to date with the event store first, so
+// only events written since the previous call are applied.
+func (e *OrderMatchingEngine) buildOrderBook(securityID string) (*OrderBook, error) {
+	if err := e.books.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync order books: %w", err)
+	}
+
+	orderBook := e.books.Book(securityID).CrossingSnapshot()
+	return e.refreshOrderBook(orderBook), nil
+}
+
+// refreshOrderBook checks every order in a book snapshot against its listing or
+// bid aggregate. Orders that can no longer trade are dropped and quantities are
+// taken from the aggregates, which are the source of truth for fills.
+func (e *OrderMatchingEngine) refreshOrderBook(orderBook *OrderBook) *OrderBook {
+	refreshed := NewOrderBook(orderBook.SecurityID)
+
+	owners := make(map[string]string)
+	ownerOf := func(userID string) string {
+		owner, resolved := owners[userID]
+		if !resolved {
+			owner = e.beneficialOwner(userID)
+			owners[userID] = owner
 		}
-		if sellOrders[j].Price == nil {
-			return false
+		return owner
+	}
+
+	for _, entry := range orderBook.GetSellOrders() {
+		l, err := e.listings.FindByID(entry.ListingID)
+		if err != nil {
+			fmt.Printf("Failed to load listing %s for matching: %v\n", entry.ListingID, err)
+			continue
 		}
-		if *sellOrders[i].Price == *sellOrders[j].Price {
-			return sellOrders[i].Timestamp.Before(sellOrders[j].Timestamp)
+		if !l.IsActive() || l.IsExpired() {
+			continue
 		}
-		return *sellOrders[i].Price < *sellOrders[j].Price
-	})
 
-	// Sort buy orders by price (descending), then by time (ascending)
-	buyOrders := orderBook.GetBuyOrders()
-	sort.Slice(buyOrders, func(i, j int) bool {
-		if buyOrders[i].Price == nil && buyOrders[j].Price == nil {
-			return buyOrders[i].Timestamp.Before(buyOrders[j].Timestamp)
-		}
-		if buyOrders[i].Price == nil {
-			return true // Market orders have priority
+		requiresAccreditation, eligible := listingRestrictions(l)
+		if !eligible {
+			continue
 		}
-		if buyOrders[j].Price == nil {
-			return false
+
+		entry.Quantity = l.SharesRemaining
+		entry.IsAccredited = requiresAccreditation
+		entry.Conditions = l.Conditions
+		entry.Hidden = l.GetSharesHidden()
+		entry.OwnerID = ownerOf(l.SellerID)
+		refreshed.AddSellOrder(entry)
+	}
+
+	accredited := make(map[string]bool)
+	for _, entry := range orderBook.GetBuyOrders() {
+		b, err := e.bids.FindByID(*entry.BidID)
+		if err != nil {
+			fmt.Printf("Failed to load bid %s for matching: %v\n", *entry.BidID, err)
+			continue
 		}
-		if *buyOrders[i].Price == *buyOrders[j].Price {
-			return buyOrders[i].Timestamp.Before(buyOrders[j].Timestamp)
+		if !b.IsActive() || b.IsExpired() {
+			continue
 		}
-		return *buyOrders[i].Price > *buyOrders[j].Price
-	})
 
-	// Match orders
-	sellIndex := 0
-	buyIndex := 0
-
-	for sellIndex < len(sellOrders) && buyIndex < len(buyOrders) {
-		sellOrder := sellOrders[sellIndex]
-		buyOrder := buyOrders[buyIndex]
-
-		// Check if orders can be matched
-		if !e.canMatch(sellOrder, buyOrder) {
-			// Try next buy order
-			buyIndex++
-			if buyIndex >= len(buyOrders) {
-				// No more buy orders, try next sell order
-				sellIndex++
-				buyIndex = 0
+		isAccredited, checked := accredited[b.BidderID]
+		if !checked {
+			isAccredited, err = e.investors.IsAccredited(b.BidderID)
+			if err != nil {
+				fmt.Printf("Failed to verify bidder %s for matching: %v\n", b.BidderID, err)
+				isAccredited = false
 			}
-			continue
+			accredited[b.BidderID] = isAccredited
 		}
 
-		// Determine trade price (seller's price takes precedence in price-time priority)
-		var tradePrice float64
-		if sellOrder.Price != nil {
-			tradePrice = *sellOrder.Price
-		} else if buyOrder.Price != nil {
-			tradePrice = *buyOrder.Price
-		} else {
-			// Both are market orders - need a reference price
-			// In practice, this would use last trade price or opening price
-			tradePrice = 100.0 // Placeholder
-		}
+		entry.Quantity = b.SharesRemaining
+		entry.IsAccredited = isAccredited
+		entry.Conditions = b.Conditions
+		entry.OwnerID = ownerOf(b.BidderID)
+		refreshed.AddBuyOrder(entry)
+	}
 
-		// Determine quantity
-		quantity := min(sellOrder.Quantity, buyOrder.Quantity)
+	return refreshed
+}
 
-		// Create match result
-		match := &MatchResult{
-			TradeID:          e.generateTradeID(),
-			ListingID:        sellOrder.ListingID,
-			BidID:            buyOrder.BidID,
-			BuyerID:          buyOrder.UserID,
-			SellerID:         sellOrder.UserID,
-			SecurityID:       orderBook.SecurityID,
-			SharesTraded:     quantity,
-			TradePrice:       tradePrice,
-			TotalAmount:      float64(quantity) * tradePrice,
-			SettlementDate:   e.calculateSettlementDate(),
-			MatchingAlgorithm: string(PriceTimePriority),
-		}
+// listingRestrictions reports whether a listing only trades with accredited
+// buyers and whether the engine can enforce its restriction at all. Investor
+// type and jurisdiction are not tracked yet, so listings restricted on those
+// are kept out of automated matching rather than matched unchecked.
+func listingRestrictions(l *listing.ListingAggregate) (requiresAccreditation, eligible bool) {
+	requiresAccreditation = l.AccreditedOnly
+	if l.RestrictionType == nil {
+		return requiresAccreditation, true
+	}
 
-		matches = append(matches, match)
+	switch *l.RestrictionType {
+	case listing.RestrictionNone:
+		return requiresAccreditation, true
+	case listing.RestrictionAccredited:
+		return true, true
+	default:
+		return requiresAccreditation, false
+	}
+}
 
-		// Update order quantities
-		sellOrder.Quantity -= quantity
-		buyOrder.Quantity -= quantity
+// withBooks returns a copy of the engine that matches against the given
+// books, such as the book owned by a security's matching actor
+func (e *OrderMatchingEngine) withBooks(books *OrderBookManager) *OrderMatchingEngine {
+	scoped := *e
+	scoped.books = books
+	return &scoped
+}
 
-		// Remove fully filled orders
-		if sellOrder.Quantity == 0 {
-			sellIndex++
-		}
-		if buyOrder.Quantity == 0 {
-			buyIndex++
+// OrderBooks returns the live order books maintained by the engine
+func (e *OrderMatchingEngine) OrderBooks() *OrderBookManager {
+	return e.books
+}
+
+// RebuildOrderBooks discards the live order books and replays them from the
+// event store
+func (e *OrderMatchingEngine) RebuildOrderBooks() error {
+	return e.books.Rebuild()
+}
+
+// matchPriceTimePriority implements price-time priority matching
+func (e *OrderMatchingEngine) matchPriceTimePriority(orderBook *OrderBook) ([]*MatchResult, error) {
+	var matches []*MatchResult
+
+	sellOrders := splitIcebergReserves(orderBook.GetSellOrders())
+	buyOrders := orderBook.GetBuyOrders()
+	sortByPriceTimePriority(sellOrders, buyOrders)
+
+	// Match each sell order against the best buy orders it is compatible with.
+	// Buy orders skipped for one seller (e.g. on accreditation or because
+	// their conditions cannot be met) stay available to the next, in their
+	// original priority.
+	for _, sellOrder := range sellOrders {
+		for _, buyOrder := range buyOrders {
+			if sellOrder.Quantity == 0 {
+				break
+			}
+			if buyOrder.Quantity == 0 || !e.canMatch(sellOrder, buyOrder) {
+				continue
+			}
+			if e.preventSelfTrade(orderBook, sellOrder, buyOrder) {
+				continue
+			}
+
+			// Determine trade price (seller's price takes precedence in price-time priority)
+			var tradePrice money.Decimal
+			if sellOrder.Price != nil {
+				tradePrice = *sellOrder.Price
+			} else if buyOrder.Price != nil {
+				tradePrice = *buyOrder.Price
+			} else if reference := e.referencePrice(orderBook.SecurityID); reference != nil {
+				// Both are market orders, so they trade at the reference price
+				tradePrice = *reference
+			} else {
+				// Two market orders cannot be priced without market data
+				continue
+			}
+			if orderBook.breaches(tradePrice) {
+				return matches, nil
+			}
+
+			// Determine quantity
+			quantity := fillQuantity(sellOrder, buyOrder, sellOrder.Quantity)
+			if quantity == 0 {
+				continue
+			}
+
+			totalAmount, err := tradeAmount(tradePrice, quantity, orderBook.Currency)
+			if err != nil {
+				return nil, err
+			}
+
+			// Create match result
+			match := &MatchResult{
+				TradeID:          e.generateTradeID(),
+				ListingID:        sellOrder.ListingID,
+				BidID:            buyOrder.BidID,
+				BuyerID:          buyOrder.UserID,
+				SellerID:         sellOrder.UserID,
+				SecurityID:       orderBook.SecurityID,
+				SharesTraded:     quantity,
+				TradePrice:       tradePrice,
+				TotalAmount:      totalAmount,
+				SettlementDate:   e.calculateSettlementDate(),
+				MatchingAlgorithm: string(PriceTimePriority),
+			}
+
+			matches = append(matches, match)
+
+			// Update order quantities
+			sellOrder.Quantity -= quantity
+			buyOrder.Quantity -= quantity
 		}
 	}
 
 	return matches, nil
 }
 
-// matchUniformPriceAuction implements uniform price auction matching
+// matchUniformPriceAuction runs a call auction: every order that can trade is
+// executed at a single clearing price chosen to maximize volume. The
+// indicative price and imbalance are published before any trade is made.
 func (e *OrderMatchingEngine) matchUniformPriceAuction(orderBook *OrderBook) ([]*MatchResult, error) {
-	// Simplified implementation - in practice this would be more complex
-	// This algorithm finds a single clearing price where supply meets demand
+	clearing, err := CalculateAuctionClearing(orderBook, e.referencePrice(orderBook.SecurityID))
+	if err != nil {
+		return nil, fmt.Errorf("no clearing price found: %w", err)
+	}
+	if clearing == nil {
+		return []*MatchResult{}, nil
+	}
 
-	sellOrders := orderBook.GetSellOrders()
-	buyOrders := orderBook.GetBuyOrders()
+	e.publishIndicativePrice(clearing)
+	if orderBook.breaches(clearing.Price) {
+		return []*MatchResult{}, nil
+	}
 
-	// Sort orders
-	sort.Slice(sellOrders, func(i, j int) bool {
-		if sellOrders[i].Price == nil {
-			return true
+	// Only orders willing to trade at the clearing price take part
+	var sellOrders, buyOrders []*OrderBookEntry
+	for _, order := range orderBook.GetSellOrders() {
+		if order.Price == nil || !order.Price.GreaterThan(clearing.Price) {
+			sellOrders = append(sellOrders, order)
 		}
-		if sellOrders[j].Price == nil {
-			return false
+	}
+	for _, order := range orderBook.GetBuyOrders() {
+		if order.Price == nil || !order.Price.LessThan(clearing.Price) {
+			buyOrders = append(buyOrders, order)
 		}
-		return *sellOrders[i].Price < *sellOrders[j].Price
-	})
+	}
+	sellOrders = splitIcebergReserves(sellOrders)
+	sortByPriceTimePriority(sellOrders, buyOrders)
 
-	sort.Slice(buyOrders, func(i, j int) bool {
-		if buyOrders[i].Price == nil {
-			return true
-		}
-		if buyOrders[j].Price == nil {
-			return false
+	// Allocate in price-time priority on both sides. The side with surplus
+	// interest is left with its least aggressive and latest orders unfilled.
+	var matches []*MatchResult
+	remaining := clearing.ExecutableVolume
+	for _, sellOrder := range sellOrders {
+		for _, buyOrder := range buyOrders {
+			if sellOrder.Quantity == 0 || remaining == 0 {
+				break
+			}
+			if buyOrder.Quantity == 0 || !e.canMatch(sellOrder, buyOrder) {
+				continue
+			}
+			if e.preventSelfTrade(orderBook, sellOrder, buyOrder) {
+				continue
+			}
+
+			quantity := fillQuantity(sellOrder, buyOrder, remaining)
+			if quantity == 0 {
+				continue
+			}
+
+			totalAmount, err := tradeAmount(clearing.Price, quantity, orderBook.Currency)
+			if err != nil {
+				return nil, err
+			}
+
+			match := &MatchResult{
+				TradeID:           e.generateTradeID(),
+				ListingID:         sellOrder.ListingID,
+				BidID:             buyOrder.BidID,
+				BuyerID:           buyOrder.UserID,
+				SellerID:          sellOrder.UserID,
+				SecurityID:        orderBook.SecurityID,
+				SharesTraded:      quantity,
+				TradePrice:        clearing.Price,
+				TotalAmount:       totalAmount,
+				SettlementDate:    e.calculateSettlementDate(),
+				MatchingAlgorithm: string(UniformPriceAuction),
+			}
+			matches = append(matches, match)
+
+			sellOrder.Quantity -= quantity
+			buyOrder.Quantity -= quantity
+			remaining -= quantity
 		}
-		return *buyOrders[i].Price > *buyOrders[j].Price
-	})
+	}
 
-	// Find clearing price
-	clearingPrice, err := e.findClearingPrice(sellOrders, buyOrders)
+	return matches, nil
+}
+
+// IndicativeAuctionPrice calculates and publishes the price a call auction
+// for the security would clear at if it ran now. A nil clearing is returned
+// when the book does not cross.
+func (e *OrderMatchingEngine) IndicativeAuctionPrice(securityID string) (*AuctionClearing, error) {
+	clearing, err := e.CalculateIndicativeAuctionPrice(securityID)
+	if err != nil {
+		return nil, err
+	}
+	if clearing != nil {
+		e.publishIndicativePrice(clearing)
+	}
+	return clearing, nil
+}
+
+// CalculateIndicativeAuctionPrice calculates the price a call auction for the
+// security would clear at if it ran now, without publishing it
+func (e *OrderMatchingEngine) CalculateIndicativeAuctionPrice(securityID string) (*AuctionClearing, error) {
+	orderBook, err := e.buildOrderBook(securityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build order book: %w", err)
+	}
+
+	clearing, err := CalculateAuctionClearing(orderBook, e.referencePrice(securityID))
 	if err != nil {
 		return nil, fmt.Errorf("no clearing price found: %w", err)
 	}
+	return clearing, nil
+}
+
+// referencePrice returns the price used to break auction ties and to price
+// market orders that cross each other, or nil when no market data is
+// available
+func (e *OrderMatchingEngine) referencePrice(securityID string) *money.Decimal {
+	if e.marketData == nil {
+		return nil
+	}
+	price, err := e.marketData.GetReferencePrice(securityID)
+	if err != nil || !price.IsPositive() {
+		return nil
+	}
+	return &price
+}
+
+// publishIndicativePrice announces the auction price and imbalance so
+// participants see them before the auction executes
+func (e *OrderMatchingEngine) publishIndicativePrice(clearing *AuctionClearing) {
+	if e.eventBus == nil {
+		return
+	}
+	event := NewAuctionIndicativePriceCalculated(clearing)
+	if err := e.eventBus.Publish(event); err != nil {
+		fmt.Printf("Failed to publish indicative price for %s: %v\n", clearing.SecurityID, err)
+	}
+}
+
+// matchNegotiated matches bids only against the listing they were placed on,
+// rather than against the best price in the book. Bids are taken in time
+// order and trade at the listing's asking price when it has one.
+func (e *OrderMatchingEngine) matchNegotiated(orderBook *OrderBook) ([]*MatchResult, error) {
+	sellOrders := make(map[string]*OrderBookEntry)
+	for _, order := range orderBook.GetSellOrders() {
+		sellOrders[order.ListingID] = order
+	}
+
+	buyOrders := orderBook.GetBuyOrders()
+	sort.SliceStable(buyOrders, func(i, j int) bool {
+		return buyOrders[i].Timestamp.Before(buyOrders[j].Timestamp)
+	})
 
-	// Create matches at clearing price
 	var matches []*MatchResult
-	sellIndex := 0
-	buyIndex := 0
+	for _, buyOrder := range buyOrders {
+		b, err := e.bids.FindByID(*buyOrder.BidID)
+		if err != nil {
+			fmt.Printf("Failed to load bid %s for matching: %v\n", *buyOrder.BidID, err)
+			continue
+		}
 
-	for sellIndex < len(sellOrders) && buyIndex < len(buyOrders) {
-		sellOrder := sellOrders[sellIndex]
-		buyOrder := buyOrders[buyIndex]
+		sellOrder, ok := sellOrders[b.ListingID]
+		if !ok || sellOrder.Quantity == 0 || buyOrder.Quantity == 0 || !e.canMatch(sellOrder, buyOrder) {
+			continue
+		}
+		if e.preventSelfTrade(orderBook, sellOrder, buyOrder) {
+			continue
+		}
 
-		// Only match orders that would execute at clearing price
-		if sellOrder.Price != nil && *sellOrder.Price > clearingPrice {
-			break
+		price := sellOrder.Price
+		if price == nil {
+			price = buyOrder.Price
+		}
+		if price == nil {
+			continue
 		}
-		if buyOrder.Price != nil && *buyOrder.Price < clearingPrice {
-			break
+		if orderBook.breaches(*price) {
+			return matches, nil
 		}
 
-		if !e.canMatch(sellOrder, buyOrder) {
-			buyIndex++
+		quantity := fillQuantity(sellOrder, buyOrder, sellOrder.Quantity)
+		if quantity == 0 {
 			continue
 		}
 
-		quantity := min(sellOrder.Quantity, buyOrder.Quantity)
+		totalAmount, err := tradeAmount(*price, quantity, orderBook.Currency)
+		if err != nil {
+			return nil, err
+		}
 
 		match := &MatchResult{
-			TradeID:          e.generateTradeID(),
-			ListingID:        sellOrder.ListingID,
-			BidID:            buyOrder.BidID,
-			BuyerID:          buyOrder.UserID,
-			SellerID:         sellOrder.UserID,
-			SecurityID:       orderBook.SecurityID,
-			SharesTraded:     quantity,
-			TradePrice:       clearingPrice,
-			TotalAmount:      float64(quantity) * clearingPrice,
-			SettlementDate:   e.calculateSettlementDate(),
-			MatchingAlgorithm: string(UniformPriceAuction),
+			TradeID:           e.generateTradeID(),
+			ListingID:         sellOrder.ListingID,
+			BidID:             buyOrder.BidID,
+			BuyerID:           buyOrder.UserID,
+			SellerID:          sellOrder.UserID,
+			SecurityID:        orderBook.SecurityID,
+			SharesTraded:      quantity,
+			TradePrice:        *price,
+			TotalAmount:       totalAmount,
+			SettlementDate:    e.calculateSettlementDate(),
+			MatchingAlgorithm: string(NegotiatedTrading),
 		}
-
 		matches = append(matches, match)
 
 		sellOrder.Quantity -= quantity
 		buyOrder.Quantity -= quantity
+	}
 
-		if sellOrder.Quantity == 0 {
-			sellIndex++
-		}
-		if buyOrder.Quantity == 0 {
-			buyIndex++
+	return matches, nil
+}
+
+// Helper methods
+
+// splitIcebergReserves gives the hidden reserve of every iceberg order its own
+// entry, timestamped now. Each tranche shown from a reserve loses time
+// priority, so the reserve trades behind every order already resting at its
+// price while the displayed shares keep their place.
+func splitIcebergReserves(orders []*OrderBookEntry) []*OrderBookEntry {
+	now := time.Now()
+	split := make([]*OrderBookEntry, 0, len(orders))
+	var reserves []*OrderBookEntry
+
+	for _, order := range orders {
+		if order.Hidden <= 0 || order.Displayed() <= 0 {
+			split = append(split, order)
+			continue
 		}
+
+		displayed := copyEntry(order)
+		displayed.Quantity = order.Displayed()
+		displayed.Hidden = 0
+		split = append(split, displayed)
+
+		reserve := copyEntry(order)
+		reserve.Quantity = order.Hidden
+		reserve.Hidden = 0
+		reserve.Timestamp = now
+		reserves = append(reserves, reserve)
 	}
 
-	return matches, nil
+	return append(split, reserves...)
 }
 
-// matchNegotiated implements negotiated trading matching
-func (e *OrderMatchingEngine) matchNegotiated(orderBook *OrderBook) ([]*MatchResult, error) {
-	// Negotiated trading allows for more flexible matching rules
-	// This is a simplified implementation
-	return []*MatchResult{}, nil
+// sortByPriceTimePriority orders sells by ascending and buys by descending
+// price, market orders first and earlier orders first within a price. Orders
+// with the same price and time keep their relative order.
+func sortByPriceTimePriority(sellOrders, buyOrders []*OrderBookEntry) {
+	sort.SliceStable(sellOrders, func(i, j int) bool {
+		if sellOrders[i].Price == nil && sellOrders[j].Price == nil {
+			return sellOrders[i].Timestamp.Before(sellOrders[j].Timestamp)
+		}
+		if sellOrders[i].Price == nil {
+			return true // Market orders have priority
+		}
+		if sellOrders[j].Price == nil {
+			return false
+		}
+		if sellOrders[i].Price.Equal(*sellOrders[j].Price) {
+			return sellOrders[i].Timestamp.Before(sellOrders[j].Timestamp)
+		}
+		return sellOrders[i].Price.LessThan(*sellOrders[j].Price)
+	})
+
+	sort.SliceStable(buyOrders, func(i, j int) bool {
+		if buyOrders[i].Price == nil && buyOrders[j].Price == nil {
+			return buyOrders[i].Timestamp.Before(buyOrders[j].Timestamp)
+		}
+		if buyOrders[i].Price == nil {
+			return true // Market orders have priority
+		}
+		if buyOrders[j].Price == nil {
+			return false
+		}
+		if buyOrders[i].Price.Equal(*buyOrders[j].Price) {
+			return buyOrders[i].Timestamp.Before(buyOrders[j].Timestamp)
+		}
+		return buyOrders[i].Price.GreaterThan(*buyOrders[j].Price)
+	})
 }
 
-// Helper methods
+// fillQuantity returns the shares a sell and a buy order can trade with each
+// other, at most limit, or zero if the conditions of either order rule out
+// every quantity on offer
+func fillQuantity(sellOrder, buyOrder *OrderBookEntry, limit int64) int64 {
+	quantity := min(limit, min(sellOrder.Quantity, buyOrder.Quantity))
+	if !sellOrder.Conditions.AllowsFill(quantity, sellOrder.Quantity) || !buyOrder.Conditions.AllowsFill(quantity, buyOrder.Quantity) {
+		return 0
+	}
+	return quantity
+}
 
 func (e *OrderMatchingEngine) canMatch(sellOrder, buyOrder *OrderBookEntry) bool {
-	// Check accreditation requirements
+	// Listings restricted to accredited investors only trade with accredited buyers
 	if sellOrder.IsAccredited && !buyOrder.IsAccredited {
-		// This would check if the listing requires accredited buyers
-		// For now, assume all trades require accreditation
 		return false
 	}
 
 	// Check price compatibility
 	if sellOrder.Price != nil && buyOrder.Price != nil {
-		return *buyOrder.Price >= *sellOrder.Price
+		return !buyOrder.Price.LessThan(*sellOrder.Price)
 	}
 
 	// Market orders can always match
 	return true
 }
 
-func (e *OrderMatchingEngine) findClearingPrice(sellOrders, buyOrders []*OrderBookEntry) (float64, error) {
-	// Simplified clearing price calculation
-	// In practice, this would analyze supply and demand curves
-	
-	if len(sellOrders) == 0 || len(buyOrders) == 0 {
-		return 0, fmt.Errorf("insufficient orders")
-	}
-
-	// Use midpoint of best bid and ask as clearing price
-	var bestAsk, bestBid float64
-	
-	if sellOrders[0].Price != nil {
-		bestAsk = *sellOrders[0].Price
-	} else {
-		bestAsk = 100.0 // Default price for market orders
-	}
-	
-	if buyOrders[0].Price != nil {
-		bestBid = *buyOrders[0].Price
-	} else {
-		bestBid = 100.0 // Default price for market orders
-	}
-
-	if bestBid >= bestAsk {
-		return (bestBid + bestAsk) / 2, nil
-	}
-
-	return 0, fmt.Errorf("no overlap between bid and ask")
-}
-
 func (e *OrderMatchingEngine) generateTradeID() string {
-	// Generate unique trade ID
-	return fmt.Sprintf("trade_%d", time.Now().UnixNano())
+	// Generate unique trade ID; several trades can be matched in the same instant
+	return fmt.Sprintf("trade_%s", uuid.New().String())
 }
 
+// calculateSettlementDate returns the close of business on the settlement
+// date of a trade made now, T+N business days on the trading calendar
 func (e *OrderMatchingEngine) calculateSettlementDate() time.Time {
-	// T+2 settlement (trade date + 2 business days)
-	return time.Now().AddDate(0, 0, 2)
-}
-
-func (e *OrderMatchingEngine) getActiveListings(securityID string) ([]ListingSummary, error) {
-	// This would query the listing repository/projection
-	// For now, return empty slice
-	return []ListingSummary{}, nil
-}
-
-func (e *OrderMatchingEngine) getActiveBids(securityID string) ([]BidSummary, error) {
-	// This would query the bid repository/projection
-	// For now, return empty slice
-	return []BidSummary{}, nil
+	return e.tradingCalendar().SettlementDate(time.Now())
 }
 
-// Helper types for order matching
-
-type ListingSummary struct {
-	ListingID       string
-	SellerID        string
-	SharesRemaining int64
-	CurrentPrice    *float64
-	CreatedAt       time.Time
-	ExpiresAt       *time.Time
-}
-
-type BidSummary struct {
-	BidID           string
-	BidderID        string
-	SharesRemaining int64
-	BidPrice        float64
-	PlacedAt        time.Time
-	ExpiresAt       *time.Time
-	IsAccredited    bool
+// tradingCalendar returns the calendar set on the engine, or the default
+// calendar when none is
+func (e *OrderMatchingEngine) tradingCalendar() *calendar.Calendar {
+	if e.calendar == nil {
+		return calendar.Default()
+	}
+	return e.calendar
 }
 
 // OrderBook represents the current order book for a security
 type OrderBook struct {
 	SecurityID string
+	Currency   string // Currency the security trades in; empty means money.DefaultCurrency
 	BuyOrders  []*OrderBookEntry
 	SellOrders []*OrderBookEntry
+	Prevented  []*SelfTradePrevention // Self-trades prevented while matching this book
+	Band       *PriceBand             // Range trades must fall in; nil for no limit
+	Halt       *VolatilityHalt        // First trade price found outside the band
 }
 
 func NewOrderBook(securityID string) *OrderBook {
@@ -460,6 +1039,16 @@ func NewOrderBook(securityID string) *OrderBook {
 	}
 }
 
+// breaches returns true, recording the halt, if a trade at the price would
+// fall outside the book's price band. Matching must stop when it does.
+func (ob *OrderBook) breaches(price money.Decimal) bool {
+	if ob.Band.Contains(price) {
+		return false
+	}
+	ob.Halt = &VolatilityHalt{Band: *ob.Band, TradePrice: price}
+	return true
+}
+
 func (ob *OrderBook) AddBuyOrder(order *OrderBookEntry) {
 	ob.BuyOrders = append(ob.BuyOrders, order)
 }
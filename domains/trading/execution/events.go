@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
 )
 
 // Trade Execution Domain Events
@@ -18,14 +19,15 @@ type TradeMatched struct {
 	SellerID          string  `json:"sellerId"`
 	SecurityID        string  `json:"securityId"`
 	SharesTraded      int64   `json:"sharesTraded"`
-	TradePrice        float64 `json:"tradePrice"`
-	TotalAmount       float64 `json:"totalAmount"`
+	TradePrice        money.Decimal `json:"tradePrice"`
+	TotalAmount       money.Decimal `json:"totalAmount"`
+	Currency          string  `json:"currency,omitempty"` // Empty for trades matched before currencies were recorded
 	SettlementDate    time.Time `json:"settlementDate"`
 	MatchingAlgorithm string  `json:"matchingAlgorithm"`
 }
 
 // NewTradeMatched creates a new TradeMatched event
-func NewTradeMatched(tradeID, listingID string, bidID *string, buyerID, sellerID, securityID string, sharesTraded int64, tradePrice, totalAmount float64, settlementDate time.Time, matchingAlgorithm string) *TradeMatched {
+func NewTradeMatched(tradeID, listingID string, bidID *string, buyerID, sellerID, securityID string, sharesTraded int64, tradePrice money.Decimal, totalAmount money.Money, settlementDate time.Time, matchingAlgorithm string) *TradeMatched {
 	return &TradeMatched{
 		BaseEvent:         events.NewBaseEvent(tradeID, "Trade"),
 		ListingID:         listingID,
@@ -35,7 +37,8 @@ func NewTradeMatched(tradeID, listingID string, bidID *string, buyerID, sellerID
 		SecurityID:        securityID,
 		SharesTraded:      sharesTraded,
 		TradePrice:        tradePrice,
-		TotalAmount:       totalAmount,
+		TotalAmount:       totalAmount.Amount,
+		Currency:          totalAmount.Currency,
 		SettlementDate:    settlementDate,
 		MatchingAlgorithm: matchingAlgorithm,
 	}
@@ -137,18 +140,18 @@ func (e *TradeSettlementInitiated) GetMetadata() events.Metadata {
 // PaymentReceived event is emitted when payment is received in escrow
 type PaymentReceived struct {
 	events.BaseEvent
-	Amount          float64   `json:"amount"`
+	Amount          money.Decimal `json:"amount"`
 	Currency        string    `json:"currency"`
 	PaymentMethod   string    `json:"paymentMethod"`
 	TransactionID   string    `json:"transactionId"`
 	ReceivedAt      time.Time `json:"receivedAt"`
 }
 
-func NewPaymentReceived(tradeID string, amount float64, currency, paymentMethod, transactionID string, receivedAt time.Time) *PaymentReceived {
+func NewPaymentReceived(tradeID string, amount money.Money, paymentMethod, transactionID string, receivedAt time.Time) *PaymentReceived {
 	return &PaymentReceived{
 		BaseEvent:     events.NewBaseEvent(tradeID, "Trade"),
-		Amount:        amount,
-		Currency:      currency,
+		Amount:        amount.Amount,
+		Currency:      amount.Currency,
 		PaymentMethod: paymentMethod,
 		TransactionID: transactionID,
 		ReceivedAt:    receivedAt,
@@ -220,19 +223,23 @@ func (e *SharesTransferred) GetMetadata() events.Metadata {
 type TradeSettled struct {
 	events.BaseEvent
 	SettledAt         time.Time `json:"settledAt"`
-	FinalAmount       float64   `json:"finalAmount"`
-	Fees              float64   `json:"fees"`
-	Taxes             float64   `json:"taxes"`
+	FinalAmount       money.Decimal `json:"finalAmount"`
+	Fees              money.Decimal `json:"fees"`
+	FeeBreakdown      *FeeBreakdown `json:"feeBreakdown,omitempty"` // Nil for trades settled before fees were itemized
+	Taxes             money.Decimal `json:"taxes"`
+	Currency          string    `json:"currency,omitempty"` // Empty for trades settled before currencies were recorded
 	SettlementMethod  string    `json:"settlementMethod"`
 }
 
-func NewTradeSettled(tradeID string, settledAt time.Time, finalAmount, fees, taxes float64, settlementMethod string) *TradeSettled {
+func NewTradeSettled(tradeID string, settledAt time.Time, finalAmount money.Money, fees *FeeBreakdown, taxes money.Money, settlementMethod string) *TradeSettled {
 	return &TradeSettled{
 		BaseEvent:        events.NewBaseEvent(tradeID, "Trade"),
 		SettledAt:        settledAt,
-		FinalAmount:      finalAmount,
-		Fees:             fees,
-		Taxes:            taxes,
+		FinalAmount:      finalAmount.Amount,
+		Fees:             fees.Total,
+		FeeBreakdown:     fees,
+		Taxes:            taxes.Amount,
+		Currency:         finalAmount.Currency,
 		SettlementMethod: settlementMethod,
 	}
 }
@@ -328,4 +335,326 @@ func (e *TradeCancelled) GetMetadata() events.Metadata {
 		EventVersion:  e.Version,
 		Timestamp:     e.Timestamp,
 	}
-}
\ No newline at end of file
+}
+
+// TradeAdjustmentRequested event is emitted when an administrator asks for a
+// trade to be busted or corrected
+type TradeAdjustmentRequested struct {
+	events.BaseEvent
+	AdjustmentID   string              `json:"adjustmentId"`
+	AdjustmentType TradeAdjustmentType `json:"adjustmentType"`
+	Reason         string              `json:"reason"`
+	RequestedBy    string              `json:"requestedBy"`
+	TradePrice     *money.Decimal      `json:"tradePrice,omitempty"`   // Corrections only
+	SharesTraded   int64               `json:"sharesTraded,omitempty"` // Corrections only
+	RequestedAt    time.Time           `json:"requestedAt"`
+}
+
+func NewTradeAdjustmentRequested(tradeID, adjustmentID string, adjustmentType TradeAdjustmentType, reason, requestedBy string, tradePrice *money.Decimal, sharesTraded int64, requestedAt time.Time) *TradeAdjustmentRequested {
+	return &TradeAdjustmentRequested{
+		BaseEvent:      events.NewBaseEvent(tradeID, "Trade"),
+		AdjustmentID:   adjustmentID,
+		AdjustmentType: adjustmentType,
+		Reason:         reason,
+		RequestedBy:    requestedBy,
+		TradePrice:     tradePrice,
+		SharesTraded:   sharesTraded,
+		RequestedAt:    requestedAt,
+	}
+}
+
+func (e *TradeAdjustmentRequested) GetEventType() string     { return "TradeAdjustmentRequested" }
+func (e *TradeAdjustmentRequested) GetAggregateID() string   { return e.AggregateID }
+func (e *TradeAdjustmentRequested) GetAggregateType() string { return e.AggregateType }
+
+func (e *TradeAdjustmentRequested) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *TradeAdjustmentRequested) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// TradeAdjustmentApproved event is emitted when an administrator approves a
+// pending bust or correction
+type TradeAdjustmentApproved struct {
+	events.BaseEvent
+	AdjustmentID string    `json:"adjustmentId"`
+	ApprovedBy   string    `json:"approvedBy"`
+	ApprovedAt   time.Time `json:"approvedAt"`
+}
+
+func NewTradeAdjustmentApproved(tradeID, adjustmentID, approvedBy string, approvedAt time.Time) *TradeAdjustmentApproved {
+	return &TradeAdjustmentApproved{
+		BaseEvent:    events.NewBaseEvent(tradeID, "Trade"),
+		AdjustmentID: adjustmentID,
+		ApprovedBy:   approvedBy,
+		ApprovedAt:   approvedAt,
+	}
+}
+
+func (e *TradeAdjustmentApproved) GetEventType() string     { return "TradeAdjustmentApproved" }
+func (e *TradeAdjustmentApproved) GetAggregateID() string   { return e.AggregateID }
+func (e *TradeAdjustmentApproved) GetAggregateType() string { return e.AggregateType }
+
+func (e *TradeAdjustmentApproved) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *TradeAdjustmentApproved) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// TradeAdjustmentRejected event is emitted when an administrator rejects a
+// pending bust or correction, leaving the trade as it was
+type TradeAdjustmentRejected struct {
+	events.BaseEvent
+	AdjustmentID string    `json:"adjustmentId"`
+	RejectedBy   string    `json:"rejectedBy"`
+	Reason       string    `json:"reason"`
+	RejectedAt   time.Time `json:"rejectedAt"`
+}
+
+func NewTradeAdjustmentRejected(tradeID, adjustmentID, rejectedBy, reason string, rejectedAt time.Time) *TradeAdjustmentRejected {
+	return &TradeAdjustmentRejected{
+		BaseEvent:    events.NewBaseEvent(tradeID, "Trade"),
+		AdjustmentID: adjustmentID,
+		RejectedBy:   rejectedBy,
+		Reason:       reason,
+		RejectedAt:   rejectedAt,
+	}
+}
+
+func (e *TradeAdjustmentRejected) GetEventType() string     { return "TradeAdjustmentRejected" }
+func (e *TradeAdjustmentRejected) GetAggregateID() string   { return e.AggregateID }
+func (e *TradeAdjustmentRejected) GetAggregateType() string { return e.AggregateType }
+
+func (e *TradeAdjustmentRejected) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *TradeAdjustmentRejected) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// TradeBusted event is emitted when an approved bust voids a trade executed
+// in error. It carries the voided trade so consumers need not load it.
+type TradeBusted struct {
+	events.BaseEvent
+	AdjustmentID string        `json:"adjustmentId"`
+	Reason       string        `json:"reason"`
+	RequestedBy  string        `json:"requestedBy"`
+	ApprovedBy   []string      `json:"approvedBy"`
+	SecurityID   string        `json:"securityId"`
+	BuyerID      string        `json:"buyerId"`
+	SellerID     string        `json:"sellerId"`
+	SharesTraded int64         `json:"sharesTraded"`
+	TradePrice   money.Decimal `json:"tradePrice"`
+	BustedAt     time.Time     `json:"bustedAt"`
+}
+
+func NewTradeBusted(trade *TradeAggregate, adjustment *TradeAdjustment, bustedAt time.Time) *TradeBusted {
+	return &TradeBusted{
+		BaseEvent:    events.NewBaseEvent(trade.ID, "Trade"),
+		AdjustmentID: adjustment.ID,
+		Reason:       adjustment.Reason,
+		RequestedBy:  adjustment.RequestedBy,
+		ApprovedBy:   adjustment.ApprovedBy,
+		SecurityID:   trade.SecurityID,
+		BuyerID:      trade.BuyerID,
+		SellerID:     trade.SellerID,
+		SharesTraded: trade.SharesTraded,
+		TradePrice:   trade.TradePrice,
+		BustedAt:     bustedAt,
+	}
+}
+
+func (e *TradeBusted) GetEventType() string     { return "TradeBusted" }
+func (e *TradeBusted) GetAggregateID() string   { return e.AggregateID }
+func (e *TradeBusted) GetAggregateType() string { return e.AggregateType }
+
+func (e *TradeBusted) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *TradeBusted) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// TradeCorrected event is emitted when an approved correction changes the
+// price or quantity of a trade
+type TradeCorrected struct {
+	events.BaseEvent
+	AdjustmentID   string        `json:"adjustmentId"`
+	Reason         string        `json:"reason"`
+	RequestedBy    string        `json:"requestedBy"`
+	ApprovedBy     []string      `json:"approvedBy"`
+	SecurityID     string        `json:"securityId"`
+	PreviousPrice  money.Decimal `json:"previousPrice"`
+	PreviousShares int64         `json:"previousShares"`
+	TradePrice     money.Decimal `json:"tradePrice"`
+	SharesTraded   int64         `json:"sharesTraded"`
+	TotalAmount    money.Decimal `json:"totalAmount"`
+	Currency       string        `json:"currency"`
+	CorrectedAt    time.Time     `json:"correctedAt"`
+}
+
+func NewTradeCorrected(trade *TradeAggregate, adjustment *TradeAdjustment, totalAmount money.Decimal, correctedAt time.Time) *TradeCorrected {
+	return &TradeCorrected{
+		BaseEvent:      events.NewBaseEvent(trade.ID, "Trade"),
+		AdjustmentID:   adjustment.ID,
+		Reason:         adjustment.Reason,
+		RequestedBy:    adjustment.RequestedBy,
+		ApprovedBy:     adjustment.ApprovedBy,
+		SecurityID:     trade.SecurityID,
+		PreviousPrice:  trade.TradePrice,
+		PreviousShares: trade.SharesTraded,
+		TradePrice:     *adjustment.TradePrice,
+		SharesTraded:   adjustment.SharesTraded,
+		TotalAmount:    totalAmount,
+		Currency:       trade.TotalAmount.Currency,
+		CorrectedAt:    correctedAt,
+	}
+}
+
+func (e *TradeCorrected) GetEventType() string     { return "TradeCorrected" }
+func (e *TradeCorrected) GetAggregateID() string   { return e.AggregateID }
+func (e *TradeCorrected) GetAggregateType() string { return e.AggregateType }
+
+func (e *TradeCorrected) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *TradeCorrected) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// AuctionIndicativePriceCalculated event is published before a call auction
+// executes, announcing the price it will clear at and the interest left over
+type AuctionIndicativePriceCalculated struct {
+	events.BaseEvent
+	SecurityID       string         `json:"securityId"`
+	IndicativePrice  money.Decimal  `json:"indicativePrice"`
+	ExecutableVolume int64          `json:"executableVolume"`
+	Demand           int64          `json:"demand"`
+	Supply           int64          `json:"supply"`
+	Imbalance        int64          `json:"imbalance"`
+	ImbalanceSide    string         `json:"imbalanceSide,omitempty"`
+	ReferencePrice   *money.Decimal `json:"referencePrice,omitempty"`
+}
+
+func NewAuctionIndicativePriceCalculated(clearing *AuctionClearing) *AuctionIndicativePriceCalculated {
+	return &AuctionIndicativePriceCalculated{
+		BaseEvent:        events.NewBaseEvent(clearing.SecurityID, "Auction"),
+		SecurityID:       clearing.SecurityID,
+		IndicativePrice:  clearing.Price,
+		ExecutableVolume: clearing.ExecutableVolume,
+		Demand:           clearing.Demand,
+		Supply:           clearing.Supply,
+		Imbalance:        clearing.Imbalance,
+		ImbalanceSide:    clearing.ImbalanceSide(),
+		ReferencePrice:   clearing.ReferencePrice,
+	}
+}
+
+func (e *AuctionIndicativePriceCalculated) GetEventType() string     { return "AuctionIndicativePriceCalculated" }
+func (e *AuctionIndicativePriceCalculated) GetAggregateID() string   { return e.AggregateID }
+func (e *AuctionIndicativePriceCalculated) GetAggregateType() string { return e.AggregateType }
+
+func (e *AuctionIndicativePriceCalculated) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *AuctionIndicativePriceCalculated) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// SelfTradePrevented event is published when an order is cancelled or
+// decremented, or a bilateral trade refused, because the buyer and seller
+// trade for the same beneficial owner
+type SelfTradePrevented struct {
+	events.BaseEvent
+	SecurityID          string `json:"securityId"`
+	OrderID             string `json:"orderId,omitempty"`
+	CounterpartyOrderID string `json:"counterpartyOrderId,omitempty"`
+	BuyerID             string `json:"buyerId"`
+	SellerID            string `json:"sellerId"`
+	BeneficialOwnerID   string `json:"beneficialOwnerId"`
+	Mode                string `json:"mode"`
+	SharesCancelled     int64  `json:"sharesCancelled"`
+}
+
+func NewSelfTradePrevented(securityID, orderID, counterpartyOrderID, buyerID, sellerID, beneficialOwnerID string, mode SelfTradePreventionMode, sharesCancelled int64) *SelfTradePrevented {
+	return &SelfTradePrevented{
+		BaseEvent:           events.NewBaseEvent(securityID, "SelfTradePrevention"),
+		SecurityID:          securityID,
+		OrderID:             orderID,
+		CounterpartyOrderID: counterpartyOrderID,
+		BuyerID:             buyerID,
+		SellerID:            sellerID,
+		BeneficialOwnerID:   beneficialOwnerID,
+		Mode:                string(mode),
+		SharesCancelled:     sharesCancelled,
+	}
+}
+
+func (e *SelfTradePrevented) GetEventType() string     { return "SelfTradePrevented" }
+func (e *SelfTradePrevented) GetAggregateID() string   { return e.AggregateID }
+func (e *SelfTradePrevented) GetAggregateType() string { return e.AggregateType }
+
+func (e *SelfTradePrevented) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *SelfTradePrevented) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
@@ -1,10 +1,21 @@
 package execution
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
+	"securities-marketplace/domains/securities"
+	"securities-marketplace/domains/shared/audit"
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
 	"securities-marketplace/domains/shared/testutil"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/calendar"
+	"securities-marketplace/domains/trading/listing"
+	"securities-marketplace/domains/trading/negotiation"
+	"securities-marketplace/domains/trading/rfq"
 )
 
 func TestTradeAggregate_MatchTrade_Simple(t *testing.T) {
@@ -19,8 +30,8 @@ func TestTradeAggregate_MatchTrade_Simple(t *testing.T) {
 		"seller-202",
 		"security-303",
 		100,
-		50.00,
-		5000.00,
+		money.NewDecimalFromInt(50),
+		usd("5000"),
 		time.Now().AddDate(0, 0, 2),
 		"price_time_priority",
 	)
@@ -32,7 +43,7 @@ func TestTradeAggregate_MatchTrade_Simple(t *testing.T) {
 	testutil.AssertEqual(t, "buyer-101", trade.BuyerID, "Buyer ID should be set")
 	testutil.AssertEqual(t, "seller-202", trade.SellerID, "Seller ID should be set")
 	testutil.AssertEqual(t, int64(100), trade.SharesTraded, "Shares traded should be set")
-	testutil.AssertEqual(t, 50.00, trade.TradePrice, "Trade price should be set")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(50), trade.TradePrice, "Trade price should be set")
 
 	// Check events
 	events := trade.GetUncommittedEvents()
@@ -105,12 +116,12 @@ func TestTradeAggregate_SettlementWorkflow_Simple(t *testing.T) {
 
 	// Test payment recording
 	t.Run("record payment", func(t *testing.T) {
-		err := trade.ReceivePayment(5000.00, "USD", "wire_transfer", "txn-456")
+		err := trade.ReceivePayment(usd("5000"), "wire_transfer", "txn-456")
 		
 		testutil.AssertNoError(t, err, "Payment recording should succeed")
 		testutil.AssertEqual(t, TradeStatusPaymentReceived, trade.Status, "Status should be payment received")
 		testutil.AssertNotNil(t, trade.PaymentInfo, "Payment info should be set")
-		testutil.AssertEqual(t, 5000.00, trade.PaymentInfo.Amount, "Payment amount should match")
+		testutil.AssertEqual(t, usd("5000"), trade.PaymentInfo.Amount, "Payment amount should match")
 	})
 
 	// Test share transfer
@@ -125,12 +136,18 @@ func TestTradeAggregate_SettlementWorkflow_Simple(t *testing.T) {
 
 	// Test settlement completion
 	t.Run("complete settlement", func(t *testing.T) {
-		err := trade.SettleTrade(5000.00, 25.00, 15.00, "automated")
+		fees, err := NewFeeBreakdown("schedule-1", "stock", []FeeItem{
+			{Type: FeeTypeCommission, PayerID: "buyer-789", Side: "buyer", Role: "taker", Amount: money.MustParseDecimal("15"), Recipient: FeeRecipientPlatform},
+			{Type: FeeTypePlatform, PayerID: "seller-101", Side: "seller", Amount: money.MustParseDecimal("10"), Recipient: FeeRecipientPlatform},
+		})
+		testutil.AssertNoError(t, err, "Fees should be totalled")
+		err = trade.SettleTrade(usd("5000"), fees, usd("15"), "automated")
 		
 		testutil.AssertNoError(t, err, "Settlement completion should succeed")
 		testutil.AssertEqual(t, TradeStatusSettled, trade.Status, "Status should be settled")
-		testutil.AssertEqual(t, 25.00, trade.Fees, "Fees should be set")
-		testutil.AssertEqual(t, 15.00, trade.Taxes, "Taxes should be set")
+		testutil.AssertEqual(t, usd("25"), trade.Fees, "Fees should be set")
+		testutil.AssertEqual(t, fees, trade.FeeBreakdown, "Fee breakdown should be recorded")
+		testutil.AssertEqual(t, usd("15"), trade.Taxes, "Taxes should be set")
 		testutil.AssertTrue(t, trade.IsSettled(), "Trade should be marked as settled")
 	})
 }
@@ -150,7 +167,7 @@ func TestOrderMatchingEngine_Simple(t *testing.T) {
 		SecurityID:   "TEST-001",
 		OrderType:    "sell",
 		Quantity:     100,
-		Price:        float64Ptr(50.00),
+		Price:        decimalPtr("50.00"),
 		Timestamp:    testutil.TestTime,
 		IsAccredited: true,
 	})
@@ -162,7 +179,7 @@ func TestOrderMatchingEngine_Simple(t *testing.T) {
 		SecurityID:   "TEST-001",
 		OrderType:    "buy",
 		Quantity:     150,
-		Price:        float64Ptr(52.00),
+		Price:        decimalPtr("52.00"),
 		Timestamp:    testutil.TestTime,
 		IsAccredited: true,
 	})
@@ -178,7 +195,335 @@ func TestOrderMatchingEngine_Simple(t *testing.T) {
 	testutil.AssertEqual(t, "seller-1", match.SellerID, "Should match with seller")
 	testutil.AssertEqual(t, "buyer-1", match.BuyerID, "Should match with buyer")
 	testutil.AssertEqual(t, int64(100), match.SharesTraded, "Should trade 100 shares")
-	testutil.AssertEqual(t, 50.00, match.TradePrice, "Should use seller's price")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(50), match.TradePrice, "Should use seller's price")
+}
+
+func TestOrderMatchingEngine_MarketOrdersTradeAtReferencePrice(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	engine := NewOrderMatchingEngine(setup.EventStore, setup.EventBus)
+	newBook := func() *OrderBook {
+		orderBook := NewOrderBook("TEST-001")
+		orderBook.AddSellOrder(newBookEntry("sell", "ask-market", 100, nil, testutil.TestTime))
+		orderBook.AddBuyOrder(newBookEntry("buy", "bid-market", 100, nil, testutil.TestTime))
+		return orderBook
+	}
+
+	// Act & Assert
+	matches, err := engine.matchPriceTimePriority(newBook())
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertLengthEqual(t, 0, matches, "Market orders should not cross without a reference price")
+
+	engine.SetMarketDataProvider(&testMarketData{lastPrice: money.MustParseDecimal("42.50")})
+	matches, err = engine.matchPriceTimePriority(newBook())
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertLengthEqual(t, 1, matches, "Market orders should cross at the reference price")
+	testutil.AssertEqual(t, money.MustParseDecimal("42.50"), matches[0].TradePrice, "Trade should use the reference price")
+}
+
+func TestOrderMatchingEngine_ValuesTradesInSecurityCurrency(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	engine := NewOrderMatchingEngine(setup.EventStore, setup.EventBus)
+
+	security := securities.NewSecurityAggregate("TEST-EUR")
+	testutil.AssertNoError(t, security.ListSecurity("seller-1", securities.SecurityTypeStock, "Euro Corp", "EURC", 1000000, nil, "EUR", nil), "Security should be listed")
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, security), "Security should be saved")
+
+	// Act
+	match, err := engine.QuoteMatch("TEST-EUR", "buyer-1", "seller-1", 10, money.NewDecimalFromInt(50))
+	_, overflowErr := engine.QuoteMatch("TEST-EUR", "buyer-1", "seller-1", 1000000000, money.NewDecimalFromInt(100000))
+
+	// Assert
+	testutil.AssertNoError(t, err, "Quote should match")
+	testutil.AssertEqual(t, money.New(money.NewDecimalFromInt(500), "EUR"), match.TotalAmount, "Trade should be valued in the security's currency")
+	testutil.AssertTrue(t, errors.Is(overflowErr, money.ErrOverflow), "Trades too large to value should be refused")
+}
+
+func TestExecutionService_RunMatchingFillsListingsAndBids(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	verifier := testInvestorVerifier{}
+	service.SetInvestorVerifier(verifier)
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	accredited := listing.NewListingAggregate("listing-1")
+	accredited.CreateListing("TEST-001", "seller-1", 100, listing.ListingTypeFixed, nil, nil, decimalPtr("50.00"), nil, true, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, accredited), "Listing should be saved")
+
+	bid := bidding.NewBidAggregate("bid-1")
+	bid.PlaceBid("listing-1", "buyer-1", 40, money.NewDecimalFromInt(52), bidding.BidTypeLimit, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, bid), "Bid should be saved")
+
+	// Act & Assert: accredited-only listings do not trade with unaccredited buyers
+	trades, err := service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertLengthEqual(t, 0, trades, "Unaccredited buyers should not match accredited-only listings")
+
+	verifier["buyer-1"] = true
+	trades, err = service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertLengthEqual(t, 1, trades, "Accredited buyers should match")
+	testutil.AssertEqual(t, int64(40), trades[0].SharesTraded, "Whole bid should trade")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(50), trades[0].TradePrice, "Seller's price should be used")
+
+	filledListing, _ := listing.NewEventSourcedListingRepository(setup.EventStore).FindByID("listing-1")
+	testutil.AssertEqual(t, int64(60), filledListing.SharesRemaining, "Listing shares should be reduced")
+	filledBid, _ := bidding.NewEventSourcedBidRepository(setup.EventStore).FindByID("bid-1")
+	testutil.AssertEqual(t, bidding.BidStatusFilled, filledBid.Status, "Bid should be filled")
+
+	book, err := service.GetOrderBook("TEST-001")
+	testutil.AssertNoError(t, err, "Order book should sync")
+	bestAsk, _ := book.BestAsk()
+	testutil.AssertEqual(t, int64(60), bestAsk.Quantity, "Order book should reflect the fill")
+	_, resting := book.BestBid()
+	testutil.AssertFalse(t, resting, "Filled bids should leave the order book")
+
+	trades, _ = service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertLengthEqual(t, 0, trades, "Filled orders should not match again")
+}
+
+func TestExecutionService_RunMatchingFollowsTradingCalendar(t *testing.T) {
+	// Arrange: a market that trades all day every day but today
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetInvestorVerifier(testInvestorVerifier{})
+	cal := &calendar.Calendar{
+		Name:           "around the clock",
+		Location:       time.UTC,
+		Close:          24*time.Hour - time.Minute,
+		Weekend:        map[time.Weekday]bool{},
+		Holidays:       map[string]string{time.Now().UTC().Format("2006-01-02"): "Test holiday"},
+		EarlyCloses:    map[string]time.Duration{},
+		SettlementDays: 2,
+	}
+	service.SetTradingCalendar(cal)
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	offer := listing.NewListingAggregate("listing-1")
+	offer.CreateListing("TEST-001", "seller-1", 100, listing.ListingTypeFixed, nil, nil, decimalPtr("50.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, offer), "Listing should be saved")
+
+	bid := bidding.NewBidAggregate("bid-1")
+	bid.PlaceBid("listing-1", "buyer-1", 40, money.NewDecimalFromInt(50), bidding.BidTypeLimit, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, bid), "Bid should be saved")
+
+	// Act & Assert: nothing matches on a holiday
+	_, err := service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertError(t, err, "Matching should not run while the market is closed")
+
+	cal.Holidays = map[string]string{}
+	trades, err := service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertNoError(t, err, "Matching should run while the market is open")
+	testutil.AssertLengthEqual(t, 1, trades, "Orders should match")
+	testutil.AssertTimeEqual(t, cal.SettlementDate(time.Now()), trades[0].SettlementDate, "Trade should settle on the calendar")
+	testutil.AssertEqual(t, 2, trades[0].GetDaysToSettlement(cal), "Trade should settle T+2 business days")
+	testutil.AssertFalse(t, trades[0].IsOverdue(cal), "New trades should not be overdue")
+}
+
+func TestStopOrderMonitor_TriggersStopBidsAtLastTradePrice(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetInvestorVerifier(testInvestorVerifier{"buyer-1": true, "buyer-2": true})
+	monitor := NewStopOrderMonitor(service)
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	offer := listing.NewListingAggregate("listing-1")
+	offer.CreateListing("TEST-001", "seller-1", 100, listing.ListingTypeFixed, nil, nil, decimalPtr("50.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, offer), "Listing should be saved")
+
+	stop := bidding.NewBidAggregate("bid-stop")
+	testutil.AssertNoError(t, stop.PlaceStopBid("listing-1", "buyer-1", 10, money.NewDecimalFromInt(51), nil, nil), "Stop bid should be placed")
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, stop), "Stop bid should be saved")
+
+	stopLimit := bidding.NewBidAggregate("bid-stop-limit")
+	testutil.AssertNoError(t, stopLimit.PlaceStopBid("listing-1", "buyer-2", 10, money.NewDecimalFromInt(50), decimalPtr("49.00"), nil), "Stop-limit bid should be placed")
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, stopLimit), "Stop-limit bid should be saved")
+
+	// Act & Assert: untriggered stops stay off the book
+	trades, err := service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertLengthEqual(t, 0, trades, "Untriggered stop bids should not match")
+
+	data, _ := json.Marshal(&TradeMatched{SecurityID: "TEST-001", TradePrice: money.NewDecimalFromInt(50)})
+	err = monitor.HandleTradeMatched(&events.GenericDomainEvent{EventType: "TradeMatched", EventData: data})
+	testutil.AssertNoError(t, err, "Trade events should be handled")
+
+	book, _ := service.GetOrderBook("TEST-001")
+	released, resting := book.Get("bid-stop-limit")
+	testutil.AssertTrue(t, resting, "Triggered stop-limit bid should join the book")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(49), *released.Price, "Stop-limit bids rest at their limit price")
+	_, resting = book.Get("bid-stop")
+	testutil.AssertFalse(t, resting, "Stops above the last trade price should not trigger")
+
+	trades, _ = service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertLengthEqual(t, 0, trades, "Stop-limit bids should respect their limit")
+
+	triggered, err := monitor.OnTradePrice("TEST-001", money.NewDecimalFromInt(51))
+	testutil.AssertNoError(t, err, "Polled prices should trigger stops")
+	testutil.AssertLengthEqual(t, 1, triggered, "Stop bid should trigger once its price is reached")
+
+	trades, _ = service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertLengthEqual(t, 1, trades, "Triggered stop bids should match as market orders")
+	testutil.AssertEqual(t, "buyer-1", trades[0].BuyerID, "Stop bidder should buy")
+
+	triggeredBid, _ := bidding.NewEventSourcedBidRepository(setup.EventStore).FindByID("bid-stop")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(51), *triggeredBid.TriggeredPrice, "Trigger price should be recorded")
+	testutil.AssertEqual(t, bidding.BidStatusFilled, triggeredBid.Status, "Triggered stop bid should fill")
+}
+
+func TestExecutionService_NegotiatedTradeWorkflow(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetInvestorVerifier(testInvestorVerifier{"buyer-1": true})
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	block := listing.NewListingAggregate("listing-1")
+	block.CreateListing("TEST-001", "seller-1", 10000, listing.ListingTypeFixed, nil, nil, decimalPtr("50.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, block), "Listing should be saved")
+
+	// Act & Assert: the parties take turns
+	opened, err := service.OpenNegotiation("listing-1", "buyer-1", 5000, money.NewDecimalFromInt(45), "Block of 5000?")
+	testutil.AssertNoError(t, err, "Buyer should open the negotiation")
+	_, err = service.AcceptNegotiation(opened.ID, "buyer-1")
+	testutil.AssertError(t, err, "Buyers cannot accept their own offer")
+
+	countered, err := service.CounterNegotiation(opened.ID, "seller-1", 5000, money.NewDecimalFromInt(48), "48 and it's yours")
+	testutil.AssertNoError(t, err, "Seller should counter")
+	head, _ := setup.EventStore.GetHeadEventNumber()
+	testutil.AssertEqual(t, head, countered.GetLastEventNumber(), "Counter-offers should return their position as a consistency token")
+	testutil.AssertTrue(t, opened.GetLastEventNumber() > 0, "Opening offers should return their position as a consistency token")
+	_, err = service.GetNegotiation(opened.ID, "someone-else")
+	testutil.AssertError(t, err, "Only the parties should see the thread")
+
+	trade, err := service.AcceptNegotiation(opened.ID, "buyer-1")
+	testutil.AssertNoError(t, err, "Buyer should accept the counter-offer")
+	testutil.AssertEqual(t, int64(5000), trade.SharesTraded, "Agreed shares should trade")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(48), trade.TradePrice, "Agreed price should be used")
+	testutil.AssertEqual(t, string(NegotiatedTrading), trade.MatchingAlgorithm, "Trade should be negotiated")
+
+	thread, err := service.GetNegotiation(opened.ID, "seller-1")
+	testutil.AssertNoError(t, err, "Seller should see the thread")
+	testutil.AssertLengthEqual(t, 2, thread.Offers, "Both offers should be in the thread")
+	testutil.AssertEqual(t, negotiation.NegotiationStatusAccepted, thread.Status, "Negotiation should be accepted")
+	testutil.AssertEqual(t, trade.ID, thread.TradeID, "Negotiation should link to its trade")
+
+	filledListing, _ := listing.NewEventSourcedListingRepository(setup.EventStore).FindByID("listing-1")
+	testutil.AssertEqual(t, int64(5000), filledListing.SharesRemaining, "Listing shares should be reduced")
+
+	err = service.RejectNegotiation(opened.ID, "seller-1", "Changed my mind")
+	testutil.AssertError(t, err, "Accepted negotiations cannot be rejected")
+}
+
+func TestExecutionService_AuditsNegotiationsWithActor(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	auditLog := audit.NewInMemoryAuditLog()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetAuditLogger(auditLog)
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	block := listing.NewListingAggregate("listing-1")
+	block.CreateListing("TEST-001", "seller-1", 10000, listing.ListingTypeFixed, nil, nil, decimalPtr("50.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, block), "Listing should be saved")
+	actor := &audit.Actor{UserID: "buyer-1", Role: "investor", IPAddress: "10.0.0.1", SessionID: "sess-1"}
+
+	// Act
+	opened, err := service.WithActor(actor).OpenNegotiation("listing-1", "buyer-1", 5000, money.NewDecimalFromInt(45), "Block of 5000?")
+	testutil.AssertNoError(t, err, "Buyer should open the negotiation")
+	err = service.RejectNegotiation(opened.ID, "seller-1", "No thanks")
+
+	// Assert
+	testutil.AssertNoError(t, err, "Seller should reject the negotiation")
+	entries, err := auditLog.Query(audit.Filter{ResourceType: "Negotiation"})
+	testutil.AssertNoError(t, err, "Audit log should be queried")
+	testutil.AssertLengthEqual(t, 2, entries, "Each negotiation command should be audited")
+
+	byUser := map[string]*audit.Entry{}
+	for _, entry := range entries {
+		byUser[entry.UserID] = entry
+	}
+	testutil.AssertEqual(t, opened.ID, byUser["buyer-1"].ResourceID, "Entry should name the negotiation")
+	testutil.AssertEqual(t, "10.0.0.1", byUser["buyer-1"].IPAddress, "Request actor should be recorded")
+	testutil.AssertEqual(t, "sess-1", byUser["buyer-1"].SessionID, "Request session should be recorded")
+	testutil.AssertEqual(t, audit.StatusSuccess, byUser["seller-1"].Status, "Commands without an actor should still be audited")
+	testutil.AssertEqual(t, "", byUser["seller-1"].IPAddress, "Actor should not leak to the unscoped service")
+}
+
+func TestOrderMatchingEngine_MatchSpecificOrders(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	engine := NewOrderMatchingEngine(setup.EventStore, setup.EventBus)
+	engine.SetInvestorVerifier(testInvestorVerifier{})
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	l := listing.NewListingAggregate("listing-1")
+	l.CreateListing("TEST-001", "seller-1", 100, listing.ListingTypeFixed, nil, nil, decimalPtr("50.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, l), "Listing should be saved")
+
+	bid := bidding.NewBidAggregate("bid-1")
+	bid.PlaceBid("listing-1", "buyer-1", 150, money.NewDecimalFromInt(52), bidding.BidTypeLimit, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, bid), "Bid should be saved")
+
+	low := bidding.NewBidAggregate("bid-2")
+	low.PlaceBid("listing-1", "buyer-2", 10, money.NewDecimalFromInt(49), bidding.BidTypeLimit, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, low), "Bid should be saved")
+
+	// Act
+	match, err := engine.MatchSpecificOrders("listing-1", "bid-1")
+
+	// Assert
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertEqual(t, int64(100), match.SharesTraded, "Available shares should trade")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(50), match.TradePrice, "Asking price should be used")
+	testutil.AssertEqual(t, string(NegotiatedTrading), match.MatchingAlgorithm, "Match should be negotiated")
+
+	_, err = engine.MatchSpecificOrders("listing-1", "bid-2")
+	testutil.AssertError(t, err, "Bids below the asking price should not match")
+	_, err = engine.MatchSpecificOrders("listing-2", "bid-1")
+	testutil.AssertError(t, err, "Bids only match the listing they were placed on")
+}
+
+func TestExecutionService_RequestForQuoteWorkflow(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+
+	security := securities.NewSecurityAggregate("SEC-001")
+	security.ListSecurity("issuer-1", securities.SecurityTypeStock, "Test Corp", "TST", 100000, nil, "", nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, security), "Security should be saved")
+
+	// Act & Assert: only holders can be asked
+	_, err := service.RequestQuotes("SEC-001", "buyer-1", 5000, []string{"issuer-1", "not-a-holder"}, time.Hour, "")
+	testutil.AssertError(t, err, "Requests should only go to current holders")
+
+	request, err := service.RequestQuotes("SEC-001", "buyer-1", 5000, []string{"issuer-1"}, time.Hour, "Block bid")
+	testutil.AssertNoError(t, err, "Request should be sent")
+
+	_, err = service.SubmitQuote(request.ID, "not-a-holder", 5000, money.NewDecimalFromInt(20))
+	testutil.AssertError(t, err, "Only recipients should quote")
+	quoted, err := service.SubmitQuote(request.ID, "issuer-1", 5000, money.NewDecimalFromInt(20))
+	testutil.AssertNoError(t, err, "Holder should quote")
+
+	_, err = service.GetQuoteRequest(request.ID, "someone-else")
+	testutil.AssertError(t, err, "Requests should be private to their participants")
+
+	quoteID := quoted.Quotes[0].QuoteID
+	_, err = service.AcceptQuote(request.ID, quoteID, "issuer-1")
+	testutil.AssertError(t, err, "Only the requester should accept")
+
+	trade, err := service.AcceptQuote(request.ID, quoteID, "buyer-1")
+	testutil.AssertNoError(t, err, "Requester should accept the quote")
+	testutil.AssertEqual(t, string(RequestForQuote), trade.MatchingAlgorithm, "Trade should be an RFQ trade")
+	testutil.AssertEqual(t, "issuer-1", trade.SellerID, "Holder should sell")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(100000), trade.TotalAmount.Amount, "Quoted price should be used")
+
+	accepted, _ := service.GetQuoteRequest(request.ID, "buyer-1")
+	testutil.AssertEqual(t, rfq.RFQStatusAccepted, accepted.Status, "Request should be accepted")
+	testutil.AssertEqual(t, trade.ID, accepted.TradeID, "Request should link to its trade")
 }
 
 // Benchmark tests
@@ -192,8 +537,8 @@ func BenchmarkTradeMatching_Simple(b *testing.B) {
 			"seller-101",
 			"security-001",
 			100,
-			50.00,
-			5000.00,
+			money.NewDecimalFromInt(50),
+			usd("5000"),
 			time.Now().AddDate(0, 0, 2),
 			"price_time_priority",
 		)
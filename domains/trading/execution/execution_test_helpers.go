@@ -4,6 +4,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"securities-marketplace/domains/securities"
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/testutil"
 )
 
 // Test helpers for execution domain
@@ -25,8 +30,8 @@ func NewTestTradeWithMatch() *TradeAggregate {
 		"seller-101",
 		"security-001",
 		100,
-		50.00,
-		5000.00,
+		money.NewDecimalFromInt(50),
+		money.New(money.NewDecimalFromInt(5000), money.DefaultCurrency),
 		time.Now().AddDate(0, 0, 2),
 		"price_time_priority",
 	)
@@ -43,13 +48,85 @@ func NewTestConfirmedTrade() *TradeAggregate {
 	return trade
 }
 
+// saveTestAggregate stores an aggregate's uncommitted events in the test event store
+func saveTestAggregate(store *testutil.TestEventStore, aggregate events.Aggregate) error {
+	for i, domainEvent := range aggregate.GetUncommittedEvents() {
+		record, err := store.CreateEventFromDomain(domainEvent, "system", "", nil)
+		if err != nil {
+			return err
+		}
+		record.AggregateVersion = i + 1
+		if err := store.SaveEvent(record); err != nil {
+			return err
+		}
+	}
+	aggregate.MarkEventsAsCommitted()
+	return nil
+}
+
+// saveTestSecurity lists a stock so matching on it can check for trading
+// halts
+func saveTestSecurity(store *testutil.TestEventStore, securityID string) error {
+	security := securities.NewSecurityAggregate(securityID)
+	if err := security.ListSecurity("issuer-1", securities.SecurityTypeStock, "Test Corp", "TST", 1000000, nil, "", nil); err != nil {
+		return err
+	}
+	return saveTestAggregate(store, security)
+}
+
+// testInvestorVerifier reports accreditation from a fixed set of users
+type testInvestorVerifier map[string]bool
+
+func (v testInvestorVerifier) IsAccredited(userID string) (bool, error) {
+	return v[userID], nil
+}
+
+// testOwners maps users to their beneficial owner; other users own themselves
+type testOwners map[string]string
+
+func (o testOwners) BeneficialOwner(userID string) (string, error) {
+	if owner, linked := o[userID]; linked {
+		return owner, nil
+	}
+	return userID, nil
+}
+
+// testMarketData is a MarketDataProvider with fixed market hours and prices
+type testMarketData struct {
+	lastPrice money.Decimal
+	opensAt   time.Time
+	closesAt  time.Time
+	isOpen    bool
+}
+
+func (m *testMarketData) GetLastTradePrice(securityID string) (money.Decimal, error) {
+	return m.lastPrice, nil
+}
+
+func (m *testMarketData) GetMarketHours() (open, close time.Time, isOpen bool) {
+	return m.opensAt, m.closesAt, m.isOpen
+}
+
+func (m *testMarketData) GetVolatility(securityID string, period time.Duration) (float64, error) {
+	return 0, nil
+}
+
+func (m *testMarketData) GetReferencePrice(securityID string) (money.Decimal, error) {
+	return m.lastPrice, nil
+}
+
 // Helper functions for tests
 func stringPtr(s string) *string {
 	return &s
 }
 
-func float64Ptr(f float64) *float64 {
-	return &f
+func decimalPtr(value string) *money.Decimal {
+	d := money.MustParseDecimal(value)
+	return &d
+}
+
+func usd(amount string) money.Money {
+	return money.New(money.MustParseDecimal(amount), "USD")
 }
 
 // Test constants
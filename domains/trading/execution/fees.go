@@ -0,0 +1,79 @@
+package execution
+
+import (
+	"fmt"
+
+	"securities-marketplace/domains/shared/money"
+)
+
+// FeeCalculator works out the fees the parties to a trade pay. The execution
+// service charges them when the trade settles.
+type FeeCalculator interface {
+	CalculateFees(trade *TradeAggregate) (*FeeBreakdown, error)
+}
+
+// FeeType is what a fee is charged for
+type FeeType string
+
+const (
+	FeeTypeCommission FeeType = "commission" // Charged on the trade value
+	FeeTypePlatform   FeeType = "platform"   // Flat fee per party per trade
+)
+
+// FeeRecipientPlatform is the recipient of the fees the platform keeps.
+// Commission shares paid to brokers name the broker instead.
+const FeeRecipientPlatform = "platform"
+
+// FeeItem is one fee a party to a trade pays, and who it is paid to
+type FeeItem struct {
+	Type      FeeType       `json:"type"`
+	PayerID   string        `json:"payerId"`
+	Side      string        `json:"side"`           // buyer or seller
+	Role      string        `json:"role,omitempty"` // maker or taker, for commissions
+	Amount    money.Decimal `json:"amount"`
+	Recipient string        `json:"recipient"`
+}
+
+// FeeBreakdown itemizes the fees charged on a trade. Amounts are in the trade
+// currency.
+type FeeBreakdown struct {
+	ScheduleID   string        `json:"scheduleId,omitempty"` // Empty when no schedule applied
+	SecurityType string        `json:"securityType,omitempty"`
+	Items        []FeeItem     `json:"items"`
+	Total        money.Decimal `json:"total"`
+}
+
+// NewFeeBreakdown creates a breakdown of the given items and totals them
+func NewFeeBreakdown(scheduleID, securityType string, items []FeeItem) (*FeeBreakdown, error) {
+	breakdown := &FeeBreakdown{
+		ScheduleID:   scheduleID,
+		SecurityType: securityType,
+		Items:        items,
+	}
+	if breakdown.Items == nil {
+		breakdown.Items = []FeeItem{}
+	}
+	for _, item := range breakdown.Items {
+		total, err := breakdown.Total.Add(item.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to total fees: %w", err)
+		}
+		breakdown.Total = total
+	}
+	return breakdown, nil
+}
+
+// PaidBy returns the fees the user pays
+func (b *FeeBreakdown) PaidBy(userID string) (money.Decimal, error) {
+	var total money.Decimal
+	for _, item := range b.Items {
+		if item.PayerID != userID {
+			continue
+		}
+		var err error
+		if total, err = total.Add(item.Amount); err != nil {
+			return money.Zero, fmt.Errorf("failed to total fees: %w", err)
+		}
+	}
+	return total, nil
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -10,7 +11,9 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"securities-marketplace/domains/shared/audit"
 	"securities-marketplace/domains/shared/auth"
+	"securities-marketplace/domains/shared/events"
 	"securities-marketplace/domains/shared/web"
 	"securities-marketplace/domains/trading/execution"
 )
@@ -71,12 +74,13 @@ func (h *TradeExecutionHandler) HandleExecuteTrade(w http.ResponseWriter, r *htt
 	}
 
 	// Execute the trade
-	trade, err := h.service.ExecuteTradeMatch(req.MatchResult)
+	trade, err := h.service.WithActor(audit.ActorFromContext(r.Context())).ExecuteTradeMatch(req.MatchResult)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to execute trade: %v", err), http.StatusInternalServerError)
 		return
 	}
 
+	events.SetConsistencyToken(w, trade.GetLastEventNumber())
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
@@ -111,16 +115,25 @@ func (h *TradeExecutionHandler) HandleRunMatching(w http.ResponseWriter, r *http
 		req.Algorithm = execution.PriceTimePriority // Default algorithm
 	}
 
-	// Run matching
-	trades, err := h.service.RunMatching(req.SecurityID, req.Algorithm)
+	// The service runs matching on the security's actor when it has a
+	// sequencer, so concurrent requests are serialized across instances
+	trades, err := h.service.WithActor(audit.ActorFromContext(r.Context())).RunMatching(req.SecurityID, req.Algorithm)
+	if errors.Is(err, execution.ErrSecurityLeased) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Matching failed: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	// Prepare response
+	var lastEventNumber int64
 	tradeResults := make([]map[string]interface{}, len(trades))
 	for i, trade := range trades {
+		if trade.GetLastEventNumber() > lastEventNumber {
+			lastEventNumber = trade.GetLastEventNumber()
+		}
 		tradeResults[i] = map[string]interface{}{
 			"tradeId":      trade.ID,
 			"buyerId":      trade.BuyerID,
@@ -133,6 +146,7 @@ func (h *TradeExecutionHandler) HandleRunMatching(w http.ResponseWriter, r *http
 		}
 	}
 
+	events.SetConsistencyToken(w, lastEventNumber)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success":    true,
@@ -160,12 +174,15 @@ func (h *TradeExecutionHandler) HandleConfirmTrade(w http.ResponseWriter, r *htt
 	}
 
 	// Confirm the trade
-	err := h.service.ConfirmTrade(tradeID, userID)
+	err := h.service.WithActor(audit.ActorFromContext(r.Context())).ConfirmTrade(tradeID, userID)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to confirm trade: %v", err), http.StatusInternalServerError)
 		return
 	}
 
+	if trade, err := h.service.GetTrade(tradeID); err == nil {
+		events.SetConsistencyToken(w, trade.GetLastEventNumber())
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
@@ -406,6 +423,8 @@ func parseTradeStatus(status string) (execution.TradeStatus, bool) {
 		return execution.TradeStatusFailed, true
 	case "cancelled":
 		return execution.TradeStatusCancelled, true
+	case "busted":
+		return execution.TradeStatusBusted, true
 	default:
 		return "", false
 	}
@@ -0,0 +1,208 @@
+package execution
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"securities-marketplace/domains/shared/audit"
+	"securities-marketplace/domains/shared/auth"
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/negotiation"
+)
+
+// NegotiationHandler exposes the negotiated-trade workflow to buyers and
+// sellers
+type NegotiationHandler struct {
+	service *ExecutionService
+}
+
+// NewNegotiationHandler creates a new negotiation handler
+func NewNegotiationHandler(service *ExecutionService) *NegotiationHandler {
+	return &NegotiationHandler{service: service}
+}
+
+// OfferRequest is the body of a new offer or counter-offer
+type OfferRequest struct {
+	ListingID string        `json:"listingId,omitempty"` // Opening offers only
+	Shares    int64         `json:"shares"`
+	Price     money.Decimal `json:"price"`
+	Message   string        `json:"message"`
+}
+
+// RegisterRoutes registers the handler routes. Callers are expected to mount
+// the router behind authentication.
+func (h *NegotiationHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/negotiations", h.HandleList).Methods("GET")
+	router.HandleFunc("/negotiations", h.HandleOpen).Methods("POST")
+	router.HandleFunc("/negotiations/{id}", h.HandleGet).Methods("GET")
+	router.HandleFunc("/negotiations/{id}/counter", h.HandleCounter).Methods("POST")
+	router.HandleFunc("/negotiations/{id}/accept", h.HandleAccept).Methods("POST")
+	router.HandleFunc("/negotiations/{id}/reject", h.HandleReject).Methods("POST")
+}
+
+// HandleList returns every negotiation the user is a party to
+func (h *NegotiationHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	negotiations, err := h.service.GetNegotiationsByUser(userID)
+	if err != nil {
+		http.Error(w, "Failed to get negotiations", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"success":      true,
+		"negotiations": negotiations,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleOpen makes an opening offer against a listing
+func (h *NegotiationHandler) HandleOpen(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var request OfferRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if request.ListingID == "" {
+		http.Error(w, "Listing ID is required", http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.service.WithActor(audit.ActorFromContext(r.Context())).OpenNegotiation(request.ListingID, userID, request.Shares, request.Price, request.Message)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	events.SetConsistencyToken(w, n.GetLastEventNumber())
+	writeNegotiation(w, http.StatusCreated, n)
+}
+
+// HandleGet returns a negotiation thread to one of its parties
+func (h *NegotiationHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	n, err := h.service.GetNegotiation(mux.Vars(r)["id"], userID)
+	if err != nil {
+		http.Error(w, "Negotiation not found", http.StatusNotFound)
+		return
+	}
+
+	writeNegotiation(w, http.StatusOK, n)
+}
+
+// HandleCounter answers the latest offer with a new one
+func (h *NegotiationHandler) HandleCounter(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var request OfferRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.service.WithActor(audit.ActorFromContext(r.Context())).CounterNegotiation(mux.Vars(r)["id"], userID, request.Shares, request.Price, request.Message)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	events.SetConsistencyToken(w, n.GetLastEventNumber())
+	writeNegotiation(w, http.StatusOK, n)
+}
+
+// HandleAccept accepts the latest offer and books the trade
+func (h *NegotiationHandler) HandleAccept(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	trade, err := h.service.WithActor(audit.ActorFromContext(r.Context())).AcceptNegotiation(mux.Vars(r)["id"], userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"tradeId": trade.ID,
+		"status":  trade.Status,
+	}
+
+	events.SetConsistencyToken(w, trade.GetLastEventNumber())
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleReject ends a negotiation without a trade
+func (h *NegotiationHandler) HandleReject(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if err := h.service.WithActor(audit.ActorFromContext(r.Context())).RejectNegotiation(id, userID, request.Reason); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	n, err := h.service.GetNegotiation(id, userID)
+	if err != nil {
+		http.Error(w, "Negotiation not found", http.StatusNotFound)
+		return
+	}
+
+	events.SetConsistencyToken(w, n.GetLastEventNumber())
+	writeNegotiation(w, http.StatusOK, n)
+}
+
+func requireUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	user := auth.GetUserFromContext(r.Context())
+	if user == nil || user.UserID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return "", false
+	}
+	return user.UserID, true
+}
+
+func writeNegotiation(w http.ResponseWriter, status int, n *negotiation.NegotiationAggregate) {
+	response := map[string]interface{}{
+		"success":     true,
+		"negotiation": n,
+		"awaiting":    n.AwaitingResponseFrom(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,600 @@
+package execution
+
+import (
+	"container/list"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+)
+
+// DepthLevel summarizes the resting quantity at one price
+type DepthLevel struct {
+	Price      money.Decimal `json:"price"`
+	Quantity   int64         `json:"quantity"`
+	OrderCount int           `json:"orderCount"`
+}
+
+// LimitOrderBook is the live order book for one security. Limit orders rest in
+// price levels, each a FIFO queue, kept in a balanced tree so the best price,
+// inserts and removals are O(log n) in the number of levels. Market orders
+// queue ahead of every level on their side.
+type LimitOrderBook struct {
+	SecurityID string
+
+	mu   sync.RWMutex
+	bids *bookSide
+	asks *bookSide
+}
+
+// NewLimitOrderBook creates an empty order book for a security
+func NewLimitOrderBook(securityID string) *LimitOrderBook {
+	return &LimitOrderBook{
+		SecurityID: securityID,
+		bids:       newBookSide(true),
+		asks:       newBookSide(false),
+	}
+}
+
+// OrderID returns the identifier of the order behind a book entry: the bid ID
+// for buy orders and the listing ID for sell orders
+func (e *OrderBookEntry) OrderID() string {
+	if e.BidID != nil {
+		return *e.BidID
+	}
+	return e.ListingID
+}
+
+// Displayed returns the shares of an order shown in the book
+func (e *OrderBookEntry) Displayed() int64 {
+	return e.Quantity - e.Hidden
+}
+
+// reduceTo lowers an order's remaining quantity, taking displayed shares
+// before the hidden reserve, and returns how many displayed shares went
+func (e *OrderBookEntry) reduceTo(quantity int64) int64 {
+	displayed := e.Displayed()
+	remainingDisplayed := max(0, displayed-(e.Quantity-quantity))
+	e.Quantity = quantity
+	e.Hidden = quantity - remainingDisplayed
+	return displayed - remainingDisplayed
+}
+
+// Add places an order at the back of its price level
+func (b *LimitOrderBook) Add(entry *OrderBookEntry) error {
+	if entry.Quantity <= 0 {
+		return fmt.Errorf("order %s has no quantity", entry.OrderID())
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	side, err := b.side(entry.OrderType)
+	if err != nil {
+		return err
+	}
+	if b.bids.contains(entry.OrderID()) || b.asks.contains(entry.OrderID()) {
+		return fmt.Errorf("order %s is already in the book", entry.OrderID())
+	}
+
+	side.add(entry)
+	return nil
+}
+
+// Remove takes an order out of the book, reporting whether it was present
+func (b *LimitOrderBook) Remove(orderID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.bids.remove(orderID) || b.asks.remove(orderID)
+}
+
+// Update changes an order's remaining quantity and price. Reducing quantity
+// keeps time priority; a price change or quantity increase sends the order to
+// the back of its new level as of the given time. A non-positive quantity
+// removes the order.
+func (b *LimitOrderBook) Update(orderID string, quantity int64, price *money.Decimal, at time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	side := b.bids
+	if !side.contains(orderID) {
+		side = b.asks
+	}
+	resting, exists := side.orders[orderID]
+	if !exists {
+		return false
+	}
+
+	if quantity <= 0 {
+		side.remove(orderID)
+		return true
+	}
+
+	entry := resting.entry
+	if samePrice(entry.Price, price) && quantity <= entry.Quantity {
+		side.reduce(resting, quantity)
+		return true
+	}
+
+	side.remove(orderID)
+	updated := *entry
+	updated.Quantity = quantity
+	updated.Hidden = min(updated.Hidden, quantity)
+	updated.Price = price
+	updated.Timestamp = at
+	side.add(&updated)
+	return true
+}
+
+// Refresh shows a new tranche of an iceberg order from its hidden reserve,
+// leaving hidden shares in reserve. As on exchanges, the order loses its time
+// priority and goes to the back of its price level as of the given time.
+func (b *LimitOrderBook) Refresh(orderID string, hidden int64, at time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	side := b.bids
+	if !side.contains(orderID) {
+		side = b.asks
+	}
+	resting, exists := side.orders[orderID]
+	if !exists {
+		return false
+	}
+
+	side.remove(orderID)
+	refreshed := *resting.entry
+	refreshed.Hidden = min(hidden, refreshed.Quantity)
+	refreshed.Timestamp = at
+	side.add(&refreshed)
+	return true
+}
+
+// Get returns a copy of a resting order
+func (b *LimitOrderBook) Get(orderID string) (*OrderBookEntry, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, side := range []*bookSide{b.bids, b.asks} {
+		if resting, exists := side.orders[orderID]; exists {
+			entry := *resting.entry
+			return &entry, true
+		}
+	}
+	return nil, false
+}
+
+// BestBid returns the highest bid price level
+func (b *LimitOrderBook) BestBid() (DepthLevel, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.bids.best()
+}
+
+// BestAsk returns the lowest ask price level
+func (b *LimitOrderBook) BestAsk() (DepthLevel, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.asks.best()
+}
+
+// Depth returns up to the given number of price levels on each side, best first
+func (b *LimitOrderBook) Depth(levels int) (bids, asks []DepthLevel) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.bids.depth(levels), b.asks.depth(levels)
+}
+
+// LevelOrders copies the limit orders resting in up to the given number of
+// price levels on each side, best level first and in time priority within
+// each level
+func (b *LimitOrderBook) LevelOrders(levels int) (bids, asks []*OrderBookEntry) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.bids.levelOrders(levels), b.asks.levelOrders(levels)
+}
+
+// Len returns the number of resting orders
+func (b *LimitOrderBook) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.bids.orders) + len(b.asks.orders)
+}
+
+// Snapshot copies the whole book in priority order
+func (b *LimitOrderBook) Snapshot() *OrderBook {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	snapshot := NewOrderBook(b.SecurityID)
+	b.bids.walk(func(entry *OrderBookEntry) bool {
+		snapshot.AddBuyOrder(copyEntry(entry))
+		return true
+	})
+	b.asks.walk(func(entry *OrderBookEntry) bool {
+		snapshot.AddSellOrder(copyEntry(entry))
+		return true
+	})
+	return snapshot
+}
+
+// CrossingSnapshot copies only the orders that could trade against the other
+// side right now, in priority order. Its size depends on how far the book
+// crosses rather than on how many orders rest in it.
+func (b *LimitOrderBook) CrossingSnapshot() *OrderBook {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	snapshot := NewOrderBook(b.SecurityID)
+	for _, entry := range b.bids.crossing(b.asks) {
+		snapshot.AddBuyOrder(copyEntry(entry))
+	}
+	for _, entry := range b.asks.crossing(b.bids) {
+		snapshot.AddSellOrder(copyEntry(entry))
+	}
+	return snapshot
+}
+
+// side returns the book side for an order type
+func (b *LimitOrderBook) side(orderType string) (*bookSide, error) {
+	switch orderType {
+	case "buy":
+		return b.bids, nil
+	case "sell":
+		return b.asks, nil
+	default:
+		return nil, fmt.Errorf("unknown order type: %s", orderType)
+	}
+}
+
+// restingOrder locates an order within its side
+type restingOrder struct {
+	entry   *OrderBookEntry
+	level   *priceLevel // nil for market orders
+	element *list.Element
+}
+
+// priceLevel is the FIFO queue of orders resting at one price
+type priceLevel struct {
+	price    money.Decimal
+	quantity int64 // Displayed shares only; iceberg reserves are not shown
+	orders   *list.List
+}
+
+// bookSide holds one side of the book
+type bookSide struct {
+	buy            bool
+	levels         *levelTree
+	market         *list.List
+	marketQuantity int64
+	orders         map[string]*restingOrder
+}
+
+func newBookSide(buy bool) *bookSide {
+	return &bookSide{
+		buy:    buy,
+		levels: &levelTree{},
+		market: list.New(),
+		orders: make(map[string]*restingOrder),
+	}
+}
+
+func (s *bookSide) contains(orderID string) bool {
+	_, exists := s.orders[orderID]
+	return exists
+}
+
+func (s *bookSide) add(entry *OrderBookEntry) {
+	resting := &restingOrder{entry: entry}
+
+	if entry.Price == nil {
+		resting.element = s.market.PushBack(entry)
+		s.marketQuantity += entry.Quantity
+	} else {
+		level := s.levels.get(*entry.Price)
+		if level == nil {
+			level = &priceLevel{price: *entry.Price, orders: list.New()}
+			s.levels.insert(level)
+		}
+		resting.level = level
+		resting.element = level.orders.PushBack(entry)
+		level.quantity += entry.Displayed()
+	}
+
+	s.orders[entry.OrderID()] = resting
+}
+
+func (s *bookSide) remove(orderID string) bool {
+	resting, exists := s.orders[orderID]
+	if !exists {
+		return false
+	}
+	delete(s.orders, orderID)
+
+	if resting.level == nil {
+		s.market.Remove(resting.element)
+		s.marketQuantity -= resting.entry.Quantity
+		return true
+	}
+
+	resting.level.orders.Remove(resting.element)
+	resting.level.quantity -= resting.entry.Displayed()
+	if resting.level.orders.Len() == 0 {
+		s.levels.delete(resting.level.price)
+	}
+	return true
+}
+
+func (s *bookSide) reduce(resting *restingOrder, quantity int64) {
+	delta := resting.entry.Quantity - quantity
+	displayedDelta := resting.entry.reduceTo(quantity)
+	if resting.level == nil {
+		s.marketQuantity -= delta
+	} else {
+		resting.level.quantity -= displayedDelta
+	}
+}
+
+// bestLevel returns the best limit price level
+func (s *bookSide) bestLevel() *priceLevel {
+	if s.buy {
+		return s.levels.max()
+	}
+	return s.levels.min()
+}
+
+func (s *bookSide) best() (DepthLevel, bool) {
+	level := s.bestLevel()
+	if level == nil {
+		return DepthLevel{}, false
+	}
+	return level.depth(), true
+}
+
+func (s *bookSide) depth(levels int) []DepthLevel {
+	result := make([]DepthLevel, 0, levels)
+	s.walkLevels(func(level *priceLevel) bool {
+		if len(result) >= levels {
+			return false
+		}
+		result = append(result, level.depth())
+		return true
+	})
+	return result
+}
+
+func (s *bookSide) levelOrders(levels int) []*OrderBookEntry {
+	var result []*OrderBookEntry
+	visited := 0
+	s.walkLevels(func(level *priceLevel) bool {
+		if visited >= levels {
+			return false
+		}
+		visited++
+		for element := level.orders.Front(); element != nil; element = element.Next() {
+			result = append(result, copyEntry(element.Value.(*OrderBookEntry)))
+		}
+		return true
+	})
+	return result
+}
+
+// walkLevels visits limit price levels best first until fn returns false
+func (s *bookSide) walkLevels(fn func(*priceLevel) bool) {
+	if s.buy {
+		s.levels.descend(fn)
+	} else {
+		s.levels.ascend(fn)
+	}
+}
+
+// walk visits orders in priority order until fn returns false
+func (s *bookSide) walk(fn func(*OrderBookEntry) bool) {
+	for element := s.market.Front(); element != nil; element = element.Next() {
+		if !fn(element.Value.(*OrderBookEntry)) {
+			return
+		}
+	}
+	s.walkLevels(func(level *priceLevel) bool {
+		for element := level.orders.Front(); element != nil; element = element.Next() {
+			if !fn(element.Value.(*OrderBookEntry)) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// crossing returns this side's orders that could trade against the other
+// side, in priority order. Orders priced through the other side's best limit
+// always qualify; beyond that, the other side's market orders can take
+// liquidity up to their total size.
+func (s *bookSide) crossing(other *bookSide) []*OrderBookEntry {
+	var result []*OrderBookEntry
+	otherBest := other.bestLevel()
+	remainingForMarket := other.marketQuantity
+
+	s.walk(func(entry *OrderBookEntry) bool {
+		if entry.Price == nil && (otherBest != nil || other.marketQuantity > 0) {
+			result = append(result, entry)
+			return true
+		}
+		if entry.Price != nil && otherBest != nil && s.crosses(*entry.Price, otherBest.price) {
+			result = append(result, entry)
+			return true
+		}
+		if remainingForMarket > 0 {
+			result = append(result, entry)
+			remainingForMarket -= entry.Quantity
+			return true
+		}
+		return false
+	})
+
+	return result
+}
+
+// crosses reports whether a price on this side trades against a price on the other
+func (s *bookSide) crosses(price, otherPrice money.Decimal) bool {
+	if s.buy {
+		return !price.LessThan(otherPrice)
+	}
+	return !price.GreaterThan(otherPrice)
+}
+
+func (l *priceLevel) depth() DepthLevel {
+	return DepthLevel{Price: l.price, Quantity: l.quantity, OrderCount: l.orders.Len()}
+}
+
+// levelTree is a treap of price levels keyed by price
+type levelTree struct {
+	root *levelNode
+}
+
+type levelNode struct {
+	level       *priceLevel
+	priority    uint32
+	left, right *levelNode
+}
+
+func (t *levelTree) get(price money.Decimal) *priceLevel {
+	node := t.root
+	for node != nil {
+		switch {
+		case price.LessThan(node.level.price):
+			node = node.left
+		case price.GreaterThan(node.level.price):
+			node = node.right
+		default:
+			return node.level
+		}
+	}
+	return nil
+}
+
+func (t *levelTree) insert(level *priceLevel) {
+	t.root = insertLevel(t.root, level)
+}
+
+func (t *levelTree) delete(price money.Decimal) {
+	t.root = deleteLevel(t.root, price)
+}
+
+func (t *levelTree) min() *priceLevel {
+	node := t.root
+	if node == nil {
+		return nil
+	}
+	for node.left != nil {
+		node = node.left
+	}
+	return node.level
+}
+
+func (t *levelTree) max() *priceLevel {
+	node := t.root
+	if node == nil {
+		return nil
+	}
+	for node.right != nil {
+		node = node.right
+	}
+	return node.level
+}
+
+func (t *levelTree) ascend(fn func(*priceLevel) bool) {
+	ascendLevels(t.root, fn)
+}
+
+func (t *levelTree) descend(fn func(*priceLevel) bool) {
+	descendLevels(t.root, fn)
+}
+
+func insertLevel(node *levelNode, level *priceLevel) *levelNode {
+	if node == nil {
+		return &levelNode{level: level, priority: rand.Uint32()}
+	}
+	if level.price.LessThan(node.level.price) {
+		node.left = insertLevel(node.left, level)
+		if node.left.priority > node.priority {
+			node = rotateRight(node)
+		}
+	} else {
+		node.right = insertLevel(node.right, level)
+		if node.right.priority > node.priority {
+			node = rotateLeft(node)
+		}
+	}
+	return node
+}
+
+func deleteLevel(node *levelNode, price money.Decimal) *levelNode {
+	if node == nil {
+		return nil
+	}
+	switch {
+	case price.LessThan(node.level.price):
+		node.left = deleteLevel(node.left, price)
+	case price.GreaterThan(node.level.price):
+		node.right = deleteLevel(node.right, price)
+	case node.left == nil:
+		return node.right
+	case node.right == nil:
+		return node.left
+	case node.left.priority > node.right.priority:
+		node = rotateRight(node)
+		node.right = deleteLevel(node.right, price)
+	default:
+		node = rotateLeft(node)
+		node.left = deleteLevel(node.left, price)
+	}
+	return node
+}
+
+func rotateRight(node *levelNode) *levelNode {
+	left := node.left
+	node.left = left.right
+	left.right = node
+	return left
+}
+
+func rotateLeft(node *levelNode) *levelNode {
+	right := node.right
+	node.right = right.left
+	right.left = node
+	return right
+}
+
+func ascendLevels(node *levelNode, fn func(*priceLevel) bool) bool {
+	if node == nil {
+		return true
+	}
+	return ascendLevels(node.left, fn) && fn(node.level) && ascendLevels(node.right, fn)
+}
+
+func descendLevels(node *levelNode, fn func(*priceLevel) bool) bool {
+	if node == nil {
+		return true
+	}
+	return descendLevels(node.right, fn) && fn(node.level) && descendLevels(node.left, fn)
+}
+
+func samePrice(a, b *money.Decimal) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+func copyEntry(entry *OrderBookEntry) *OrderBookEntry {
+	copied := *entry
+	return &copied
+}
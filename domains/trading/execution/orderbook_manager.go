@@ -0,0 +1,516 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/listing"
+	"securities-marketplace/domains/trading/orders"
+)
+
+// orderBookSyncBatchSize is how many events are read per event store page
+const orderBookSyncBatchSize = 1000
+
+// OrderBookManager keeps a live LimitOrderBook per security. Books are built
+// by replaying listing and bid events from the event store and then kept
+// current by reading only the events written since the last sync. A manager
+// scoped to one security, as owned by a matching actor, keeps only that
+// security's book.
+type OrderBookManager struct {
+	eventStore events.EventStore
+	securityID string // Only security kept, empty for every security
+
+	mu       sync.Mutex
+	books    map[string]*LimitOrderBook
+	listings map[string]*OrderBookEntry // last known state of every listing
+	bids     map[string]*OrderBookEntry // last known state of every bid
+	stops    map[string]*pendingStop    // stop bids held off the book until triggered
+	position int64
+}
+
+// pendingStop is a stop or stop-limit bid waiting for its stop price
+type pendingStop struct {
+	entry     *OrderBookEntry // Price is the limit price, nil for stop bids
+	stopPrice money.Decimal
+}
+
+// NewOrderBookManager creates a new order book manager
+func NewOrderBookManager(eventStore events.EventStore) *OrderBookManager {
+	return &OrderBookManager{
+		eventStore: eventStore,
+		books:      make(map[string]*LimitOrderBook),
+		listings:   make(map[string]*OrderBookEntry),
+		bids:       make(map[string]*OrderBookEntry),
+		stops:      make(map[string]*pendingStop),
+	}
+}
+
+// newSecurityOrderBookManager creates an order book manager that keeps only
+// the book of one security
+func newSecurityOrderBookManager(eventStore events.EventStore, securityID string) *OrderBookManager {
+	m := NewOrderBookManager(eventStore)
+	m.securityID = securityID
+	return m
+}
+
+// Book returns the live order book for a security
+func (m *OrderBookManager) Book(securityID string) *LimitOrderBook {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.book(securityID)
+}
+
+// Position returns the event_number of the last event applied to the books
+func (m *OrderBookManager) Position() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.position
+}
+
+// TriggeredStops returns the IDs of the pending stop bids on a security whose
+// stop price the last trade price has reached, oldest first
+func (m *OrderBookManager) TriggeredStops(securityID string, lastTradePrice money.Decimal) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var triggered []*OrderBookEntry
+	for _, stop := range m.stops {
+		if stop.entry.SecurityID == securityID && !lastTradePrice.LessThan(stop.stopPrice) {
+			triggered = append(triggered, stop.entry)
+		}
+	}
+	sort.Slice(triggered, func(i, j int) bool {
+		return triggered[i].Timestamp.Before(triggered[j].Timestamp)
+	})
+
+	bidIDs := make([]string, len(triggered))
+	for i, entry := range triggered {
+		bidIDs[i] = *entry.BidID
+	}
+	return bidIDs
+}
+
+// Orders returns a copy of every live listing and bid, including stop bids
+// waiting to be triggered
+func (m *OrderBookManager) Orders() []*OrderBookEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var entries []*OrderBookEntry
+	for _, book := range m.books {
+		snapshot := book.Snapshot()
+		entries = append(entries, snapshot.GetSellOrders()...)
+		entries = append(entries, snapshot.GetBuyOrders()...)
+	}
+	for _, stop := range m.stops {
+		entries = append(entries, copyEntry(stop.entry))
+	}
+	return entries
+}
+
+// Securities returns the securities that have a book, sorted
+func (m *OrderBookManager) Securities() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	securityIDs := make([]string, 0, len(m.books))
+	for securityID := range m.books {
+		securityIDs = append(securityIDs, securityID)
+	}
+	sort.Strings(securityIDs)
+	return securityIDs
+}
+
+// StopSecurities returns the securities that have pending stop bids
+func (m *OrderBookManager) StopSecurities() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var securityIDs []string
+	for _, stop := range m.stops {
+		if !seen[stop.entry.SecurityID] {
+			seen[stop.entry.SecurityID] = true
+			securityIDs = append(securityIDs, stop.entry.SecurityID)
+		}
+	}
+	sort.Strings(securityIDs)
+	return securityIDs
+}
+
+// Rebuild discards every book and replays the event store from the start
+func (m *OrderBookManager) Rebuild() error {
+	m.mu.Lock()
+	m.books = make(map[string]*LimitOrderBook)
+	m.listings = make(map[string]*OrderBookEntry)
+	m.bids = make(map[string]*OrderBookEntry)
+	m.stops = make(map[string]*pendingStop)
+	m.position = 0
+	m.mu.Unlock()
+
+	return m.Sync()
+}
+
+// Sync applies every event written since the last sync
+func (m *OrderBookManager) Sync() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for {
+		records, err := m.eventStore.GetAllEvents(m.position, orderBookSyncBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get events: %w", err)
+		}
+
+		for _, record := range records {
+			if err := m.applyRecord(record); err != nil {
+				return fmt.Errorf("failed to apply event %d (%s): %w", record.EventNumber, record.EventType, err)
+			}
+			m.position = record.EventNumber
+		}
+
+		if len(records) < orderBookSyncBatchSize {
+			return nil
+		}
+	}
+}
+
+// Apply updates the books from a single listing or bid event. Other events
+// are ignored.
+func (m *OrderBookManager) Apply(event events.DomainEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.apply(event)
+}
+
+// applyRecord decodes a stored event and applies it
+func (m *OrderBookManager) applyRecord(record *events.Event) error {
+	event, err := decodeOrderBookEvent(record.EventType, record.EventData)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return nil
+	}
+	return m.apply(event)
+}
+
+func (m *OrderBookManager) apply(event events.DomainEvent) error {
+	switch e := event.(type) {
+	case *events.GenericDomainEvent:
+		decoded, err := decodeOrderBookEvent(e.EventType, e.EventData)
+		if err != nil || decoded == nil {
+			return err
+		}
+		return m.apply(decoded)
+
+	case *listing.ListingCreated:
+		if _, exists := m.listings[e.AggregateID]; exists {
+			return nil
+		}
+		if m.securityID != "" && e.SecurityID != m.securityID {
+			// Bids on listings that are not kept are skipped as well
+			return nil
+		}
+		price := e.CurrentPrice
+		if listing.ListingType(e.ListingType) == listing.ListingTypeMarket {
+			price = nil
+		}
+		entry := &OrderBookEntry{
+			ListingID:    e.AggregateID,
+			UserID:       e.SellerID,
+			SecurityID:   e.SecurityID,
+			OrderType:    "sell",
+			Quantity:     e.SharesOffered,
+			Price:        price,
+			Timestamp:    e.Timestamp,
+			IsAccredited: e.AccreditedOnly,
+			ExpiresAt:    e.ExpiresAt,
+			Conditions:   resolveConditions(e.Conditions, e.ExpiresAt),
+		}
+		if e.DisplayQuantity > 0 && e.DisplayQuantity < e.SharesOffered {
+			entry.Hidden = e.SharesOffered - e.DisplayQuantity
+		}
+		m.listings[e.AggregateID] = entry
+		return m.book(e.SecurityID).Add(copyEntry(entry))
+
+	case *listing.ListingPriceUpdated:
+		entry, exists := m.listings[e.AggregateID]
+		if !exists {
+			return nil
+		}
+		price := e.NewPrice
+		entry.Price = &price
+		entry.Timestamp = e.Timestamp
+		m.book(entry.SecurityID).Update(e.AggregateID, entry.Quantity, entry.Price, e.Timestamp)
+
+	case *listing.ListingSharesReduced:
+		entry, exists := m.listings[e.AggregateID]
+		if !exists {
+			return nil
+		}
+		entry.reduceTo(e.SharesRemaining)
+		m.book(entry.SecurityID).Update(e.AggregateID, e.SharesRemaining, entry.Price, e.Timestamp)
+
+	case *listing.ListingOfferReduced:
+		entry, exists := m.listings[e.AggregateID]
+		if !exists {
+			return nil
+		}
+		entry.reduceTo(e.SharesRemaining)
+		m.book(entry.SecurityID).Update(e.AggregateID, e.SharesRemaining, entry.Price, e.Timestamp)
+
+	case *listing.ListingDisplayRefreshed:
+		entry, exists := m.listings[e.AggregateID]
+		if !exists {
+			return nil
+		}
+		// A new iceberg tranche goes to the back of its price level
+		entry.Hidden = e.SharesHidden
+		entry.Timestamp = e.Timestamp
+		m.book(entry.SecurityID).Refresh(e.AggregateID, e.SharesHidden, e.Timestamp)
+
+	case *listing.ListingCancelled:
+		m.removeListing(e.AggregateID)
+	case *listing.ListingExpired:
+		m.removeListing(e.AggregateID)
+	case *listing.ListingCompleted:
+		m.removeListing(e.AggregateID)
+
+	case *listing.ListingReactivated:
+		entry, exists := m.listings[e.AggregateID]
+		if !exists || entry.Quantity <= 0 {
+			return nil
+		}
+		// Reactivated listings rejoin the back of their price level
+		entry.Timestamp = e.Timestamp
+		book := m.book(entry.SecurityID)
+		if _, resting := book.Get(e.AggregateID); !resting {
+			return book.Add(copyEntry(entry))
+		}
+
+	case *bidding.BidPlaced:
+		if _, exists := m.bids[e.AggregateID]; exists {
+			return nil
+		}
+		if _, exists := m.stops[e.AggregateID]; exists {
+			return nil
+		}
+		target, exists := m.listings[e.ListingID]
+		if !exists {
+			// Bids are placed against listings; without one the security is unknown
+			return nil
+		}
+		var price *money.Decimal
+		switch bidding.BidType(e.BidType) {
+		case bidding.BidTypeMarket, bidding.BidTypeStop:
+		default:
+			bidPrice := e.BidPrice
+			price = &bidPrice
+		}
+		conditions := resolveConditions(e.Conditions, e.ExpiresAt)
+		if bidding.BidType(e.BidType) == bidding.BidTypeAllOrNone {
+			conditions.AllOrNone = true
+		}
+		bidID := e.AggregateID
+		entry := &OrderBookEntry{
+			ListingID:  e.ListingID,
+			BidID:      &bidID,
+			UserID:     e.BidderID,
+			SecurityID: target.SecurityID,
+			OrderType:  "buy",
+			Quantity:   e.SharesRequested,
+			Price:      price,
+			Timestamp:  e.Timestamp,
+			ExpiresAt:  e.ExpiresAt,
+			Conditions: conditions,
+		}
+		switch bidding.BidType(e.BidType) {
+		case bidding.BidTypeStop, bidding.BidTypeStopLimit:
+			// Stop orders stay off the book until they are triggered
+			stopPrice := e.BidPrice
+			if e.StopPrice != nil {
+				stopPrice = *e.StopPrice
+			}
+			m.stops[e.AggregateID] = &pendingStop{entry: entry, stopPrice: stopPrice}
+			return nil
+		}
+		m.bids[e.AggregateID] = entry
+		return m.book(entry.SecurityID).Add(copyEntry(entry))
+
+	case *bidding.BidTriggered:
+		stop, exists := m.stops[e.AggregateID]
+		if !exists {
+			return nil
+		}
+		delete(m.stops, e.AggregateID)
+		// Triggered bids join the book at the time they were released
+		entry := stop.entry
+		if bidding.BidType(e.ReleasedAs) == bidding.BidTypeMarket {
+			entry.Price = nil
+		}
+		entry.Timestamp = e.Timestamp
+		m.bids[e.AggregateID] = entry
+		return m.book(entry.SecurityID).Add(copyEntry(entry))
+
+	case *bidding.BidModified:
+		if stop, exists := m.stops[e.AggregateID]; exists {
+			stop.entry.Quantity = e.NewSharesRequested
+			if stop.entry.Price != nil {
+				price := e.NewBidPrice
+				stop.entry.Price = &price
+			} else {
+				// Stop bids trigger at their bid price
+				stop.stopPrice = e.NewBidPrice
+			}
+			return nil
+		}
+		entry, exists := m.bids[e.AggregateID]
+		if !exists {
+			return nil
+		}
+		// Shares already filled stay filled; only the remainder changes
+		entry.Quantity += e.NewSharesRequested - e.OldSharesRequested
+		if entry.Price != nil {
+			price := e.NewBidPrice
+			entry.Price = &price
+		}
+		m.book(entry.SecurityID).Update(e.AggregateID, entry.Quantity, entry.Price, e.Timestamp)
+
+	case *bidding.BidPartiallyFilled:
+		entry, exists := m.bids[e.AggregateID]
+		if !exists {
+			return nil
+		}
+		entry.Quantity = e.SharesRemaining
+		m.book(entry.SecurityID).Update(e.AggregateID, e.SharesRemaining, entry.Price, e.Timestamp)
+
+	case *bidding.BidFilled:
+		// Filled bids are remembered in case a bust or correction returns
+		// shares to them
+		if entry, exists := m.bids[e.AggregateID]; exists {
+			entry.Quantity = 0
+			m.book(entry.SecurityID).Remove(e.AggregateID)
+		}
+
+	case *bidding.BidFillAdjusted:
+		entry, exists := m.bids[e.AggregateID]
+		if !exists {
+			return nil
+		}
+		entry.Quantity += e.PreviousShares - e.SharesFilled
+		book := m.book(entry.SecurityID)
+		if _, resting := book.Get(e.AggregateID); resting {
+			book.Update(e.AggregateID, entry.Quantity, entry.Price, e.Timestamp)
+			return nil
+		}
+		// Bids bid for returned shares from the back of their price level
+		entry.Timestamp = e.Timestamp
+		return book.Add(copyEntry(entry))
+
+	case *bidding.BidWithdrawn:
+		m.removeBid(e.AggregateID)
+	case *bidding.BidExpired:
+		m.removeBid(e.AggregateID)
+	case *bidding.BidRejected:
+		m.removeBid(e.AggregateID)
+	}
+
+	return nil
+}
+
+// resolveConditions fills in the time in force of orders placed before it
+// was recorded
+func resolveConditions(conditions orders.Conditions, expiresAt *time.Time) orders.Conditions {
+	if conditions.TimeInForce == "" {
+		conditions.TimeInForce = orders.DefaultTimeInForce(expiresAt)
+	}
+	return conditions
+}
+
+// removeListing takes a listing off its book but remembers it for reactivation
+func (m *OrderBookManager) removeListing(listingID string) {
+	if entry, exists := m.listings[listingID]; exists {
+		m.book(entry.SecurityID).Remove(listingID)
+	}
+}
+
+// removeBid takes a bid off its book for good
+func (m *OrderBookManager) removeBid(bidID string) {
+	delete(m.stops, bidID)
+	if entry, exists := m.bids[bidID]; exists {
+		m.book(entry.SecurityID).Remove(bidID)
+		delete(m.bids, bidID)
+	}
+}
+
+// book returns the book for a security, creating it if needed. Callers must
+// hold m.mu.
+func (m *OrderBookManager) book(securityID string) *LimitOrderBook {
+	book, exists := m.books[securityID]
+	if !exists {
+		book = NewLimitOrderBook(securityID)
+		m.books[securityID] = book
+	}
+	return book
+}
+
+// decodeOrderBookEvent deserializes the listing and bid events that move the
+// order book. It returns nil for every other event type.
+func decodeOrderBookEvent(eventType string, data []byte) (events.DomainEvent, error) {
+	var event events.DomainEvent
+	switch eventType {
+	case "ListingCreated":
+		event = &listing.ListingCreated{}
+	case "ListingPriceUpdated":
+		event = &listing.ListingPriceUpdated{}
+	case "ListingSharesReduced":
+		event = &listing.ListingSharesReduced{}
+	case "ListingOfferReduced":
+		event = &listing.ListingOfferReduced{}
+	case "ListingDisplayRefreshed":
+		event = &listing.ListingDisplayRefreshed{}
+	case "ListingCancelled":
+		event = &listing.ListingCancelled{}
+	case "ListingExpired":
+		event = &listing.ListingExpired{}
+	case "ListingCompleted":
+		event = &listing.ListingCompleted{}
+	case "ListingReactivated":
+		event = &listing.ListingReactivated{}
+	case "BidPlaced":
+		event = &bidding.BidPlaced{}
+	case "BidModified":
+		event = &bidding.BidModified{}
+	case "BidPartiallyFilled":
+		event = &bidding.BidPartiallyFilled{}
+	case "BidFilled":
+		event = &bidding.BidFilled{}
+	case "BidWithdrawn":
+		event = &bidding.BidWithdrawn{}
+	case "BidExpired":
+		event = &bidding.BidExpired{}
+	case "BidRejected":
+		event = &bidding.BidRejected{}
+	case "BidTriggered":
+		event = &bidding.BidTriggered{}
+	case "BidFillAdjusted":
+		event = &bidding.BidFillAdjusted{}
+	default:
+		return nil, nil
+	}
+
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize %s: %w", eventType, err)
+	}
+	return event, nil
+}
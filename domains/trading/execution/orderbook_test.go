@@ -0,0 +1,288 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/testutil"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/listing"
+	"securities-marketplace/domains/trading/orders"
+)
+
+func newBookEntry(orderType, id string, quantity int64, price *money.Decimal, at time.Time) *OrderBookEntry {
+	entry := &OrderBookEntry{
+		ListingID:  id,
+		UserID:     "user-" + id,
+		SecurityID: "TEST-001",
+		OrderType:  orderType,
+		Quantity:   quantity,
+		Price:      price,
+		Timestamp:  at,
+	}
+	if orderType == "buy" {
+		entry.BidID = stringPtr(id)
+	}
+	return entry
+}
+
+func saveDomainEvents(t *testing.T, store *testutil.TestEventStore, domainEvents ...events.DomainEvent) {
+	for _, domainEvent := range domainEvents {
+		record, err := store.CreateEventFromDomain(domainEvent, "system", "", nil)
+		testutil.AssertNoError(t, err, "Event should be created")
+		testutil.AssertNoError(t, store.SaveEvent(record), "Event should be saved")
+	}
+}
+
+func TestLimitOrderBook_PricePriorityAndFIFO(t *testing.T) {
+	book := NewLimitOrderBook("TEST-001")
+	book.Add(newBookEntry("sell", "ask-early", 30, decimalPtr("10.00"), testutil.TestTime))
+	book.Add(newBookEntry("sell", "ask-late", 50, decimalPtr("10.00"), testutil.TestTime.Add(time.Minute)))
+	book.Add(newBookEntry("sell", "ask-high", 20, decimalPtr("11.00"), testutil.TestTime))
+	book.Add(newBookEntry("buy", "bid-low", 40, decimalPtr("9.00"), testutil.TestTime))
+	book.Add(newBookEntry("buy", "bid-high", 10, decimalPtr("9.50"), testutil.TestTime))
+
+	testutil.AssertError(t, book.Add(newBookEntry("sell", "ask-early", 5, decimalPtr("12.00"), testutil.TestTime)), "Duplicate orders should be rejected")
+
+	bestAsk, ok := book.BestAsk()
+	testutil.AssertTrue(t, ok, "Book should have an ask")
+	testutil.AssertEqual(t, money.MustParseDecimal("10.00"), bestAsk.Price, "Best ask should be the lowest price")
+	testutil.AssertEqual(t, int64(80), bestAsk.Quantity, "Best ask should aggregate its level")
+	testutil.AssertEqual(t, 2, bestAsk.OrderCount, "Best ask level should hold two orders")
+
+	bestBid, _ := book.BestBid()
+	testutil.AssertEqual(t, money.MustParseDecimal("9.50"), bestBid.Price, "Best bid should be the highest price")
+
+	bids, asks := book.Depth(1)
+	testutil.AssertLengthEqual(t, 1, bids, "Depth should be limited on the bid side")
+	testutil.AssertLengthEqual(t, 1, asks, "Depth should be limited on the ask side")
+
+	sells := book.Snapshot().GetSellOrders()
+	testutil.AssertLengthEqual(t, 3, sells, "Snapshot should hold every ask")
+	testutil.AssertEqual(t, "ask-early", sells[0].ListingID, "Earlier orders should lead their level")
+	testutil.AssertEqual(t, "ask-late", sells[1].ListingID, "Later orders should queue behind")
+	testutil.AssertEqual(t, "ask-high", sells[2].ListingID, "Worse prices should come last")
+}
+
+func TestLimitOrderBook_UpdateKeepsPriorityOnlyWhenReducing(t *testing.T) {
+	book := NewLimitOrderBook("TEST-001")
+	book.Add(newBookEntry("sell", "first", 50, decimalPtr("10.00"), testutil.TestTime))
+	book.Add(newBookEntry("sell", "second", 50, decimalPtr("10.00"), testutil.TestTime.Add(time.Minute)))
+
+	book.Update("first", 20, decimalPtr("10.00"), testutil.TestTime.Add(2*time.Minute))
+	sells := book.Snapshot().GetSellOrders()
+	testutil.AssertEqual(t, "first", sells[0].ListingID, "Reducing quantity should keep time priority")
+	testutil.AssertEqual(t, int64(20), sells[0].Quantity, "Quantity should be reduced")
+
+	book.Update("first", 30, decimalPtr("10.00"), testutil.TestTime.Add(3*time.Minute))
+	sells = book.Snapshot().GetSellOrders()
+	testutil.AssertEqual(t, "second", sells[0].ListingID, "Increasing quantity should lose time priority")
+
+	book.Update("first", 0, decimalPtr("10.00"), testutil.TestTime.Add(4*time.Minute))
+	testutil.AssertEqual(t, 1, book.Len(), "Orders updated to zero quantity should leave the book")
+	testutil.AssertFalse(t, book.Remove("first"), "Removed orders should not be found")
+}
+
+func TestLimitOrderBook_CrossingSnapshot(t *testing.T) {
+	book := NewLimitOrderBook("TEST-001")
+	book.Add(newBookEntry("sell", "ask-1", 100, decimalPtr("10.00"), testutil.TestTime))
+	book.Add(newBookEntry("sell", "ask-2", 100, decimalPtr("12.00"), testutil.TestTime))
+	book.Add(newBookEntry("buy", "bid-1", 100, decimalPtr("11.00"), testutil.TestTime))
+	book.Add(newBookEntry("buy", "bid-2", 100, decimalPtr("9.00"), testutil.TestTime))
+
+	snapshot := book.CrossingSnapshot()
+	testutil.AssertLengthEqual(t, 1, snapshot.GetSellOrders(), "Only crossing asks should be copied")
+	testutil.AssertLengthEqual(t, 1, snapshot.GetBuyOrders(), "Only crossing bids should be copied")
+
+	book.Add(newBookEntry("buy", "bid-market", 150, nil, testutil.TestTime))
+	snapshot = book.CrossingSnapshot()
+	testutil.AssertLengthEqual(t, 2, snapshot.GetSellOrders(), "Asks should be copied to cover market bids")
+
+	snapshot.GetSellOrders()[0].Quantity = 0
+	live, _ := book.Get("ask-1")
+	testutil.AssertEqual(t, int64(100), live.Quantity, "Snapshots should not share entries with the book")
+}
+
+func TestOrderBookManager_SyncsFromEventStore(t *testing.T) {
+	store := testutil.NewTestEventStore()
+	saveDomainEvents(t, store,
+		listing.NewListingCreated("listing-1", "TEST-001", "seller-1", 100, string(listing.ListingTypeFixed), nil, nil, decimalPtr("10.00"), nil, false, nil),
+		listing.NewListingCreated("listing-2", "TEST-002", "seller-2", 50, string(listing.ListingTypeFixed), nil, nil, decimalPtr("20.00"), nil, false, nil),
+		bidding.NewBidPlaced("bid-1", "listing-1", "buyer-1", 40, money.MustParseDecimal("9.50"), string(bidding.BidTypeLimit), nil),
+		bidding.NewBidPlaced("bid-stop", "listing-1", "buyer-2", 10, money.NewDecimalFromInt(9), string(bidding.BidTypeStop), nil),
+	)
+
+	manager := NewOrderBookManager(store)
+	testutil.AssertNoError(t, manager.Sync(), "Sync should succeed")
+
+	book := manager.Book("TEST-001")
+	testutil.AssertEqual(t, 2, book.Len(), "Listing and limit bid should rest in the book")
+	testutil.AssertEqual(t, 1, manager.Book("TEST-002").Len(), "Listings should be booked by security")
+	_, resting := book.Get("bid-stop")
+	testutil.AssertFalse(t, resting, "Stop bids should stay off the book")
+
+	saveDomainEvents(t, store,
+		listing.NewListingSharesReduced("listing-1", 30, 70, "trade-1", "buyer-9", money.NewDecimalFromInt(10)),
+		bidding.NewBidModified("bid-1", 40, 40, money.MustParseDecimal("9.50"), money.MustParseDecimal("9.75"), "buyer-1", "improve"),
+		listing.NewListingCancelled("listing-2", "withdrawn", "seller-2"),
+	)
+	testutil.AssertNoError(t, manager.Sync(), "Incremental sync should succeed")
+
+	bestAsk, _ := book.BestAsk()
+	testutil.AssertEqual(t, int64(70), bestAsk.Quantity, "Share reductions should be applied")
+	bestBid, _ := book.BestBid()
+	testutil.AssertEqual(t, money.MustParseDecimal("9.75"), bestBid.Price, "Bid modifications should be applied")
+	testutil.AssertEqual(t, 0, manager.Book("TEST-002").Len(), "Cancelled listings should leave the book")
+	testutil.AssertEqual(t, int64(7), manager.Position(), "Position should reach the last event")
+
+	saveDomainEvents(t, store,
+		bidding.NewBidPartiallyFilled("bid-1", 40, 0, money.MustParseDecimal("9.75"), "trade-2", "seller-1"),
+		bidding.NewBidFilled("bid-1", 40, money.MustParseDecimal("9.75"), testutil.TestTime, "trade-2", "seller-1"),
+	)
+	testutil.AssertNoError(t, manager.Sync(), "Fill should sync")
+	_, resting = book.Get("bid-1")
+	testutil.AssertFalse(t, resting, "Filled bids should leave the book")
+
+	saveDomainEvents(t, store, bidding.NewBidFillAdjusted("bid-1", "trade-2", 40, 15, money.MustParseDecimal("9.75"), "corrected"))
+	testutil.AssertNoError(t, manager.Sync(), "Fill adjustment should sync")
+	bestBid, _ = book.BestBid()
+	testutil.AssertEqual(t, int64(25), bestBid.Quantity, "Shares taken off a fill should be bid for again")
+
+	saveDomainEvents(t, store, listing.NewListingReactivated("listing-2", "seller-2", "relisted"))
+	testutil.AssertNoError(t, manager.Rebuild(), "Rebuild should succeed")
+	testutil.AssertEqual(t, 2, manager.Book("TEST-001").Len(), "Rebuild should restore the book")
+	testutil.AssertEqual(t, 1, manager.Book("TEST-002").Len(), "Reactivated listings should rejoin the book")
+}
+
+func TestOrderBookManager_IcebergDisplaysOneTrancheAndRequeuesOnRefresh(t *testing.T) {
+	store := testutil.NewTestEventStore()
+	iceberg := listing.NewListingCreated("listing-iceberg", "TEST-001", "seller-1", 1000, string(listing.ListingTypeLimit), nil, nil, decimalPtr("10.00"), nil, false, nil)
+	iceberg.DisplayQuantity = 100
+	saveDomainEvents(t, store,
+		iceberg,
+		listing.NewListingCreated("listing-2", "TEST-001", "seller-2", 50, string(listing.ListingTypeFixed), nil, nil, decimalPtr("10.00"), nil, false, nil),
+	)
+
+	manager := NewOrderBookManager(store)
+	testutil.AssertNoError(t, manager.Sync(), "Sync should succeed")
+
+	book := manager.Book("TEST-001")
+	bestAsk, _ := book.BestAsk()
+	testutil.AssertEqual(t, int64(150), bestAsk.Quantity, "Depth should only show the displayed tranche")
+	testutil.AssertEqual(t, "listing-iceberg", book.Snapshot().GetSellOrders()[0].ListingID, "Iceberg should keep its time priority")
+
+	saveDomainEvents(t, store, listing.NewListingSharesReduced("listing-iceberg", 40, 960, "trade-1", "buyer-1", money.NewDecimalFromInt(10)))
+	testutil.AssertNoError(t, manager.Sync(), "Incremental sync should succeed")
+
+	bestAsk, _ = book.BestAsk()
+	testutil.AssertEqual(t, int64(110), bestAsk.Quantity, "Sales should come out of the displayed tranche")
+	testutil.AssertEqual(t, "listing-iceberg", book.Snapshot().GetSellOrders()[0].ListingID, "Partial sales should keep time priority")
+
+	saveDomainEvents(t, store,
+		listing.NewListingSharesReduced("listing-iceberg", 60, 900, "trade-2", "buyer-1", money.NewDecimalFromInt(10)),
+		listing.NewListingDisplayRefreshed("listing-iceberg", 100, 800),
+	)
+	testutil.AssertNoError(t, manager.Sync(), "Incremental sync should succeed")
+
+	bestAsk, _ = book.BestAsk()
+	testutil.AssertEqual(t, int64(150), bestAsk.Quantity, "Refresh should display the next tranche")
+	sells := book.Snapshot().GetSellOrders()
+	testutil.AssertEqual(t, "listing-2", sells[0].ListingID, "Refreshed icebergs should lose time priority")
+	testutil.AssertEqual(t, int64(900), sells[1].Quantity, "Book should still hold the full reserve")
+	testutil.AssertEqual(t, int64(800), sells[1].Hidden, "Reserve should shrink by the new tranche")
+}
+
+func TestExecutionService_IcebergListingTradesAgainstFullReserve(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetInvestorVerifier(testInvestorVerifier{})
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	iceberg := listing.NewListingAggregate("listing-iceberg")
+	err := iceberg.CreateIcebergListing("TEST-001", "seller-1", 1000, 100, listing.ListingTypeLimit, decimalPtr("50.00"), nil, false, nil, orders.Conditions{})
+	testutil.AssertNoError(t, err, "Iceberg listing should be created")
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, iceberg), "Iceberg listing should be saved")
+
+	visible := listing.NewListingAggregate("listing-visible")
+	visible.CreateListing("TEST-001", "seller-2", 200, listing.ListingTypeFixed, nil, nil, decimalPtr("50.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, visible), "Listing should be saved")
+
+	bid := bidding.NewBidAggregate("bid-1")
+	bid.PlaceBid("listing-visible", "buyer-1", 500, money.NewDecimalFromInt(50), bidding.BidTypeLimit, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, bid), "Bid should be saved")
+
+	// Act
+	trades, err := service.RunMatching("TEST-001", PriceTimePriority)
+
+	// Assert
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertLengthEqual(t, 3, trades, "Displayed tranche, visible listing and reserve should trade")
+	testutil.AssertEqual(t, "listing-iceberg", *trades[0].ListingID, "Displayed tranche should trade first")
+	testutil.AssertEqual(t, int64(100), trades[0].SharesTraded, "Only the displayed tranche keeps priority")
+	testutil.AssertEqual(t, "listing-visible", *trades[1].ListingID, "Visible listing should trade ahead of the reserve")
+	testutil.AssertEqual(t, "listing-iceberg", *trades[2].ListingID, "Reserve should fill the rest")
+	testutil.AssertEqual(t, int64(200), trades[2].SharesTraded, "Reserve should fill the rest")
+
+	remaining, _ := listing.NewEventSourcedListingRepository(setup.EventStore).FindByID("listing-iceberg")
+	testutil.AssertEqual(t, int64(700), remaining.SharesRemaining, "Reserve should be reduced")
+	testutil.AssertEqual(t, int64(100), remaining.SharesDisplayed, "A fresh tranche should be displayed")
+
+	book, _ := service.GetOrderBook("TEST-001")
+	bestAsk, _ := book.BestAsk()
+	testutil.AssertEqual(t, int64(100), bestAsk.Quantity, "Depth should only show the fresh tranche")
+}
+
+func TestExecutionService_BookDepthShowsDisplayedQuantitiesAnonymously(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+
+	iceberg := listing.NewListingAggregate("listing-iceberg")
+	err := iceberg.CreateIcebergListing("TEST-001", "seller-1", 1000, 100, listing.ListingTypeLimit, decimalPtr("51.00"), nil, false, nil, orders.Conditions{})
+	testutil.AssertNoError(t, err, "Iceberg listing should be created")
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, iceberg), "Iceberg listing should be saved")
+
+	visible := listing.NewListingAggregate("listing-visible")
+	visible.CreateListing("TEST-001", "seller-2", 200, listing.ListingTypeFixed, nil, nil, decimalPtr("52.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, visible), "Listing should be saved")
+
+	bid := bidding.NewBidAggregate("bid-1")
+	bid.PlaceBid("listing-visible", "buyer-1", 300, money.NewDecimalFromInt(50), bidding.BidTypeLimit, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, bid), "Bid should be saved")
+
+	// Act
+	depth, err := service.GetBookDepth("TEST-001", DepthOptions{Levels: 10, Orders: true, Anonymize: true, ViewerID: "seller-1"})
+
+	// Assert
+	testutil.AssertNoError(t, err, "Depth should be returned")
+	testutil.AssertLengthEqual(t, 2, depth.Asks, "Each ask price should be its own level")
+	testutil.AssertEqual(t, int64(100), depth.BestAsk.Quantity, "Iceberg reserve should be hidden")
+	testutil.AssertEqual(t, "1", depth.Spread.String(), "Spread should be best ask less best bid")
+	testutil.AssertEqual(t, "50.5", depth.MidPrice.String(), "Mid price should be halfway")
+	testutil.AssertTrue(t, depth.IndicativeAuction == nil, "Uncrossed books have no indicative auction")
+
+	testutil.AssertLengthEqual(t, 3, depth.Orders, "Orders in the levels should be listed")
+	for _, order := range depth.Orders {
+		switch order.Participant {
+		case "seller-1":
+			testutil.AssertTrue(t, order.Own, "Viewer should see their own order")
+			testutil.AssertEqual(t, int64(100), order.Quantity, "Orders should show the displayed tranche only")
+		case "":
+			testutil.AssertFalse(t, order.Own, "Other orders should not be the viewer's")
+		default:
+			t.Errorf("Participant %s should be anonymized", order.Participant)
+		}
+	}
+
+	depth, err = service.GetBookDepth("TEST-001", DepthOptions{Levels: 1, Orders: true})
+	testutil.AssertNoError(t, err, "Depth should be returned")
+	testutil.AssertLengthEqual(t, 1, depth.Asks, "Levels should be capped")
+	testutil.AssertLengthEqual(t, 2, depth.Orders, "Only orders in the returned levels should be listed")
+	testutil.AssertEqual(t, "buyer-1", depth.Orders[0].Participant, "Participants should show when not anonymized")
+
+	_, err = service.GetBookDepth("TEST-001", DepthOptions{Levels: MaxDepthLevels + 1})
+	testutil.AssertError(t, err, "Too many levels should be rejected")
+}
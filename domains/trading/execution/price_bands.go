@@ -0,0 +1,196 @@
+package execution
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/securities"
+	"securities-marketplace/domains/shared/money"
+)
+
+// PriceBandConfig sets the price bands of one type of security. The static
+// band is a fixed fraction either side of the reference price. The dynamic
+// band is centred on the last trade price and is VolatilityMultiplier times
+// the security's volatility over VolatilityPeriod either side of it. A trade
+// must fall inside both.
+type PriceBandConfig struct {
+	StaticRange          money.Decimal `json:"staticRange"`          // e.g. 0.10 for 10% either side of the reference price
+	VolatilityMultiplier float64       `json:"volatilityMultiplier"` // 0 disables the dynamic band
+	VolatilityPeriod     time.Duration `json:"volatilityPeriod"`
+	CoolingOff           time.Duration `json:"coolingOff"` // How long a halt lasts before the reopening auction
+}
+
+// Validate checks that the config describes usable bands
+func (c PriceBandConfig) Validate() error {
+	if !c.StaticRange.IsPositive() || !c.StaticRange.LessThan(money.NewDecimalFromInt(1)) {
+		return fmt.Errorf("static range must be between 0 and 1")
+	}
+	if c.VolatilityMultiplier < 0 {
+		return fmt.Errorf("volatility multiplier cannot be negative")
+	}
+	if c.VolatilityMultiplier > 0 && c.VolatilityPeriod <= 0 {
+		return fmt.Errorf("dynamic bands need a volatility period")
+	}
+	if c.CoolingOff <= 0 {
+		return fmt.Errorf("cooling-off period must be positive")
+	}
+	return nil
+}
+
+// PriceBands holds the band config of each security type. Types without
+// their own config use Default.
+type PriceBands struct {
+	Default PriceBandConfig
+	ByType  map[securities.SecurityType]PriceBandConfig
+}
+
+// DefaultPriceBands returns bands suited to the securities traded on the
+// marketplace: tight for bonds and preferred shares, wide for derivatives
+func DefaultPriceBands() PriceBands {
+	equity := PriceBandConfig{
+		StaticRange:          money.MustParseDecimal("0.10"),
+		VolatilityMultiplier: 2,
+		VolatilityPeriod:     30 * 24 * time.Hour,
+		CoolingOff:           5 * time.Minute,
+	}
+	fixedIncome := equity
+	fixedIncome.StaticRange = money.MustParseDecimal("0.05")
+	derivative := equity
+	derivative.StaticRange = money.MustParseDecimal("0.25")
+	derivative.VolatilityMultiplier = 3
+
+	return PriceBands{
+		Default: equity,
+		ByType: map[securities.SecurityType]PriceBandConfig{
+			securities.SecurityTypeStock:     equity,
+			securities.SecurityTypeBond:      fixedIncome,
+			securities.SecurityTypePreferred: fixedIncome,
+			securities.SecurityTypeWarrant:   derivative,
+			securities.SecurityTypeOption:    derivative,
+		},
+	}
+}
+
+// Validate checks every config in the bands
+func (b PriceBands) Validate() error {
+	if err := b.Default.Validate(); err != nil {
+		return fmt.Errorf("invalid default price band: %w", err)
+	}
+	for securityType, config := range b.ByType {
+		if err := config.Validate(); err != nil {
+			return fmt.Errorf("invalid price band for %s: %w", securityType, err)
+		}
+	}
+	return nil
+}
+
+// For returns the band config of a security type
+func (b PriceBands) For(securityType securities.SecurityType) PriceBandConfig {
+	if config, ok := b.ByType[securityType]; ok {
+		return config
+	}
+	return b.Default
+}
+
+// PriceBand is the range a security may trade in during one matching run
+type PriceBand struct {
+	SecurityID string
+	Reference  money.Decimal
+	Lower      money.Decimal
+	Upper      money.Decimal
+	CoolingOff time.Duration
+}
+
+// Contains returns true if a trade at the price is inside the band. A nil
+// band contains every price.
+func (b *PriceBand) Contains(price money.Decimal) bool {
+	if b == nil {
+		return true
+	}
+	return !price.LessThan(b.Lower) && !price.GreaterThan(b.Upper)
+}
+
+// VolatilityHalt is a trade price that fell outside the security's price
+// band. Matching stops at the first one; the security must be halted.
+type VolatilityHalt struct {
+	Band       PriceBand
+	TradePrice money.Decimal
+}
+
+// newPriceBand builds the band of a security from its config and market
+// data. The dynamic band is left out when there is no usable last trade price
+// or volatility, or when it does not overlap the static band.
+func newPriceBand(securityID string, config PriceBandConfig, marketData MarketDataProvider) (*PriceBand, error) {
+	reference, err := marketData.GetReferencePrice(securityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reference price: %w", err)
+	}
+	if !reference.IsPositive() {
+		return nil, fmt.Errorf("no reference price for %s", securityID)
+	}
+
+	staticLower, staticUpper, err := priceRange(reference, config.StaticRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate price band: %w", err)
+	}
+	band := &PriceBand{
+		SecurityID: securityID,
+		Reference:  reference,
+		Lower:      staticLower,
+		Upper:      staticUpper,
+		CoolingOff: config.CoolingOff,
+	}
+
+	if config.VolatilityMultiplier <= 0 {
+		return band, nil
+	}
+	lastPrice, err := marketData.GetLastTradePrice(securityID)
+	if err != nil || !lastPrice.IsPositive() {
+		return band, nil
+	}
+	volatility, err := marketData.GetVolatility(securityID, config.VolatilityPeriod)
+	if err != nil || volatility <= 0 {
+		return band, nil
+	}
+
+	dynamicRange := money.NewDecimalFromFloat(config.VolatilityMultiplier * volatility)
+	lower, upper, err := priceRange(lastPrice, dynamicRange)
+	if err != nil {
+		return band, nil
+	}
+	if lower.LessThan(band.Lower) {
+		lower = band.Lower
+	}
+	if upper.GreaterThan(band.Upper) {
+		upper = band.Upper
+	}
+	if lower.GreaterThan(upper) {
+		return band, nil
+	}
+	band.Lower, band.Upper = lower, upper
+	return band, nil
+}
+
+// priceRange returns the prices the given fraction below and above the price,
+// rounded outward
+func priceRange(price, fraction money.Decimal) (money.Decimal, money.Decimal, error) {
+	one := money.NewDecimalFromInt(1)
+	below, err := one.Sub(fraction)
+	if err != nil {
+		return money.Zero, money.Zero, err
+	}
+	above, err := one.Add(fraction)
+	if err != nil {
+		return money.Zero, money.Zero, err
+	}
+
+	lower, err := price.Mul(below, money.RoundDown)
+	if err != nil {
+		return money.Zero, money.Zero, err
+	}
+	upper, err := price.Mul(above, money.RoundUp)
+	if err != nil {
+		return money.Zero, money.Zero, err
+	}
+	return lower, upper, nil
+}
@@ -0,0 +1,86 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/securities"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/testutil"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/listing"
+)
+
+func TestNewPriceBand_IntersectsStaticAndDynamicBands(t *testing.T) {
+	marketData := testutil.NewStubMarketDataProvider()
+	marketData.LastTradePrice = money.NewDecimalFromInt(105)
+	marketData.Volatility = 0.02
+	config := DefaultPriceBands().For(securities.SecurityTypeStock)
+
+	band, err := newPriceBand("TEST-001", config, marketData)
+	testutil.AssertNoError(t, err, "Band should be built")
+	testutil.AssertEqual(t, money.MustParseDecimal("100.8"), band.Lower, "Dynamic band should follow the last trade")
+	testutil.AssertEqual(t, money.MustParseDecimal("109.2"), band.Upper, "Dynamic band should follow the last trade")
+	testutil.AssertTrue(t, band.Contains(money.MustParseDecimal("109.2")), "Bounds should be inside the band")
+	testutil.AssertFalse(t, band.Contains(money.NewDecimalFromInt(100)), "Prices below the dynamic band should be outside")
+
+	// A more volatile security's dynamic band reaches past the static band
+	marketData.Volatility = 0.05
+	band, _ = newPriceBand("TEST-001", config, marketData)
+	testutil.AssertEqual(t, money.MustParseDecimal("94.5"), band.Lower, "Dynamic band should set the lower bound")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(110), band.Upper, "Static band should cap the upper bound")
+
+	bond, _ := newPriceBand("TEST-001", DefaultPriceBands().For(securities.SecurityTypeBond), marketData)
+	testutil.AssertEqual(t, money.NewDecimalFromInt(95), bond.Lower, "Bands should be configured per security type")
+}
+
+func TestExecutionService_HaltsOnPriceBandBreachAndReopensByAuction(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetInvestorVerifier(testInvestorVerifier{})
+	service.SetMarketDataProvider(testutil.NewStubMarketDataProvider())
+	bands := DefaultPriceBands()
+	bands.ByType[securities.SecurityTypeStock] = PriceBandConfig{StaticRange: money.MustParseDecimal("0.10"), CoolingOff: time.Nanosecond}
+	service.SetPriceBands(bands)
+
+	security := securities.NewSecurityAggregate("TEST-001")
+	security.ListSecurity("issuer-1", securities.SecurityTypeStock, "Test Corp", "TST", 1000, nil, "", nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, security), "Security should be saved")
+
+	// Fat-finger listing at a hundredth of the reference price
+	offer := listing.NewListingAggregate("listing-1")
+	offer.CreateListing("TEST-001", "seller-1", 100, listing.ListingTypeFixed, nil, nil, decimalPtr("1.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, offer), "Listing should be saved")
+
+	bid := bidding.NewBidAggregate("bid-1")
+	bid.PlaceBid("listing-1", "buyer-1", 40, money.NewDecimalFromInt(100), bidding.BidTypeLimit, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, bid), "Bid should be saved")
+
+	// Act
+	trades, err := service.RunMatching("TEST-001", PriceTimePriority)
+
+	// Assert
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertLengthEqual(t, 0, trades, "Trade outside the band should not be made")
+
+	securityRepository := securities.NewEventSourcedSecurityRepository(setup.EventStore)
+	halted, _ := securityRepository.FindByID("TEST-001")
+	testutil.AssertEqual(t, securities.SecurityStatusHalted, halted.Status, "Security should be halted")
+	testutil.AssertLengthEqual(t, 1, setup.EventBus.GetEventsByType("SecurityTradingHalted"), "Halt should be announced")
+
+	_, err = service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertError(t, err, "Halted security should not match")
+
+	reopened, err := service.ReopenHaltedSecurities()
+	testutil.AssertNoError(t, err, "Reopening should succeed")
+	testutil.AssertLengthEqual(t, 1, reopened, "Security should reopen after cooling off")
+
+	resumed, _ := securityRepository.FindByID("TEST-001")
+	testutil.AssertEqual(t, securities.SecurityStatusActive, resumed.Status, "Security should trade again")
+	testutil.AssertNotNil(t, resumed.LastTradePrice, "Reopening price should be recorded")
+
+	matched := setup.EventBus.GetEventsByType("TradeMatched")
+	testutil.AssertLengthEqual(t, 1, matched, "Reopening auction should cross the book")
+	testutil.AssertEqual(t, *resumed.LastTradePrice, matched[0].(*TradeMatched).TradePrice, "Trading should resume at the auction price")
+}
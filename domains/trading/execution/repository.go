@@ -2,12 +2,17 @@ package execution
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"securities-marketplace/domains/shared/events"
 )
 
+// TradesProjectionName names the projection that maintains trades_projection.
+// Trade queries wait on it when they carry a consistency token.
+const TradesProjectionName = "trades_projection"
+
 // TradeRepository defines the interface for trade persistence
 type TradeRepository interface {
 	FindByID(tradeID string) (*TradeAggregate, error)
@@ -72,6 +77,10 @@ func (r *EventSourcedTradeRepository) FindByID(tradeID string) (*TradeAggregate,
 		return nil, fmt.Errorf("failed to load from history: %w", err)
 	}
 
+	if len(eventRecords) > 0 {
+		trade.SetLastEventNumber(eventRecords[len(eventRecords)-1].EventNumber)
+	}
+
 	return trade, nil
 }
 
@@ -265,61 +274,42 @@ func (r *EventSourcedTradeRepository) convertEventRecordsToDomainEvents(eventRec
 
 // convertEventRecordToDomainEvent converts a single event record to domain event
 func (r *EventSourcedTradeRepository) convertEventRecordToDomainEvent(eventRecord *events.Event) (events.DomainEvent, error) {
+	var event events.DomainEvent
 	switch eventRecord.EventType {
 	case "TradeMatched":
-		return r.deserializeTradeMatched(eventRecord.EventData)
+		event = &TradeMatched{}
 	case "TradeConfirmed":
-		return r.deserializeTradeConfirmed(eventRecord.EventData)
+		event = &TradeConfirmed{}
 	case "TradeSettlementInitiated":
-		return r.deserializeTradeSettlementInitiated(eventRecord.EventData)
+		event = &TradeSettlementInitiated{}
 	case "PaymentReceived":
-		return r.deserializePaymentReceived(eventRecord.EventData)
+		event = &PaymentReceived{}
 	case "SharesTransferred":
-		return r.deserializeSharesTransferred(eventRecord.EventData)
+		event = &SharesTransferred{}
 	case "TradeSettled":
-		return r.deserializeTradeSettled(eventRecord.EventData)
+		event = &TradeSettled{}
 	case "TradeFailed":
-		return r.deserializeTradeFailed(eventRecord.EventData)
+		event = &TradeFailed{}
 	case "TradeCancelled":
-		return r.deserializeTradeCancelled(eventRecord.EventData)
+		event = &TradeCancelled{}
+	case "TradeAdjustmentRequested":
+		event = &TradeAdjustmentRequested{}
+	case "TradeAdjustmentApproved":
+		event = &TradeAdjustmentApproved{}
+	case "TradeAdjustmentRejected":
+		event = &TradeAdjustmentRejected{}
+	case "TradeBusted":
+		event = &TradeBusted{}
+	case "TradeCorrected":
+		event = &TradeCorrected{}
 	default:
 		return nil, fmt.Errorf("unknown event type: %s", eventRecord.EventType)
 	}
-}
-
-// Event deserialization methods (simplified implementations)
-func (r *EventSourcedTradeRepository) deserializeTradeMatched(data []byte) (*TradeMatched, error) {
-	// Implementation would deserialize JSON to TradeMatched
-	// For now, return a basic event
-	return &TradeMatched{}, nil
-}
-
-func (r *EventSourcedTradeRepository) deserializeTradeConfirmed(data []byte) (*TradeConfirmed, error) {
-	return &TradeConfirmed{}, nil
-}
-
-func (r *EventSourcedTradeRepository) deserializeTradeSettlementInitiated(data []byte) (*TradeSettlementInitiated, error) {
-	return &TradeSettlementInitiated{}, nil
-}
 
-func (r *EventSourcedTradeRepository) deserializePaymentReceived(data []byte) (*PaymentReceived, error) {
-	return &PaymentReceived{}, nil
-}
-
-func (r *EventSourcedTradeRepository) deserializeSharesTransferred(data []byte) (*SharesTransferred, error) {
-	return &SharesTransferred{}, nil
-}
-
-func (r *EventSourcedTradeRepository) deserializeTradeSettled(data []byte) (*TradeSettled, error) {
-	return &TradeSettled{}, nil
-}
-
-func (r *EventSourcedTradeRepository) deserializeTradeFailed(data []byte) (*TradeFailed, error) {
-	return &TradeFailed{}, nil
-}
-
-func (r *EventSourcedTradeRepository) deserializeTradeCancelled(data []byte) (*TradeCancelled, error) {
-	return &TradeCancelled{}, nil
+	if err := json.Unmarshal(eventRecord.EventData, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize %s: %w", eventRecord.EventType, err)
+	}
+	return event, nil
 }
 
 // NotFoundError represents a resource not found error
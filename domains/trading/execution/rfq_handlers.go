@@ -0,0 +1,209 @@
+package execution
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"securities-marketplace/domains/shared/audit"
+	"securities-marketplace/domains/shared/auth"
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+)
+
+// maxQuoteTTL bounds how long a request for quote can stay open
+const maxQuoteTTL = 24 * time.Hour
+
+// RFQHandler exposes request-for-quote trading. Requesting and quoting need
+// trade write permission; requests are only visible to the requester and
+// the recipients.
+type RFQHandler struct {
+	service *ExecutionService
+	rbac    *auth.RBAC
+}
+
+// NewRFQHandler creates a new RFQ handler
+func NewRFQHandler(service *ExecutionService) *RFQHandler {
+	return &RFQHandler{
+		service: service,
+		rbac:    auth.NewRBAC(),
+	}
+}
+
+// QuoteRequestRequest is the body of a new request for quote
+type QuoteRequestRequest struct {
+	SecurityID string   `json:"securityId"`
+	Shares     int64    `json:"shares"`
+	Recipients []string `json:"recipients"`
+	TTLSeconds int      `json:"ttlSeconds"`
+	Message    string   `json:"message"`
+}
+
+// QuoteRequest is the body of a firm quote
+type QuoteRequest struct {
+	Shares int64         `json:"shares"`
+	Price  money.Decimal `json:"price"`
+}
+
+// RegisterRoutes registers the handler routes. Callers are expected to mount
+// the router behind authentication.
+func (h *RFQHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/rfqs", h.HandleList).Methods("GET")
+	router.HandleFunc("/rfqs", h.HandleRequest).Methods("POST")
+	router.HandleFunc("/rfqs/{id}", h.HandleGet).Methods("GET")
+	router.HandleFunc("/rfqs/{id}/quotes", h.HandleQuote).Methods("POST")
+	router.HandleFunc("/rfqs/{id}/quotes/{quoteId}/accept", h.HandleAccept).Methods("POST")
+}
+
+// HandleList returns every request the user sent or received
+func (h *RFQHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	requests, err := h.service.GetQuoteRequestsByUser(userID)
+	if err != nil {
+		http.Error(w, "Failed to get requests for quote", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"success":  true,
+		"requests": requests,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleRequest sends a request for quote to selected holders
+func (h *RFQHandler) HandleRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireTrader(w, r)
+	if !ok {
+		return
+	}
+
+	var request QuoteRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	ttl := time.Duration(request.TTLSeconds) * time.Second
+	if ttl <= 0 || ttl > maxQuoteTTL {
+		http.Error(w, "Time-to-live must be between 1 second and 24 hours", http.StatusBadRequest)
+		return
+	}
+
+	rfq, err := h.service.WithActor(audit.ActorFromContext(r.Context())).RequestQuotes(request.SecurityID, userID, request.Shares, request.Recipients, ttl, request.Message)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	events.SetConsistencyToken(w, rfq.GetLastEventNumber())
+	writeQuoteRequest(w, http.StatusCreated, rfq)
+}
+
+// HandleGet returns a request as the user is allowed to see it
+func (h *RFQHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	rfq, err := h.service.GetQuoteRequest(mux.Vars(r)["id"], userID)
+	if err != nil {
+		http.Error(w, "Request for quote not found", http.StatusNotFound)
+		return
+	}
+
+	writeQuoteRequest(w, http.StatusOK, rfq)
+}
+
+// HandleQuote submits a recipient's firm quote
+func (h *RFQHandler) HandleQuote(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireTrader(w, r)
+	if !ok {
+		return
+	}
+
+	var request QuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if _, err := h.service.GetQuoteRequest(id, userID); err != nil {
+		http.Error(w, "Request for quote not found", http.StatusNotFound)
+		return
+	}
+
+	rfq, err := h.service.WithActor(audit.ActorFromContext(r.Context())).SubmitQuote(id, userID, request.Shares, request.Price)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	events.SetConsistencyToken(w, rfq.GetLastEventNumber())
+	writeQuoteRequest(w, http.StatusOK, rfq.ViewFor(userID))
+}
+
+// HandleAccept accepts a quote and books the trade
+func (h *RFQHandler) HandleAccept(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireTrader(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	if _, err := h.service.GetQuoteRequest(vars["id"], userID); err != nil {
+		http.Error(w, "Request for quote not found", http.StatusNotFound)
+		return
+	}
+
+	trade, err := h.service.WithActor(audit.ActorFromContext(r.Context())).AcceptQuote(vars["id"], vars["quoteId"], userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"tradeId": trade.ID,
+		"status":  trade.Status,
+	}
+
+	events.SetConsistencyToken(w, trade.GetLastEventNumber())
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// requireTrader returns the authenticated user if they may trade
+func (h *RFQHandler) requireTrader(w http.ResponseWriter, r *http.Request) (string, bool) {
+	user := auth.GetUserFromContext(r.Context())
+	if user == nil || user.UserID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return "", false
+	}
+	if !h.rbac.HasPermission(user.Roles, auth.PermissionTradeWrite) {
+		http.Error(w, "Insufficient permissions", http.StatusForbidden)
+		return "", false
+	}
+	return user.UserID, true
+}
+
+func writeQuoteRequest(w http.ResponseWriter, status int, rfq interface{}) {
+	response := map[string]interface{}{
+		"success": true,
+		"request": rfq,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
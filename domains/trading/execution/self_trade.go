@@ -0,0 +1,129 @@
+package execution
+
+import (
+	"fmt"
+)
+
+// SelfTradePreventionMode decides which orders are cancelled when two orders
+// of the same beneficial owner would trade with each other
+type SelfTradePreventionMode string
+
+const (
+	SelfTradeCancelNewest SelfTradePreventionMode = "cancel_newest" // Cancel the order that arrived last
+	SelfTradeCancelOldest SelfTradePreventionMode = "cancel_oldest" // Cancel the order that arrived first
+	SelfTradeCancelBoth   SelfTradePreventionMode = "cancel_both"
+	SelfTradeDecrement    SelfTradePreventionMode = "decrement" // Reduce both by the smaller quantity, which cancels the smaller order
+	SelfTradeReject       SelfTradePreventionMode = "reject"    // Bilateral trades are refused; no resting order is involved
+)
+
+// ParseSelfTradePreventionMode returns the book matching mode with the given
+// name. An empty name selects the default, cancel newest.
+func ParseSelfTradePreventionMode(name string) (SelfTradePreventionMode, error) {
+	switch mode := SelfTradePreventionMode(name); mode {
+	case "":
+		return SelfTradeCancelNewest, nil
+	case SelfTradeCancelNewest, SelfTradeCancelOldest, SelfTradeCancelBoth, SelfTradeDecrement:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown self-trade prevention mode: %s", name)
+	}
+}
+
+// SelfTradePrevention records an order cancelled or decremented in a matching
+// round because it would have traded with an order of the same beneficial
+// owner
+type SelfTradePrevention struct {
+	Order        *OrderBookEntry // The order as it was when prevented
+	Counterparty *OrderBookEntry
+	Mode         SelfTradePreventionMode
+	Shares       int64 // Shares taken off the order
+}
+
+// Cancels reports whether the whole order is cancelled rather than decremented
+func (p *SelfTradePrevention) Cancels() bool {
+	return p.Mode != SelfTradeDecrement || p.Shares >= p.Order.Quantity
+}
+
+// Owner returns the beneficial owner an order trades for, falling back to the
+// user who placed it
+func (e *OrderBookEntry) Owner() string {
+	if e.OwnerID != "" {
+		return e.OwnerID
+	}
+	return e.UserID
+}
+
+// preventSelfTrade reports whether a sell and a buy order belong to the same
+// beneficial owner and so must not trade. When they do, the engine's
+// self-trade prevention mode takes shares off one or both orders and the
+// prevention is recorded on the book.
+func (e *OrderMatchingEngine) preventSelfTrade(orderBook *OrderBook, sellOrder, buyOrder *OrderBookEntry) bool {
+	if sellOrder.Owner() != buyOrder.Owner() {
+		return false
+	}
+	if sellOrder.Quantity == 0 || buyOrder.Quantity == 0 {
+		return true
+	}
+
+	newest, oldest := buyOrder, sellOrder
+	if sellOrder.Timestamp.After(buyOrder.Timestamp) {
+		newest, oldest = sellOrder, buyOrder
+	}
+
+	switch e.selfTradeMode {
+	case SelfTradeCancelOldest:
+		orderBook.prevent(e.selfTradeMode, oldest, newest, oldest.Quantity)
+	case SelfTradeCancelBoth:
+		orderBook.prevent(e.selfTradeMode, sellOrder, buyOrder, sellOrder.Quantity, buyOrder.Quantity)
+	case SelfTradeDecrement:
+		shares := min(sellOrder.Quantity, buyOrder.Quantity)
+		orderBook.prevent(e.selfTradeMode, sellOrder, buyOrder, shares, shares)
+	default:
+		orderBook.prevent(SelfTradeCancelNewest, newest, oldest, newest.Quantity)
+	}
+	return true
+}
+
+// prevent takes shares off an order, and optionally its counterparty, and
+// records each prevention
+func (ob *OrderBook) prevent(mode SelfTradePreventionMode, order, counterparty *OrderBookEntry, shares int64, counterpartyShares ...int64) {
+	orderCopy, counterpartyCopy := copyEntry(order), copyEntry(counterparty)
+
+	ob.Prevented = append(ob.Prevented, &SelfTradePrevention{Order: orderCopy, Counterparty: counterpartyCopy, Mode: mode, Shares: shares})
+	order.Quantity -= shares
+
+	for _, shares := range counterpartyShares {
+		ob.Prevented = append(ob.Prevented, &SelfTradePrevention{Order: counterpartyCopy, Counterparty: orderCopy, Mode: mode, Shares: shares})
+		counterparty.Quantity -= shares
+	}
+}
+
+// checkSelfTrade refuses a bilateral trade between accounts of the same
+// beneficial owner and announces the refusal
+func (e *OrderMatchingEngine) checkSelfTrade(securityID, orderID, buyerID, sellerID string) error {
+	buyerOwner, sellerOwner := e.beneficialOwner(buyerID), e.beneficialOwner(sellerID)
+	if buyerOwner != sellerOwner {
+		return nil
+	}
+
+	if e.eventBus != nil {
+		event := NewSelfTradePrevented(securityID, orderID, "", buyerID, sellerID, buyerOwner, SelfTradeReject, 0)
+		if err := e.eventBus.Publish(event); err != nil {
+			fmt.Printf("Failed to publish self-trade prevention for %s: %v\n", securityID, err)
+		}
+	}
+	return fmt.Errorf("buyer %s and seller %s trade for the same beneficial owner", buyerID, sellerID)
+}
+
+// beneficialOwner resolves the owner a user trades for. Users whose owner
+// cannot be resolved are treated as trading only for themselves.
+func (e *OrderMatchingEngine) beneficialOwner(userID string) string {
+	if e.owners == nil {
+		return userID
+	}
+	owner, err := e.owners.BeneficialOwner(userID)
+	if err != nil || owner == "" {
+		return userID
+	}
+	return owner
+}
@@ -0,0 +1,127 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/testutil"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/listing"
+)
+
+// selfTradeBook holds a resting sell from fund-1, a later buy from another
+// fund-1 account and a worse-priced sell from an unrelated seller
+func selfTradeBook() *OrderBook {
+	book := NewOrderBook("TEST-001")
+	own := newBookEntry("sell", "sell-own", 100, decimalPtr("10.00"), testutil.TestTime)
+	own.OwnerID = "fund-1"
+	other := newBookEntry("sell", "sell-other", 100, decimalPtr("11.00"), testutil.TestTime)
+	buy := newBookEntry("buy", "buy-own", 60, decimalPtr("11.00"), testutil.TestTime.Add(time.Second))
+	buy.OwnerID = "fund-1"
+	book.AddSellOrder(own)
+	book.AddSellOrder(other)
+	book.AddBuyOrder(buy)
+	return book
+}
+
+func TestMatching_PreventsSelfTradesInEveryMode(t *testing.T) {
+	tests := []struct {
+		mode      SelfTradePreventionMode
+		prevented map[string]int64 // order ID to shares taken off it
+		traded    int64            // shares the buy trades with the unrelated seller
+	}{
+		{SelfTradeCancelNewest, map[string]int64{"buy-own": 60}, 0},
+		{SelfTradeCancelOldest, map[string]int64{"sell-own": 100}, 60},
+		{SelfTradeCancelBoth, map[string]int64{"sell-own": 100, "buy-own": 60}, 0},
+		{SelfTradeDecrement, map[string]int64{"sell-own": 60, "buy-own": 60}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			engine := &OrderMatchingEngine{selfTradeMode: tt.mode}
+			book := selfTradeBook()
+
+			matches, err := engine.matchPriceTimePriority(book)
+			testutil.AssertNoError(t, err, "Matching should succeed")
+
+			var traded int64
+			for _, match := range matches {
+				testutil.AssertEqual(t, "sell-other", match.ListingID, "Owner should never trade with itself")
+				traded += match.SharesTraded
+			}
+			testutil.AssertEqual(t, tt.traded, traded, "Only unrelated orders should trade")
+
+			testutil.AssertLengthEqual(t, len(tt.prevented), book.Prevented, "Every prevented order should be recorded")
+			for _, prevention := range book.Prevented {
+				testutil.AssertEqual(t, tt.prevented[prevention.Order.OrderID()], prevention.Shares, "Prevention should take the right shares")
+				testutil.AssertEqual(t, tt.mode, prevention.Mode, "Prevention should record the mode")
+			}
+		})
+	}
+}
+
+func TestMatching_PreventsSelfTradesInAuctions(t *testing.T) {
+	engine := &OrderMatchingEngine{selfTradeMode: SelfTradeCancelNewest}
+	book := selfTradeBook()
+
+	matches, err := engine.matchUniformPriceAuction(book)
+	testutil.AssertNoError(t, err, "Auction should succeed")
+	for _, match := range matches {
+		testutil.AssertEqual(t, "sell-other", match.ListingID, "Owner should never trade with itself")
+	}
+	testutil.AssertLengthEqual(t, 1, book.Prevented, "Newest order should be prevented")
+	testutil.AssertEqual(t, "buy-own", book.Prevented[0].Order.OrderID(), "Newest order should be prevented")
+}
+
+func TestExecutionService_RunMatchingPreventsLinkedAccountSelfTrades(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetInvestorVerifier(testInvestorVerifier{})
+	service.SetOwnershipResolver(testOwners{"seller-1": "fund-1", "buyer-1": "fund-1"})
+	service.SetSelfTradePrevention(SelfTradeDecrement)
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	offer := listing.NewListingAggregate("listing-1")
+	offer.CreateListing("TEST-001", "seller-1", 100, listing.ListingTypeFixed, nil, nil, decimalPtr("50.00"), nil, false, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, offer), "Listing should be saved")
+
+	bid := bidding.NewBidAggregate("bid-1")
+	bid.PlaceBid("listing-1", "buyer-1", 40, money.NewDecimalFromInt(50), bidding.BidTypeLimit, nil)
+	testutil.AssertNoError(t, saveTestAggregate(setup.EventStore, bid), "Bid should be saved")
+
+	// Act
+	trades, err := service.RunMatching("TEST-001", PriceTimePriority)
+
+	// Assert
+	testutil.AssertNoError(t, err, "Matching should succeed")
+	testutil.AssertLengthEqual(t, 0, trades, "Linked accounts should not trade")
+
+	withdrawn, _ := bidding.NewEventSourcedBidRepository(setup.EventStore).FindByID("bid-1")
+	testutil.AssertEqual(t, bidding.BidStatusWithdrawn, withdrawn.Status, "Smaller order should be cancelled")
+	reduced, _ := listing.NewEventSourcedListingRepository(setup.EventStore).FindByID("listing-1")
+	testutil.AssertEqual(t, listing.ListingStatusActive, reduced.Status, "Larger order should stay active")
+	testutil.AssertEqual(t, int64(60), reduced.SharesRemaining, "Larger order should be decremented")
+
+	book, _ := service.GetOrderBook("TEST-001")
+	bestAsk, _ := book.BestAsk()
+	testutil.AssertEqual(t, int64(60), bestAsk.Quantity, "Book should show the decremented listing")
+	testutil.AssertLengthEqual(t, 2, setup.EventBus.GetEventsByType("SelfTradePrevented"), "Each prevented order should be announced")
+}
+
+func TestOrderMatchingEngine_RejectsBilateralSelfTrades(t *testing.T) {
+	setup := testutil.NewTestSetup()
+	engine := NewOrderMatchingEngine(setup.EventStore, setup.EventBus)
+	engine.SetOwnershipResolver(testOwners{"seller-1": "fund-1", "buyer-1": "fund-1"})
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	_, err := engine.QuoteMatch("TEST-001", "buyer-1", "seller-1", 10, money.NewDecimalFromInt(50))
+	testutil.AssertError(t, err, "Quotes between linked accounts should be refused")
+	testutil.AssertLengthEqual(t, 1, setup.EventBus.GetEventsByType("SelfTradePrevented"), "Refusal should be announced")
+
+	_, err = engine.QuoteMatch("TEST-001", "buyer-2", "seller-1", 10, money.NewDecimalFromInt(50))
+	testutil.AssertNoError(t, err, "Unrelated accounts should trade")
+	_, err = engine.QuoteMatch("TEST-404", "buyer-2", "seller-1", 10, money.NewDecimalFromInt(50))
+	testutil.AssertError(t, err, "Securities that cannot be checked for a halt should not trade")
+}
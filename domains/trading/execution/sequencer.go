@@ -0,0 +1,375 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/storage"
+)
+
+var (
+	// ErrSecurityLeased is returned when another process holds the matching
+	// lease of a security
+	ErrSecurityLeased = errors.New("security is matched by another process")
+
+	// ErrMatchingLeaseLost is returned by a fence once the actor's lease has
+	// run out or been taken over, so a command stops before saving events
+	ErrMatchingLeaseLost = errors.New("matching lease was lost")
+
+	// ErrSequencerClosed is returned for commands sent after the sequencer
+	// was closed
+	ErrSequencerClosed = errors.New("matching sequencer is closed")
+)
+
+const (
+	defaultMatchingLeaseTTL    = 15 * time.Second
+	defaultMatchingIdleTimeout = time.Minute
+)
+
+// Fence is checked by a sequenced command right before it saves events. It
+// fails once the command's actor no longer holds the security's lease.
+type Fence func() error
+
+// matchingLeaseKey is the cache key of a security's matching lease
+func matchingLeaseKey(securityID string) string {
+	return "matching:lease:" + securityID
+}
+
+// MatchingSequencer serializes the commands that change a security's orders.
+// Each security's book is owned by one goroutine, its actor, which keeps the
+// book current from the event store and runs the commands sent to it one at
+// a time against it. Across processes a lease in the cache
+// decides whose actor may trade a security: a process that cannot take the
+// lease refuses the command, and takes the security over once the owner's
+// lease expires. Leases are renewed in the background while held, and each
+// command is given a fence to check before it saves events.
+//
+// The execution service sends its commands through the sequencer set with
+// SetMatchingSequencer; one sequencer should be shared by every service in a
+// process.
+type MatchingSequencer struct {
+	cache       storage.Cache
+	eventStore  events.EventStore
+	owner       string
+	leaseTTL    time.Duration
+	idleTimeout time.Duration
+
+	mu     sync.Mutex
+	actors map[string]*matchingActor
+	closed chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+}
+
+// matchingActor owns the book of one security
+type matchingActor struct {
+	securityID string
+	books      *OrderBookManager // Keeps only the security's book; used by the actor alone
+	commands   chan matchingCommand
+	done       chan struct{}  // Closed once the actor stops taking commands
+	lease      *matchingLease // Lease taken by the actor, nil if not held
+}
+
+// matchingLease is a lease held by an actor. It is renewed by its own
+// goroutine so a long command cannot let it lapse unnoticed.
+type matchingLease struct {
+	mu    sync.Mutex
+	until time.Time // When the lease runs out if not renewed, zero once lost
+
+	stop    chan struct{} // Closed to stop renewing
+	stopped chan struct{} // Closed once renewal has stopped
+}
+
+// matchingCommand is run by the actor of its security, on the actor's book
+type matchingCommand struct {
+	run   func(fence Fence, books *OrderBookManager) (interface{}, error)
+	reply chan matchingReply
+}
+
+type matchingReply struct {
+	result interface{}
+	err    error
+}
+
+// NewMatchingSequencer creates a new matching sequencer that keeps its
+// leases in the given cache. Actors build their books from the event store.
+func NewMatchingSequencer(cache storage.Cache, eventStore events.EventStore) *MatchingSequencer {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return &MatchingSequencer{
+		cache:       cache,
+		eventStore:  eventStore,
+		owner:       fmt.Sprintf("%s-%s", hostname, uuid.New().String()),
+		leaseTTL:    defaultMatchingLeaseTTL,
+		idleTimeout: defaultMatchingIdleTimeout,
+		actors:      make(map[string]*matchingActor),
+		closed:      make(chan struct{}),
+	}
+}
+
+// SetLeaseTTL sets how long a lease lasts without renewal, which is how long
+// a security goes unmatched after its owner dies. Leases are renewed every
+// third of the TTL.
+func (q *MatchingSequencer) SetLeaseTTL(ttl time.Duration) {
+	q.leaseTTL = ttl
+}
+
+// SetIdleTimeout sets how long an actor waits for a command before it stops
+// and releases its lease
+func (q *MatchingSequencer) SetIdleTimeout(timeout time.Duration) {
+	q.idleTimeout = timeout
+}
+
+// Close stops every actor and releases their leases
+func (q *MatchingSequencer) Close() {
+	q.once.Do(func() {
+		close(q.closed)
+	})
+	q.wg.Wait()
+}
+
+// submit sends a command to the security's actor and waits for its reply.
+// The command is run with a fence and the actor's book. An actor that
+// stopped for being idle is replaced.
+func (q *MatchingSequencer) submit(securityID string, run func(fence Fence, books *OrderBookManager) (interface{}, error)) (interface{}, error) {
+	command := matchingCommand{run: run, reply: make(chan matchingReply, 1)}
+
+	for {
+		actor, err := q.actor(securityID)
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case actor.commands <- command:
+			reply := <-command.reply
+			return reply.result, reply.err
+		case <-actor.done:
+		case <-q.closed:
+			return nil, ErrSequencerClosed
+		}
+	}
+}
+
+// actor returns the running actor of a security, starting one if needed
+func (q *MatchingSequencer) actor(securityID string) (*matchingActor, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	select {
+	case <-q.closed:
+		return nil, ErrSequencerClosed
+	default:
+	}
+
+	if actor, exists := q.actors[securityID]; exists {
+		return actor, nil
+	}
+
+	actor := &matchingActor{
+		securityID: securityID,
+		books:      newSecurityOrderBookManager(q.eventStore, securityID),
+		commands:   make(chan matchingCommand),
+		done:       make(chan struct{}),
+	}
+	q.actors[securityID] = actor
+
+	q.wg.Add(1)
+	go q.run(actor)
+	return actor, nil
+}
+
+// run is the actor loop. Commands are run one at a time, and only while the
+// actor holds the security's lease.
+func (q *MatchingSequencer) run(actor *matchingActor) {
+	defer q.wg.Done()
+
+	idle := time.NewTimer(q.idleTimeout)
+	defer idle.Stop()
+
+	stop := func() {
+		q.mu.Lock()
+		delete(q.actors, actor.securityID)
+		q.mu.Unlock()
+		close(actor.done)
+
+		q.releaseLease(actor)
+	}
+
+	for {
+		select {
+		case command := <-actor.commands:
+			if err := q.holdLease(actor); err != nil {
+				command.reply <- matchingReply{err: err}
+			} else {
+				lease := actor.lease
+				result, err := command.run(func() error {
+					return q.checkLease(actor.securityID, lease)
+				}, actor.books)
+				command.reply <- matchingReply{result: result, err: err}
+			}
+
+			idle.Reset(q.idleTimeout)
+
+		case <-idle.C:
+			stop()
+			return
+
+		case <-q.closed:
+			stop()
+			return
+		}
+	}
+}
+
+// holdLease makes sure the actor holds its security's lease, taking it if
+// the actor has none or lost the one it had
+func (q *MatchingSequencer) holdLease(actor *matchingActor) error {
+	if actor.lease != nil {
+		if actor.lease.heldAt(time.Now()) {
+			return nil
+		}
+		actor.lease.halt()
+		actor.lease = nil
+	}
+
+	ctx := context.Background()
+	key := matchingLeaseKey(actor.securityID)
+	takenAt := time.Now()
+
+	acquired, err := q.cache.SetNX(ctx, key, q.owner, q.leaseTTL)
+	if err != nil {
+		return fmt.Errorf("failed to acquire matching lease: %w", err)
+	}
+	if !acquired {
+		holder, err := q.cache.Get(ctx, key)
+		if err != nil && err != redis.Nil {
+			return fmt.Errorf("failed to read matching lease: %w", err)
+		}
+		return fmt.Errorf("%w: %s is held by %s", ErrSecurityLeased, actor.securityID, holder)
+	}
+
+	actor.lease = &matchingLease{
+		until:   takenAt.Add(q.leaseTTL),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go q.renewLease(actor.securityID, actor.lease)
+	return nil
+}
+
+// renewLease extends the lease every third of its TTL until it is halted or
+// lost. Renewal only extends the lease while the cache still names this
+// process as its holder.
+func (q *MatchingSequencer) renewLease(securityID string, lease *matchingLease) {
+	defer close(lease.stopped)
+
+	ticker := time.NewTicker(q.leaseTTL / 3)
+	defer ticker.Stop()
+
+	key := matchingLeaseKey(securityID)
+	for {
+		select {
+		case <-lease.stop:
+			return
+
+		case <-ticker.C:
+			// Time the new expiry from before the request, so the local view
+			// of the lease never outlasts the cache's
+			renewedAt := time.Now()
+			renewed, err := q.cache.ExpireIfEqual(context.Background(), key, q.owner, q.leaseTTL)
+			if err != nil {
+				// The lease runs out on its own unless a later renewal works
+				log.Printf("Failed to renew matching lease of %s: %v", securityID, err)
+				continue
+			}
+			if !renewed {
+				log.Printf("Lost matching lease of %s", securityID)
+				lease.lose()
+				return
+			}
+			lease.extend(renewedAt.Add(q.leaseTTL))
+		}
+	}
+}
+
+// checkLease is the fence of the commands run under a lease. It fails once
+// the lease has run out locally or the cache names another holder.
+func (q *MatchingSequencer) checkLease(securityID string, lease *matchingLease) error {
+	if !lease.heldAt(time.Now()) {
+		return fmt.Errorf("%w: %s", ErrMatchingLeaseLost, securityID)
+	}
+
+	holder, err := q.cache.Get(context.Background(), matchingLeaseKey(securityID))
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to read matching lease: %w", err)
+	}
+	if holder != q.owner {
+		lease.lose()
+		return fmt.Errorf("%w: %s", ErrMatchingLeaseLost, securityID)
+	}
+	return nil
+}
+
+// releaseLease stops renewing the actor's lease and gives it up, so another
+// process can take the security over without waiting for it to expire
+func (q *MatchingSequencer) releaseLease(actor *matchingActor) {
+	if actor.lease == nil {
+		return
+	}
+	lease := actor.lease
+	actor.lease = nil
+	lease.halt()
+
+	if !lease.heldAt(time.Now()) {
+		return
+	}
+	if _, err := q.cache.DelIfEqual(context.Background(), matchingLeaseKey(actor.securityID), q.owner); err != nil {
+		log.Printf("Failed to release matching lease of %s: %v", actor.securityID, err)
+	}
+}
+
+// heldAt reports whether the lease is still held at the given time
+func (l *matchingLease) heldAt(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return !l.until.IsZero() && now.Before(l.until)
+}
+
+// extend moves the lease's expiry to until, unless it was lost meanwhile
+func (l *matchingLease) extend(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.until.IsZero() {
+		l.until = until
+	}
+}
+
+// lose marks the lease as no longer held
+func (l *matchingLease) lose() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.until = time.Time{}
+}
+
+// halt stops renewing the lease and waits for renewal to stop
+func (l *matchingLease) halt() {
+	select {
+	case <-l.stop:
+	default:
+		close(l.stop)
+	}
+	<-l.stopped
+}
@@ -0,0 +1,241 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/storage"
+	"securities-marketplace/domains/shared/testutil"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/listing"
+)
+
+// overlapDetector records commands that ran at the same time
+type overlapDetector struct {
+	running  int32
+	overlaps int32
+	rounds   int32
+}
+
+func (d *overlapDetector) run(fence Fence, books *OrderBookManager) (interface{}, error) {
+	if atomic.AddInt32(&d.running, 1) > 1 {
+		atomic.AddInt32(&d.overlaps, 1)
+	}
+	time.Sleep(time.Millisecond)
+	atomic.AddInt32(&d.rounds, 1)
+	atomic.AddInt32(&d.running, -1)
+	return nil, fence()
+}
+
+func TestMatchingSequencer_SerializesMatchingAcrossProcesses(t *testing.T) {
+	// Arrange
+	cache := storage.NewInMemoryCache()
+	detector := &overlapDetector{}
+	first := NewMatchingSequencer(cache, testutil.NewTestEventStore())
+	defer first.Close()
+	second := NewMatchingSequencer(cache, testutil.NewTestEventStore())
+	defer second.Close()
+
+	_, err := first.submit("TEST-001", detector.run)
+	testutil.AssertNoError(t, err, "First process should take the lease")
+
+	// Act
+	var wg sync.WaitGroup
+	var leased, fenced int32
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := first.submit("TEST-001", detector.run); err != nil {
+				atomic.AddInt32(&fenced, 1)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := second.submit("TEST-001", detector.run); errors.Is(err, ErrSecurityLeased) {
+				atomic.AddInt32(&leased, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	testutil.AssertEqual(t, int32(0), detector.overlaps, "Commands for a security should never overlap")
+	testutil.AssertEqual(t, int32(21), detector.rounds, "Only the lease holder should run commands")
+	testutil.AssertEqual(t, int32(0), fenced, "Lease holder should pass its fence")
+	testutil.AssertEqual(t, int32(20), leased, "Other processes should be refused while the lease is held")
+}
+
+func TestMatchingSequencer_TakesOverWhenLeaseExpires(t *testing.T) {
+	// Arrange
+	cache := storage.NewInMemoryCache()
+	detector := &overlapDetector{}
+	sequencer := NewMatchingSequencer(cache, testutil.NewTestEventStore())
+	defer sequencer.Close()
+	sequencer.SetLeaseTTL(30 * time.Millisecond)
+
+	// A process that died while holding the lease
+	cache.Set(context.Background(), matchingLeaseKey("TEST-001"), "crashed-worker", 30*time.Millisecond)
+
+	// Act & Assert
+	_, err := sequencer.submit("TEST-001", detector.run)
+	testutil.AssertTrue(t, errors.Is(err, ErrSecurityLeased), "Security should not be matched while another process holds it")
+
+	time.Sleep(40 * time.Millisecond)
+	_, err = sequencer.submit("TEST-001", detector.run)
+	testutil.AssertNoError(t, err, "Security should be taken over once the lease expires")
+
+	time.Sleep(60 * time.Millisecond)
+	holder, _ := cache.Get(context.Background(), matchingLeaseKey("TEST-001"))
+	testutil.AssertEqual(t, sequencer.owner, holder, "Lease should be renewed while the actor runs")
+
+	sequencer.Close()
+	exists, _ := cache.Exists(context.Background(), matchingLeaseKey("TEST-001"))
+	testutil.AssertEqual(t, int64(0), exists, "Closing should release the lease")
+}
+
+func TestMatchingSequencer_RenewsDuringLongCommandsAndFencesLostLeases(t *testing.T) {
+	// Arrange
+	cache := storage.NewInMemoryCache()
+	sequencer := NewMatchingSequencer(cache, testutil.NewTestEventStore())
+	defer sequencer.Close()
+	sequencer.SetLeaseTTL(30 * time.Millisecond)
+	key := matchingLeaseKey("TEST-001")
+
+	// Act
+	_, longErr := sequencer.submit("TEST-001", func(fence Fence, books *OrderBookManager) (interface{}, error) {
+		time.Sleep(100 * time.Millisecond)
+		return nil, fence()
+	})
+	_, stolenErr := sequencer.submit("TEST-001", func(fence Fence, books *OrderBookManager) (interface{}, error) {
+		// Another process took the lease while this command ran
+		cache.Set(context.Background(), key, "other-worker", time.Minute)
+		return nil, fence()
+	})
+	renewed, _ := cache.ExpireIfEqual(context.Background(), key, sequencer.owner, time.Minute)
+	_, refusedErr := sequencer.submit("TEST-001", func(fence Fence, books *OrderBookManager) (interface{}, error) {
+		return nil, nil
+	})
+	sequencer.Close()
+	holder, _ := cache.Get(context.Background(), key)
+
+	// Assert
+	testutil.AssertNoError(t, longErr, "Lease should be renewed while a command outlasts its TTL")
+	testutil.AssertTrue(t, errors.Is(stolenErr, ErrMatchingLeaseLost), "Fence should fail once another process holds the lease")
+	testutil.AssertFalse(t, renewed, "Renewal should not extend another holder's lease")
+	testutil.AssertTrue(t, errors.Is(refusedErr, ErrSecurityLeased), "Commands should be refused after the lease is lost")
+	testutil.AssertEqual(t, "other-worker", holder, "Releasing should not delete another holder's lease")
+}
+
+func TestExecutionService_SequencesTradingCommands(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	cache := storage.NewInMemoryCache()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetInvestorVerifier(testInvestorVerifier{})
+	sequencer := NewMatchingSequencer(cache, setup.EventStore)
+	defer sequencer.Close()
+	service.SetMatchingSequencer(sequencer)
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	cache.Set(context.Background(), matchingLeaseKey("TEST-002"), "other-worker", time.Minute)
+
+	// Act
+	_, ownErr := service.RunMatching("TEST-001", PriceTimePriority)
+	_, leasedErr := service.RunMatching("TEST-002", PriceTimePriority)
+	_, stopErr := service.TriggerStopBids("TEST-002", money.NewDecimalFromInt(50))
+
+	// Assert
+	testutil.AssertNoError(t, ownErr, "Matching should run on the security's actor")
+	holder, _ := cache.Get(context.Background(), matchingLeaseKey("TEST-001"))
+	testutil.AssertEqual(t, sequencer.owner, holder, "Matching should take the security's lease")
+	testutil.AssertTrue(t, errors.Is(leasedErr, ErrSecurityLeased), "Matching should be refused for securities leased elsewhere")
+	testutil.AssertTrue(t, errors.Is(stopErr, ErrSecurityLeased), "Stop triggers should be refused for securities leased elsewhere")
+}
+
+func TestExecutionService_SequencesOrderEntryOnActorBooks(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	cache := storage.NewInMemoryCache()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetInvestorVerifier(testInvestorVerifier{})
+	sequencer := NewMatchingSequencer(cache, setup.EventStore)
+	defer sequencer.Close()
+	service.SetMatchingSequencer(sequencer)
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	listingRepository := listing.NewEventSourcedListingRepository(setup.EventStore)
+	listings := listing.NewListingService(listingRepository, setup.EventStore, setup.EventBus)
+	listings.SetSequencer(service)
+	bids := bidding.NewBidService(bidding.NewEventSourcedBidRepository(setup.EventStore), listingRepository, setup.EventStore, setup.EventBus)
+	bids.SetSequencer(service)
+
+	// A listing entered before another process leased its security
+	leasedOffer, err := listing.NewListingService(listingRepository, setup.EventStore, setup.EventBus).CreateListing(&listing.CreateListingCommand{
+		SecurityID:    "TEST-002",
+		SellerID:      "seller-2",
+		SharesOffered: 100,
+		ListingType:   listing.ListingTypeFixed,
+		CurrentPrice:  decimalPtr("10.00"),
+	})
+	testutil.AssertNoError(t, err, "Listing should be entered without a sequencer")
+	cache.Set(context.Background(), matchingLeaseKey("TEST-002"), "other-worker", time.Minute)
+
+	// Act
+	offer, offerErr := listings.CreateListing(&listing.CreateListingCommand{
+		SecurityID:    "TEST-001",
+		SellerID:      "seller-1",
+		SharesOffered: 100,
+		ListingType:   listing.ListingTypeFixed,
+		CurrentPrice:  decimalPtr("10.00"),
+	})
+	_, bidErr := bids.PlaceBid(&bidding.PlaceBidCommand{
+		ListingID:       offer.ID,
+		BidderID:        "buyer-1",
+		SharesRequested: 40,
+		BidPrice:        decimalPtr("10.00"),
+		BidType:         bidding.BidTypeLimit,
+	})
+	trades, matchErr := service.RunMatching("TEST-001", PriceTimePriority)
+	_, leasedListingErr := listings.CreateListing(&listing.CreateListingCommand{
+		SecurityID:    "TEST-002",
+		SellerID:      "seller-2",
+		SharesOffered: 10,
+		ListingType:   listing.ListingTypeFixed,
+		CurrentPrice:  decimalPtr("10.00"),
+	})
+	_, leasedBidErr := bids.PlaceBid(&bidding.PlaceBidCommand{
+		ListingID:       leasedOffer.ID,
+		BidderID:        "buyer-1",
+		SharesRequested: 10,
+		BidPrice:        decimalPtr("10.00"),
+		BidType:         bidding.BidTypeLimit,
+	})
+	_, leasedNegotiationErr := service.OpenNegotiation(leasedOffer.ID, "buyer-1", 10, money.NewDecimalFromInt(9), "")
+	sharedPosition := service.matchingEngine.OrderBooks().Position()
+	_, expireErr := service.ExpireOrders()
+
+	// Assert
+	testutil.AssertNoError(t, offerErr, "Listing should be entered on the security's actor")
+	testutil.AssertNoError(t, bidErr, "Bid should be entered on the security's actor")
+	testutil.AssertNoError(t, matchErr, "Matching should run on the security's actor")
+	testutil.AssertLengthEqual(t, 1, trades, "Matching should see the orders entered on the actor")
+
+	sequencer.mu.Lock()
+	actorBooks := sequencer.actors["TEST-001"].books
+	sequencer.mu.Unlock()
+	testutil.AssertEqual(t, []string{"TEST-001"}, actorBooks.Securities(), "Actor should keep only its security's book")
+	_, hasBid := actorBooks.Book("TEST-001").BestBid()
+	testutil.AssertFalse(t, hasBid, "Filled bid should leave the actor's book")
+	testutil.AssertEqual(t, int64(0), sharedPosition, "Commands should not sync the shared books")
+
+	testutil.AssertTrue(t, errors.Is(leasedListingErr, ErrSecurityLeased), "Listings should be refused for securities leased elsewhere")
+	testutil.AssertTrue(t, errors.Is(leasedBidErr, ErrSecurityLeased), "Bids should be refused for securities leased elsewhere")
+	testutil.AssertTrue(t, errors.Is(leasedNegotiationErr, ErrSecurityLeased), "Negotiations should be refused for securities leased elsewhere")
+	testutil.AssertNoError(t, expireErr, "Expiry should skip securities leased elsewhere")
+}
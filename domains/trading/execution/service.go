@@ -1,12 +1,24 @@
 package execution
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	
+	"securities-marketplace/domains/securities"
+	"securities-marketplace/domains/shared/audit"
 	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/calendar"
+	"securities-marketplace/domains/trading/listing"
+	"securities-marketplace/domains/trading/negotiation"
+	"securities-marketplace/domains/trading/orders"
+	"securities-marketplace/domains/trading/rfq"
+	"securities-marketplace/domains/trading/session"
 )
 
 // ExecutionService provides application services for trade execution
@@ -15,6 +27,18 @@ type ExecutionService struct {
 	eventStore     events.EventStore
 	eventBus       events.EventBus
 	matchingEngine *OrderMatchingEngine
+	listings       listing.ListingRepository
+	bids           bidding.BidRepository
+	negotiations   negotiation.NegotiationRepository
+	quoteRequests  rfq.RFQRepository
+	securities     securities.SecurityRepository
+	sessions       session.SessionRepository
+	riskEngine     RiskEngine
+	feeCalculator  FeeCalculator
+	auditLog       audit.Logger
+	actor          *audit.Actor
+	sequencer      *MatchingSequencer
+	fence          Fence // Set on the copy a sequenced command runs on
 }
 
 // NewExecutionService creates a new execution service
@@ -26,11 +50,140 @@ func NewExecutionService(repository TradeRepository, eventStore events.EventStor
 		eventStore:     eventStore,
 		eventBus:       eventBus,
 		matchingEngine: matchingEngine,
+		listings:       listing.NewEventSourcedListingRepository(eventStore),
+		bids:           bidding.NewEventSourcedBidRepository(eventStore),
+		negotiations:   negotiation.NewEventSourcedNegotiationRepository(eventStore),
+		quoteRequests:  rfq.NewEventSourcedRFQRepository(eventStore),
+		securities:     securities.NewEventSourcedSecurityRepository(eventStore),
+		sessions:       session.NewEventSourcedSessionRepository(eventStore),
 	}
 }
 
-// ExecuteTradeMatch creates a new trade from a match result
-func (s *ExecutionService) ExecuteTradeMatch(match *MatchResult) (*TradeAggregate, error) {
+// SetAuditLogger enables audit logging of every command executed through the service
+func (s *ExecutionService) SetAuditLogger(logger audit.Logger) {
+	s.auditLog = logger
+}
+
+// WithActor returns a copy of the service that attributes its audit entries to
+// the given actor. Handlers pass the actor from the request context.
+func (s *ExecutionService) WithActor(actor *audit.Actor) *ExecutionService {
+	scoped := *s
+	scoped.actor = actor
+	return &scoped
+}
+
+// SetMatchingSequencer routes every command that can trade a security
+// through the sequencer, so only the process holding the security's lease
+// trades it and its commands run one at a time
+func (s *ExecutionService) SetMatchingSequencer(sequencer *MatchingSequencer) {
+	s.sequencer = sequencer
+}
+
+// sequence runs a command that changes the security's orders on the
+// security's actor when there is a sequencer. The command is given a copy of
+// the service that matches against the actor's book and checks the fence
+// before saving events. Commands called by a command already running on an
+// actor run in place.
+func (s *ExecutionService) sequence(securityID string, command func(*ExecutionService) (interface{}, error)) (interface{}, error) {
+	if s.sequencer == nil || s.fence != nil {
+		return command(s)
+	}
+
+	return s.sequencer.submit(securityID, func(fence Fence, books *OrderBookManager) (interface{}, error) {
+		fenced := *s
+		fenced.fence = fence
+		fenced.matchingEngine = s.matchingEngine.withBooks(books)
+		return command(&fenced)
+	})
+}
+
+// SequenceOrders runs a command that changes the security's orders on the
+// security's actor, for the listing and bid services. The command checks the
+// fence right before it saves events.
+func (s *ExecutionService) SequenceOrders(securityID string, command func(fence func() error) error) error {
+	_, err := s.sequence(securityID, func(fenced *ExecutionService) (interface{}, error) {
+		return nil, command(fenced.checkFence)
+	})
+	return err
+}
+
+// checkFence stops a sequenced command from saving events once its actor has
+// lost the security's lease
+func (s *ExecutionService) checkFence() error {
+	if s.fence == nil {
+		return nil
+	}
+	return s.fence()
+}
+
+// SetInvestorVerifier replaces the verifier the matching engine uses to check
+// buyer accreditation
+func (s *ExecutionService) SetInvestorVerifier(verifier InvestorVerifier) {
+	s.matchingEngine.SetInvestorVerifier(verifier)
+}
+
+// SetOwnershipResolver replaces the resolver the matching engine uses to
+// group accounts by beneficial owner
+func (s *ExecutionService) SetOwnershipResolver(resolver OwnershipResolver) {
+	s.matchingEngine.SetOwnershipResolver(resolver)
+}
+
+// SetSelfTradePrevention sets how matching resolves orders of the same
+// beneficial owner that would trade with each other
+func (s *ExecutionService) SetSelfTradePrevention(mode SelfTradePreventionMode) {
+	s.matchingEngine.SetSelfTradePrevention(mode)
+}
+
+// SetMarketDataProvider sets the source of reference prices the matching
+// engine uses to break call auction ties
+func (s *ExecutionService) SetMarketDataProvider(provider MarketDataProvider) {
+	s.matchingEngine.SetMarketDataProvider(provider)
+}
+
+// SetPriceBands enables price bands and volatility halts in matching runs
+func (s *ExecutionService) SetPriceBands(bands PriceBands) {
+	s.matchingEngine.SetPriceBands(bands)
+}
+
+// SetTradingCalendar sets the calendar that decides settlement dates and the
+// market hours matching runs in
+func (s *ExecutionService) SetTradingCalendar(cal *calendar.Calendar) {
+	s.matchingEngine.SetTradingCalendar(cal)
+}
+
+// SetRiskEngine enables pre-trade and post-trade risk checks on every trade
+// the service executes
+func (s *ExecutionService) SetRiskEngine(engine RiskEngine) {
+	s.riskEngine = engine
+}
+
+// SetFeeCalculator sets the fee engine that works out the fees charged when
+// trades settle. Without one trades settle without fees.
+func (s *ExecutionService) SetFeeCalculator(calculator FeeCalculator) {
+	s.feeCalculator = calculator
+}
+
+// ExecuteTradeMatch creates a new trade from a match result and fills the
+// matched listing and bid. The trade, listing and bid events are saved together
+// so the order book never sees a trade without its fills. Other aggregates
+// changed by the same command, such as the negotiation that agreed the trade,
+// are saved in the same batch.
+func (s *ExecutionService) ExecuteTradeMatch(match *MatchResult, related ...events.Aggregate) (*TradeAggregate, error) {
+	result, err := s.sequence(match.SecurityID, func(fenced *ExecutionService) (interface{}, error) {
+		return fenced.executeTradeMatch(match, related...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*TradeAggregate), nil
+}
+
+// executeTradeMatch books a match on the actor of its security
+func (s *ExecutionService) executeTradeMatch(match *MatchResult, related ...events.Aggregate) (*TradeAggregate, error) {
+	if err := s.checkTradeRisk(match); err != nil {
+		return nil, err
+	}
+
 	// Create new trade aggregate
 	trade := NewTradeAggregate(match.TradeID)
 	
@@ -50,15 +203,69 @@ func (s *ExecutionService) ExecuteTradeMatch(match *MatchResult) (*TradeAggregat
 		return nil, fmt.Errorf("failed to match trade: %w", err)
 	}
 
+	// Feed the fill back into the listing and bid. Quote trades have neither.
+	var filled []events.Aggregate
+	if match.ListingID != "" {
+		l, err := s.listings.FindByID(match.ListingID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find listing: %w", err)
+		}
+		err = l.ReduceShares(match.SharesTraded, match.TradeID, match.BuyerID, match.TradePrice)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reduce listing shares: %w", err)
+		}
+		filled = append(filled, l)
+	}
+
+	if match.BidID != nil {
+		b, err := s.bids.FindByID(*match.BidID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find bid: %w", err)
+		}
+		err = b.PartiallyFill(match.SharesTraded, match.TradePrice, match.TradeID, match.SellerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fill bid: %w", err)
+		}
+		filled = append(filled, b)
+	}
+
 	// Save events
-	err = s.saveAggregateEvents(trade, "system")
+	err = s.saveAggregateEvents(trade, "system", append(filled, related...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save trade events: %w", err)
 	}
 
+	if checker, ok := s.riskEngine.(PostTradeRiskChecker); ok {
+		if err := checker.CheckExecutedTrade(match); err != nil {
+			// Log error but don't fail the operation; the breach is recorded
+			fmt.Printf("Post-trade risk check failed for trade %s: %v\n", match.TradeID, err)
+		}
+	}
+
 	return trade, nil
 }
 
+// checkTradeRisk rejects a trade between counterparties that have used up
+// their exposure to each other, or that the risk engine rates extreme
+func (s *ExecutionService) checkTradeRisk(match *MatchResult) error {
+	if s.riskEngine == nil {
+		return nil
+	}
+
+	if err := s.riskEngine.ValidateCounterparty(match.BuyerID, match.SellerID); err != nil {
+		return fmt.Errorf("trade rejected by risk checks: %w", err)
+	}
+
+	assessment, err := s.riskEngine.AssessTradeRisk(match)
+	if err != nil {
+		return fmt.Errorf("failed to assess trade risk: %w", err)
+	}
+	if assessment.RiskLevel == "extreme" {
+		return fmt.Errorf("trade rejected by risk checks: %s", strings.Join(assessment.RiskFactors, "; "))
+	}
+	return nil
+}
+
 // ConfirmTrade handles trade confirmation by parties
 func (s *ExecutionService) ConfirmTrade(tradeID, confirmedBy string) error {
 	trade, err := s.repository.FindByID(tradeID)
@@ -90,13 +297,13 @@ func (s *ExecutionService) InitiateSettlement(tradeID, escrowAccountID, initiate
 }
 
 // RecordPayment records payment received for a trade
-func (s *ExecutionService) RecordPayment(tradeID string, amount float64, currency, paymentMethod, transactionID string) error {
+func (s *ExecutionService) RecordPayment(tradeID string, amount money.Money, paymentMethod, transactionID string) error {
 	trade, err := s.repository.FindByID(tradeID)
 	if err != nil {
 		return fmt.Errorf("failed to find trade: %w", err)
 	}
 
-	err = trade.ReceivePayment(amount, currency, paymentMethod, transactionID)
+	err = trade.ReceivePayment(amount, paymentMethod, transactionID)
 	if err != nil {
 		return fmt.Errorf("failed to record payment: %w", err)
 	}
@@ -119,13 +326,22 @@ func (s *ExecutionService) RecordShareTransfer(tradeID string, sharesCount int64
 	return s.saveAggregateEvents(trade, "system")
 }
 
-// SettleTrade completes the settlement of a trade
-func (s *ExecutionService) SettleTrade(tradeID string, finalAmount, fees, taxes float64, settlementMethod string) error {
+// SettleTrade completes the settlement of a trade, charging the fees the fee
+// calculator works out
+func (s *ExecutionService) SettleTrade(tradeID string, finalAmount, taxes money.Money, settlementMethod string) error {
 	trade, err := s.repository.FindByID(tradeID)
 	if err != nil {
 		return fmt.Errorf("failed to find trade: %w", err)
 	}
 
+	var fees *FeeBreakdown
+	if s.feeCalculator != nil {
+		fees, err = s.feeCalculator.CalculateFees(trade)
+		if err != nil {
+			return fmt.Errorf("failed to calculate fees: %w", err)
+		}
+	}
+
 	err = trade.SettleTrade(finalAmount, fees, taxes, settlementMethod)
 	if err != nil {
 		return fmt.Errorf("failed to settle trade: %w", err)
@@ -164,18 +380,46 @@ func (s *ExecutionService) CancelTrade(tradeID, cancellationReason, cancelledBy
 	return s.saveAggregateEvents(trade, cancelledBy)
 }
 
-// RunMatching executes order matching for a security
+// RunMatching executes order matching for a security. Fill-or-kill orders
+// the run could not fill, and what is left of immediate-or-cancel orders, are
+// cancelled afterwards. A trade outside the security's price band is not
+// made; the security is halted instead.
 func (s *ExecutionService) RunMatching(securityID string, algorithm MatchingAlgorithm) ([]*TradeAggregate, error) {
+	result, err := s.sequence(securityID, func(fenced *ExecutionService) (interface{}, error) {
+		return fenced.runMatching(securityID, algorithm)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]*TradeAggregate), nil
+}
+
+// runMatching runs a matching round on the actor of the security
+func (s *ExecutionService) runMatching(securityID string, algorithm MatchingAlgorithm) ([]*TradeAggregate, error) {
 	// Get match results from matching engine
-	matches, err := s.matchingEngine.MatchOrders(securityID, algorithm)
+	round, err := s.matchingEngine.RunMatchingRound(securityID, algorithm)
 	if err != nil {
 		return nil, fmt.Errorf("matching failed: %w", err)
 	}
 
+	trades := s.executeRound(round)
+
+	if round.Halt != nil {
+		if err := s.haltTrading(round.Halt); err != nil {
+			fmt.Printf("Failed to halt trading in %s: %v\n", securityID, err)
+		}
+	}
+
+	return trades, nil
+}
+
+// executeRound books the trades of a matching round and applies its
+// self-trade preventions and immediate order cancellations
+func (s *ExecutionService) executeRound(round *MatchingRound) []*TradeAggregate {
 	var trades []*TradeAggregate
 	
 	// Create trades from matches
-	for _, match := range matches {
+	for _, match := range round.Matches {
 		trade, err := s.ExecuteTradeMatch(match)
 		if err != nil {
 			// Log error but continue with other matches
@@ -185,7 +429,759 @@ func (s *ExecutionService) RunMatching(securityID string, algorithm MatchingAlgo
 		trades = append(trades, trade)
 	}
 
-	return trades, nil
+	for _, prevention := range round.Prevented {
+		if err := s.applySelfTradePrevention(prevention); err != nil {
+			fmt.Printf("Failed to prevent self-trade by order %s: %v\n", prevention.Order.OrderID(), err)
+		}
+	}
+
+	for _, entry := range round.Killed {
+		if err := s.killOrder(entry); err != nil {
+			fmt.Printf("Failed to cancel immediate order %s: %v\n", entry.OrderID(), err)
+		}
+	}
+
+	return trades
+}
+
+// haltTrading halts a security after a matching run found a trade outside
+// its price band. Trading resumes with a reopening auction once the
+// cooling-off period has passed.
+func (s *ExecutionService) haltTrading(halt *VolatilityHalt) error {
+	security, err := s.securities.FindByID(halt.Band.SecurityID)
+	if err != nil {
+		return fmt.Errorf("failed to find security: %w", err)
+	}
+	if security.IsHalted() {
+		return nil
+	}
+
+	reason := fmt.Sprintf("trade at %s outside price band %s to %s", halt.TradePrice, halt.Band.Lower, halt.Band.Upper)
+	err = security.HaltTrading(reason, halt.TradePrice, halt.Band.Lower, halt.Band.Upper, time.Now().Add(halt.Band.CoolingOff))
+	if err != nil {
+		return fmt.Errorf("failed to halt trading: %w", err)
+	}
+	return s.saveStandaloneEvents(security, "system")
+}
+
+// ReopenHaltedSecurities runs the reopening auction of every halted security
+// whose cooling-off period has ended and resumes trading in it. Securities
+// still cooling off have their indicative reopening price published. Nothing
+// reopens while the market is closed. It returns the IDs of the securities
+// reopened.
+func (s *ExecutionService) ReopenHaltedSecurities() ([]string, error) {
+	halted, err := s.securities.FindByStatus(securities.SecurityStatusHalted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find halted securities: %w", err)
+	}
+
+	now := time.Now()
+	if s.matchingEngine.checkMarketOpen(now) != nil {
+		return nil, nil
+	}
+
+	// Securities leased by another process are reopened by that process
+	var reopened []string
+	for _, security := range halted {
+		if !security.IsReadyToReopen(now) {
+			if _, err := s.PublishIndicativeAuctionPrice(security.ID); err != nil && !errors.Is(err, ErrSecurityLeased) {
+				fmt.Printf("Failed to publish indicative reopening price for %s: %v\n", security.ID, err)
+			}
+			continue
+		}
+
+		securityID := security.ID
+		result, err := s.sequence(securityID, func(fenced *ExecutionService) (interface{}, error) {
+			return fenced.reopen(securityID, now)
+		})
+		if errors.Is(err, ErrSecurityLeased) {
+			continue
+		}
+		if err != nil {
+			fmt.Printf("Failed to reopen %s: %v\n", securityID, err)
+			continue
+		}
+		if result.(bool) {
+			reopened = append(reopened, securityID)
+		}
+	}
+
+	return reopened, nil
+}
+
+// reopen runs a halted security's reopening auction and resumes trading at
+// its clearing price. The security is reloaded on its actor, and left alone
+// if it was reopened meanwhile. It reports whether the security reopened.
+func (s *ExecutionService) reopen(securityID string, now time.Time) (bool, error) {
+	security, err := s.securities.FindByID(securityID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find security: %w", err)
+	}
+	if !security.IsHalted() || !security.IsReadyToReopen(now) {
+		return false, nil
+	}
+
+	round, err := s.matchingEngine.RunReopeningAuction(security.ID)
+	if err != nil {
+		return false, fmt.Errorf("reopening auction failed: %w", err)
+	}
+
+	var reopeningPrice *money.Decimal
+	if trades := s.executeRound(round); len(trades) > 0 {
+		reopeningPrice = &trades[0].TradePrice
+	}
+
+	if err := security.ResumeTrading("system", reopeningPrice); err != nil {
+		return false, fmt.Errorf("failed to resume trading: %w", err)
+	}
+	return true, s.saveStandaloneEvents(security, "system")
+}
+
+// applySelfTradePrevention cancels or decrements an order that matching
+// stopped from trading with its own beneficial owner and announces it
+func (s *ExecutionService) applySelfTradePrevention(prevention *SelfTradePrevention) error {
+	order := prevention.Order
+	reason := fmt.Sprintf("self-trade prevention (%s)", prevention.Mode)
+
+	if order.BidID != nil {
+		bid, err := s.bids.FindByID(*order.BidID)
+		if err != nil {
+			return fmt.Errorf("failed to load bid: %w", err)
+		}
+		if !bid.IsActive() {
+			return nil
+		}
+		if prevention.Cancels() || prevention.Shares >= bid.SharesRemaining {
+			err = bid.Withdraw(reason, "system")
+		} else {
+			err = bid.ModifyBid(bid.SharesRequested-prevention.Shares, bid.BidPrice, "system", reason)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to reduce bid: %w", err)
+		}
+		if err := s.saveStandaloneEvents(bid, "system"); err != nil {
+			return err
+		}
+	} else {
+		l, err := s.listings.FindByID(order.ListingID)
+		if err != nil {
+			return fmt.Errorf("failed to load listing: %w", err)
+		}
+		if !l.IsActive() {
+			return nil
+		}
+		if prevention.Cancels() || prevention.Shares >= l.SharesRemaining {
+			err = l.Cancel(reason, "system")
+		} else {
+			err = l.ReduceOffer(prevention.Shares, "system", reason)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to reduce listing: %w", err)
+		}
+		if err := s.saveStandaloneEvents(l, "system"); err != nil {
+			return err
+		}
+	}
+
+	buyer, seller := order, prevention.Counterparty
+	if order.OrderType == "sell" {
+		buyer, seller = seller, order
+	}
+	event := NewSelfTradePrevented(order.SecurityID, order.OrderID(), prevention.Counterparty.OrderID(), buyer.UserID, seller.UserID, order.Owner(), prevention.Mode, prevention.Shares)
+	if err := s.eventBus.Publish(event); err != nil {
+		fmt.Printf("Failed to publish self-trade prevention for %s: %v\n", order.OrderID(), err)
+	}
+	return nil
+}
+
+// killOrder cancels a fill-or-kill order that could not be filled or the
+// remainder of an immediate-or-cancel order
+func (s *ExecutionService) killOrder(entry *OrderBookEntry) error {
+	reason := "immediate-or-cancel remainder cancelled"
+	if entry.Conditions.FillOrKill {
+		reason = "fill-or-kill order could not be filled"
+	}
+
+	if entry.BidID != nil {
+		bid, err := s.bids.FindByID(*entry.BidID)
+		if err != nil {
+			return fmt.Errorf("failed to load bid: %w", err)
+		}
+		if !bid.IsActive() {
+			return nil
+		}
+		if err := bid.Withdraw(reason, "system"); err != nil {
+			return fmt.Errorf("failed to withdraw bid: %w", err)
+		}
+		return s.saveStandaloneEvents(bid, "system")
+	}
+
+	l, err := s.listings.FindByID(entry.ListingID)
+	if err != nil {
+		return fmt.Errorf("failed to load listing: %w", err)
+	}
+	if !l.IsActive() {
+		return nil
+	}
+	if err := l.Cancel(reason, "system"); err != nil {
+		return fmt.Errorf("failed to cancel listing: %w", err)
+	}
+	return s.saveStandaloneEvents(l, "system")
+}
+
+// PublishIndicativeAuctionPrice publishes the price a call auction for the
+// security would currently clear at
+func (s *ExecutionService) PublishIndicativeAuctionPrice(securityID string) (*AuctionClearing, error) {
+	result, err := s.sequence(securityID, func(fenced *ExecutionService) (interface{}, error) {
+		return fenced.matchingEngine.IndicativeAuctionPrice(securityID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*AuctionClearing), nil
+}
+
+// ExpireOrders expires good-till-date listings and bids whose expiration has
+// passed and, once the market has closed, day orders placed before the
+// close. Day orders are only expired when a trading calendar or market data
+// provider gives the market hours. Each security's orders are expired on its
+// actor. It returns the number of orders expired.
+func (s *ExecutionService) ExpireOrders() (int, error) {
+	securityIDs, err := s.bookedSecurities()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var marketClose *time.Time
+	if _, closesAt, isOpen, known := s.matchingEngine.MarketHours(now); known && !isOpen && !now.Before(closesAt) {
+		marketClose = &closesAt
+	}
+
+	expired := 0
+	for _, securityID := range securityIDs {
+		result, err := s.sequence(securityID, func(fenced *ExecutionService) (interface{}, error) {
+			return fenced.expireSecurityOrders(securityID, now, marketClose), nil
+		})
+		if err != nil {
+			fmt.Printf("Failed to expire orders in %s: %v\n", securityID, err)
+			continue
+		}
+		expired += result.(int)
+	}
+
+	return expired, nil
+}
+
+// orderExpiry reports whether an order is due to expire: at its expiration,
+// or at the market close for day orders
+func orderExpiry(entry *OrderBookEntry, now time.Time, marketClose *time.Time) bool {
+	switch {
+	case entry.ExpiresAt != nil && !now.Before(*entry.ExpiresAt):
+		return true
+	case entry.Conditions.TimeInForce == orders.TimeInForceDAY && marketClose != nil:
+		return true
+	}
+	return false
+}
+
+// expireSecurityOrders expires the security's due orders on its actor. It
+// returns the number of orders expired.
+func (s *ExecutionService) expireSecurityOrders(securityID string, now time.Time, marketClose *time.Time) int {
+	if err := s.matchingEngine.OrderBooks().Sync(); err != nil {
+		fmt.Printf("Failed to sync order book of %s: %v\n", securityID, err)
+		return 0
+	}
+
+	expired := 0
+	for _, entry := range s.matchingEngine.OrderBooks().Orders() {
+		if entry.SecurityID != securityID || !orderExpiry(entry, now, marketClose) {
+			continue
+		}
+
+		var closedAt *time.Time
+		if entry.ExpiresAt == nil || now.Before(*entry.ExpiresAt) {
+			closedAt = marketClose
+		}
+
+		ok, err := s.expireOrder(entry, closedAt)
+		if err != nil {
+			fmt.Printf("Failed to expire order %s: %v\n", entry.OrderID(), err)
+			continue
+		}
+		if ok {
+			expired++
+		}
+	}
+	return expired
+}
+
+// expireOrder expires a listing or bid at its expiration, or at the market
+// close when one is given. Orders placed after that close are left alone.
+func (s *ExecutionService) expireOrder(entry *OrderBookEntry, marketClose *time.Time) (bool, error) {
+	if entry.BidID != nil {
+		bid, err := s.bids.FindByID(*entry.BidID)
+		if err != nil {
+			return false, fmt.Errorf("failed to load bid: %w", err)
+		}
+		if bid.Status != bidding.BidStatusActive && bid.Status != bidding.BidStatusPartiallyFilled {
+			return false, nil
+		}
+		if marketClose == nil {
+			err = bid.Expire()
+		} else if bid.PlacedAt.Before(*marketClose) {
+			err = bid.ExpireAtClose(*marketClose)
+		} else {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to expire bid: %w", err)
+		}
+		return true, s.saveStandaloneEvents(bid, "system")
+	}
+
+	l, err := s.listings.FindByID(entry.ListingID)
+	if err != nil {
+		return false, fmt.Errorf("failed to load listing: %w", err)
+	}
+	if l.Status != listing.ListingStatusActive {
+		return false, nil
+	}
+	if marketClose == nil {
+		err = l.Expire()
+	} else if l.CreatedAt.Before(*marketClose) {
+		err = l.ExpireAtClose(*marketClose)
+	} else {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to expire listing: %w", err)
+	}
+	return true, s.saveStandaloneEvents(l, "system")
+}
+
+// TriggerStopBids releases every pending stop bid on a security whose stop
+// price the last trade price has reached. Stop bids join the book as market
+// orders and stop-limit bids as limit orders, oldest first. It returns the
+// IDs of the bids that were triggered.
+func (s *ExecutionService) TriggerStopBids(securityID string, lastTradePrice money.Decimal) ([]string, error) {
+	result, err := s.sequence(securityID, func(fenced *ExecutionService) (interface{}, error) {
+		return fenced.triggerStopBids(securityID, lastTradePrice)
+	})
+	triggered, _ := result.([]string)
+	return triggered, err
+}
+
+// triggerStopBids releases the security's triggered stop bids on its actor
+func (s *ExecutionService) triggerStopBids(securityID string, lastTradePrice money.Decimal) ([]string, error) {
+	if err := s.matchingEngine.OrderBooks().Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync order books: %w", err)
+	}
+
+	var triggered []string
+	for _, bidID := range s.matchingEngine.OrderBooks().TriggeredStops(securityID, lastTradePrice) {
+		bid, err := s.bids.FindByID(bidID)
+		if err != nil {
+			return triggered, fmt.Errorf("failed to load bid %s: %w", bidID, err)
+		}
+		if !bid.ShouldTrigger(lastTradePrice) {
+			continue
+		}
+
+		if err := bid.Trigger(lastTradePrice); err != nil {
+			return triggered, fmt.Errorf("failed to trigger bid %s: %w", bidID, err)
+		}
+		if err := s.saveStandaloneEvents(bid, "system"); err != nil {
+			return triggered, fmt.Errorf("failed to save bid %s: %w", bidID, err)
+		}
+		triggered = append(triggered, bidID)
+	}
+
+	return triggered, nil
+}
+
+// GetStopSecurities returns the securities that have stop bids waiting to be
+// triggered
+func (s *ExecutionService) GetStopSecurities() ([]string, error) {
+	if err := s.matchingEngine.OrderBooks().Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync order books: %w", err)
+	}
+	return s.matchingEngine.OrderBooks().StopSecurities(), nil
+}
+
+// OpenNegotiation starts a negotiation with a buyer's offer against a
+// specific listing. Negotiations are answered on the actor of their security,
+// so an offer cannot cross an acceptance.
+func (s *ExecutionService) OpenNegotiation(listingID, buyerID string, shares int64, price money.Decimal, message string) (*negotiation.NegotiationAggregate, error) {
+	l, err := s.listings.FindByID(listingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find listing: %w", err)
+	}
+
+	result, err := s.sequence(l.SecurityID, func(fenced *ExecutionService) (interface{}, error) {
+		return fenced.openNegotiation(listingID, buyerID, shares, price, message)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*negotiation.NegotiationAggregate), nil
+}
+
+// openNegotiation opens a negotiation on the actor of the listing's security
+func (s *ExecutionService) openNegotiation(listingID, buyerID string, shares int64, price money.Decimal, message string) (*negotiation.NegotiationAggregate, error) {
+	l, err := s.listings.FindByID(listingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find listing: %w", err)
+	}
+	if !l.IsActive() {
+		return nil, fmt.Errorf("listing %s is not active", listingID)
+	}
+	if shares > l.SharesRemaining {
+		return nil, fmt.Errorf("listing %s only has %d shares remaining", listingID, l.SharesRemaining)
+	}
+
+	n := negotiation.NewNegotiationAggregate(fmt.Sprintf("negotiation_%s", uuid.New().String()))
+	err = n.Open(listingID, l.SecurityID, l.SellerID, buyerID, shares, price, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open negotiation: %w", err)
+	}
+
+	if err := s.saveStandaloneEvents(n, buyerID); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// CounterNegotiation answers the latest offer in a negotiation with a new one
+func (s *ExecutionService) CounterNegotiation(negotiationID, proposedBy string, shares int64, price money.Decimal, message string) (*negotiation.NegotiationAggregate, error) {
+	n, err := s.negotiations.FindByID(negotiationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find negotiation: %w", err)
+	}
+
+	result, err := s.sequence(n.SecurityID, func(fenced *ExecutionService) (interface{}, error) {
+		return fenced.counterNegotiation(negotiationID, proposedBy, shares, price, message)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*negotiation.NegotiationAggregate), nil
+}
+
+// counterNegotiation counters on the actor of the negotiation's security
+func (s *ExecutionService) counterNegotiation(negotiationID, proposedBy string, shares int64, price money.Decimal, message string) (*negotiation.NegotiationAggregate, error) {
+	n, err := s.negotiations.FindByID(negotiationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find negotiation: %w", err)
+	}
+
+	err = n.Counter(proposedBy, shares, price, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to counter offer: %w", err)
+	}
+
+	if err := s.saveStandaloneEvents(n, proposedBy); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// AcceptNegotiation accepts the latest offer in a negotiation and books the
+// agreed trade. The acceptance and the trade are saved together.
+func (s *ExecutionService) AcceptNegotiation(negotiationID, acceptedBy string) (*TradeAggregate, error) {
+	n, err := s.negotiations.FindByID(negotiationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find negotiation: %w", err)
+	}
+
+	result, err := s.sequence(n.SecurityID, func(fenced *ExecutionService) (interface{}, error) {
+		return fenced.acceptNegotiation(negotiationID, acceptedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*TradeAggregate), nil
+}
+
+// acceptNegotiation accepts a negotiation on the actor of its security. The
+// negotiation is reloaded there so a concurrent answer is not missed.
+func (s *ExecutionService) acceptNegotiation(negotiationID, acceptedBy string) (*TradeAggregate, error) {
+	n, err := s.negotiations.FindByID(negotiationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find negotiation: %w", err)
+	}
+
+	offer := n.LatestOffer()
+	if offer == nil {
+		return nil, fmt.Errorf("negotiation %s has no offer to accept", negotiationID)
+	}
+
+	match, err := s.matchingEngine.NegotiatedMatch(n.ListingID, n.BuyerID, offer.Shares, offer.Price)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match negotiated trade: %w", err)
+	}
+
+	err = n.Accept(acceptedBy, match.TradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept offer: %w", err)
+	}
+
+	return s.ExecuteTradeMatch(match, n)
+}
+
+// RejectNegotiation ends a negotiation without a trade
+func (s *ExecutionService) RejectNegotiation(negotiationID, rejectedBy, reason string) error {
+	n, err := s.negotiations.FindByID(negotiationID)
+	if err != nil {
+		return fmt.Errorf("failed to find negotiation: %w", err)
+	}
+
+	_, err = s.sequence(n.SecurityID, func(fenced *ExecutionService) (interface{}, error) {
+		return nil, fenced.rejectNegotiation(negotiationID, rejectedBy, reason)
+	})
+	return err
+}
+
+// rejectNegotiation rejects on the actor of the negotiation's security
+func (s *ExecutionService) rejectNegotiation(negotiationID, rejectedBy, reason string) error {
+	n, err := s.negotiations.FindByID(negotiationID)
+	if err != nil {
+		return fmt.Errorf("failed to find negotiation: %w", err)
+	}
+
+	err = n.Reject(rejectedBy, reason)
+	if err != nil {
+		return fmt.Errorf("failed to reject negotiation: %w", err)
+	}
+
+	return s.saveStandaloneEvents(n, rejectedBy)
+}
+
+// GetNegotiation returns a negotiation thread to one of its parties
+func (s *ExecutionService) GetNegotiation(negotiationID, userID string) (*negotiation.NegotiationAggregate, error) {
+	n, err := s.negotiations.FindByID(negotiationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find negotiation: %w", err)
+	}
+	if !n.IsParticipant(userID) {
+		return nil, fmt.Errorf("negotiation %s is only visible to its buyer and seller", negotiationID)
+	}
+	return n, nil
+}
+
+// GetNegotiationsByUser returns every negotiation the user is a party to
+func (s *ExecutionService) GetNegotiationsByUser(userID string) ([]*negotiation.NegotiationAggregate, error) {
+	return s.negotiations.FindByParticipant(userID)
+}
+
+// RequestQuotes sends a request for firm quotes to selected holders of a
+// security. Every recipient must currently hold shares.
+func (s *ExecutionService) RequestQuotes(securityID, requesterID string, shares int64, recipients []string, ttl time.Duration, message string) (*rfq.RFQAggregate, error) {
+	security, err := s.securities.FindByID(securityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find security: %w", err)
+	}
+	if !security.IsTradable() {
+		return nil, fmt.Errorf("security %s is not tradable (status: %s)", securityID, security.Status)
+	}
+	for _, recipient := range recipients {
+		if holding, ok := security.Ownership[recipient]; !ok || holding.SharesOwned <= 0 {
+			return nil, fmt.Errorf("recipient %s does not hold security %s", recipient, securityID)
+		}
+	}
+
+	request := rfq.NewRFQAggregate(fmt.Sprintf("rfq_%s", uuid.New().String()))
+	err = request.Request(securityID, requesterID, shares, recipients, message, time.Now().Add(ttl))
+	if err != nil {
+		return nil, fmt.Errorf("failed to request quotes: %w", err)
+	}
+
+	if err := s.saveStandaloneEvents(request, requesterID); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// SubmitQuote records a recipient's firm quote. The holder must own the
+// quoted shares. Quotes are submitted on the actor of the request's security,
+// so a quote cannot cross an acceptance.
+func (s *ExecutionService) SubmitQuote(rfqID, holderID string, shares int64, price money.Decimal) (*rfq.RFQAggregate, error) {
+	request, err := s.quoteRequests.FindByID(rfqID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request for quote: %w", err)
+	}
+
+	result, err := s.sequence(request.SecurityID, func(fenced *ExecutionService) (interface{}, error) {
+		return fenced.submitQuote(rfqID, holderID, shares, price)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*rfq.RFQAggregate), nil
+}
+
+// submitQuote submits a quote on the actor of the request's security
+func (s *ExecutionService) submitQuote(rfqID, holderID string, shares int64, price money.Decimal) (*rfq.RFQAggregate, error) {
+	request, err := s.quoteRequests.FindByID(rfqID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request for quote: %w", err)
+	}
+
+	if err := s.checkHolding(request.SecurityID, holderID, shares); err != nil {
+		return nil, err
+	}
+
+	err = request.SubmitQuote(fmt.Sprintf("quote_%s", uuid.New().String()), holderID, shares, price)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit quote: %w", err)
+	}
+
+	if err := s.saveStandaloneEvents(request, holderID); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// AcceptQuote accepts a quote on behalf of the requester and books the
+// trade. The acceptance and the trade are saved together.
+func (s *ExecutionService) AcceptQuote(rfqID, quoteID, acceptedBy string) (*TradeAggregate, error) {
+	request, err := s.quoteRequests.FindByID(rfqID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request for quote: %w", err)
+	}
+
+	result, err := s.sequence(request.SecurityID, func(fenced *ExecutionService) (interface{}, error) {
+		return fenced.acceptQuote(rfqID, quoteID, acceptedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*TradeAggregate), nil
+}
+
+// acceptQuote accepts a quote on the actor of its security. The request is
+// reloaded there so a concurrent acceptance is not missed.
+func (s *ExecutionService) acceptQuote(rfqID, quoteID, acceptedBy string) (*TradeAggregate, error) {
+	request, err := s.quoteRequests.FindByID(rfqID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request for quote: %w", err)
+	}
+
+	quote := request.GetQuote(quoteID)
+	if quote == nil {
+		return nil, fmt.Errorf("quote %s not found", quoteID)
+	}
+
+	// The holder may have sold since quoting
+	if err := s.checkHolding(request.SecurityID, quote.HolderID, quote.Shares); err != nil {
+		return nil, err
+	}
+
+	match, err := s.matchingEngine.QuoteMatch(request.SecurityID, request.RequesterID, quote.HolderID, quote.Shares, quote.Price)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match quote: %w", err)
+	}
+
+	err = request.AcceptQuote(quoteID, acceptedBy, match.TradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept quote: %w", err)
+	}
+
+	return s.ExecuteTradeMatch(match, request)
+}
+
+// GetQuoteRequest returns a request for quote as the user is allowed to see it
+func (s *ExecutionService) GetQuoteRequest(rfqID, userID string) (*rfq.RFQAggregate, error) {
+	request, err := s.quoteRequests.FindByID(rfqID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request for quote: %w", err)
+	}
+	if !request.IsVisibleTo(userID) {
+		return nil, fmt.Errorf("request for quote %s is only visible to its requester and recipients", rfqID)
+	}
+	return request.ViewFor(userID), nil
+}
+
+// GetQuoteRequestsByUser returns every request the user sent or received, as
+// they are allowed to see them
+func (s *ExecutionService) GetQuoteRequestsByUser(userID string) ([]*rfq.RFQAggregate, error) {
+	requests, err := s.quoteRequests.FindByParticipant(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]*rfq.RFQAggregate, len(requests))
+	for i, request := range requests {
+		views[i] = request.ViewFor(userID)
+	}
+	return views, nil
+}
+
+// ExpireQuoteRequests expires every open request whose time-to-live has
+// passed, each on the actor of its security
+func (s *ExecutionService) ExpireQuoteRequests() error {
+	requests, err := s.quoteRequests.FindOpen()
+	if err != nil {
+		return fmt.Errorf("failed to find open requests for quote: %w", err)
+	}
+
+	for _, request := range requests {
+		if !request.IsExpired() {
+			continue
+		}
+		rfqID := request.ID
+		_, err := s.sequence(request.SecurityID, func(fenced *ExecutionService) (interface{}, error) {
+			return nil, fenced.expireQuoteRequest(rfqID)
+		})
+		if err != nil {
+			fmt.Printf("Failed to expire request for quote %s: %v\n", rfqID, err)
+		}
+	}
+
+	return nil
+}
+
+// expireQuoteRequest expires a request on the actor of its security. The
+// request is reloaded there so a quote accepted meanwhile is not expired.
+func (s *ExecutionService) expireQuoteRequest(rfqID string) error {
+	request, err := s.quoteRequests.FindByID(rfqID)
+	if err != nil {
+		return fmt.Errorf("failed to find request for quote: %w", err)
+	}
+	if !request.IsOpen() || !request.IsExpired() {
+		return nil
+	}
+
+	if err := request.Expire(); err != nil {
+		return fmt.Errorf("failed to expire request for quote: %w", err)
+	}
+	return s.saveStandaloneEvents(request, "system")
+}
+
+// checkHolding verifies that a holder owns at least the given shares
+func (s *ExecutionService) checkHolding(securityID, holderID string, shares int64) error {
+	security, err := s.securities.FindByID(securityID)
+	if err != nil {
+		return fmt.Errorf("failed to find security: %w", err)
+	}
+	if holding, ok := security.Ownership[holderID]; !ok || holding.SharesOwned < shares {
+		return fmt.Errorf("holder %s does not own %d shares of %s", holderID, shares, securityID)
+	}
+	return nil
+}
+
+// RebuildOrderBooks replays the live order books from the event store. It is
+// called on startup and can be used to recover from a corrupted book.
+func (s *ExecutionService) RebuildOrderBooks() error {
+	return s.matchingEngine.RebuildOrderBooks()
+}
+
+// GetOrderBook returns the live order book for a security
+func (s *ExecutionService) GetOrderBook(securityID string) (*LimitOrderBook, error) {
+	if err := s.matchingEngine.OrderBooks().Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync order books: %w", err)
+	}
+	return s.matchingEngine.OrderBooks().Book(securityID), nil
 }
 
 // GetTrade retrieves a trade by ID
@@ -222,7 +1218,7 @@ func (s *ExecutionService) GetOverdueTrades() ([]*TradeAggregate, error) {
 
 	var overdueTrades []*TradeAggregate
 	for _, trade := range trades {
-		if trade.IsOverdue() {
+		if trade.IsOverdue(s.matchingEngine.tradingCalendar()) {
 			overdueTrades = append(overdueTrades, trade)
 		}
 	}
@@ -306,13 +1302,15 @@ func (s *ExecutionService) GetMarketStatistics(securityID string, period time.Du
 	}
 
 	var totalVolume int64
-	var totalValue float64
-	var prices []float64
+	var totalValue money.Decimal
+	var prices []money.Decimal
 
 	for _, trade := range trades {
 		if trade.IsSettled() {
 			totalVolume += trade.SharesTraded
-			totalValue += trade.TotalAmount
+			if totalValue, err = totalValue.Add(trade.TotalAmount.Amount); err != nil {
+				return nil, fmt.Errorf("failed to calculate total value: %w", err)
+			}
 			prices = append(prices, trade.TradePrice)
 		}
 	}
@@ -324,23 +1322,31 @@ func (s *ExecutionService) GetMarketStatistics(securityID string, period time.Du
 		// Calculate price statistics
 		stats.HighPrice = prices[0]
 		stats.LowPrice = prices[0]
-		var priceSum float64
+		var priceSum money.Decimal
 
 		for _, price := range prices {
-			if price > stats.HighPrice {
+			if price.GreaterThan(stats.HighPrice) {
 				stats.HighPrice = price
 			}
-			if price < stats.LowPrice {
+			if price.LessThan(stats.LowPrice) {
 				stats.LowPrice = price
 			}
-			priceSum += price
+			if priceSum, err = priceSum.Add(price); err != nil {
+				return nil, fmt.Errorf("failed to calculate average price: %w", err)
+			}
 		}
 
-		stats.AveragePrice = priceSum / float64(len(prices))
+		stats.AveragePrice, err = priceSum.DivInt(int64(len(prices)), money.RoundHalfEven)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate average price: %w", err)
+		}
 		stats.LastPrice = prices[len(prices)-1]
 
 		if stats.TotalVolume > 0 {
-			stats.VWAP = totalValue / float64(totalVolume)
+			stats.VWAP, err = totalValue.DivInt(totalVolume, money.RoundHalfEven)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate VWAP: %w", err)
+			}
 		}
 	}
 
@@ -353,22 +1359,31 @@ type MarketStatistics struct {
 	Period       time.Duration `json:"period"`
 	TradeCount   int           `json:"tradeCount"`
 	TotalVolume  int64         `json:"totalVolume"`
-	TotalValue   float64       `json:"totalValue"`
-	HighPrice    float64       `json:"highPrice"`
-	LowPrice     float64       `json:"lowPrice"`
-	LastPrice    float64       `json:"lastPrice"`
-	AveragePrice float64       `json:"averagePrice"`
-	VWAP         float64       `json:"vwap"` // Volume Weighted Average Price
+	TotalValue   money.Decimal `json:"totalValue"`
+	HighPrice    money.Decimal `json:"highPrice"`
+	LowPrice     money.Decimal `json:"lowPrice"`
+	LastPrice    money.Decimal `json:"lastPrice"`
+	AveragePrice money.Decimal `json:"averagePrice"`
+	VWAP         money.Decimal `json:"vwap"` // Volume Weighted Average Price
 }
 
 // saveAggregateEvents saves uncommitted events from an aggregate
-func (s *ExecutionService) saveAggregateEvents(trade *TradeAggregate, userID string) error {
+func (s *ExecutionService) saveAggregateEvents(trade *TradeAggregate, userID string, related ...events.Aggregate) error {
 	uncommittedEvents := trade.GetUncommittedEvents()
 	if len(uncommittedEvents) == 0 {
 		return nil
 	}
 
+	// Capture the stored state before this command for the audit diff
+	var before *TradeAggregate
+	if s.auditLog != nil {
+		if previous, err := s.repository.FindByID(trade.ID); err == nil {
+			before = previous
+		}
+	}
+
 	// Convert domain events to event store events
+	var relatedEvents []events.DomainEvent
 	var events []*events.Event
 	correlationID := uuid.New().String()
 
@@ -388,14 +1403,41 @@ func (s *ExecutionService) saveAggregateEvents(trade *TradeAggregate, userID str
 		events = append(events, event)
 	}
 
-	// Save events
-	err := s.eventStore.SaveEvents(events)
+	// Events of aggregates changed by the same command are saved in the same
+	// batch so they commit or fail together
+	for _, aggregate := range related {
+		for i, domainEvent := range aggregate.GetUncommittedEvents() {
+			prevEventID := events[len(events)-1].EventID
+			event, err := s.eventStore.CreateEventFromDomain(domainEvent, userID, correlationID, &prevEventID)
+			if err != nil {
+				return fmt.Errorf("failed to create event: %w", err)
+			}
+
+			event.AggregateVersion = aggregate.GetVersion() + i + 1
+			events = append(events, event)
+			relatedEvents = append(relatedEvents, domainEvent)
+		}
+	}
+
+	// Save events, unless a sequenced command lost its lease meanwhile
+	err := s.checkFence()
+	if err == nil {
+		err = s.eventStore.SaveEvents(events)
+	}
 	if err != nil {
+		s.recordAudit(trade, before, trade, uncommittedEvents, userID, correlationID, err)
 		return fmt.Errorf("failed to save events: %w", err)
 	}
 
+	// Remember where this command landed in the global stream so callers can
+	// return it as a consistency token
+	trade.SetLastEventNumber(events[len(events)-1].EventNumber)
+	for _, aggregate := range related {
+		setLastEventNumber(aggregate, events[len(events)-1].EventNumber)
+	}
+
 	// Publish events to event bus
-	for _, domainEvent := range uncommittedEvents {
+	for _, domainEvent := range append(uncommittedEvents, relatedEvents...) {
 		err = s.eventBus.Publish(domainEvent)
 		if err != nil {
 			// Log error but don't fail the operation
@@ -403,12 +1445,100 @@ func (s *ExecutionService) saveAggregateEvents(trade *TradeAggregate, userID str
 		}
 	}
 
+	s.recordAudit(trade, before, trade, uncommittedEvents, userID, correlationID, nil)
+
 	// Mark events as committed
 	trade.MarkEventsAsCommitted()
+	for _, aggregate := range related {
+		aggregate.MarkEventsAsCommitted()
+	}
+
+	return nil
+}
+
+// saveStandaloneEvents saves uncommitted events from an aggregate changed by
+// a command that does not create a trade
+func (s *ExecutionService) saveStandaloneEvents(aggregate events.Aggregate, userID string) error {
+	uncommittedEvents := aggregate.GetUncommittedEvents()
+	if len(uncommittedEvents) == 0 {
+		return nil
+	}
+
+	var events []*events.Event
+	correlationID := uuid.New().String()
+
+	for i, domainEvent := range uncommittedEvents {
+		var causationID *string
+		if i > 0 {
+			prevEventID := events[i-1].EventID
+			causationID = &prevEventID
+		}
+
+		event, err := s.eventStore.CreateEventFromDomain(domainEvent, userID, correlationID, causationID)
+		if err != nil {
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+
+		event.AggregateVersion = aggregate.GetVersion() + i + 1
+		events = append(events, event)
+	}
+
+	// These aggregates have no common loader for the stored state, so their
+	// audit entries list the events without a state diff
+	err := s.checkFence()
+	if err == nil {
+		err = s.eventStore.SaveEvents(events)
+	}
+	if err != nil {
+		s.recordAudit(aggregate, nil, nil, uncommittedEvents, userID, correlationID, err)
+		return fmt.Errorf("failed to save events: %w", err)
+	}
+	setLastEventNumber(aggregate, events[len(events)-1].EventNumber)
+
+	for _, domainEvent := range uncommittedEvents {
+		if err := s.eventBus.Publish(domainEvent); err != nil {
+			// Log error but don't fail the operation
+			fmt.Printf("Failed to publish event %s: %v\n", domainEvent.GetEventType(), err)
+		}
+	}
+
+	s.recordAudit(aggregate, nil, nil, uncommittedEvents, userID, correlationID, nil)
 
+	aggregate.MarkEventsAsCommitted()
 	return nil
 }
 
+// setLastEventNumber records where an aggregate's events landed in the global
+// stream, for aggregates that track it
+func setLastEventNumber(aggregate events.Aggregate, eventNumber int64) {
+	if positioned, ok := aggregate.(interface{ SetLastEventNumber(int64) }); ok {
+		positioned.SetLastEventNumber(eventNumber)
+	}
+}
+
+// recordAudit writes an audit entry for a command's events and state change
+func (s *ExecutionService) recordAudit(aggregate events.Aggregate, before, after interface{}, uncommittedEvents []events.DomainEvent, userID, correlationID string, cmdErr error) {
+	if s.auditLog == nil {
+		return
+	}
+
+	err := audit.RecordCommand(s.auditLog, audit.CommandRecord{
+		AggregateID:   aggregate.GetID(),
+		AggregateType: aggregate.GetType(),
+		UserID:        userID,
+		Actor:         s.actor,
+		CorrelationID: correlationID,
+		Before:        before,
+		After:         after,
+		Events:        uncommittedEvents,
+		Err:           cmdErr,
+	})
+	if err != nil {
+		// Log error but don't fail the operation
+		fmt.Printf("Failed to record audit entry for %s %s: %v\n", aggregate.GetType(), aggregate.GetID(), err)
+	}
+}
+
 // generateEscrowAccountID generates a unique escrow account ID
 func (s *ExecutionService) generateEscrowAccountID() string {
 	return fmt.Sprintf("escrow_%s", uuid.New().String())
@@ -0,0 +1,142 @@
+package execution
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"securities-marketplace/domains/shared/audit"
+	"securities-marketplace/domains/shared/auth"
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/trading/session"
+)
+
+// SessionHandler exposes the trading session. Anyone signed in can see the
+// state of the market and of a security; only administrators move them.
+type SessionHandler struct {
+	service *ExecutionService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(service *ExecutionService) *SessionHandler {
+	return &SessionHandler{service: service}
+}
+
+// SessionChangeRequest is the body of a session state change
+type SessionChangeRequest struct {
+	State  string `json:"state"`
+	Reason string `json:"reason"`
+}
+
+// RegisterRoutes registers the read-only routes. Callers are expected to
+// mount the router behind authentication.
+func (h *SessionHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/session", h.HandleGetMarket).Methods("GET")
+	router.HandleFunc("/session/securities/{id}", h.HandleGetSecurity).Methods("GET")
+}
+
+// RegisterAdminRoutes registers the routes that change session state.
+// Callers are expected to mount the router behind admin authorization.
+func (h *SessionHandler) RegisterAdminRoutes(router *mux.Router) {
+	router.HandleFunc("/session", h.HandleChangeMarket).Methods("PUT")
+	router.HandleFunc("/securities/{id}/session", h.HandleChangeSecurity).Methods("PUT")
+}
+
+// HandleGetMarket returns the market session
+func (h *SessionHandler) HandleGetMarket(w http.ResponseWriter, r *http.Request) {
+	market, err := h.service.GetMarketSession()
+	if err != nil {
+		http.Error(w, "Failed to load session", http.StatusInternalServerError)
+		return
+	}
+
+	writeSession(w, map[string]interface{}{
+		"state":     market.State,
+		"managed":   market.IsManaged(),
+		"reason":    market.Reason,
+		"changedAt": market.ChangedAt,
+	})
+}
+
+// HandleGetSecurity returns the state a security trades in
+func (h *SessionHandler) HandleGetSecurity(w http.ResponseWriter, r *http.Request) {
+	securityID := mux.Vars(r)["id"]
+	state, managed, err := h.service.GetSessionState(securityID)
+	if err != nil {
+		http.Error(w, "Failed to load session", http.StatusInternalServerError)
+		return
+	}
+
+	writeSession(w, map[string]interface{}{
+		"securityId": securityID,
+		"state":      state,
+		"managed":    managed,
+	})
+}
+
+// HandleChangeMarket moves the market session to a new state
+func (h *SessionHandler) HandleChangeMarket(w http.ResponseWriter, r *http.Request) {
+	state, req, ok := decodeSessionChange(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.WithActor(audit.ActorFromContext(r.Context())).ChangeMarketSession(state, req.Reason, sessionAdminID(r)); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if market, err := h.service.GetMarketSession(); err == nil {
+		events.SetConsistencyToken(w, market.GetLastEventNumber())
+	}
+	h.HandleGetMarket(w, r)
+}
+
+// HandleChangeSecurity moves a security's own session to a new state
+func (h *SessionHandler) HandleChangeSecurity(w http.ResponseWriter, r *http.Request) {
+	state, req, ok := decodeSessionChange(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.WithActor(audit.ActorFromContext(r.Context())).ChangeSecuritySession(mux.Vars(r)["id"], state, req.Reason, sessionAdminID(r)); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if security, err := h.service.GetSecuritySession(mux.Vars(r)["id"]); err == nil {
+		events.SetConsistencyToken(w, security.GetLastEventNumber())
+	}
+	h.HandleGetSecurity(w, r)
+}
+
+func decodeSessionChange(w http.ResponseWriter, r *http.Request) (session.State, *SessionChangeRequest, bool) {
+	var req SessionChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return "", nil, false
+	}
+
+	state, err := session.ParseState(req.State)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", nil, false
+	}
+	return state, &req, true
+}
+
+func sessionAdminID(r *http.Request) string {
+	if user := auth.GetUserFromContext(r.Context()); user != nil {
+		return user.UserID
+	}
+	return "system"
+}
+
+func writeSession(w http.ResponseWriter, details map[string]interface{}) {
+	response := map[string]interface{}{
+		"success": true,
+		"session": details,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,149 @@
+package execution
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"securities-marketplace/domains/trading/session"
+)
+
+// SessionGate returns the gate that checks order entry and matching against
+// the trading session, for the listing and bid services to share
+func (s *ExecutionService) SessionGate() *session.Gate {
+	return s.matchingEngine.sessions
+}
+
+// GetMarketSession returns the market session
+func (s *ExecutionService) GetMarketSession() (*session.SessionAggregate, error) {
+	return s.sessions.FindMarket()
+}
+
+// GetSecuritySession returns a security's own session
+func (s *ExecutionService) GetSecuritySession(securityID string) (*session.SessionAggregate, error) {
+	return s.sessions.FindSecurity(securityID)
+}
+
+// GetSessionState returns the state a security trades in. managed is false
+// while sessions are not enforced for it.
+func (s *ExecutionService) GetSessionState(securityID string) (state session.State, managed bool, err error) {
+	return s.matchingEngine.sessions.State(securityID)
+}
+
+// ChangeMarketSession moves the market to a new phase of the trading day.
+// Leaving the opening or closing auction uncrosses every security with
+// orders in the book at a uniform price first.
+func (s *ExecutionService) ChangeMarketSession(to session.State, reason, changedBy string) error {
+	market, err := s.sessions.FindMarket()
+	if err != nil {
+		return fmt.Errorf("failed to load market session: %w", err)
+	}
+	if !market.State.CanTransitionTo(to) {
+		return fmt.Errorf("market session cannot move from %s to %s", market.State, to)
+	}
+
+	if market.State.IsAuction() && to != session.StateHalted {
+		securityIDs, err := s.bookedSecurities()
+		if err != nil {
+			return err
+		}
+		s.uncross(securityIDs)
+	}
+
+	if err := market.Transition(to, reason, changedBy); err != nil {
+		return fmt.Errorf("failed to change market session: %w", err)
+	}
+	return s.saveStandaloneEvents(market, changedBy)
+}
+
+// ChangeSecuritySession moves one security to a new state of its own, such
+// as halting it, or back to open to follow the market again. Leaving the
+// security's own opening auction uncrosses it first.
+func (s *ExecutionService) ChangeSecuritySession(securityID string, to session.State, reason, changedBy string) error {
+	security, err := s.sessions.FindSecurity(securityID)
+	if err != nil {
+		return fmt.Errorf("failed to load session of %s: %w", securityID, err)
+	}
+	if !security.State.CanTransitionTo(to) {
+		return fmt.Errorf("session of %s cannot move from %s to %s", securityID, security.State, to)
+	}
+
+	if security.State.IsAuction() && to != session.StateHalted {
+		s.uncross([]string{securityID})
+	}
+
+	if err := security.Transition(to, reason, changedBy); err != nil {
+		return fmt.Errorf("failed to change session of %s: %w", securityID, err)
+	}
+	return s.saveStandaloneEvents(security, changedBy)
+}
+
+// SyncMarketSession moves the market session to the phase the schedule puts
+// it in at now, on the trading calendar, passing through each phase on the
+// way. It returns the states the market moved through.
+func (s *ExecutionService) SyncMarketSession(schedule session.Schedule, now time.Time) ([]session.State, error) {
+	market, err := s.sessions.FindMarket()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load market session: %w", err)
+	}
+
+	target := schedule.StateAt(s.matchingEngine.tradingCalendar(), now)
+
+	var changed []session.State
+	for _, next := range session.Path(market.State, target) {
+		if err := s.ChangeMarketSession(next, "scheduled", "system"); err != nil {
+			return changed, err
+		}
+		changed = append(changed, next)
+	}
+	return changed, nil
+}
+
+// uncross runs the call auction that ends an auction phase on each security.
+// A security that fails to uncross is logged and left for the next phase.
+func (s *ExecutionService) uncross(securityIDs []string) {
+	for _, securityID := range securityIDs {
+		_, err := s.sequence(securityID, func(fenced *ExecutionService) (interface{}, error) {
+			return nil, fenced.uncrossSecurity(securityID)
+		})
+		if err != nil {
+			fmt.Printf("Failed to uncross %s: %v\n", securityID, err)
+		}
+	}
+}
+
+// uncrossSecurity runs the call auction of one security on its actor
+func (s *ExecutionService) uncrossSecurity(securityID string) error {
+	round, err := s.matchingEngine.UncrossAuction(securityID)
+	if err != nil {
+		return err
+	}
+
+	s.executeRound(round)
+
+	if round.Halt != nil {
+		if err := s.haltTrading(round.Halt); err != nil {
+			fmt.Printf("Failed to halt trading in %s: %v\n", securityID, err)
+		}
+	}
+	return nil
+}
+
+// bookedSecurities returns the securities with orders in the book
+func (s *ExecutionService) bookedSecurities() ([]string, error) {
+	books := s.matchingEngine.OrderBooks()
+	if err := books.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync order books: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var securityIDs []string
+	for _, entry := range books.Orders() {
+		if !seen[entry.SecurityID] {
+			seen[entry.SecurityID] = true
+			securityIDs = append(securityIDs, entry.SecurityID)
+		}
+	}
+	sort.Strings(securityIDs)
+	return securityIDs, nil
+}
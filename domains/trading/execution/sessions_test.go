@@ -0,0 +1,83 @@
+package execution
+
+import (
+	"testing"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/testutil"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/listing"
+	"securities-marketplace/domains/trading/session"
+)
+
+func TestExecutionService_SessionGatesOrderEntryAndMatching(t *testing.T) {
+	// Arrange
+	setup := testutil.NewTestSetup()
+	service := NewExecutionService(NewEventSourcedTradeRepository(setup.EventStore), setup.EventStore, setup.EventBus)
+	service.SetInvestorVerifier(testInvestorVerifier{})
+	service.SetMarketDataProvider(testutil.NewStubMarketDataProvider())
+	testutil.AssertNoError(t, saveTestSecurity(setup.EventStore, "TEST-001"), "Security should be saved")
+
+	listingRepository := listing.NewEventSourcedListingRepository(setup.EventStore)
+	listings := listing.NewListingService(listingRepository, setup.EventStore, setup.EventBus)
+	listings.SetEntryGate(service.SessionGate())
+	bids := bidding.NewBidService(bidding.NewEventSourcedBidRepository(setup.EventStore), listingRepository, setup.EventStore, setup.EventBus)
+	bids.SetEntryGate(service.SessionGate())
+
+	testutil.AssertNoError(t, service.ChangeMarketSession(session.StatePreOpen, "", "admin"), "Market should move to pre-open")
+
+	// Act: orders collect in pre-open and the opening auction
+	offer, err := listings.CreateListing(&listing.CreateListingCommand{
+		SecurityID:    "TEST-001",
+		SellerID:      "seller-1",
+		SharesOffered: 100,
+		ListingType:   listing.ListingTypeFixed,
+		CurrentPrice:  decimalPtr("10.00"),
+	})
+	testutil.AssertNoError(t, err, "Listings should be entered in pre-open")
+	_, err = bids.PlaceBid(&bidding.PlaceBidCommand{
+		ListingID:       offer.ID,
+		BidderID:        "buyer-1",
+		SharesRequested: 40,
+		BidPrice:        decimalPtr("10.00"),
+		BidType:         bidding.BidTypeLimit,
+	})
+	testutil.AssertNoError(t, err, "Bids should be entered in pre-open")
+
+	_, err = service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertError(t, err, "Nothing should match in pre-open")
+
+	testutil.AssertNoError(t, service.ChangeMarketSession(session.StateOpeningAuction, "", "admin"), "Market should move to the opening auction")
+	_, err = service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertError(t, err, "Continuous matching should wait for the auction to uncross")
+
+	testutil.AssertNoError(t, service.ChangeMarketSession(session.StateOpen, "", "admin"), "Market should open")
+
+	// Assert: opening the market uncrossed the book
+	matched := setup.EventBus.GetEventsByType("TradeMatched")
+	testutil.AssertLengthEqual(t, 1, matched, "Opening auction should uncross the book")
+	testutil.AssertEqual(t, string(UniformPriceAuction), matched[0].(*TradeMatched).MatchingAlgorithm, "Opening trade should be an auction trade")
+
+	testutil.AssertNoError(t, service.ChangeSecuritySession("TEST-001", session.StateHalted, "pending news", "admin"), "Security should halt")
+	_, err = service.RunMatching("TEST-001", PriceTimePriority)
+	testutil.AssertError(t, err, "Halted security should not match")
+	state, managed, _ := service.GetSessionState("TEST-001")
+	testutil.AssertTrue(t, managed, "Session should be managed")
+	testutil.AssertEqual(t, session.StateHalted, state, "Security halt should override the open market")
+
+	testutil.AssertNoError(t, service.ChangeMarketSession(session.StateClosed, "", "admin"), "Market should close")
+	_, err = listings.CreateListing(&listing.CreateListingCommand{
+		SecurityID:    "TEST-001",
+		SellerID:      "seller-2",
+		SharesOffered: 10,
+		ListingType:   listing.ListingTypeFixed,
+		CurrentPrice:  decimalPtr("10.00"),
+	})
+	testutil.AssertError(t, err, "Closed market should reject new listings")
+	err = listings.UpdatePrice(&listing.UpdateListingPriceCommand{ListingID: offer.ID, NewPrice: money.NewDecimalFromInt(11), UpdatedBy: "seller-1"})
+	testutil.AssertError(t, err, "Closed market should reject modifications")
+	err = listings.CancelListing(&listing.CancelListingCommand{ListingID: offer.ID, Reason: "market closed", CancelledBy: "seller-1"})
+	testutil.AssertNoError(t, err, "Listings should be cancellable while closed")
+
+	testutil.AssertLengthEqual(t, 5, setup.EventBus.GetEventsByType("SessionStateChanged"), "Every transition should be announced")
+}
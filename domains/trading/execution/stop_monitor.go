@@ -0,0 +1,129 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+)
+
+// StopMarket is the part of the execution service the stop order monitor
+// drives
+type StopMarket interface {
+	TriggerStopBids(securityID string, lastTradePrice money.Decimal) ([]string, error)
+	GetStopSecurities() ([]string, error)
+}
+
+// StopOrderMonitor watches last trade prices and releases stop and
+// stop-limit bids to the order book when their stop price is reached. Prices
+// come from TradeMatched events and, when a market data provider is set,
+// from polling it for every security with pending stops.
+type StopOrderMonitor struct {
+	market       StopMarket
+	marketData   MarketDataProvider
+	pollInterval time.Duration
+
+	mu sync.Mutex // Event bus handlers run concurrently; triggering must not
+}
+
+// NewStopOrderMonitor creates a new stop order monitor
+func NewStopOrderMonitor(market StopMarket) *StopOrderMonitor {
+	return &StopOrderMonitor{
+		market:       market,
+		pollInterval: 10 * time.Second,
+	}
+}
+
+// SetMarketDataProvider sets the source of last trade prices that Run polls
+func (m *StopOrderMonitor) SetMarketDataProvider(provider MarketDataProvider) {
+	m.marketData = provider
+}
+
+// Subscribe registers the monitor for TradeMatched events
+func (m *StopOrderMonitor) Subscribe(bus events.EventBus) error {
+	if err := bus.Subscribe("TradeMatched", m.HandleTradeMatched); err != nil {
+		return fmt.Errorf("failed to subscribe to TradeMatched: %w", err)
+	}
+	return nil
+}
+
+// HandleTradeMatched triggers stop bids on the traded security at the trade
+// price
+func (m *StopOrderMonitor) HandleTradeMatched(event events.DomainEvent) error {
+	matched, ok := event.(*TradeMatched)
+	if !ok {
+		data, err := event.GetEventData()
+		if err != nil {
+			return fmt.Errorf("failed to get event data: %w", err)
+		}
+		matched = &TradeMatched{}
+		if err := json.Unmarshal(data, matched); err != nil {
+			return fmt.Errorf("failed to deserialize TradeMatched: %w", err)
+		}
+	}
+
+	_, err := m.OnTradePrice(matched.SecurityID, matched.TradePrice)
+	return err
+}
+
+// OnTradePrice triggers every stop bid on the security that the last trade
+// price has reached and returns their IDs
+func (m *StopOrderMonitor) OnTradePrice(securityID string, lastTradePrice money.Decimal) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	triggered, err := m.market.TriggerStopBids(securityID, lastTradePrice)
+	if err != nil {
+		return triggered, fmt.Errorf("failed to trigger stop bids for %s: %w", securityID, err)
+	}
+	if len(triggered) > 0 {
+		log.Printf("Triggered %d stop bids for %s at %s", len(triggered), securityID, lastTradePrice)
+	}
+	return triggered, nil
+}
+
+// Run polls the market data provider until the context is cancelled. Without
+// a provider the monitor relies on TradeMatched events alone.
+func (m *StopOrderMonitor) Run(ctx context.Context) {
+	if m.marketData == nil {
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Poll()
+		}
+	}
+}
+
+// Poll checks the last trade price of every security with pending stops
+func (m *StopOrderMonitor) Poll() {
+	securityIDs, err := m.market.GetStopSecurities()
+	if err != nil {
+		log.Printf("Failed to get securities with stop bids: %v", err)
+		return
+	}
+
+	for _, securityID := range securityIDs {
+		price, err := m.marketData.GetLastTradePrice(securityID)
+		if err != nil {
+			log.Printf("Failed to get last trade price for %s: %v", securityID, err)
+			continue
+		}
+		if _, err := m.OnTradePrice(securityID, price); err != nil {
+			log.Printf("%v", err)
+		}
+	}
+}
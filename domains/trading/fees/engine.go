@@ -0,0 +1,205 @@
+package fees
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/execution"
+)
+
+// Liquidity roles of the parties to a trade
+const (
+	RoleMaker = "maker"
+	RoleTaker = "taker"
+)
+
+// defaultVolumeWindow is how far back traded volume counts toward tiers
+const defaultVolumeWindow = 30 * 24 * time.Hour
+
+// basisPoints is the number of basis points in one
+var basisPoints = money.NewDecimalFromInt(10000)
+
+// BrokerResolver finds the broker a user trades through
+type BrokerResolver interface {
+	// BrokerFor returns the broker's user ID, or an empty string when the
+	// user trades directly
+	BrokerFor(userID string) (string, error)
+}
+
+// Engine works out the fees of a trade from the schedule of its security
+// type. Each party pays a commission at their side's maker or taker rate,
+// discounted by their volume tier and kept between the minimum and maximum
+// fee, of which the broker's share goes to their broker; and the flat
+// platform fee. It implements execution.FeeCalculator.
+type Engine struct {
+	schedules    ScheduleStore
+	history      History
+	brokers      BrokerResolver
+	volumeWindow time.Duration
+}
+
+var _ execution.FeeCalculator = (*Engine)(nil)
+
+// NewEngine creates a new fee engine that counts volume over the 30 days
+// before each trade was matched
+func NewEngine(schedules ScheduleStore, history History) *Engine {
+	return &Engine{
+		schedules:    schedules,
+		history:      history,
+		volumeWindow: defaultVolumeWindow,
+	}
+}
+
+// SetBrokerResolver sets how parties are matched to their brokers. Without
+// one the platform keeps every commission.
+func (e *Engine) SetBrokerResolver(resolver BrokerResolver) {
+	e.brokers = resolver
+}
+
+// SetVolumeWindow sets how far back from the match traded volume counts
+// toward tiers
+func (e *Engine) SetVolumeWindow(window time.Duration) {
+	e.volumeWindow = window
+}
+
+// party is one side of the trade being charged
+type party struct {
+	side   string
+	userID string
+	role   string
+	rates  Rates
+}
+
+// CalculateFees itemizes the fees each party to the trade pays. Trades in
+// securities without a schedule are free.
+func (e *Engine) CalculateFees(trade *execution.TradeAggregate) (*execution.FeeBreakdown, error) {
+	securityType, err := e.history.SecurityType(trade.SecurityID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := e.schedules.ListSchedules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fee schedules: %w", err)
+	}
+	schedule := schedules.For(securityType)
+	if schedule == nil {
+		return execution.NewFeeBreakdown("", string(securityType), nil)
+	}
+
+	buyerRole, sellerRole, err := e.liquidityRoles(trade)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []execution.FeeItem
+	for _, p := range []party{
+		{side: "buyer", userID: trade.BuyerID, role: buyerRole, rates: schedule.Buyer},
+		{side: "seller", userID: trade.SellerID, role: sellerRole, rates: schedule.Seller},
+	} {
+		partyItems, err := e.partyFees(schedule, trade, p)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, partyItems...)
+	}
+
+	return execution.NewFeeBreakdown(schedule.ScheduleID, string(securityType), items)
+}
+
+// partyFees returns the commission and platform fee one party pays
+func (e *Engine) partyFees(schedule *Schedule, trade *execution.TradeAggregate, p party) ([]execution.FeeItem, error) {
+	places := money.MinorUnits(trade.TotalAmount.Currency)
+
+	rate := p.rates.Taker
+	if p.role == RoleMaker {
+		rate = p.rates.Maker
+	}
+	commission, err := trade.TotalAmount.Amount.Mul(rate, money.RoundHalfEven)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate commission: %w", err)
+	}
+	if commission, err = commission.Div(basisPoints, money.RoundHalfEven); err != nil {
+		return nil, fmt.Errorf("failed to calculate commission: %w", err)
+	}
+
+	if len(schedule.Tiers) > 0 {
+		volume, err := e.history.TradedVolume(p.userID, trade.ID, trade.MatchedAt.Add(-e.volumeWindow), trade.MatchedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find traded volume: %w", err)
+		}
+		discount, err := commission.Mul(schedule.Discount(volume), money.RoundHalfEven)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply volume discount: %w", err)
+		}
+		if commission, err = commission.Sub(discount); err != nil {
+			return nil, fmt.Errorf("failed to apply volume discount: %w", err)
+		}
+	}
+
+	if commission.LessThan(schedule.MinimumFee) {
+		commission = schedule.MinimumFee
+	}
+	if schedule.MaximumFee.IsPositive() && commission.GreaterThan(schedule.MaximumFee) {
+		commission = schedule.MaximumFee
+	}
+	commission = commission.Round(places, money.RoundHalfEven)
+
+	var items []execution.FeeItem
+	fee := func(feeType execution.FeeType, role string, amount money.Decimal, recipient string) {
+		if amount.IsPositive() {
+			items = append(items, execution.FeeItem{
+				Type:      feeType,
+				PayerID:   p.userID,
+				Side:      p.side,
+				Role:      role,
+				Amount:    amount,
+				Recipient: recipient,
+			})
+		}
+	}
+
+	brokerID, brokerAmount := "", money.Decimal{}
+	if e.brokers != nil && schedule.BrokerShare.IsPositive() {
+		brokerID, err = e.brokers.BrokerFor(p.userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find broker: %w", err)
+		}
+		if brokerID != "" {
+			if brokerAmount, err = commission.Mul(schedule.BrokerShare, money.RoundHalfEven); err != nil {
+				return nil, fmt.Errorf("failed to calculate broker share: %w", err)
+			}
+			brokerAmount = brokerAmount.Round(places, money.RoundDown)
+		}
+	}
+	platformAmount, err := commission.Sub(brokerAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate broker share: %w", err)
+	}
+	fee(execution.FeeTypeCommission, p.role, platformAmount, execution.FeeRecipientPlatform)
+	fee(execution.FeeTypeCommission, p.role, brokerAmount, brokerID)
+	fee(execution.FeeTypePlatform, "", schedule.PlatformFee.Round(places, money.RoundHalfEven), execution.FeeRecipientPlatform)
+
+	return items, nil
+}
+
+// liquidityRoles returns the roles of the buyer and the seller. The order
+// that rested in the book first made the market. Call auctions have no
+// resting side, so in them, as in trades not matched between a listing and a
+// bid, both parties take.
+func (e *Engine) liquidityRoles(trade *execution.TradeAggregate) (string, string, error) {
+	if trade.MatchingAlgorithm == string(execution.UniformPriceAuction) ||
+		trade.ListingID == nil || *trade.ListingID == "" || trade.BidID == nil || *trade.BidID == "" {
+		return RoleTaker, RoleTaker, nil
+	}
+
+	listedAt, bidAt, err := e.history.OrderTimes(*trade.ListingID, *trade.BidID)
+	if err != nil {
+		return "", "", err
+	}
+	if bidAt.Before(listedAt) {
+		return RoleMaker, RoleTaker, nil
+	}
+	return RoleTaker, RoleMaker, nil
+}
@@ -0,0 +1,189 @@
+package fees
+
+import (
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/securities"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/testutil"
+	"securities-marketplace/domains/trading/execution"
+)
+
+// stubHistory returns fixed security types, volumes and order times
+type stubHistory struct {
+	types    map[string]securities.SecurityType
+	volumes  map[string]money.Decimal
+	listedAt time.Time
+	bidAt    time.Time
+	from, to time.Time // Last volume window asked for
+}
+
+func (h *stubHistory) SecurityType(securityID string) (securities.SecurityType, error) {
+	return h.types[securityID], nil
+}
+
+func (h *stubHistory) TradedVolume(userID, excludedTradeID string, from, to time.Time) (money.Decimal, error) {
+	h.from, h.to = from, to
+	return h.volumes[userID], nil
+}
+
+func (h *stubHistory) OrderTimes(listingID, bidID string) (time.Time, time.Time, error) {
+	return h.listedAt, h.bidAt, nil
+}
+
+// stubBrokers maps users to their brokers
+type stubBrokers map[string]string
+
+func (b stubBrokers) BrokerFor(userID string) (string, error) {
+	return b[userID], nil
+}
+
+func newTestEngine(t *testing.T, schedules ...Schedule) (*Engine, *stubHistory) {
+	store := NewInMemoryScheduleStore()
+	for i := range schedules {
+		testutil.AssertNoError(t, store.SaveSchedule(&schedules[i]), "Schedule should save")
+	}
+	history := &stubHistory{
+		types: map[string]securities.SecurityType{
+			"stock-1": securities.SecurityTypeStock,
+			"bond-1":  securities.SecurityTypeBond,
+		},
+		volumes:  map[string]money.Decimal{},
+		listedAt: testutil.TestTime,
+		bidAt:    testutil.TestTime.Add(time.Minute),
+	}
+	return NewEngine(store, history), history
+}
+
+func testTrade(t *testing.T, securityID string, shares int64, price, algorithm string) *execution.TradeAggregate {
+	tradePrice := money.MustParseDecimal(price)
+	bidID := "bid-1"
+	totalAmount, err := tradePrice.MulInt(shares)
+	testutil.AssertNoError(t, err, "Trade should be valued")
+	trade := execution.NewTradeAggregate("trade-1")
+	err = trade.MatchTrade("listing-1", &bidID, "buyer-1", "seller-1", securityID, shares, tradePrice, money.New(totalAmount, "USD"), time.Now().Add(48*time.Hour), algorithm)
+	testutil.AssertNoError(t, err, "Trade should match")
+	return trade
+}
+
+func settle(t *testing.T, trade *execution.TradeAggregate, fees *execution.FeeBreakdown) {
+	testutil.AssertNoError(t, trade.ConfirmTrade("buyer-1"), "Buyer should confirm")
+	testutil.AssertNoError(t, trade.ConfirmTrade("seller-1"), "Seller should confirm")
+	testutil.AssertNoError(t, trade.InitiateSettlement("escrow-1", "system"), "Settlement should start")
+	testutil.AssertNoError(t, trade.ReceivePayment(trade.TotalAmount, "wire", "tx-1"), "Payment should be received")
+	testutil.AssertNoError(t, trade.TransferShares(trade.SharesTraded, "seller-1", "buyer-1", "book_entry", "hash"), "Shares should transfer")
+	testutil.AssertNoError(t, trade.SettleTrade(trade.TotalAmount, fees, money.ZeroIn("USD"), "automated"), "Trade should settle")
+}
+
+func TestEngine_ItemizesMakerTakerTieredAndBrokerFees(t *testing.T) {
+	// Arrange
+	engine, history := newTestEngine(t, Schedule{
+		ScheduleID:   "stocks",
+		SecurityType: securities.SecurityTypeStock,
+		Buyer:        Rates{Maker: money.MustParseDecimal("5"), Taker: money.MustParseDecimal("10")},
+		Seller:       Rates{Maker: money.MustParseDecimal("5"), Taker: money.MustParseDecimal("10")},
+		Tiers: []Tier{
+			{MinimumVolume: money.NewDecimalFromInt(100000), Discount: money.MustParseDecimal("0.5")},
+			{MinimumVolume: money.NewDecimalFromInt(1000000), Discount: money.MustParseDecimal("0.8")},
+		},
+		MinimumFee:  money.NewDecimalFromInt(1),
+		MaximumFee:  money.NewDecimalFromInt(40),
+		BrokerShare: money.MustParseDecimal("0.3"),
+		PlatformFee: money.NewDecimalFromInt(2),
+	})
+	engine.SetBrokerResolver(stubBrokers{"buyer-1": "broker-1"})
+	history.volumes["buyer-1"] = money.NewDecimalFromInt(150000)
+
+	trade := testTrade(t, "stock-1", 1000, "50", string(execution.PriceTimePriority))
+
+	// Act
+	breakdown, err := engine.CalculateFees(trade)
+
+	// Assert
+	testutil.AssertNoError(t, err, "Fees should be calculated")
+	testutil.AssertEqual(t, "stocks", breakdown.ScheduleID, "Stock schedule should apply")
+	testutil.AssertEqual(t, []execution.FeeItem{
+		{Type: execution.FeeTypeCommission, PayerID: "buyer-1", Side: "buyer", Role: RoleTaker, Amount: money.MustParseDecimal("17.5"), Recipient: execution.FeeRecipientPlatform},
+		{Type: execution.FeeTypeCommission, PayerID: "buyer-1", Side: "buyer", Role: RoleTaker, Amount: money.MustParseDecimal("7.5"), Recipient: "broker-1"},
+		{Type: execution.FeeTypePlatform, PayerID: "buyer-1", Side: "buyer", Amount: money.NewDecimalFromInt(2), Recipient: execution.FeeRecipientPlatform},
+		{Type: execution.FeeTypeCommission, PayerID: "seller-1", Side: "seller", Role: RoleMaker, Amount: money.NewDecimalFromInt(25), Recipient: execution.FeeRecipientPlatform},
+		{Type: execution.FeeTypePlatform, PayerID: "seller-1", Side: "seller", Amount: money.NewDecimalFromInt(2), Recipient: execution.FeeRecipientPlatform},
+	}, breakdown.Items, "Taker buyer should get the tier discount and split with their broker; resting seller should pay the maker rate")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(54), breakdown.Total, "Total should add up the items")
+	paid, err := breakdown.PaidBy("seller-1")
+	testutil.AssertNoError(t, err, "Seller fees should be totalled")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(27), paid, "Seller should pay their commission and platform fee")
+	testutil.AssertTimeEqual(t, trade.MatchedAt, history.to, "Volume should count up to the match, not settlement")
+	testutil.AssertTimeEqual(t, trade.MatchedAt.Add(-defaultVolumeWindow), history.from, "Volume should count over the window before the match")
+
+	breakdown, _ = engine.CalculateFees(testTrade(t, "stock-1", 100000, "50", string(execution.UniformPriceAuction)))
+	testutil.AssertEqual(t, RoleTaker, breakdown.Items[len(breakdown.Items)-2].Role, "Both sides of an auction should take")
+	testutil.AssertEqual(t, money.NewDecimalFromInt(40), breakdown.Items[len(breakdown.Items)-2].Amount, "Commission should be capped at the maximum fee")
+
+	breakdown, _ = engine.CalculateFees(testTrade(t, "stock-1", 1, "50", string(execution.PriceTimePriority)))
+	testutil.AssertEqual(t, money.NewDecimalFromInt(1), breakdown.Items[len(breakdown.Items)-2].Amount, "Commission should be raised to the minimum fee")
+
+	breakdown, _ = engine.CalculateFees(testTrade(t, "bond-1", 1000, "50", string(execution.PriceTimePriority)))
+	testutil.AssertLengthEqual(t, 0, breakdown.Items, "Security types without a schedule should trade free")
+	testutil.AssertEqual(t, "bond", breakdown.SecurityType, "Breakdown should record the security type")
+}
+
+func TestSchedule_ValidatesAndFallsBackToDefault(t *testing.T) {
+	// Arrange
+	schedules := Schedules{
+		{ScheduleID: "default"},
+		{ScheduleID: "bonds", SecurityType: securities.SecurityTypeBond},
+	}
+	invalid := Schedule{MinimumFee: money.NewDecimalFromInt(10), MaximumFee: money.NewDecimalFromInt(5)}
+	negative := Schedule{Buyer: Rates{Taker: money.MustParseDecimal("-1")}}
+	overShare := Schedule{BrokerShare: money.MustParseDecimal("1.5")}
+
+	// Act & Assert
+	testutil.AssertEqual(t, "bonds", schedules.For(securities.SecurityTypeBond).ScheduleID, "Security type schedule should win")
+	testutil.AssertEqual(t, "default", schedules.For(securities.SecurityTypeStock).ScheduleID, "Default schedule should apply otherwise")
+	testutil.AssertNil(t, Schedules{}.For(securities.SecurityTypeStock), "No schedule should apply when none is configured")
+	testutil.AssertError(t, invalid.Validate(), "Maximum fee should not be below the minimum")
+	testutil.AssertError(t, negative.Validate(), "Rates should not be negative")
+	testutil.AssertError(t, overShare.Validate(), "Broker share should be a fraction")
+
+	store := NewInMemoryScheduleStore()
+	testutil.AssertNoError(t, store.SaveSchedule(&Schedule{SecurityType: securities.SecurityTypeBond}), "Schedule should save")
+	testutil.AssertError(t, store.SaveSchedule(&Schedule{SecurityType: securities.SecurityTypeBond}), "Security types should have one schedule")
+}
+
+func TestBuildRevenueReport_TotalsSettledFees(t *testing.T) {
+	// Arrange
+	engine, _ := newTestEngine(t, Schedule{
+		ScheduleID:  "default",
+		Buyer:       Rates{Taker: money.MustParseDecimal("10")},
+		Seller:      Rates{Maker: money.MustParseDecimal("10")},
+		BrokerShare: money.MustParseDecimal("0.5"),
+		PlatformFee: money.NewDecimalFromInt(1),
+	})
+	engine.SetBrokerResolver(stubBrokers{"seller-1": "broker-1"})
+
+	settled := testTrade(t, "stock-1", 1000, "10", string(execution.PriceTimePriority))
+	fees, err := engine.CalculateFees(settled)
+	testutil.AssertNoError(t, err, "Fees should be calculated")
+	settle(t, settled, fees)
+	unsettled := testTrade(t, "stock-1", 1000, "10", string(execution.PriceTimePriority))
+
+	// Act
+	report, err := BuildRevenueReport([]*execution.TradeAggregate{settled, unsettled}, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	empty, emptyErr := BuildRevenueReport([]*execution.TradeAggregate{settled}, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+
+	// Assert
+	testutil.AssertNoError(t, err, "Report should be built")
+	testutil.AssertNoError(t, emptyErr, "Empty report should be built")
+	testutil.AssertEqual(t, 1, report.Trades, "Only settled trades should be reported")
+	testutil.AssertEqual(t, []RevenueTotal{{
+		Currency:        "USD",
+		Charged:         money.NewDecimalFromInt(22),
+		PlatformRevenue: money.NewDecimalFromInt(17),
+		BrokerPayouts:   money.NewDecimalFromInt(5),
+	}}, report.Totals, "Fees should be split between the platform and brokers")
+	testutil.AssertLengthEqual(t, 3, report.Lines, "Fees should be reported per type and recipient")
+	testutil.AssertEqual(t, RevenueLine{Currency: "USD", SecurityType: "stock", Type: execution.FeeTypeCommission, Recipient: "broker-1", Amount: money.NewDecimalFromInt(5), Items: 1}, report.Lines[0], "Broker share should be its own line")
+	testutil.AssertEqual(t, 0, empty.Trades, "Trades settled outside the period should be left out")
+}
@@ -0,0 +1,156 @@
+package fees
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"securities-marketplace/domains/shared/auth"
+	"securities-marketplace/domains/trading/execution"
+)
+
+// defaultReportDays is the period a revenue report covers when it is not
+// given one
+const defaultReportDays = 30
+
+// Handler lets administrators edit fee schedules and report fee revenue
+type Handler struct {
+	schedules ScheduleStore
+	trades    execution.TradeRepository
+}
+
+// NewHandler creates a new fee handler
+func NewHandler(schedules ScheduleStore, trades execution.TradeRepository) *Handler {
+	return &Handler{schedules: schedules, trades: trades}
+}
+
+// RegisterAdminRoutes registers the handler routes. Callers are expected to
+// mount the router behind admin authorization.
+func (h *Handler) RegisterAdminRoutes(router *mux.Router) {
+	router.HandleFunc("/fees/schedules", h.HandleListSchedules).Methods("GET")
+	router.HandleFunc("/fees/schedules", h.HandleCreateSchedule).Methods("POST")
+	router.HandleFunc("/fees/schedules/{id}", h.HandleUpdateSchedule).Methods("PUT")
+	router.HandleFunc("/fees/schedules/{id}", h.HandleDeleteSchedule).Methods("DELETE")
+	router.HandleFunc("/fees/revenue", h.HandleRevenueReport).Methods("GET")
+}
+
+// HandleListSchedules returns every configured schedule
+func (h *Handler) HandleListSchedules(w http.ResponseWriter, r *http.Request) {
+	schedules, err := h.schedules.ListSchedules()
+	if err != nil {
+		http.Error(w, "Failed to load fee schedules", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success":   true,
+		"schedules": schedules,
+		"count":     len(schedules),
+	})
+}
+
+// HandleCreateSchedule adds a schedule
+func (h *Handler) HandleCreateSchedule(w http.ResponseWriter, r *http.Request) {
+	h.saveSchedule(w, r, "", http.StatusCreated)
+}
+
+// HandleUpdateSchedule replaces a schedule
+func (h *Handler) HandleUpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	h.saveSchedule(w, r, mux.Vars(r)["id"], http.StatusOK)
+}
+
+// HandleDeleteSchedule removes a schedule
+func (h *Handler) HandleDeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	if err := h.schedules.DeleteSchedule(mux.Vars(r)["id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
+
+// HandleRevenueReport totals the fees of trades settled between the from and
+// to dates, both inclusive and given as YYYY-MM-DD. Without them the report
+// covers the last 30 days.
+func (h *Handler) HandleRevenueReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	to := time.Now()
+	from := to.AddDate(0, 0, -defaultReportDays)
+
+	if value := query.Get("from"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			http.Error(w, "from must be a date in YYYY-MM-DD format", http.StatusBadRequest)
+			return
+		}
+		from = date
+	}
+	if value := query.Get("to"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			http.Error(w, "to must be a date in YYYY-MM-DD format", http.StatusBadRequest)
+			return
+		}
+		to = date.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
+	}
+
+	trades, err := h.trades.FindByStatus(execution.TradeStatusSettled)
+	if err != nil {
+		http.Error(w, "Failed to load settled trades", http.StatusInternalServerError)
+		return
+	}
+
+	report, err := BuildRevenueReport(trades, from, to)
+	if err != nil {
+		http.Error(w, "Failed to build revenue report", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"report":  report,
+	})
+}
+
+func (h *Handler) saveSchedule(w http.ResponseWriter, r *http.Request, scheduleID string, status int) {
+	var schedule Schedule
+	if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := schedule.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	schedule.ScheduleID = scheduleID
+	schedule.UpdatedBy = "system"
+	if user := auth.GetUserFromContext(r.Context()); user != nil {
+		schedule.UpdatedBy = user.UserID
+	}
+	schedule.UpdatedAt = time.Now()
+
+	if err := h.schedules.SaveSchedule(&schedule); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	writeJSON(w, status, map[string]interface{}{
+		"success":  true,
+		"schedule": schedule,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, response map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,85 @@
+package fees
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/securities"
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/bidding"
+	"securities-marketplace/domains/trading/execution"
+	"securities-marketplace/domains/trading/listing"
+)
+
+// History reports what the fee engine needs to know beyond the trade itself
+type History interface {
+	// SecurityType returns the type of a security
+	SecurityType(securityID string) (securities.SecurityType, error)
+	// TradedVolume returns the value the user traded, bought or sold, in
+	// trades matched from from up to to. Void trades and the excluded trade
+	// do not count.
+	TradedVolume(userID, excludedTradeID string, from, to time.Time) (money.Decimal, error)
+	// OrderTimes returns when the trade's listing and bid were placed
+	OrderTimes(listingID, bidID string) (listedAt, bidAt time.Time, err error)
+}
+
+// TradeHistory answers from the security, trade, listing and bid
+// repositories
+type TradeHistory struct {
+	securities securities.SecurityRepository
+	trades     execution.TradeRepository
+	listings   listing.ListingRepository
+	bids       bidding.BidRepository
+}
+
+// NewTradeHistory creates a history backed by the event-sourced repositories
+func NewTradeHistory(eventStore events.EventStore) *TradeHistory {
+	return &TradeHistory{
+		securities: securities.NewEventSourcedSecurityRepository(eventStore),
+		trades:     execution.NewEventSourcedTradeRepository(eventStore),
+		listings:   listing.NewEventSourcedListingRepository(eventStore),
+		bids:       bidding.NewEventSourcedBidRepository(eventStore),
+	}
+}
+
+// SecurityType returns the type of the security
+func (h *TradeHistory) SecurityType(securityID string) (securities.SecurityType, error) {
+	security, err := h.securities.FindByID(securityID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find security: %w", err)
+	}
+	return security.SecurityType, nil
+}
+
+// TradedVolume sums the value of the user's trades matched in the period
+func (h *TradeHistory) TradedVolume(userID, excludedTradeID string, from, to time.Time) (money.Decimal, error) {
+	trades, err := h.trades.FindByUser(userID)
+	if err != nil {
+		return money.Decimal{}, fmt.Errorf("failed to find trades: %w", err)
+	}
+
+	total := money.NewDecimalFromInt(0)
+	for _, trade := range trades {
+		if trade.ID == excludedTradeID || trade.IsVoid() || trade.MatchedAt.Before(from) || !trade.MatchedAt.Before(to) {
+			continue
+		}
+		if total, err = total.Add(trade.TotalAmount.Amount); err != nil {
+			return money.Decimal{}, fmt.Errorf("failed to total traded volume: %w", err)
+		}
+	}
+	return total, nil
+}
+
+// OrderTimes returns when the listing was created and the bid placed
+func (h *TradeHistory) OrderTimes(listingID, bidID string) (time.Time, time.Time, error) {
+	l, err := h.listings.FindByID(listingID)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to find listing: %w", err)
+	}
+	b, err := h.bids.FindByID(bidID)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to find bid: %w", err)
+	}
+	return l.CreatedAt, b.PlacedAt, nil
+}
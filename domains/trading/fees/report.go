@@ -0,0 +1,118 @@
+package fees
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/execution"
+)
+
+// RevenueLine totals the fees of one type, on one security type, paid to one
+// recipient
+type RevenueLine struct {
+	Currency     string            `json:"currency"`
+	SecurityType string            `json:"securityType,omitempty"`
+	Type         execution.FeeType `json:"type"`
+	Recipient    string            `json:"recipient"`
+	Amount       money.Decimal     `json:"amount"`
+	Items        int               `json:"items"`
+}
+
+// RevenueTotal totals the fees charged in one currency
+type RevenueTotal struct {
+	Currency        string        `json:"currency"`
+	Charged         money.Decimal `json:"charged"`         // Every fee charged
+	PlatformRevenue money.Decimal `json:"platformRevenue"` // What the platform keeps
+	BrokerPayouts   money.Decimal `json:"brokerPayouts"`   // Commission shares owed to brokers
+}
+
+// RevenueReport totals the fees charged on trades settled in a period
+type RevenueReport struct {
+	From       time.Time      `json:"from"`
+	To         time.Time      `json:"to"`
+	Trades     int            `json:"trades"`
+	Unitemized int            `json:"unitemized"` // Trades settled before fees were itemized
+	Totals     []RevenueTotal `json:"totals"`
+	Lines      []RevenueLine  `json:"lines"`
+}
+
+// BuildRevenueReport totals the itemized fees of the trades settled from from
+// up to to. Other trades are ignored.
+func BuildRevenueReport(trades []*execution.TradeAggregate, from, to time.Time) (*RevenueReport, error) {
+	report := &RevenueReport{From: from, To: to, Totals: []RevenueTotal{}, Lines: []RevenueLine{}}
+
+	type lineKey struct {
+		currency, securityType, recipient string
+		feeType                           execution.FeeType
+	}
+	lines := make(map[lineKey]*RevenueLine)
+	totals := make(map[string]*RevenueTotal)
+
+	for _, trade := range trades {
+		if !trade.IsSettled() || trade.SettledAt == nil || trade.SettledAt.Before(from) || !trade.SettledAt.Before(to) {
+			continue
+		}
+		report.Trades++
+		if trade.FeeBreakdown == nil {
+			report.Unitemized++
+			continue
+		}
+
+		currency := trade.TotalAmount.Currency
+		total, ok := totals[currency]
+		if !ok {
+			total = &RevenueTotal{Currency: currency}
+			totals[currency] = total
+		}
+
+		for _, item := range trade.FeeBreakdown.Items {
+			key := lineKey{currency: currency, securityType: trade.FeeBreakdown.SecurityType, recipient: item.Recipient, feeType: item.Type}
+			line, ok := lines[key]
+			if !ok {
+				line = &RevenueLine{Currency: currency, SecurityType: key.securityType, Type: item.Type, Recipient: item.Recipient}
+				lines[key] = line
+			}
+			line.Items++
+
+			recipientTotal := &total.BrokerPayouts
+			if item.Recipient == execution.FeeRecipientPlatform {
+				recipientTotal = &total.PlatformRevenue
+			}
+			for _, sum := range []*money.Decimal{&line.Amount, &total.Charged, recipientTotal} {
+				added, err := sum.Add(item.Amount)
+				if err != nil {
+					return nil, fmt.Errorf("failed to total %s fees: %w", currency, err)
+				}
+				*sum = added
+			}
+		}
+	}
+
+	for _, total := range totals {
+		report.Totals = append(report.Totals, *total)
+	}
+	sort.Slice(report.Totals, func(i, j int) bool {
+		return report.Totals[i].Currency < report.Totals[j].Currency
+	})
+
+	for _, line := range lines {
+		report.Lines = append(report.Lines, *line)
+	}
+	sort.Slice(report.Lines, func(i, j int) bool {
+		a, b := report.Lines[i], report.Lines[j]
+		if a.Currency != b.Currency {
+			return a.Currency < b.Currency
+		}
+		if a.SecurityType != b.SecurityType {
+			return a.SecurityType < b.SecurityType
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Recipient < b.Recipient
+	})
+
+	return report, nil
+}
@@ -0,0 +1,122 @@
+package fees
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"securities-marketplace/domains/securities"
+	"securities-marketplace/domains/shared/money"
+)
+
+// Rates are the commission rates of one side of a trade, in basis points of
+// the trade value
+type Rates struct {
+	Maker money.Decimal `json:"maker"`
+	Taker money.Decimal `json:"taker"`
+}
+
+// Tier discounts the commission of users who have traded at least
+// MinimumVolume over the trailing volume window
+type Tier struct {
+	MinimumVolume money.Decimal `json:"minimumVolume"`
+	Discount      money.Decimal `json:"discount"` // Fraction of the commission waived, e.g. 0.25
+}
+
+// Schedule sets the fees charged on trades in securities of one type. An
+// empty SecurityType makes the schedule the default for types without their
+// own. Amounts are in the trade currency.
+type Schedule struct {
+	ScheduleID   string                  `json:"scheduleId"`
+	SecurityType securities.SecurityType `json:"securityType,omitempty"`
+	Buyer        Rates                   `json:"buyer"`
+	Seller       Rates                   `json:"seller"`
+	Tiers        []Tier                  `json:"tiers,omitempty"`
+	MinimumFee   money.Decimal           `json:"minimumFee"`  // Least commission a party pays
+	MaximumFee   money.Decimal           `json:"maximumFee"`  // Most commission a party pays, zero for no cap
+	BrokerShare  money.Decimal           `json:"brokerShare"` // Fraction of the commission paid to the party's broker
+	PlatformFee  money.Decimal           `json:"platformFee"` // Flat fee each party pays per trade
+	UpdatedBy    string                  `json:"updatedBy"`
+	UpdatedAt    time.Time               `json:"updatedAt"`
+}
+
+// Validate checks that the schedule can be applied
+func (s *Schedule) Validate() error {
+	for name, rate := range map[string]money.Decimal{
+		"buyer maker": s.Buyer.Maker, "buyer taker": s.Buyer.Taker,
+		"seller maker": s.Seller.Maker, "seller taker": s.Seller.Taker,
+	} {
+		if rate.IsNegative() {
+			return fmt.Errorf("%s rate cannot be negative", name)
+		}
+	}
+	for name, amount := range map[string]money.Decimal{
+		"minimum fee": s.MinimumFee, "maximum fee": s.MaximumFee, "platform fee": s.PlatformFee,
+	} {
+		if amount.IsNegative() {
+			return fmt.Errorf("%s cannot be negative", name)
+		}
+	}
+	if s.MaximumFee.IsPositive() && s.MaximumFee.LessThan(s.MinimumFee) {
+		return fmt.Errorf("maximum fee cannot be less than the minimum fee")
+	}
+	if !isFraction(s.BrokerShare) {
+		return fmt.Errorf("broker share must be a fraction between 0 and 1")
+	}
+
+	seen := make(map[money.Decimal]bool)
+	for _, tier := range s.Tiers {
+		if !tier.MinimumVolume.IsPositive() {
+			return fmt.Errorf("tier minimum volume must be positive")
+		}
+		if seen[tier.MinimumVolume] {
+			return fmt.Errorf("two tiers start at a volume of %s", tier.MinimumVolume)
+		}
+		seen[tier.MinimumVolume] = true
+		if !isFraction(tier.Discount) {
+			return fmt.Errorf("tier discount must be a fraction between 0 and 1")
+		}
+	}
+	return nil
+}
+
+// Discount returns the commission discount of the highest tier the volume
+// reaches, or zero when it reaches none
+func (s *Schedule) Discount(volume money.Decimal) money.Decimal {
+	tiers := append([]Tier(nil), s.Tiers...)
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].MinimumVolume.LessThan(tiers[j].MinimumVolume)
+	})
+
+	var discount money.Decimal
+	for _, tier := range tiers {
+		if volume.LessThan(tier.MinimumVolume) {
+			break
+		}
+		discount = tier.Discount
+	}
+	return discount
+}
+
+// Schedules is the full set of configured fee schedules
+type Schedules []Schedule
+
+// For returns the schedule of the security type, falling back to the
+// default schedule, or nil when neither is configured
+func (ss Schedules) For(securityType securities.SecurityType) *Schedule {
+	var fallback *Schedule
+	for i := range ss {
+		schedule := &ss[i]
+		switch schedule.SecurityType {
+		case securityType:
+			return schedule
+		case "":
+			fallback = schedule
+		}
+	}
+	return fallback
+}
+
+func isFraction(d money.Decimal) bool {
+	return !d.IsNegative() && !d.GreaterThan(money.NewDecimalFromInt(1))
+}
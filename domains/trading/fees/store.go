@@ -0,0 +1,195 @@
+package fees
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"securities-marketplace/domains/securities"
+)
+
+// ScheduleStore keeps the fee schedules administrators configure
+type ScheduleStore interface {
+	ListSchedules() (Schedules, error)
+	SaveSchedule(schedule *Schedule) error
+	DeleteSchedule(scheduleID string) error
+}
+
+// PostgresScheduleStore keeps fee schedules in the fee_schedules table
+type PostgresScheduleStore struct {
+	db *sql.DB
+}
+
+// NewPostgresScheduleStore creates a new PostgreSQL-backed schedule store
+func NewPostgresScheduleStore(db *sql.DB) *PostgresScheduleStore {
+	return &PostgresScheduleStore{db: db}
+}
+
+// ListSchedules returns every configured schedule
+func (s *PostgresScheduleStore) ListSchedules() (Schedules, error) {
+	rows, err := s.db.Query(`
+		SELECT schedule_id, security_type, buyer_maker_bps, buyer_taker_bps, seller_maker_bps, seller_taker_bps,
+		       tiers, minimum_fee, maximum_fee, broker_share, platform_fee, updated_by, updated_at
+		FROM fee_schedules
+		ORDER BY security_type
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fee schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := Schedules{}
+	for rows.Next() {
+		var schedule Schedule
+		var securityType string
+		var tiers []byte
+		err := rows.Scan(
+			&schedule.ScheduleID, &securityType,
+			&schedule.Buyer.Maker, &schedule.Buyer.Taker, &schedule.Seller.Maker, &schedule.Seller.Taker,
+			&tiers, &schedule.MinimumFee, &schedule.MaximumFee, &schedule.BrokerShare, &schedule.PlatformFee,
+			&schedule.UpdatedBy, &schedule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan fee schedule: %w", err)
+		}
+		schedule.SecurityType = securities.SecurityType(securityType)
+		if err := json.Unmarshal(tiers, &schedule.Tiers); err != nil {
+			return nil, fmt.Errorf("failed to decode fee tiers: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate fee schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// SaveSchedule inserts a schedule, or replaces the schedule with the same ID
+func (s *PostgresScheduleStore) SaveSchedule(schedule *Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	if schedule.ScheduleID == "" {
+		schedule.ScheduleID = uuid.New().String()
+	}
+	if schedule.UpdatedAt.IsZero() {
+		schedule.UpdatedAt = time.Now()
+	}
+
+	tiers, err := json.Marshal(schedule.Tiers)
+	if err != nil {
+		return fmt.Errorf("failed to encode fee tiers: %w", err)
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO fee_schedules (schedule_id, security_type, buyer_maker_bps, buyer_taker_bps, seller_maker_bps, seller_taker_bps,
+		                           tiers, minimum_fee, maximum_fee, broker_share, platform_fee, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (schedule_id) DO UPDATE SET
+			security_type = EXCLUDED.security_type,
+			buyer_maker_bps = EXCLUDED.buyer_maker_bps,
+			buyer_taker_bps = EXCLUDED.buyer_taker_bps,
+			seller_maker_bps = EXCLUDED.seller_maker_bps,
+			seller_taker_bps = EXCLUDED.seller_taker_bps,
+			tiers = EXCLUDED.tiers,
+			minimum_fee = EXCLUDED.minimum_fee,
+			maximum_fee = EXCLUDED.maximum_fee,
+			broker_share = EXCLUDED.broker_share,
+			platform_fee = EXCLUDED.platform_fee,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`, schedule.ScheduleID, string(schedule.SecurityType),
+		schedule.Buyer.Maker, schedule.Buyer.Taker, schedule.Seller.Maker, schedule.Seller.Taker,
+		tiers, schedule.MinimumFee, schedule.MaximumFee, schedule.BrokerShare, schedule.PlatformFee,
+		schedule.UpdatedBy, schedule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save fee schedule: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSchedule removes a schedule
+func (s *PostgresScheduleStore) DeleteSchedule(scheduleID string) error {
+	result, err := s.db.Exec(`DELETE FROM fee_schedules WHERE schedule_id = $1`, scheduleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete fee schedule: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err == nil && deleted == 0 {
+		return fmt.Errorf("fee schedule %s not found", scheduleID)
+	}
+	return nil
+}
+
+// InMemoryScheduleStore keeps fee schedules in memory for testing and
+// development
+type InMemoryScheduleStore struct {
+	mu        sync.RWMutex
+	schedules map[string]Schedule
+}
+
+// NewInMemoryScheduleStore creates a new in-memory schedule store
+func NewInMemoryScheduleStore() *InMemoryScheduleStore {
+	return &InMemoryScheduleStore{
+		schedules: make(map[string]Schedule),
+	}
+}
+
+// ListSchedules returns every configured schedule
+func (s *InMemoryScheduleStore) ListSchedules() (Schedules, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	schedules := make(Schedules, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		schedules = append(schedules, schedule)
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].SecurityType < schedules[j].SecurityType
+	})
+	return schedules, nil
+}
+
+// SaveSchedule inserts a schedule, or replaces the schedule with the same ID.
+// Like the fee_schedules table, it allows one schedule per security type.
+func (s *InMemoryScheduleStore) SaveSchedule(schedule *Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if schedule.ScheduleID == "" {
+		schedule.ScheduleID = uuid.New().String()
+	}
+	if schedule.UpdatedAt.IsZero() {
+		schedule.UpdatedAt = time.Now()
+	}
+	for id, existing := range s.schedules {
+		if id != schedule.ScheduleID && existing.SecurityType == schedule.SecurityType {
+			return fmt.Errorf("a fee schedule already exists for security type %q", schedule.SecurityType)
+		}
+	}
+
+	s.schedules[schedule.ScheduleID] = *schedule
+	return nil
+}
+
+// DeleteSchedule removes a schedule
+func (s *InMemoryScheduleStore) DeleteSchedule(scheduleID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.schedules[scheduleID]; !ok {
+		return fmt.Errorf("fee schedule %s not found", scheduleID)
+	}
+	delete(s.schedules, scheduleID)
+	return nil
+}
@@ -2,9 +2,12 @@ package listing
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/orders"
 )
 
 // ListingType represents the type of listing
@@ -50,14 +53,22 @@ type ListingAggregate struct {
 	ListingType     ListingType     `json:"listingType"`
 	
 	// Pricing information
-	MinimumPrice    *float64        `json:"minimumPrice,omitempty"`
-	ReservePrice    *float64        `json:"reservePrice,omitempty"`
-	CurrentPrice    *float64        `json:"currentPrice,omitempty"`
+	MinimumPrice    *money.Decimal  `json:"minimumPrice,omitempty"`
+	ReservePrice    *money.Decimal  `json:"reservePrice,omitempty"`
+	CurrentPrice    *money.Decimal  `json:"currentPrice,omitempty"`
 	
 	// Restrictions and requirements
 	RestrictionType *RestrictionType `json:"restrictionType,omitempty"`
 	AccreditedOnly  bool            `json:"accreditedOnly"`
 	
+	// Execution conditions
+	Conditions      orders.Conditions `json:"conditions"`
+	
+	// Iceberg display; a zero DisplayQuantity displays every remaining share
+	DisplayQuantity    int64        `json:"displayQuantity,omitempty"`
+	SharesDisplayed    int64        `json:"sharesDisplayed"`
+	DisplayRefreshedAt time.Time    `json:"displayRefreshedAt"`
+	
 	// Status and lifecycle
 	Status          ListingStatus   `json:"status"`
 	CreatedAt       time.Time       `json:"createdAt"`
@@ -68,6 +79,7 @@ type ListingAggregate struct {
 	// Trading history
 	TotalSharesSold int64           `json:"totalSharesSold"`
 	TradeIDs        []string        `json:"tradeIds"`
+	SharesSoldByTrade map[string]int64 `json:"sharesSoldByTrade"`
 	
 	// Cancellation details
 	CancellationReason string       `json:"cancellationReason,omitempty"`
@@ -81,11 +93,42 @@ func NewListingAggregate(listingID string) *ListingAggregate {
 		Status:          ListingStatusActive,
 		TradeIDs:        make([]string, 0),
 		TotalSharesSold: 0,
+		SharesSoldByTrade: make(map[string]int64),
 	}
 }
 
 // CreateListing creates a new listing
-func (l *ListingAggregate) CreateListing(securityID, sellerID string, sharesOffered int64, listingType ListingType, minimumPrice, reservePrice, currentPrice *float64, restrictionType *RestrictionType, accreditedOnly bool, expiresAt *time.Time) error {
+func (l *ListingAggregate) CreateListing(securityID, sellerID string, sharesOffered int64, listingType ListingType, minimumPrice, reservePrice, currentPrice *money.Decimal, restrictionType *RestrictionType, accreditedOnly bool, expiresAt *time.Time) error {
+	return l.CreateListingWithConditions(securityID, sellerID, sharesOffered, listingType, minimumPrice, reservePrice, currentPrice, restrictionType, accreditedOnly, expiresAt, orders.Conditions{})
+}
+
+// CreateListingWithConditions creates a new listing that may only be sold as
+// the conditions allow and lives as long as their time in force
+func (l *ListingAggregate) CreateListingWithConditions(securityID, sellerID string, sharesOffered int64, listingType ListingType, minimumPrice, reservePrice, currentPrice *money.Decimal, restrictionType *RestrictionType, accreditedOnly bool, expiresAt *time.Time, conditions orders.Conditions) error {
+	return l.createListing(securityID, sellerID, sharesOffered, 0, listingType, minimumPrice, reservePrice, currentPrice, restrictionType, accreditedOnly, expiresAt, conditions)
+}
+
+// CreateIcebergListing creates a priced listing that only displays
+// displayQuantity shares at a time. The rest is held in a hidden reserve and
+// shown a tranche at a time as the displayed shares are sold.
+func (l *ListingAggregate) CreateIcebergListing(securityID, sellerID string, sharesOffered, displayQuantity int64, listingType ListingType, currentPrice *money.Decimal, restrictionType *RestrictionType, accreditedOnly bool, expiresAt *time.Time, conditions orders.Conditions) error {
+	if listingType != ListingTypeFixed && listingType != ListingTypeLimit {
+		return fmt.Errorf("iceberg listings must be fixed or limit priced")
+	}
+
+	if displayQuantity <= 0 || displayQuantity >= sharesOffered {
+		return fmt.Errorf("display quantity must be between 1 and %d shares", sharesOffered-1)
+	}
+
+	// Tranches trade separately, so conditions on the whole listing cannot be honoured
+	if conditions.RequiresFullFill() || conditions.MinQuantity > 0 || conditions.TimeInForce == orders.TimeInForceIOC {
+		return fmt.Errorf("iceberg listings cannot be all-or-none, have a minimum quantity or be immediate-or-cancel")
+	}
+
+	return l.createListing(securityID, sellerID, sharesOffered, displayQuantity, listingType, nil, nil, currentPrice, restrictionType, accreditedOnly, expiresAt, conditions)
+}
+
+func (l *ListingAggregate) createListing(securityID, sellerID string, sharesOffered, displayQuantity int64, listingType ListingType, minimumPrice, reservePrice, currentPrice *money.Decimal, restrictionType *RestrictionType, accreditedOnly bool, expiresAt *time.Time, conditions orders.Conditions) error {
 	if l.Version > 0 {
 		return fmt.Errorf("listing already exists")
 	}
@@ -100,11 +143,20 @@ func (l *ListingAggregate) CreateListing(securityID, sellerID string, sharesOffe
 		return fmt.Errorf("invalid pricing: %w", err)
 	}
 
+	if err := conditions.Validate(sharesOffered); err != nil {
+		return fmt.Errorf("invalid conditions: %w", err)
+	}
+
 	// Validate expiration
 	if expiresAt != nil && expiresAt.Before(time.Now()) {
 		return fmt.Errorf("expiration date cannot be in the past")
 	}
 
+	conditions, err := conditions.Resolve(expiresAt)
+	if err != nil {
+		return fmt.Errorf("invalid time in force: %w", err)
+	}
+
 	var restrictionTypeStr *string
 	if restrictionType != nil {
 		str := string(*restrictionType)
@@ -112,12 +164,14 @@ func (l *ListingAggregate) CreateListing(securityID, sellerID string, sharesOffe
 	}
 
 	event := NewListingCreated(l.ID, securityID, sellerID, sharesOffered, string(listingType), minimumPrice, reservePrice, currentPrice, restrictionTypeStr, accreditedOnly, expiresAt)
+	event.Conditions = conditions
+	event.DisplayQuantity = displayQuantity
 	l.AddEvent(event)
 	return l.ApplyEvent(event)
 }
 
 // UpdatePrice updates the listing price
-func (l *ListingAggregate) UpdatePrice(newPrice float64, updatedBy, reason string) error {
+func (l *ListingAggregate) UpdatePrice(newPrice money.Decimal, updatedBy, reason string) error {
 	if l.Status != ListingStatusActive {
 		return fmt.Errorf("cannot update price of non-active listing")
 	}
@@ -126,13 +180,13 @@ func (l *ListingAggregate) UpdatePrice(newPrice float64, updatedBy, reason strin
 		return fmt.Errorf("cannot update price of fixed-price listing")
 	}
 
-	if newPrice <= 0 {
+	if !newPrice.IsPositive() {
 		return fmt.Errorf("price must be greater than zero")
 	}
 
 	// Validate against minimum price if set
-	if l.MinimumPrice != nil && newPrice < *l.MinimumPrice {
-		return fmt.Errorf("price cannot be below minimum price of %.2f", *l.MinimumPrice)
+	if l.MinimumPrice != nil && newPrice.LessThan(*l.MinimumPrice) {
+		return fmt.Errorf("price cannot be below minimum price of %s", *l.MinimumPrice)
 	}
 
 	event := NewListingPriceUpdated(l.ID, l.CurrentPrice, newPrice, updatedBy, reason)
@@ -141,7 +195,7 @@ func (l *ListingAggregate) UpdatePrice(newPrice float64, updatedBy, reason strin
 }
 
 // ReduceShares reduces the number of available shares after a partial sale
-func (l *ListingAggregate) ReduceShares(sharesSold int64, tradeID, buyerID string, salePrice float64) error {
+func (l *ListingAggregate) ReduceShares(sharesSold int64, tradeID, buyerID string, salePrice money.Decimal) error {
 	if l.Status != ListingStatusActive {
 		return fmt.Errorf("cannot reduce shares of non-active listing")
 	}
@@ -154,6 +208,10 @@ func (l *ListingAggregate) ReduceShares(sharesSold int64, tradeID, buyerID strin
 		return fmt.Errorf("cannot sell more shares than remaining (%d > %d)", sharesSold, l.SharesRemaining)
 	}
 
+	if !l.Conditions.AllowsFill(sharesSold, l.SharesRemaining) {
+		return fmt.Errorf("sale of %d shares violates the listing's conditions", sharesSold)
+	}
+
 	newSharesRemaining := l.SharesRemaining - sharesSold
 
 	event := NewListingSharesReduced(l.ID, sharesSold, newSharesRemaining, tradeID, buyerID, salePrice)
@@ -169,6 +227,76 @@ func (l *ListingAggregate) ReduceShares(sharesSold int64, tradeID, buyerID strin
 		return l.ApplyEvent(completedEvent)
 	}
 
+	if err := l.ApplyEvent(event); err != nil {
+		return err
+	}
+	return l.refreshDisplay()
+}
+
+// AdjustSale records that a trade which sold shares from the listing was
+// busted or corrected. Shares taken off a sale are not offered again: the
+// seller keeps them, and the listing's remaining offer is unchanged.
+func (l *ListingAggregate) AdjustSale(tradeID string, sharesSold int64, salePrice money.Decimal, reason string) error {
+	previousShares, ok := l.SharesSoldByTrade[tradeID]
+	if !ok {
+		return fmt.Errorf("trade %s did not sell shares from listing %s", tradeID, l.ID)
+	}
+
+	if sharesSold < 0 || sharesSold > previousShares {
+		return fmt.Errorf("adjusted sale of %d shares must be between 0 and %d", sharesSold, previousShares)
+	}
+
+	if sharesSold > 0 && !salePrice.IsPositive() {
+		return fmt.Errorf("sale price must be greater than zero")
+	}
+
+	if reason == "" {
+		return fmt.Errorf("adjustment reason is required")
+	}
+
+	event := NewListingSaleAdjusted(l.ID, tradeID, previousShares, sharesSold, salePrice, reason)
+	l.AddEvent(event)
+	return l.ApplyEvent(event)
+}
+
+// ReduceOffer withdraws shares from the listing without selling them. The
+// shares come off the displayed quantity first, as a sale would. Withdrawing
+// every remaining share is a cancellation.
+func (l *ListingAggregate) ReduceOffer(sharesWithdrawn int64, reducedBy, reason string) error {
+	if l.Status != ListingStatusActive {
+		return fmt.Errorf("cannot reduce the offer of non-active listing")
+	}
+
+	if sharesWithdrawn <= 0 {
+		return fmt.Errorf("shares withdrawn must be greater than zero")
+	}
+
+	if sharesWithdrawn >= l.SharesRemaining {
+		return fmt.Errorf("withdrawing all %d remaining shares cancels the listing", l.SharesRemaining)
+	}
+
+	if reason == "" {
+		return fmt.Errorf("reduction reason is required")
+	}
+
+	event := NewListingOfferReduced(l.ID, sharesWithdrawn, l.SharesRemaining-sharesWithdrawn, reducedBy, reason)
+	l.AddEvent(event)
+	if err := l.ApplyEvent(event); err != nil {
+		return err
+	}
+	return l.refreshDisplay()
+}
+
+// refreshDisplay shows the next iceberg tranche once the displayed shares
+// are used up
+func (l *ListingAggregate) refreshDisplay() error {
+	if !l.IsIceberg() || l.SharesDisplayed > 0 || l.SharesRemaining == 0 {
+		return nil
+	}
+
+	displayed := min(l.DisplayQuantity, l.SharesRemaining)
+	event := NewListingDisplayRefreshed(l.ID, displayed, l.SharesRemaining-displayed)
+	l.AddEvent(event)
 	return l.ApplyEvent(event)
 }
 
@@ -206,6 +334,30 @@ func (l *ListingAggregate) Expire() error {
 	return l.ApplyEvent(event)
 }
 
+// ExpireAtClose expires a day listing once the market session it was created
+// in has closed
+func (l *ListingAggregate) ExpireAtClose(marketClose time.Time) error {
+	if l.Status != ListingStatusActive {
+		return fmt.Errorf("can only expire active listings")
+	}
+
+	if l.Conditions.TimeInForce != orders.TimeInForceDAY {
+		return fmt.Errorf("only day listings expire at the market close")
+	}
+
+	if !l.CreatedAt.Before(marketClose) {
+		return fmt.Errorf("listing was created after the market close")
+	}
+
+	if time.Now().Before(marketClose) {
+		return fmt.Errorf("market has not yet closed")
+	}
+
+	event := NewListingExpired(l.ID, marketClose)
+	l.AddEvent(event)
+	return l.ApplyEvent(event)
+}
+
 // Reactivate reactivates a cancelled listing
 func (l *ListingAggregate) Reactivate(reactivatedBy, reason string) error {
 	if l.Status != ListingStatusCancelled {
@@ -235,6 +387,10 @@ func (l *ListingAggregate) ApplyEvent(event events.DomainEvent) error {
 		return l.applyListingPriceUpdated(e)
 	case *ListingSharesReduced:
 		return l.applyListingSharesReduced(e)
+	case *ListingOfferReduced:
+		return l.applyListingOfferReduced(e)
+	case *ListingDisplayRefreshed:
+		return l.applyListingDisplayRefreshed(e)
 	case *ListingCancelled:
 		return l.applyListingCancelled(e)
 	case *ListingExpired:
@@ -243,6 +399,8 @@ func (l *ListingAggregate) ApplyEvent(event events.DomainEvent) error {
 		return l.applyListingCompleted(e)
 	case *ListingReactivated:
 		return l.applyListingReactivated(e)
+	case *ListingSaleAdjusted:
+		return l.applyListingSaleAdjusted(e)
 	default:
 		return fmt.Errorf("unknown event type: %T", event)
 	}
@@ -277,6 +435,16 @@ func (l *ListingAggregate) applyListingCreated(event *ListingCreated) error {
 	}
 	
 	l.AccreditedOnly = event.AccreditedOnly
+	l.Conditions = event.Conditions
+	if l.Conditions.TimeInForce == "" {
+		l.Conditions.TimeInForce = orders.DefaultTimeInForce(event.ExpiresAt)
+	}
+	l.DisplayQuantity = event.DisplayQuantity
+	l.SharesDisplayed = event.SharesOffered
+	if l.DisplayQuantity > 0 {
+		l.SharesDisplayed = min(l.DisplayQuantity, event.SharesOffered)
+	}
+	l.DisplayRefreshedAt = event.Timestamp
 	l.Status = ListingStatusActive
 	l.CreatedAt = event.Timestamp
 	l.ExpiresAt = event.ExpiresAt
@@ -296,6 +464,37 @@ func (l *ListingAggregate) applyListingSharesReduced(event *ListingSharesReduced
 	l.SharesRemaining = event.SharesRemaining
 	l.TotalSharesSold += event.SharesSold
 	l.TradeIDs = append(l.TradeIDs, event.TradeID)
+	if l.SharesSoldByTrade == nil {
+		l.SharesSoldByTrade = make(map[string]int64)
+	}
+	l.SharesSoldByTrade[event.TradeID] += event.SharesSold
+	
+	// Sales take displayed shares first; any excess comes out of the reserve
+	if l.DisplayQuantity > 0 {
+		l.SharesDisplayed = max(0, l.SharesDisplayed-event.SharesSold)
+	} else {
+		l.SharesDisplayed = event.SharesRemaining
+	}
+	
+	l.IncrementVersion()
+	return nil
+}
+
+func (l *ListingAggregate) applyListingOfferReduced(event *ListingOfferReduced) error {
+	l.SharesRemaining = event.SharesRemaining
+	if l.DisplayQuantity > 0 {
+		l.SharesDisplayed = max(0, l.SharesDisplayed-event.SharesWithdrawn)
+	} else {
+		l.SharesDisplayed = event.SharesRemaining
+	}
+	
+	l.IncrementVersion()
+	return nil
+}
+
+func (l *ListingAggregate) applyListingDisplayRefreshed(event *ListingDisplayRefreshed) error {
+	l.SharesDisplayed = event.SharesDisplayed
+	l.DisplayRefreshedAt = event.Timestamp
 	
 	l.IncrementVersion()
 	return nil
@@ -336,6 +535,18 @@ func (l *ListingAggregate) applyListingReactivated(event *ListingReactivated) er
 	return nil
 }
 
+func (l *ListingAggregate) applyListingSaleAdjusted(event *ListingSaleAdjusted) error {
+	l.TotalSharesSold -= event.PreviousShares - event.SharesSold
+	l.SharesSoldByTrade[event.TradeID] = event.SharesSold
+	if event.SharesSold == 0 {
+		l.TradeIDs = slices.DeleteFunc(l.TradeIDs, func(id string) bool { return id == event.TradeID })
+		delete(l.SharesSoldByTrade, event.TradeID)
+	}
+	
+	l.IncrementVersion()
+	return nil
+}
+
 // Helper methods
 
 // IsActive returns true if the listing is active and can accept trades
@@ -361,7 +572,7 @@ func (l *ListingAggregate) IsExpired() bool {
 }
 
 // GetCurrentPrice returns the current asking price
-func (l *ListingAggregate) GetCurrentPrice() *float64 {
+func (l *ListingAggregate) GetCurrentPrice() *money.Decimal {
 	return l.CurrentPrice
 }
 
@@ -392,6 +603,16 @@ func (l *ListingAggregate) CanAcceptBid(bidderID string, isAccredited bool) bool
 	return true
 }
 
+// IsIceberg returns true if the listing holds shares back in a hidden reserve
+func (l *ListingAggregate) IsIceberg() bool {
+	return l.DisplayQuantity > 0
+}
+
+// GetSharesHidden returns the number of remaining shares not displayed
+func (l *ListingAggregate) GetSharesHidden() int64 {
+	return l.SharesRemaining - l.SharesDisplayed
+}
+
 // GetFillPercentage returns the percentage of shares that have been sold
 func (l *ListingAggregate) GetFillPercentage() float64 {
 	if l.SharesOffered == 0 {
@@ -415,24 +636,24 @@ func (l *ListingAggregate) GetTimeRemaining() *time.Duration {
 }
 
 // validatePricing validates pricing based on listing type
-func (l *ListingAggregate) validatePricing(listingType ListingType, minimumPrice, reservePrice, currentPrice *float64) error {
+func (l *ListingAggregate) validatePricing(listingType ListingType, minimumPrice, reservePrice, currentPrice *money.Decimal) error {
 	switch listingType {
 	case ListingTypeFixed:
 		if currentPrice == nil {
 			return fmt.Errorf("fixed price listing requires current price")
 		}
-		if *currentPrice <= 0 {
+		if !currentPrice.IsPositive() {
 			return fmt.Errorf("current price must be greater than zero")
 		}
 		
 	case ListingTypeAuction:
-		if minimumPrice != nil && *minimumPrice <= 0 {
+		if minimumPrice != nil && !minimumPrice.IsPositive() {
 			return fmt.Errorf("minimum price must be greater than zero")
 		}
-		if reservePrice != nil && *reservePrice <= 0 {
+		if reservePrice != nil && !reservePrice.IsPositive() {
 			return fmt.Errorf("reserve price must be greater than zero")
 		}
-		if minimumPrice != nil && reservePrice != nil && *minimumPrice > *reservePrice {
+		if minimumPrice != nil && reservePrice != nil && minimumPrice.GreaterThan(*reservePrice) {
 			return fmt.Errorf("minimum price cannot be greater than reserve price")
 		}
 		
@@ -440,7 +661,7 @@ func (l *ListingAggregate) validatePricing(listingType ListingType, minimumPrice
 		if currentPrice == nil {
 			return fmt.Errorf("limit listing requires current price")
 		}
-		if *currentPrice <= 0 {
+		if !currentPrice.IsPositive() {
 			return fmt.Errorf("current price must be greater than zero")
 		}
 		
@@ -0,0 +1,96 @@
+package listing
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/orders"
+)
+
+// Listing Domain Commands
+
+// CreateListingCommand represents a command to list shares for sale. A
+// positive DisplayQuantity makes it an iceberg listing.
+type CreateListingCommand struct {
+	ListingID       string            `json:"listingId"`
+	SecurityID      string            `json:"securityId"`
+	SellerID        string            `json:"sellerId"`
+	SharesOffered   int64             `json:"sharesOffered"`
+	DisplayQuantity int64             `json:"displayQuantity,omitempty"`
+	ListingType     ListingType       `json:"listingType"`
+	MinimumPrice    *money.Decimal    `json:"minimumPrice,omitempty"`
+	ReservePrice    *money.Decimal    `json:"reservePrice,omitempty"`
+	CurrentPrice    *money.Decimal    `json:"currentPrice,omitempty"`
+	RestrictionType *RestrictionType  `json:"restrictionType,omitempty"`
+	AccreditedOnly  bool              `json:"accreditedOnly"`
+	ExpiresAt       *time.Time        `json:"expiresAt,omitempty"`
+	Conditions      orders.Conditions `json:"conditions"`
+}
+
+// Validate validates the create listing command
+func (cmd *CreateListingCommand) Validate() error {
+	if cmd.SecurityID == "" {
+		return fmt.Errorf("security ID is required")
+	}
+	if cmd.SellerID == "" {
+		return fmt.Errorf("seller ID is required")
+	}
+	return nil
+}
+
+// UpdateListingPriceCommand represents a command to change a listing's asking price
+type UpdateListingPriceCommand struct {
+	ListingID string        `json:"listingId"`
+	NewPrice  money.Decimal `json:"newPrice"`
+	UpdatedBy string        `json:"updatedBy"`
+	Reason    string        `json:"reason"`
+}
+
+// Validate validates the update listing price command
+func (cmd *UpdateListingPriceCommand) Validate() error {
+	if cmd.ListingID == "" {
+		return fmt.Errorf("listing ID is required")
+	}
+	if cmd.UpdatedBy == "" {
+		return fmt.Errorf("updated by is required")
+	}
+	return nil
+}
+
+// ReduceListingCommand represents a command to withdraw some of a listing's shares
+type ReduceListingCommand struct {
+	ListingID       string `json:"listingId"`
+	SharesWithdrawn int64  `json:"sharesWithdrawn"`
+	ReducedBy       string `json:"reducedBy"`
+	Reason          string `json:"reason"`
+}
+
+// Validate validates the reduce listing command
+func (cmd *ReduceListingCommand) Validate() error {
+	if cmd.ListingID == "" {
+		return fmt.Errorf("listing ID is required")
+	}
+	if cmd.ReducedBy == "" {
+		return fmt.Errorf("reduced by is required")
+	}
+	return nil
+}
+
+// CancelListingCommand represents a command to cancel a listing
+type CancelListingCommand struct {
+	ListingID   string `json:"listingId"`
+	Reason      string `json:"reason"`
+	CancelledBy string `json:"cancelledBy"`
+}
+
+// Validate validates the cancel listing command
+func (cmd *CancelListingCommand) Validate() error {
+	if cmd.ListingID == "" {
+		return fmt.Errorf("listing ID is required")
+	}
+	if cmd.CancelledBy == "" {
+		return fmt.Errorf("cancelled by is required")
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/orders"
 )
 
 // Listing Domain Events
@@ -16,16 +18,18 @@ type ListingCreated struct {
 	SellerID        string  `json:"sellerId"`
 	SharesOffered   int64   `json:"sharesOffered"`
 	ListingType     string  `json:"listingType"`
-	MinimumPrice    *float64 `json:"minimumPrice,omitempty"`
-	ReservePrice    *float64 `json:"reservePrice,omitempty"`
-	CurrentPrice    *float64 `json:"currentPrice,omitempty"`
+	MinimumPrice    *money.Decimal `json:"minimumPrice,omitempty"`
+	ReservePrice    *money.Decimal `json:"reservePrice,omitempty"`
+	CurrentPrice    *money.Decimal `json:"currentPrice,omitempty"`
 	RestrictionType *string  `json:"restrictionType,omitempty"`
 	AccreditedOnly  bool    `json:"accreditedOnly"`
+	Conditions      orders.Conditions `json:"conditions"`
+	DisplayQuantity int64   `json:"displayQuantity,omitempty"` // Iceberg peak size; zero displays every share
 	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
 }
 
 // NewListingCreated creates a new ListingCreated event
-func NewListingCreated(listingID, securityID, sellerID string, sharesOffered int64, listingType string, minimumPrice, reservePrice, currentPrice *float64, restrictionType *string, accreditedOnly bool, expiresAt *time.Time) *ListingCreated {
+func NewListingCreated(listingID, securityID, sellerID string, sharesOffered int64, listingType string, minimumPrice, reservePrice, currentPrice *money.Decimal, restrictionType *string, accreditedOnly bool, expiresAt *time.Time) *ListingCreated {
 	return &ListingCreated{
 		BaseEvent:       events.NewBaseEvent(listingID, "Listing"),
 		SecurityID:      securityID,
@@ -63,13 +67,13 @@ func (e *ListingCreated) GetMetadata() events.Metadata {
 // ListingPriceUpdated event is emitted when listing price is updated
 type ListingPriceUpdated struct {
 	events.BaseEvent
-	OldPrice    *float64 `json:"oldPrice,omitempty"`
-	NewPrice    float64  `json:"newPrice"`
+	OldPrice    *money.Decimal `json:"oldPrice,omitempty"`
+	NewPrice    money.Decimal  `json:"newPrice"`
 	UpdatedBy   string   `json:"updatedBy"`
 	Reason      string   `json:"reason"`
 }
 
-func NewListingPriceUpdated(listingID string, oldPrice *float64, newPrice float64, updatedBy, reason string) *ListingPriceUpdated {
+func NewListingPriceUpdated(listingID string, oldPrice *money.Decimal, newPrice money.Decimal, updatedBy, reason string) *ListingPriceUpdated {
 	return &ListingPriceUpdated{
 		BaseEvent: events.NewBaseEvent(listingID, "Listing"),
 		OldPrice:  oldPrice,
@@ -105,10 +109,10 @@ type ListingSharesReduced struct {
 	SharesRemaining int64  `json:"sharesRemaining"`
 	TradeID         string `json:"tradeId"`
 	BuyerID         string `json:"buyerId"`
-	SalePrice       float64 `json:"salePrice"`
+	SalePrice       money.Decimal `json:"salePrice"`
 }
 
-func NewListingSharesReduced(listingID string, sharesSold, sharesRemaining int64, tradeID, buyerID string, salePrice float64) *ListingSharesReduced {
+func NewListingSharesReduced(listingID string, sharesSold, sharesRemaining int64, tradeID, buyerID string, salePrice money.Decimal) *ListingSharesReduced {
 	return &ListingSharesReduced{
 		BaseEvent:       events.NewBaseEvent(listingID, "Listing"),
 		SharesSold:      sharesSold,
@@ -138,6 +142,80 @@ func (e *ListingSharesReduced) GetMetadata() events.Metadata {
 	}
 }
 
+// ListingOfferReduced event is emitted when shares are withdrawn from a
+// listing without being sold
+type ListingOfferReduced struct {
+	events.BaseEvent
+	SharesWithdrawn int64  `json:"sharesWithdrawn"`
+	SharesRemaining int64  `json:"sharesRemaining"`
+	ReducedBy       string `json:"reducedBy"`
+	Reason          string `json:"reason"`
+}
+
+func NewListingOfferReduced(listingID string, sharesWithdrawn, sharesRemaining int64, reducedBy, reason string) *ListingOfferReduced {
+	return &ListingOfferReduced{
+		BaseEvent:       events.NewBaseEvent(listingID, "Listing"),
+		SharesWithdrawn: sharesWithdrawn,
+		SharesRemaining: sharesRemaining,
+		ReducedBy:       reducedBy,
+		Reason:          reason,
+	}
+}
+
+func (e *ListingOfferReduced) GetEventType() string     { return "ListingOfferReduced" }
+func (e *ListingOfferReduced) GetAggregateID() string   { return e.AggregateID }
+func (e *ListingOfferReduced) GetAggregateType() string { return e.AggregateType }
+
+func (e *ListingOfferReduced) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *ListingOfferReduced) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// ListingDisplayRefreshed event is emitted when an iceberg listing's displayed
+// shares are used up and a new tranche is shown from the hidden reserve
+type ListingDisplayRefreshed struct {
+	events.BaseEvent
+	SharesDisplayed int64 `json:"sharesDisplayed"`
+	SharesHidden    int64 `json:"sharesHidden"`
+}
+
+func NewListingDisplayRefreshed(listingID string, sharesDisplayed, sharesHidden int64) *ListingDisplayRefreshed {
+	return &ListingDisplayRefreshed{
+		BaseEvent:       events.NewBaseEvent(listingID, "Listing"),
+		SharesDisplayed: sharesDisplayed,
+		SharesHidden:    sharesHidden,
+	}
+}
+
+func (e *ListingDisplayRefreshed) GetEventType() string     { return "ListingDisplayRefreshed" }
+func (e *ListingDisplayRefreshed) GetAggregateID() string   { return e.AggregateID }
+func (e *ListingDisplayRefreshed) GetAggregateType() string { return e.AggregateType }
+
+func (e *ListingDisplayRefreshed) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *ListingDisplayRefreshed) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
 // ListingCancelled event is emitted when a listing is cancelled
 type ListingCancelled struct {
 	events.BaseEvent
@@ -208,11 +286,11 @@ func (e *ListingExpired) GetMetadata() events.Metadata {
 type ListingCompleted struct {
 	events.BaseEvent
 	TotalSharesSold int64     `json:"totalSharesSold"`
-	FinalPrice      float64   `json:"finalPrice"`
+	FinalPrice      money.Decimal   `json:"finalPrice"`
 	CompletedAt     time.Time `json:"completedAt"`
 }
 
-func NewListingCompleted(listingID string, totalSharesSold int64, finalPrice float64, completedAt time.Time) *ListingCompleted {
+func NewListingCompleted(listingID string, totalSharesSold int64, finalPrice money.Decimal, completedAt time.Time) *ListingCompleted {
 	return &ListingCompleted{
 		BaseEvent:       events.NewBaseEvent(listingID, "Listing"),
 		TotalSharesSold: totalSharesSold,
@@ -272,4 +350,49 @@ func (e *ListingReactivated) GetMetadata() events.Metadata {
 		EventVersion:  e.Version,
 		Timestamp:     e.Timestamp,
 	}
-}
\ No newline at end of file
+}
+// ListingSaleAdjusted event is emitted when a trade that sold shares from the
+// listing is busted or corrected. A busted sale has no shares left.
+type ListingSaleAdjusted struct {
+	events.BaseEvent
+	TradeID        string        `json:"tradeId"`
+	PreviousShares int64         `json:"previousShares"`
+	SharesSold     int64         `json:"sharesSold"`
+	SalePrice      money.Decimal `json:"salePrice"`
+	Reason         string        `json:"reason"`
+}
+
+func NewListingSaleAdjusted(listingID, tradeID string, previousShares, sharesSold int64, salePrice money.Decimal, reason string) *ListingSaleAdjusted {
+	return &ListingSaleAdjusted{
+		BaseEvent:      events.NewBaseEvent(listingID, "Listing"),
+		TradeID:        tradeID,
+		PreviousShares: previousShares,
+		SharesSold:     sharesSold,
+		SalePrice:      salePrice,
+		Reason:         reason,
+	}
+}
+
+func (e *ListingSaleAdjusted) GetEventType() string     { return "ListingSaleAdjusted" }
+func (e *ListingSaleAdjusted) GetAggregateID() string   { return e.AggregateID }
+func (e *ListingSaleAdjusted) GetAggregateType() string { return e.AggregateType }
+
+func (e *ListingSaleAdjusted) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *ListingSaleAdjusted) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// ListingsProjectionName names the projection that maintains
+// listings_projection. Listing queries wait on it when they carry a
+// consistency token.
+const ListingsProjectionName = "listings_projection"
@@ -0,0 +1,67 @@
+package listing
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"securities-marketplace/domains/shared/auth"
+	"securities-marketplace/domains/shared/events"
+)
+
+// Handler exposes listing entry to sellers
+type Handler struct {
+	service *ListingService
+	rbac    *auth.RBAC
+}
+
+// NewHandler creates a new listing handler
+func NewHandler(service *ListingService) *Handler {
+	return &Handler{
+		service: service,
+		rbac:    auth.NewRBAC(),
+	}
+}
+
+// RegisterRoutes registers the handler routes. Callers are expected to mount
+// the router behind authentication.
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/listings", h.HandleCreate).Methods("POST")
+}
+
+// HandleCreate lists the signed-in seller's shares for sale
+func (h *Handler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromContext(r.Context())
+	if user == nil || user.UserID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	if !h.rbac.HasPermission(user.Roles, auth.PermissionTradeWrite) {
+		http.Error(w, "Insufficient permissions", http.StatusForbidden)
+		return
+	}
+
+	var cmd CreateListingCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	cmd.SellerID = user.UserID
+
+	listing, err := h.service.CreateListing(&cmd)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"listing": listing,
+	}
+
+	events.SetConsistencyToken(w, listing.GetLastEventNumber())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
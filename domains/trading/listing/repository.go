@@ -0,0 +1,90 @@
+package listing
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"securities-marketplace/domains/shared/events"
+)
+
+// ListingRepository defines the interface for listing persistence
+type ListingRepository interface {
+	FindByID(listingID string) (*ListingAggregate, error)
+}
+
+// EventSourcedListingRepository implements ListingRepository using event sourcing
+type EventSourcedListingRepository struct {
+	eventStore events.EventStore
+}
+
+// NewEventSourcedListingRepository creates a new event-sourced listing repository
+func NewEventSourcedListingRepository(eventStore events.EventStore) *EventSourcedListingRepository {
+	return &EventSourcedListingRepository{
+		eventStore: eventStore,
+	}
+}
+
+// FindByID finds a listing by ID by replaying events
+func (r *EventSourcedListingRepository) FindByID(listingID string) (*ListingAggregate, error) {
+	eventRecords, err := r.eventStore.GetEvents(listingID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	if len(eventRecords) == 0 {
+		return nil, fmt.Errorf("listing with id %s not found", listingID)
+	}
+
+	var domainEvents []events.DomainEvent
+	for _, eventRecord := range eventRecords {
+		domainEvent, err := r.convertEventRecordToDomainEvent(eventRecord)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert event %s: %w", eventRecord.EventType, err)
+		}
+		domainEvents = append(domainEvents, domainEvent)
+	}
+
+	listing := NewListingAggregate(listingID)
+	err = listing.LoadFromHistory(domainEvents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load from history: %w", err)
+	}
+
+	listing.SetLastEventNumber(eventRecords[len(eventRecords)-1].EventNumber)
+
+	return listing, nil
+}
+
+// convertEventRecordToDomainEvent converts a single event record to domain event
+func (r *EventSourcedListingRepository) convertEventRecordToDomainEvent(eventRecord *events.Event) (events.DomainEvent, error) {
+	var event events.DomainEvent
+	switch eventRecord.EventType {
+	case "ListingCreated":
+		event = &ListingCreated{}
+	case "ListingPriceUpdated":
+		event = &ListingPriceUpdated{}
+	case "ListingSharesReduced":
+		event = &ListingSharesReduced{}
+	case "ListingOfferReduced":
+		event = &ListingOfferReduced{}
+	case "ListingDisplayRefreshed":
+		event = &ListingDisplayRefreshed{}
+	case "ListingCancelled":
+		event = &ListingCancelled{}
+	case "ListingExpired":
+		event = &ListingExpired{}
+	case "ListingCompleted":
+		event = &ListingCompleted{}
+	case "ListingReactivated":
+		event = &ListingReactivated{}
+	case "ListingSaleAdjusted":
+		event = &ListingSaleAdjusted{}
+	default:
+		return nil, fmt.Errorf("unknown event type: %s", eventRecord.EventType)
+	}
+
+	if err := json.Unmarshal(eventRecord.EventData, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize %s: %w", eventRecord.EventType, err)
+	}
+	return event, nil
+}
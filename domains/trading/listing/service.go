@@ -0,0 +1,230 @@
+package listing
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/trading/orders"
+)
+
+// ListingService provides application services for listings
+type ListingService struct {
+	repository ListingRepository
+	eventStore events.EventStore
+	eventBus   events.EventBus
+	gate       orders.EntryGate
+	sequencer  orders.Sequencer
+}
+
+// NewListingService creates a new listing service
+func NewListingService(repository ListingRepository, eventStore events.EventStore, eventBus events.EventBus) *ListingService {
+	return &ListingService{
+		repository: repository,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+	}
+}
+
+// SetEntryGate sets the check listings must pass before they are created or
+// modified. Without one every listing is accepted.
+func (s *ListingService) SetEntryGate(gate orders.EntryGate) {
+	s.gate = gate
+}
+
+// SetSequencer sets the sequencer listing changes run on, so they reach the
+// book in the same order as matching. Without one they run in place.
+func (s *ListingService) SetSequencer(sequencer orders.Sequencer) {
+	s.sequencer = sequencer
+}
+
+// CreateListing lists shares for sale
+func (s *ListingService) CreateListing(cmd *CreateListingCommand) (*ListingAggregate, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid command: %w", err)
+	}
+
+	listingID := cmd.ListingID
+	if listingID == "" {
+		listingID = uuid.New().String()
+	}
+
+	listing := NewListingAggregate(listingID)
+	err := s.sequence(cmd.SecurityID, func(fence func() error) error {
+		if err := s.checkEntry(cmd.SecurityID); err != nil {
+			return err
+		}
+
+		var err error
+		if cmd.DisplayQuantity > 0 {
+			err = listing.CreateIcebergListing(cmd.SecurityID, cmd.SellerID, cmd.SharesOffered, cmd.DisplayQuantity, cmd.ListingType, cmd.CurrentPrice, cmd.RestrictionType, cmd.AccreditedOnly, cmd.ExpiresAt, cmd.Conditions)
+		} else {
+			err = listing.CreateListingWithConditions(cmd.SecurityID, cmd.SellerID, cmd.SharesOffered, cmd.ListingType, cmd.MinimumPrice, cmd.ReservePrice, cmd.CurrentPrice, cmd.RestrictionType, cmd.AccreditedOnly, cmd.ExpiresAt, cmd.Conditions)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create listing: %w", err)
+		}
+
+		if err := s.saveAggregateEvents(listing, cmd.SellerID, fence); err != nil {
+			return fmt.Errorf("failed to save listing: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
+
+// UpdatePrice changes a listing's asking price
+func (s *ListingService) UpdatePrice(cmd *UpdateListingPriceCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return fmt.Errorf("invalid command: %w", err)
+	}
+
+	listing, err := s.repository.FindByID(cmd.ListingID)
+	if err != nil {
+		return fmt.Errorf("failed to find listing: %w", err)
+	}
+
+	return s.sequence(listing.SecurityID, func(fence func() error) error {
+		listing, err := s.repository.FindByID(cmd.ListingID)
+		if err != nil {
+			return fmt.Errorf("failed to find listing: %w", err)
+		}
+		if err := s.checkEntry(listing.SecurityID); err != nil {
+			return err
+		}
+
+		if err := listing.UpdatePrice(cmd.NewPrice, cmd.UpdatedBy, cmd.Reason); err != nil {
+			return fmt.Errorf("failed to update price: %w", err)
+		}
+		return s.saveAggregateEvents(listing, cmd.UpdatedBy, fence)
+	})
+}
+
+// ReduceOffer withdraws some of a listing's shares
+func (s *ListingService) ReduceOffer(cmd *ReduceListingCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return fmt.Errorf("invalid command: %w", err)
+	}
+
+	listing, err := s.repository.FindByID(cmd.ListingID)
+	if err != nil {
+		return fmt.Errorf("failed to find listing: %w", err)
+	}
+
+	return s.sequence(listing.SecurityID, func(fence func() error) error {
+		listing, err := s.repository.FindByID(cmd.ListingID)
+		if err != nil {
+			return fmt.Errorf("failed to find listing: %w", err)
+		}
+		if err := s.checkEntry(listing.SecurityID); err != nil {
+			return err
+		}
+
+		if err := listing.ReduceOffer(cmd.SharesWithdrawn, cmd.ReducedBy, cmd.Reason); err != nil {
+			return fmt.Errorf("failed to reduce offer: %w", err)
+		}
+		return s.saveAggregateEvents(listing, cmd.ReducedBy, fence)
+	})
+}
+
+// CancelListing cancels a listing. Cancellation is allowed in every
+// session state.
+func (s *ListingService) CancelListing(cmd *CancelListingCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return fmt.Errorf("invalid command: %w", err)
+	}
+
+	listing, err := s.repository.FindByID(cmd.ListingID)
+	if err != nil {
+		return fmt.Errorf("failed to find listing: %w", err)
+	}
+
+	return s.sequence(listing.SecurityID, func(fence func() error) error {
+		listing, err := s.repository.FindByID(cmd.ListingID)
+		if err != nil {
+			return fmt.Errorf("failed to find listing: %w", err)
+		}
+
+		if err := listing.Cancel(cmd.Reason, cmd.CancelledBy); err != nil {
+			return fmt.Errorf("failed to cancel listing: %w", err)
+		}
+		return s.saveAggregateEvents(listing, cmd.CancelledBy, fence)
+	})
+}
+
+// GetListing retrieves a listing by ID
+func (s *ListingService) GetListing(listingID string) (*ListingAggregate, error) {
+	return s.repository.FindByID(listingID)
+}
+
+// sequence runs a command that changes the security's listings on the
+// sequencer, or in place without one
+func (s *ListingService) sequence(securityID string, command func(fence func() error) error) error {
+	if s.sequencer == nil {
+		return command(func() error { return nil })
+	}
+	return s.sequencer.SequenceOrders(securityID, command)
+}
+
+func (s *ListingService) checkEntry(securityID string) error {
+	if s.gate == nil {
+		return nil
+	}
+	if err := s.gate.CheckOrderEntry(securityID); err != nil {
+		return fmt.Errorf("order entry rejected: %w", err)
+	}
+	return nil
+}
+
+// saveAggregateEvents saves uncommitted events and publishes them. The fence
+// is checked right before saving.
+func (s *ListingService) saveAggregateEvents(listing *ListingAggregate, userID string, fence func() error) error {
+	uncommittedEvents := listing.GetUncommittedEvents()
+	if len(uncommittedEvents) == 0 {
+		return nil
+	}
+
+	var events []*events.Event
+	correlationID := uuid.New().String()
+
+	for i, domainEvent := range uncommittedEvents {
+		var causationID *string
+		if i > 0 {
+			prevEventID := events[i-1].EventID
+			causationID = &prevEventID
+		}
+
+		event, err := s.eventStore.CreateEventFromDomain(domainEvent, userID, correlationID, causationID)
+		if err != nil {
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+
+		event.AggregateVersion = listing.GetVersion() + i + 1
+		events = append(events, event)
+	}
+
+	if err := fence(); err != nil {
+		return err
+	}
+	if err := s.eventStore.SaveEvents(events); err != nil {
+		return fmt.Errorf("failed to save events: %w", err)
+	}
+
+	// Remember where this command landed in the global stream so callers can
+	// return it as a consistency token
+	listing.SetLastEventNumber(events[len(events)-1].EventNumber)
+
+	for _, domainEvent := range uncommittedEvents {
+		if err := s.eventBus.Publish(domainEvent); err != nil {
+			// Log error but don't fail the operation
+			fmt.Printf("Failed to publish event %s: %v\n", domainEvent.GetEventType(), err)
+		}
+	}
+
+	listing.MarkEventsAsCommitted()
+	return nil
+}
@@ -0,0 +1,150 @@
+package marketdata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/execution"
+)
+
+// Cached values are keyed by the security's generation, which changes every
+// time the security trades. Invalidating a security only has to write its
+// generation; values cached under older generations are never read again and
+// expire with the cache TTL.
+
+func generationKey(securityID string) string {
+	return "marketdata:" + securityID + ":generation"
+}
+
+// cacheKey returns the key of a value cached for the security, or false when
+// the cache cannot be used
+func (p *Provider) cacheKey(ctx context.Context, securityID, name string) (string, bool) {
+	if p.cache == nil {
+		return "", false
+	}
+
+	generation, err := p.cache.Get(ctx, generationKey(securityID))
+	if errors.Is(err, redis.Nil) {
+		generation = "0"
+	} else if err != nil {
+		fmt.Printf("Failed to read market data generation of %s: %v\n", securityID, err)
+		return "", false
+	}
+	return fmt.Sprintf("marketdata:%s:%s:%s", securityID, generation, name), true
+}
+
+// cached returns the value cached under the name, or computes, caches and
+// returns it
+func (p *Provider) cached(securityID, name string, compute func() (string, error)) (string, error) {
+	ctx := context.Background()
+	key, ok := p.cacheKey(ctx, securityID, name)
+	if ok {
+		if value, err := p.cache.Get(ctx, key); err == nil {
+			return value, nil
+		}
+	}
+
+	value, err := compute()
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		if err := p.cache.Set(ctx, key, value, p.config.CacheTTL); err != nil {
+			fmt.Printf("Failed to cache market data %s: %v\n", key, err)
+		}
+	}
+	return value, nil
+}
+
+func (p *Provider) cachedDecimal(securityID, name string, compute func() (money.Decimal, error)) (money.Decimal, error) {
+	value, err := p.cached(securityID, name, func() (string, error) {
+		price, err := compute()
+		if err != nil {
+			return "", err
+		}
+		return price.String(), nil
+	})
+	if err != nil {
+		return money.Zero, err
+	}
+	return money.ParseDecimal(value)
+}
+
+func (p *Provider) cachedFloat(securityID, name string, compute func() (float64, error)) (float64, error) {
+	value, err := p.cached(securityID, name, func() (string, error) {
+		f, err := compute()
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// Invalidate drops everything cached for the security
+func (p *Provider) Invalidate(securityID string) error {
+	if p.cache == nil {
+		return nil
+	}
+	if err := p.cache.Set(context.Background(), generationKey(securityID), uuid.New().String(), 0); err != nil {
+		return fmt.Errorf("failed to invalidate market data of %s: %w", securityID, err)
+	}
+	return nil
+}
+
+// Subscribe registers the provider for the trade events that change market
+// data, so cached values are dropped as soon as a security trades or a trade
+// is voided or corrected
+func (p *Provider) Subscribe(bus events.EventBus) error {
+	for _, eventType := range []string{"TradeMatched", "TradeCancelled", "TradeFailed", "TradeBusted", "TradeCorrected"} {
+		if err := bus.Subscribe(eventType, p.HandleTradeEvent); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", eventType, err)
+		}
+	}
+	return nil
+}
+
+// HandleTradeEvent invalidates the market data of the traded security
+func (p *Provider) HandleTradeEvent(event events.DomainEvent) error {
+	securityID, err := p.tradedSecurity(event)
+	if err != nil {
+		return err
+	}
+	return p.Invalidate(securityID)
+}
+
+// tradedSecurity returns the security a trade event is about. Only
+// TradeMatched carries it; for other events the trade is loaded.
+func (p *Provider) tradedSecurity(event events.DomainEvent) (string, error) {
+	if event.GetEventType() != "TradeMatched" {
+		trade, err := p.trades.FindByID(event.GetAggregateID())
+		if err != nil {
+			return "", fmt.Errorf("failed to find trade %s: %w", event.GetAggregateID(), err)
+		}
+		return trade.SecurityID, nil
+	}
+
+	matched, ok := event.(*execution.TradeMatched)
+	if !ok {
+		data, err := event.GetEventData()
+		if err != nil {
+			return "", fmt.Errorf("failed to get event data: %w", err)
+		}
+		matched = &execution.TradeMatched{}
+		if err := json.Unmarshal(data, matched); err != nil {
+			return "", fmt.Errorf("failed to deserialize TradeMatched: %w", err)
+		}
+	}
+	return matched.SecurityID, nil
+}
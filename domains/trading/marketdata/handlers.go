@@ -0,0 +1,146 @@
+package marketdata
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"securities-marketplace/domains/shared/auth"
+	"securities-marketplace/domains/shared/money"
+)
+
+// Handler exposes a security's market data, and lets administrators set the
+// valuation marks of illiquid securities
+type Handler struct {
+	provider *Provider
+}
+
+// NewHandler creates a new market data handler
+func NewHandler(provider *Provider) *Handler {
+	return &Handler{provider: provider}
+}
+
+// MarkRequest is the body of a valuation mark change
+type MarkRequest struct {
+	Price money.Decimal `json:"price"`
+	Note  string        `json:"note"`
+}
+
+// RegisterRoutes registers the read-only routes
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/securities/{id}", h.HandleGetMarketData).Methods("GET")
+}
+
+// RegisterAdminRoutes registers the routes that change valuation marks.
+// Callers are expected to mount the router behind admin authorization.
+func (h *Handler) RegisterAdminRoutes(router *mux.Router) {
+	router.HandleFunc("/securities/{id}/mark", h.HandleGetMark).Methods("GET")
+	router.HandleFunc("/securities/{id}/mark", h.HandleSetMark).Methods("PUT")
+	router.HandleFunc("/securities/{id}/mark", h.HandleDeleteMark).Methods("DELETE")
+}
+
+// HandleGetMarketData returns the last trade price, reference price and
+// volatility of a security
+func (h *Handler) HandleGetMarketData(w http.ResponseWriter, r *http.Request) {
+	securityID := mux.Vars(r)["id"]
+
+	lastPrice, err := h.provider.GetLastTradePrice(securityID)
+	if err != nil {
+		http.Error(w, "Failed to get last trade price", http.StatusInternalServerError)
+		return
+	}
+	referencePrice, err := h.provider.GetReferencePrice(securityID)
+	if err != nil {
+		http.Error(w, "Failed to get reference price", http.StatusInternalServerError)
+		return
+	}
+	volatility, err := h.provider.GetVolatility(securityID, 0)
+	if err != nil {
+		http.Error(w, "Failed to get volatility", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"marketData": map[string]interface{}{
+			"securityId":       securityID,
+			"lastTradePrice":   lastPrice,
+			"referencePrice":   referencePrice,
+			"volatility":       volatility,
+			"volatilityWindow": h.provider.config.VolatilityWindow.String(),
+		},
+	})
+}
+
+// HandleGetMark returns the valuation mark of a security
+func (h *Handler) HandleGetMark(w http.ResponseWriter, r *http.Request) {
+	mark, err := h.provider.GetMark(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Failed to load valuation mark", http.StatusInternalServerError)
+		return
+	}
+	if mark == nil {
+		http.Error(w, "No valuation mark", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"mark":    mark,
+	})
+}
+
+// HandleSetMark sets the valuation mark of a security
+func (h *Handler) HandleSetMark(w http.ResponseWriter, r *http.Request) {
+	var req MarkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	mark := &Mark{
+		SecurityID: mux.Vars(r)["id"],
+		Price:      req.Price,
+		Note:       req.Note,
+		SetBy:      "system",
+		SetAt:      time.Now(),
+	}
+	if user := auth.GetUserFromContext(r.Context()); user != nil {
+		mark.SetBy = user.UserID
+	}
+	if err := mark.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.provider.SetMark(mark); err != nil {
+		http.Error(w, "Failed to save valuation mark", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"mark":    mark,
+	})
+}
+
+// HandleDeleteMark removes the valuation mark of a security, so its reference
+// price is computed from trades again
+func (h *Handler) HandleDeleteMark(w http.ResponseWriter, r *http.Request) {
+	if err := h.provider.DeleteMark(mux.Vars(r)["id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, response map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
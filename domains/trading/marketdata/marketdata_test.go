@@ -0,0 +1,175 @@
+package marketdata
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/storage"
+	"securities-marketplace/domains/shared/testutil"
+	"securities-marketplace/domains/trading/execution"
+)
+
+// stubTrades serves fixed trades and counts the lookups that reach it
+type stubTrades struct {
+	execution.TradeRepository
+	trades  []*execution.TradeAggregate
+	lookups int
+}
+
+func (s *stubTrades) FindBySecurity(securityID string) ([]*execution.TradeAggregate, error) {
+	s.lookups++
+	var trades []*execution.TradeAggregate
+	for _, trade := range s.trades {
+		if trade.SecurityID == securityID {
+			trades = append(trades, trade)
+		}
+	}
+	return trades, nil
+}
+
+func (s *stubTrades) FindByID(tradeID string) (*execution.TradeAggregate, error) {
+	for _, trade := range s.trades {
+		if trade.ID == tradeID {
+			return trade, nil
+		}
+	}
+	return nil, fmt.Errorf("trade %s not found", tradeID)
+}
+
+// testNow is mid-session on a Wednesday
+var testNow = time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+func (s *stubTrades) add(price string, shares int64, ago time.Duration) *execution.TradeAggregate {
+	trade := execution.NewTradeAggregate("trade-" + price + "-" + ago.String())
+	trade.SecurityID = "security-1"
+	trade.TradePrice = money.MustParseDecimal(price)
+	trade.SharesTraded = shares
+	trade.Status = execution.TradeStatusMatched
+	trade.MatchedAt = testNow.Add(-ago)
+	s.trades = append(s.trades, trade)
+	return trade
+}
+
+func newTestProvider(trades *stubTrades) *Provider {
+	provider := NewProvider(trades, NewInMemoryMarkStore(), storage.NewInMemoryCache(), nil)
+	provider.now = func() time.Time { return testNow }
+	return provider
+}
+
+func TestProvider_ComputesMarketDataFromTrades(t *testing.T) {
+	// Arrange
+	trades := &stubTrades{}
+	trades.add("100", 10, 26*time.Hour) // Tuesday, before the close
+	trades.add("110", 30, 2*time.Hour)
+	trades.add("99", 10, time.Hour)
+	trades.add("500", 10, 30*time.Minute).Status = execution.TradeStatusCancelled
+	provider := newTestProvider(trades)
+
+	// Act & Assert
+	last, err := provider.GetLastTradePrice("security-1")
+	testutil.AssertNoError(t, err, "Last price should be computed")
+	testutil.AssertEqual(t, "99", last.String(), "Cancelled trades should not set the last price")
+
+	reference, err := provider.GetReferencePrice("security-1")
+	testutil.AssertNoError(t, err, "Reference price should be computed")
+	testutil.AssertEqual(t, "107.25", reference.String(), "VWAP should cover the last day")
+
+	testutil.AssertNoError(t, provider.SetConfig(Config{
+		ReferenceMethod:  ReferenceLastClose,
+		VWAPWindow:       time.Hour,
+		VolatilityWindow: 48 * time.Hour,
+		CacheTTL:         time.Minute,
+	}), "Config should be valid")
+	testutil.AssertNoError(t, provider.Invalidate("security-1"), "Invalidation should succeed")
+	reference, err = provider.GetReferencePrice("security-1")
+	testutil.AssertNoError(t, err, "Reference price should be computed")
+	testutil.AssertEqual(t, "100", reference.String(), "Last close should be Tuesday's last trade")
+
+	volatility, err := provider.GetVolatility("security-1", 0)
+	testutil.AssertNoError(t, err, "Volatility should be computed")
+	r1, r2 := math.Log(1.1), math.Log(0.9)
+	mean := (r1 + r2) / 2
+	expected := math.Sqrt((r1-mean)*(r1-mean) + (r2-mean)*(r2-mean))
+	testutil.AssertTrue(t, math.Abs(volatility-expected) < 1e-9, "Volatility should be the deviation of log returns")
+
+	volatility, err = provider.GetVolatility("security-1", 90*time.Minute)
+	testutil.AssertNoError(t, err, "Volatility should be computed")
+	testutil.AssertEqual(t, 0.0, volatility, "Too few trades should give no volatility")
+}
+
+func TestProvider_MarksOverrideTradesAndCacheIsInvalidated(t *testing.T) {
+	// Arrange
+	trades := &stubTrades{}
+	trades.add("100", 10, time.Hour)
+	provider := newTestProvider(trades)
+
+	// Act & Assert
+	for i := 0; i < 3; i++ {
+		_, err := provider.GetLastTradePrice("security-1")
+		testutil.AssertNoError(t, err, "Last price should be computed")
+	}
+	testutil.AssertEqual(t, 1, trades.lookups, "Repeated reads should be served from the cache")
+
+	newer := trades.add("120", 10, time.Minute)
+	last, _ := provider.GetLastTradePrice("security-1")
+	testutil.AssertEqual(t, "100", last.String(), "Cache should hold until the security trades")
+
+	newerAmount, err := newer.TradePrice.MulInt(10)
+	testutil.AssertNoError(t, err, "Trade should be valued")
+	matched := execution.NewTradeMatched(newer.ID, "", nil, "buyer-1", "seller-1", "security-1", 10, newer.TradePrice, money.New(newerAmount, "USD"), testNow, "continuous")
+	testutil.AssertNoError(t, provider.HandleTradeEvent(matched), "Trade event should be handled")
+	last, _ = provider.GetLastTradePrice("security-1")
+	testutil.AssertEqual(t, "120", last.String(), "Trades should invalidate the cache")
+
+	newer.Status = execution.TradeStatusCancelled
+	cancelled := execution.NewTradeCancelled(newer.ID, "error", "admin", testNow)
+	testutil.AssertNoError(t, provider.HandleTradeEvent(cancelled), "Cancellation should be handled")
+	last, _ = provider.GetLastTradePrice("security-1")
+	testutil.AssertEqual(t, "100", last.String(), "Cancelled trades should invalidate the cache")
+
+	testutil.AssertNoError(t, provider.SetMark(&Mark{SecurityID: "security-1", Price: money.NewDecimalFromInt(80), SetBy: "admin"}), "Mark should save")
+	reference, _ := provider.GetReferencePrice("security-1")
+	testutil.AssertEqual(t, "80", reference.String(), "Marks should override the computed reference price")
+
+	testutil.AssertNoError(t, provider.DeleteMark("security-1"), "Mark should delete")
+	reference, _ = provider.GetReferencePrice("security-1")
+	testutil.AssertEqual(t, "100", reference.String(), "Reference price should come from trades again")
+}
+
+// stubBooks serves fixed tops of book
+type stubBooks []*execution.TopOfBook
+
+func (b stubBooks) GetTopsOfBook() ([]*execution.TopOfBook, error) {
+	return b, nil
+}
+
+func TestQuoteRecorder_WritesBooksThatChanged(t *testing.T) {
+	// Arrange
+	books := stubBooks{
+		{SecurityID: "security-1", BestBid: &execution.DepthLevel{Price: money.MustParseDecimal("9.5"), Quantity: 100, OrderCount: 1}},
+		{SecurityID: "security-2"},
+	}
+	store := NewInMemoryQuoteStore()
+	recorder := NewQuoteRecorder(books, store, nil)
+	recorder.now = func() time.Time { return testNow }
+
+	// Act
+	first, err := recorder.Record()
+	testutil.AssertNoError(t, err, "Quotes should be recorded")
+	unchanged, _ := recorder.Record()
+	books[0].BestAsk = &execution.DepthLevel{Price: money.MustParseDecimal("10"), Quantity: 50, OrderCount: 2}
+	changed, _ := recorder.Record()
+
+	// Assert
+	testutil.AssertEqual(t, 2, first, "Every book should be written the first time")
+	testutil.AssertEqual(t, 0, unchanged, "Unchanged books should not be written again")
+	testutil.AssertEqual(t, 1, changed, "Only the book that changed should be written")
+	quote := store.GetQuote("security-1")
+	testutil.AssertEqual(t, "10", quote.BestAsk.Price.String(), "Best ask should be recorded")
+	testutil.AssertEqual(t, int64(100), quote.BestBid.Quantity, "Best bid volume should be recorded")
+	testutil.AssertTimeEqual(t, time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), quote.TradeDate, "Quote should be recorded for the trade date")
+	testutil.AssertNil(t, store.GetQuote("security-2").BestBid, "Empty sides should be recorded as empty")
+}
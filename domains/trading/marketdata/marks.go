@@ -0,0 +1,180 @@
+package marketdata
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+)
+
+// Mark is a valuation an administrator sets for a security that trades too
+// rarely for a reference price to be computed from its trades
+type Mark struct {
+	SecurityID string        `json:"securityId"`
+	Price      money.Decimal `json:"price"`
+	Note       string        `json:"note,omitempty"`
+	SetBy      string        `json:"setBy"`
+	SetAt      time.Time     `json:"setAt"`
+}
+
+// Validate checks that the mark can be used as a reference price
+func (m *Mark) Validate() error {
+	if m.SecurityID == "" {
+		return fmt.Errorf("security ID is required")
+	}
+	if !m.Price.IsPositive() {
+		return fmt.Errorf("mark price must be positive")
+	}
+	return nil
+}
+
+// MarkStore keeps valuation marks
+type MarkStore interface {
+	GetMark(securityID string) (*Mark, error) // nil when the security has no mark
+	SaveMark(mark *Mark) error
+	DeleteMark(securityID string) error
+}
+
+// PostgresMarkStore keeps valuation marks in the valuation_marks table
+type PostgresMarkStore struct {
+	db *sql.DB
+}
+
+// NewPostgresMarkStore creates a new PostgreSQL-backed mark store
+func NewPostgresMarkStore(db *sql.DB) *PostgresMarkStore {
+	return &PostgresMarkStore{db: db}
+}
+
+// GetMark returns the mark of a security, or nil when it has none
+func (s *PostgresMarkStore) GetMark(securityID string) (*Mark, error) {
+	var mark Mark
+	var note sql.NullString
+	err := s.db.QueryRow(`
+		SELECT security_id, mark_price, note, set_by, set_at
+		FROM valuation_marks
+		WHERE security_id = $1
+	`, securityID).Scan(&mark.SecurityID, &mark.Price, &note, &mark.SetBy, &mark.SetAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query valuation mark: %w", err)
+	}
+	mark.Note = note.String
+	return &mark, nil
+}
+
+// SaveMark sets or replaces the mark of a security
+func (s *PostgresMarkStore) SaveMark(mark *Mark) error {
+	if err := mark.Validate(); err != nil {
+		return err
+	}
+	if mark.SetAt.IsZero() {
+		mark.SetAt = time.Now()
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO valuation_marks (security_id, mark_price, note, set_by, set_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (security_id) DO UPDATE SET
+			mark_price = EXCLUDED.mark_price,
+			note = EXCLUDED.note,
+			set_by = EXCLUDED.set_by,
+			set_at = EXCLUDED.set_at
+	`, mark.SecurityID, mark.Price, mark.Note, mark.SetBy, mark.SetAt)
+	if err != nil {
+		return fmt.Errorf("failed to save valuation mark: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMark removes the mark of a security
+func (s *PostgresMarkStore) DeleteMark(securityID string) error {
+	result, err := s.db.Exec(`DELETE FROM valuation_marks WHERE security_id = $1`, securityID)
+	if err != nil {
+		return fmt.Errorf("failed to delete valuation mark: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err == nil && deleted == 0 {
+		return fmt.Errorf("no valuation mark for %s", securityID)
+	}
+	return nil
+}
+
+// InMemoryMarkStore keeps valuation marks in memory for testing and development
+type InMemoryMarkStore struct {
+	mu    sync.RWMutex
+	marks map[string]Mark
+}
+
+// NewInMemoryMarkStore creates a new in-memory mark store
+func NewInMemoryMarkStore() *InMemoryMarkStore {
+	return &InMemoryMarkStore{
+		marks: make(map[string]Mark),
+	}
+}
+
+// GetMark returns the mark of a security, or nil when it has none
+func (s *InMemoryMarkStore) GetMark(securityID string) (*Mark, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mark, ok := s.marks[securityID]
+	if !ok {
+		return nil, nil
+	}
+	return &mark, nil
+}
+
+// SaveMark sets or replaces the mark of a security
+func (s *InMemoryMarkStore) SaveMark(mark *Mark) error {
+	if err := mark.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if mark.SetAt.IsZero() {
+		mark.SetAt = time.Now()
+	}
+	s.marks[mark.SecurityID] = *mark
+	return nil
+}
+
+// DeleteMark removes the mark of a security
+func (s *InMemoryMarkStore) DeleteMark(securityID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.marks[securityID]; !ok {
+		return fmt.Errorf("no valuation mark for %s", securityID)
+	}
+	delete(s.marks, securityID)
+	return nil
+}
+
+// SetMark sets a security's valuation mark and drops its cached reference
+// price
+func (p *Provider) SetMark(mark *Mark) error {
+	if err := p.marks.SaveMark(mark); err != nil {
+		return err
+	}
+	return p.Invalidate(mark.SecurityID)
+}
+
+// DeleteMark removes a security's valuation mark and drops its cached
+// reference price
+func (p *Provider) DeleteMark(securityID string) error {
+	if err := p.marks.DeleteMark(securityID); err != nil {
+		return err
+	}
+	return p.Invalidate(securityID)
+}
+
+// GetMark returns a security's valuation mark, or nil when it has none
+func (p *Provider) GetMark(securityID string) (*Mark, error) {
+	return p.marks.GetMark(securityID)
+}
@@ -0,0 +1,257 @@
+package marketdata
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/storage"
+	"securities-marketplace/domains/trading/calendar"
+	"securities-marketplace/domains/trading/execution"
+)
+
+// ReferenceMethod is how a reference price is computed from trades
+type ReferenceMethod string
+
+const (
+	ReferenceVWAP      ReferenceMethod = "vwap"       // Volume-weighted average price over the VWAP window
+	ReferenceLastClose ReferenceMethod = "last_close" // Last trade price at the close of the previous session
+)
+
+// Config sets how the provider computes and caches market data
+type Config struct {
+	ReferenceMethod  ReferenceMethod `json:"referenceMethod"`
+	VWAPWindow       time.Duration   `json:"vwapWindow"`
+	VolatilityWindow time.Duration   `json:"volatilityWindow"` // Used when GetVolatility is asked for no period
+	CacheTTL         time.Duration   `json:"cacheTtl"`         // Bounds how stale sliding windows and session closes get
+}
+
+// DefaultConfig uses a one-day VWAP as the reference price
+func DefaultConfig() Config {
+	return Config{
+		ReferenceMethod:  ReferenceVWAP,
+		VWAPWindow:       24 * time.Hour,
+		VolatilityWindow: 30 * 24 * time.Hour,
+		CacheTTL:         5 * time.Minute,
+	}
+}
+
+// Validate checks that the config can be used
+func (c Config) Validate() error {
+	if c.ReferenceMethod != ReferenceVWAP && c.ReferenceMethod != ReferenceLastClose {
+		return fmt.Errorf("unknown reference method %q", c.ReferenceMethod)
+	}
+	if c.VWAPWindow <= 0 || c.VolatilityWindow <= 0 {
+		return fmt.Errorf("windows must be positive")
+	}
+	if c.CacheTTL <= 0 {
+		return fmt.Errorf("cache TTL must be positive")
+	}
+	return nil
+}
+
+// Provider computes market data from trade history. Results are cached and
+// invalidated when the security trades; see Subscribe. It implements
+// execution.MarketDataProvider.
+type Provider struct {
+	trades   execution.TradeRepository
+	marks    MarkStore
+	cache    storage.Cache
+	calendar *calendar.Calendar
+	config   Config
+	now      func() time.Time
+}
+
+var _ execution.MarketDataProvider = (*Provider)(nil)
+
+// NewProvider creates a new market data provider with the default config.
+// Without a trading calendar the default calendar decides market hours.
+func NewProvider(trades execution.TradeRepository, marks MarkStore, cache storage.Cache, cal *calendar.Calendar) *Provider {
+	if cal == nil {
+		cal = calendar.Default()
+	}
+	return &Provider{
+		trades:   trades,
+		marks:    marks,
+		cache:    cache,
+		calendar: cal,
+		config:   DefaultConfig(),
+		now:      time.Now,
+	}
+}
+
+// SetConfig replaces the provider's config
+func (p *Provider) SetConfig(config Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	p.config = config
+	return nil
+}
+
+// GetLastTradePrice returns the price of the security's most recent trade,
+// or zero when it has never traded
+func (p *Provider) GetLastTradePrice(securityID string) (money.Decimal, error) {
+	return p.cachedDecimal(securityID, "last", func() (money.Decimal, error) {
+		trades, err := p.tradesBetween(securityID, time.Time{}, p.now())
+		if err != nil {
+			return money.Zero, err
+		}
+		if len(trades) == 0 {
+			return money.Zero, nil
+		}
+		return trades[len(trades)-1].TradePrice, nil
+	})
+}
+
+// GetMarketHours returns the current or last trading session from the
+// trading calendar
+func (p *Provider) GetMarketHours() (open, close time.Time, isOpen bool) {
+	return p.calendar.MarketHours(p.now())
+}
+
+// GetVolatility returns the realized volatility of the security over the
+// period: the standard deviation of the log returns between its consecutive
+// trades, not annualized. It is zero with fewer than three trades.
+func (p *Provider) GetVolatility(securityID string, period time.Duration) (float64, error) {
+	if period <= 0 {
+		period = p.config.VolatilityWindow
+	}
+
+	return p.cachedFloat(securityID, "volatility:"+period.String(), func() (float64, error) {
+		now := p.now()
+		trades, err := p.tradesBetween(securityID, now.Add(-period), now)
+		if err != nil {
+			return 0, err
+		}
+		return realizedVolatility(trades), nil
+	})
+}
+
+// GetReferencePrice returns the security's valuation mark when an
+// administrator has set one, and otherwise its VWAP or last close as the
+// config says. When the configured method has no trades to use it falls back
+// to the last trade price, and is zero for securities that never traded.
+func (p *Provider) GetReferencePrice(securityID string) (money.Decimal, error) {
+	return p.cachedDecimal(securityID, "reference", func() (money.Decimal, error) {
+		mark, err := p.marks.GetMark(securityID)
+		if err != nil {
+			return money.Zero, fmt.Errorf("failed to get valuation mark: %w", err)
+		}
+		if mark != nil {
+			return mark.Price, nil
+		}
+
+		var price money.Decimal
+		switch p.config.ReferenceMethod {
+		case ReferenceLastClose:
+			price, err = p.lastClose(securityID)
+		default:
+			price, err = p.vwap(securityID)
+		}
+		if err != nil || price.IsPositive() {
+			return price, err
+		}
+		return p.GetLastTradePrice(securityID)
+	})
+}
+
+// vwap returns the volume-weighted average price over the VWAP window, or
+// zero when the security did not trade in it
+func (p *Provider) vwap(securityID string) (money.Decimal, error) {
+	now := p.now()
+	trades, err := p.tradesBetween(securityID, now.Add(-p.config.VWAPWindow), now)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	value, shares := money.Zero, int64(0)
+	for _, trade := range trades {
+		traded, err := trade.TradePrice.MulInt(trade.SharesTraded)
+		if err != nil {
+			return money.Zero, fmt.Errorf("failed to calculate VWAP: %w", err)
+		}
+		if value, err = value.Add(traded); err != nil {
+			return money.Zero, fmt.Errorf("failed to calculate VWAP: %w", err)
+		}
+		shares += trade.SharesTraded
+	}
+	if shares == 0 {
+		return money.Zero, nil
+	}
+	return value.DivInt(shares, money.RoundHalfEven)
+}
+
+// lastClose returns the last trade price at the close of the previous
+// session, or of the session that just closed, or zero when there is none
+func (p *Provider) lastClose(securityID string) (money.Decimal, error) {
+	open, close, isOpen := p.calendar.MarketHours(p.now())
+	cutoff := close
+	if isOpen {
+		cutoff = open
+	}
+
+	trades, err := p.tradesBetween(securityID, time.Time{}, cutoff)
+	if err != nil {
+		return money.Zero, err
+	}
+	if len(trades) == 0 {
+		return money.Zero, nil
+	}
+	return trades[len(trades)-1].TradePrice, nil
+}
+
+// tradesBetween returns the security's trades matched in the period that
+// still stand, oldest first
+func (p *Provider) tradesBetween(securityID string, from, to time.Time) ([]*execution.TradeAggregate, error) {
+	all, err := p.trades.FindBySecurity(securityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trades of %s: %w", securityID, err)
+	}
+
+	var trades []*execution.TradeAggregate
+	for _, trade := range all {
+		if trade.IsVoid() {
+			continue
+		}
+		if trade.MatchedAt.Before(from) || trade.MatchedAt.After(to) {
+			continue
+		}
+		trades = append(trades, trade)
+	}
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].MatchedAt.Before(trades[j].MatchedAt)
+	})
+	return trades, nil
+}
+
+// realizedVolatility returns the sample standard deviation of the log
+// returns between consecutive trades
+func realizedVolatility(trades []*execution.TradeAggregate) float64 {
+	var returns []float64
+	for i := 1; i < len(trades); i++ {
+		previous, current := trades[i-1].TradePrice.Float64(), trades[i].TradePrice.Float64()
+		if previous <= 0 || current <= 0 {
+			continue
+		}
+		returns = append(returns, math.Log(current/previous))
+	}
+	if len(returns) < 2 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns) - 1)
+	return math.Sqrt(variance)
+}
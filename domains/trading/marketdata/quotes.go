@@ -0,0 +1,177 @@
+package marketdata
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/calendar"
+	"securities-marketplace/domains/trading/execution"
+)
+
+// Quote is the top of a security's book on a trade date
+type Quote struct {
+	SecurityID string                `json:"securityId"`
+	TradeDate  time.Time             `json:"tradeDate"`
+	BestBid    *execution.DepthLevel `json:"bestBid,omitempty"`
+	BestAsk    *execution.DepthLevel `json:"bestAsk,omitempty"`
+	Spread     *money.Decimal        `json:"spread,omitempty"`
+}
+
+// QuoteStore keeps the latest quote of each security per trade date
+type QuoteStore interface {
+	SaveQuote(quote *Quote) error
+}
+
+// PostgresQuoteStore writes quotes to the daily rows of the
+// market_data_projection table
+type PostgresQuoteStore struct {
+	db *sql.DB
+}
+
+// NewPostgresQuoteStore creates a new PostgreSQL-backed quote store
+func NewPostgresQuoteStore(db *sql.DB) *PostgresQuoteStore {
+	return &PostgresQuoteStore{db: db}
+}
+
+// SaveQuote sets the best bid and ask of the security's daily row, creating
+// the row when the security has not traded or quoted that day
+func (s *PostgresQuoteStore) SaveQuote(quote *Quote) error {
+	var bestBid, bestAsk *money.Decimal
+	var bidVolume, askVolume int64
+	if quote.BestBid != nil {
+		bestBid, bidVolume = &quote.BestBid.Price, quote.BestBid.Quantity
+	}
+	if quote.BestAsk != nil {
+		bestAsk, askVolume = &quote.BestAsk.Price, quote.BestAsk.Quantity
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO market_data_projection (security_id, period_type, period_start, period_end, best_bid, best_ask, bid_volume, ask_volume, spread)
+		VALUES ($1, 'daily', $2, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (security_id, period_type, period_start) DO UPDATE SET
+			best_bid = EXCLUDED.best_bid,
+			best_ask = EXCLUDED.best_ask,
+			bid_volume = EXCLUDED.bid_volume,
+			ask_volume = EXCLUDED.ask_volume,
+			spread = EXCLUDED.spread
+	`, quote.SecurityID, quote.TradeDate, bestBid, bestAsk, bidVolume, askVolume, quote.Spread)
+	if err != nil {
+		return fmt.Errorf("failed to save quote of %s: %w", quote.SecurityID, err)
+	}
+	return nil
+}
+
+// InMemoryQuoteStore keeps quotes in memory for testing and development
+type InMemoryQuoteStore struct {
+	mu     sync.RWMutex
+	quotes map[string]Quote
+}
+
+// NewInMemoryQuoteStore creates a new in-memory quote store
+func NewInMemoryQuoteStore() *InMemoryQuoteStore {
+	return &InMemoryQuoteStore{
+		quotes: make(map[string]Quote),
+	}
+}
+
+// SaveQuote replaces the security's quote
+func (s *InMemoryQuoteStore) SaveQuote(quote *Quote) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.quotes[quote.SecurityID] = *quote
+	return nil
+}
+
+// GetQuote returns the security's latest quote, or nil when it has none
+func (s *InMemoryQuoteStore) GetQuote(securityID string) *Quote {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	quote, ok := s.quotes[securityID]
+	if !ok {
+		return nil
+	}
+	return &quote
+}
+
+// BookSource reports the top of every live order book. It is implemented by
+// execution.ExecutionService.
+type BookSource interface {
+	GetTopsOfBook() ([]*execution.TopOfBook, error)
+}
+
+var _ BookSource = (*execution.ExecutionService)(nil)
+
+// QuoteRecorder copies the top of each live order book to the quote store
+// whenever it changes
+type QuoteRecorder struct {
+	books    BookSource
+	store    QuoteStore
+	calendar *calendar.Calendar
+	recorded map[string]recordedQuote
+	now      func() time.Time
+}
+
+// recordedQuote is what was last written for a security, to skip unchanged
+// books
+type recordedQuote struct {
+	tradeDate        string
+	bestBid, bestAsk execution.DepthLevel
+}
+
+// NewQuoteRecorder creates a new quote recorder. Without a trading calendar
+// the default calendar decides trade dates.
+func NewQuoteRecorder(books BookSource, store QuoteStore, cal *calendar.Calendar) *QuoteRecorder {
+	if cal == nil {
+		cal = calendar.Default()
+	}
+	return &QuoteRecorder{
+		books:    books,
+		store:    store,
+		calendar: cal,
+		recorded: make(map[string]recordedQuote),
+		now:      time.Now,
+	}
+}
+
+// Record writes the quote of every book that changed since the last record,
+// and returns how many were written
+func (r *QuoteRecorder) Record() (int, error) {
+	tops, err := r.books.GetTopsOfBook()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get top of book: %w", err)
+	}
+
+	tradeDate := r.calendar.TradeDate(r.now())
+	written := 0
+	for _, top := range tops {
+		current := recordedQuote{tradeDate: tradeDate.Format("2006-01-02")}
+		if top.BestBid != nil {
+			current.bestBid = *top.BestBid
+		}
+		if top.BestAsk != nil {
+			current.bestAsk = *top.BestAsk
+		}
+		if last, ok := r.recorded[top.SecurityID]; ok && last == current {
+			continue
+		}
+
+		quote := &Quote{
+			SecurityID: top.SecurityID,
+			TradeDate:  tradeDate,
+			BestBid:    top.BestBid,
+			BestAsk:    top.BestAsk,
+			Spread:     top.Spread,
+		}
+		if err := r.store.SaveQuote(quote); err != nil {
+			return written, err
+		}
+		r.recorded[top.SecurityID] = current
+		written++
+	}
+	return written, nil
+}
@@ -0,0 +1,271 @@
+package negotiation
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+)
+
+// NegotiationStatus represents the current status of a negotiation
+type NegotiationStatus string
+
+const (
+	NegotiationStatusOpen     NegotiationStatus = "open"
+	NegotiationStatusAccepted NegotiationStatus = "accepted"
+	NegotiationStatusRejected NegotiationStatus = "rejected"
+)
+
+// Offer is one proposal in a negotiation thread
+type Offer struct {
+	ProposedBy string        `json:"proposedBy"`
+	Shares     int64         `json:"shares"`
+	Price      money.Decimal `json:"price"`
+	Message    string        `json:"message,omitempty"`
+	ProposedAt time.Time     `json:"proposedAt"`
+}
+
+// NegotiationAggregate is a one-to-one negotiation between a buyer and the
+// seller of a specific listing. The parties take turns: only the party that
+// did not make the latest offer can counter or accept it.
+type NegotiationAggregate struct {
+	events.AggregateRoot
+
+	// Parties and subject
+	ListingID  string `json:"listingId"`
+	SecurityID string `json:"securityId"`
+	SellerID   string `json:"sellerId"`
+	BuyerID    string `json:"buyerId"`
+
+	// Thread of offers, oldest first
+	Offers []Offer `json:"offers"`
+
+	// Status and lifecycle
+	Status   NegotiationStatus `json:"status"`
+	OpenedAt time.Time         `json:"openedAt"`
+	ClosedAt *time.Time        `json:"closedAt,omitempty"`
+
+	// Outcome
+	AcceptedBy      string `json:"acceptedBy,omitempty"`
+	TradeID         string `json:"tradeId,omitempty"`
+	RejectedBy      string `json:"rejectedBy,omitempty"`
+	RejectionReason string `json:"rejectionReason,omitempty"`
+}
+
+// NewNegotiationAggregate creates a new negotiation aggregate
+func NewNegotiationAggregate(negotiationID string) *NegotiationAggregate {
+	return &NegotiationAggregate{
+		AggregateRoot: events.NewAggregateRoot(negotiationID, "Negotiation"),
+		Status:        NegotiationStatusOpen,
+		Offers:        make([]Offer, 0),
+	}
+}
+
+// Open starts a negotiation with the buyer's first offer
+func (n *NegotiationAggregate) Open(listingID, securityID, sellerID, buyerID string, shares int64, price money.Decimal, message string) error {
+	if n.Version > 0 {
+		return fmt.Errorf("negotiation already exists")
+	}
+
+	if buyerID == sellerID {
+		return fmt.Errorf("seller cannot negotiate on their own listing")
+	}
+
+	if err := validateOffer(shares, price); err != nil {
+		return err
+	}
+
+	event := NewNegotiationOpened(n.ID, listingID, securityID, sellerID, buyerID, shares, price, message)
+	n.AddEvent(event)
+	return n.ApplyEvent(event)
+}
+
+// Counter answers the latest offer with a new one
+func (n *NegotiationAggregate) Counter(proposedBy string, shares int64, price money.Decimal, message string) error {
+	if err := n.checkTurn(proposedBy); err != nil {
+		return err
+	}
+
+	if err := validateOffer(shares, price); err != nil {
+		return err
+	}
+
+	event := NewNegotiationCountered(n.ID, proposedBy, shares, price, message)
+	n.AddEvent(event)
+	return n.ApplyEvent(event)
+}
+
+// Accept accepts the latest offer. The trade ID is recorded so the
+// negotiation can be traced to the trade it created.
+func (n *NegotiationAggregate) Accept(acceptedBy, tradeID string) error {
+	if err := n.checkTurn(acceptedBy); err != nil {
+		return err
+	}
+
+	if tradeID == "" {
+		return fmt.Errorf("trade ID is required")
+	}
+
+	offer := n.LatestOffer()
+	event := NewNegotiationAccepted(n.ID, acceptedBy, offer.Shares, offer.Price, tradeID)
+	n.AddEvent(event)
+	return n.ApplyEvent(event)
+}
+
+// Reject ends the negotiation without a trade. Either party can walk away at
+// any point.
+func (n *NegotiationAggregate) Reject(rejectedBy, reason string) error {
+	if n.Status != NegotiationStatusOpen {
+		return fmt.Errorf("can only reject open negotiations")
+	}
+
+	if !n.IsParticipant(rejectedBy) {
+		return fmt.Errorf("only the buyer or seller can reject the negotiation")
+	}
+
+	if reason == "" {
+		return fmt.Errorf("rejection reason is required")
+	}
+
+	event := NewNegotiationRejected(n.ID, rejectedBy, reason)
+	n.AddEvent(event)
+	return n.ApplyEvent(event)
+}
+
+// ApplyEvent applies an event to the aggregate
+func (n *NegotiationAggregate) ApplyEvent(event events.DomainEvent) error {
+	switch e := event.(type) {
+	case *NegotiationOpened:
+		return n.applyNegotiationOpened(e)
+	case *NegotiationCountered:
+		return n.applyNegotiationCountered(e)
+	case *NegotiationAccepted:
+		return n.applyNegotiationAccepted(e)
+	case *NegotiationRejected:
+		return n.applyNegotiationRejected(e)
+	default:
+		return fmt.Errorf("unknown event type: %T", event)
+	}
+}
+
+// LoadFromHistory loads the aggregate from a sequence of events
+func (n *NegotiationAggregate) LoadFromHistory(events []events.DomainEvent) error {
+	for _, event := range events {
+		if err := n.ApplyEvent(event); err != nil {
+			return fmt.Errorf("failed to apply event %s: %w", event.GetEventType(), err)
+		}
+		n.IncrementVersion()
+	}
+	return nil
+}
+
+// Event application methods
+
+func (n *NegotiationAggregate) applyNegotiationOpened(event *NegotiationOpened) error {
+	n.ListingID = event.ListingID
+	n.SecurityID = event.SecurityID
+	n.SellerID = event.SellerID
+	n.BuyerID = event.BuyerID
+	n.Status = NegotiationStatusOpen
+	n.OpenedAt = event.Timestamp
+	n.Offers = append(n.Offers, Offer{
+		ProposedBy: event.BuyerID,
+		Shares:     event.Shares,
+		Price:      event.Price,
+		Message:    event.Message,
+		ProposedAt: event.Timestamp,
+	})
+
+	n.IncrementVersion()
+	return nil
+}
+
+func (n *NegotiationAggregate) applyNegotiationCountered(event *NegotiationCountered) error {
+	n.Offers = append(n.Offers, Offer{
+		ProposedBy: event.ProposedBy,
+		Shares:     event.Shares,
+		Price:      event.Price,
+		Message:    event.Message,
+		ProposedAt: event.Timestamp,
+	})
+
+	n.IncrementVersion()
+	return nil
+}
+
+func (n *NegotiationAggregate) applyNegotiationAccepted(event *NegotiationAccepted) error {
+	n.Status = NegotiationStatusAccepted
+	n.AcceptedBy = event.AcceptedBy
+	n.TradeID = event.TradeID
+	n.ClosedAt = &event.Timestamp
+
+	n.IncrementVersion()
+	return nil
+}
+
+func (n *NegotiationAggregate) applyNegotiationRejected(event *NegotiationRejected) error {
+	n.Status = NegotiationStatusRejected
+	n.RejectedBy = event.RejectedBy
+	n.RejectionReason = event.Reason
+	n.ClosedAt = &event.Timestamp
+
+	n.IncrementVersion()
+	return nil
+}
+
+// Helper methods
+
+// LatestOffer returns the offer currently on the table, or nil before the
+// negotiation is opened
+func (n *NegotiationAggregate) LatestOffer() *Offer {
+	if len(n.Offers) == 0 {
+		return nil
+	}
+	return &n.Offers[len(n.Offers)-1]
+}
+
+// IsParticipant returns true if the user is the buyer or the seller
+func (n *NegotiationAggregate) IsParticipant(userID string) bool {
+	return userID != "" && (userID == n.BuyerID || userID == n.SellerID)
+}
+
+// AwaitingResponseFrom returns the party expected to answer the latest offer
+func (n *NegotiationAggregate) AwaitingResponseFrom() string {
+	if n.Status != NegotiationStatusOpen {
+		return ""
+	}
+	if n.LatestOffer().ProposedBy == n.BuyerID {
+		return n.SellerID
+	}
+	return n.BuyerID
+}
+
+// checkTurn verifies that the user may answer the latest offer
+func (n *NegotiationAggregate) checkTurn(userID string) error {
+	if n.Status != NegotiationStatusOpen {
+		return fmt.Errorf("negotiation is %s", n.Status)
+	}
+
+	if !n.IsParticipant(userID) {
+		return fmt.Errorf("only the buyer or seller can respond to an offer")
+	}
+
+	if userID != n.AwaitingResponseFrom() {
+		return fmt.Errorf("waiting for the other party to respond to the latest offer")
+	}
+
+	return nil
+}
+
+func validateOffer(shares int64, price money.Decimal) error {
+	if shares <= 0 {
+		return fmt.Errorf("shares must be greater than zero")
+	}
+
+	if !price.IsPositive() {
+		return fmt.Errorf("price must be greater than zero")
+	}
+
+	return nil
+}
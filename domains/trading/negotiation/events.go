@@ -0,0 +1,171 @@
+package negotiation
+
+import (
+	"encoding/json"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+)
+
+// Negotiation Domain Events
+
+// NegotiationOpened event is emitted when a buyer makes an offer against a
+// specific listing
+type NegotiationOpened struct {
+	events.BaseEvent
+	ListingID  string        `json:"listingId"`
+	SecurityID string        `json:"securityId"`
+	SellerID   string        `json:"sellerId"`
+	BuyerID    string        `json:"buyerId"`
+	Shares     int64         `json:"shares"`
+	Price      money.Decimal `json:"price"`
+	Message    string        `json:"message,omitempty"`
+}
+
+// NewNegotiationOpened creates a new NegotiationOpened event
+func NewNegotiationOpened(negotiationID, listingID, securityID, sellerID, buyerID string, shares int64, price money.Decimal, message string) *NegotiationOpened {
+	return &NegotiationOpened{
+		BaseEvent:  events.NewBaseEvent(negotiationID, "Negotiation"),
+		ListingID:  listingID,
+		SecurityID: securityID,
+		SellerID:   sellerID,
+		BuyerID:    buyerID,
+		Shares:     shares,
+		Price:      price,
+		Message:    message,
+	}
+}
+
+func (e *NegotiationOpened) GetEventType() string     { return "NegotiationOpened" }
+func (e *NegotiationOpened) GetAggregateID() string   { return e.AggregateID }
+func (e *NegotiationOpened) GetAggregateType() string { return e.AggregateType }
+
+func (e *NegotiationOpened) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *NegotiationOpened) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// NegotiationCountered event is emitted when either party answers the latest
+// offer with a new one
+type NegotiationCountered struct {
+	events.BaseEvent
+	ProposedBy string        `json:"proposedBy"`
+	Shares     int64         `json:"shares"`
+	Price      money.Decimal `json:"price"`
+	Message    string        `json:"message,omitempty"`
+}
+
+// NewNegotiationCountered creates a new NegotiationCountered event
+func NewNegotiationCountered(negotiationID, proposedBy string, shares int64, price money.Decimal, message string) *NegotiationCountered {
+	return &NegotiationCountered{
+		BaseEvent:  events.NewBaseEvent(negotiationID, "Negotiation"),
+		ProposedBy: proposedBy,
+		Shares:     shares,
+		Price:      price,
+		Message:    message,
+	}
+}
+
+func (e *NegotiationCountered) GetEventType() string     { return "NegotiationCountered" }
+func (e *NegotiationCountered) GetAggregateID() string   { return e.AggregateID }
+func (e *NegotiationCountered) GetAggregateType() string { return e.AggregateType }
+
+func (e *NegotiationCountered) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *NegotiationCountered) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// NegotiationAccepted event is emitted when the latest offer is accepted and
+// the trade it creates is booked
+type NegotiationAccepted struct {
+	events.BaseEvent
+	AcceptedBy string        `json:"acceptedBy"`
+	Shares     int64         `json:"shares"`
+	Price      money.Decimal `json:"price"`
+	TradeID    string        `json:"tradeId"`
+}
+
+// NewNegotiationAccepted creates a new NegotiationAccepted event
+func NewNegotiationAccepted(negotiationID, acceptedBy string, shares int64, price money.Decimal, tradeID string) *NegotiationAccepted {
+	return &NegotiationAccepted{
+		BaseEvent:  events.NewBaseEvent(negotiationID, "Negotiation"),
+		AcceptedBy: acceptedBy,
+		Shares:     shares,
+		Price:      price,
+		TradeID:    tradeID,
+	}
+}
+
+func (e *NegotiationAccepted) GetEventType() string     { return "NegotiationAccepted" }
+func (e *NegotiationAccepted) GetAggregateID() string   { return e.AggregateID }
+func (e *NegotiationAccepted) GetAggregateType() string { return e.AggregateType }
+
+func (e *NegotiationAccepted) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *NegotiationAccepted) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// NegotiationRejected event is emitted when either party walks away
+type NegotiationRejected struct {
+	events.BaseEvent
+	RejectedBy string `json:"rejectedBy"`
+	Reason     string `json:"reason"`
+}
+
+// NewNegotiationRejected creates a new NegotiationRejected event
+func NewNegotiationRejected(negotiationID, rejectedBy, reason string) *NegotiationRejected {
+	return &NegotiationRejected{
+		BaseEvent:  events.NewBaseEvent(negotiationID, "Negotiation"),
+		RejectedBy: rejectedBy,
+		Reason:     reason,
+	}
+}
+
+func (e *NegotiationRejected) GetEventType() string     { return "NegotiationRejected" }
+func (e *NegotiationRejected) GetAggregateID() string   { return e.AggregateID }
+func (e *NegotiationRejected) GetAggregateType() string { return e.AggregateType }
+
+func (e *NegotiationRejected) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *NegotiationRejected) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
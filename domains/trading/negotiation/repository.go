@@ -0,0 +1,110 @@
+package negotiation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"securities-marketplace/domains/shared/events"
+)
+
+// NegotiationRepository defines the interface for negotiation persistence
+type NegotiationRepository interface {
+	FindByID(negotiationID string) (*NegotiationAggregate, error)
+	FindByParticipant(userID string) ([]*NegotiationAggregate, error)
+}
+
+// EventSourcedNegotiationRepository implements NegotiationRepository using
+// event sourcing
+type EventSourcedNegotiationRepository struct {
+	eventStore events.EventStore
+}
+
+// NewEventSourcedNegotiationRepository creates a new event-sourced negotiation repository
+func NewEventSourcedNegotiationRepository(eventStore events.EventStore) *EventSourcedNegotiationRepository {
+	return &EventSourcedNegotiationRepository{
+		eventStore: eventStore,
+	}
+}
+
+// FindByID finds a negotiation by ID by replaying events
+func (r *EventSourcedNegotiationRepository) FindByID(negotiationID string) (*NegotiationAggregate, error) {
+	eventRecords, err := r.eventStore.GetEvents(negotiationID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	if len(eventRecords) == 0 {
+		return nil, fmt.Errorf("negotiation with id %s not found", negotiationID)
+	}
+
+	var domainEvents []events.DomainEvent
+	for _, eventRecord := range eventRecords {
+		domainEvent, err := r.convertEventRecordToDomainEvent(eventRecord)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert event %s: %w", eventRecord.EventType, err)
+		}
+		domainEvents = append(domainEvents, domainEvent)
+	}
+
+	negotiation := NewNegotiationAggregate(negotiationID)
+	err = negotiation.LoadFromHistory(domainEvents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load from history: %w", err)
+	}
+
+	negotiation.SetLastEventNumber(eventRecords[len(eventRecords)-1].EventNumber)
+
+	return negotiation, nil
+}
+
+// FindByParticipant finds every negotiation the user is the buyer or seller in
+func (r *EventSourcedNegotiationRepository) FindByParticipant(userID string) ([]*NegotiationAggregate, error) {
+	// This is inefficient for event sourcing - would need projection in real system
+	openedEvents, err := r.eventStore.GetEventsByType("NegotiationOpened", 1000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get negotiation events: %w", err)
+	}
+
+	var negotiations []*NegotiationAggregate
+	for _, eventRecord := range openedEvents {
+		domainEvent, err := r.convertEventRecordToDomainEvent(eventRecord)
+		if err != nil {
+			continue // Skip invalid events
+		}
+
+		opened := domainEvent.(*NegotiationOpened)
+		if opened.BuyerID != userID && opened.SellerID != userID {
+			continue
+		}
+
+		negotiation, err := r.FindByID(eventRecord.AggregateID)
+		if err != nil {
+			continue // Skip if can't load
+		}
+		negotiations = append(negotiations, negotiation)
+	}
+
+	return negotiations, nil
+}
+
+// convertEventRecordToDomainEvent converts a single event record to domain event
+func (r *EventSourcedNegotiationRepository) convertEventRecordToDomainEvent(eventRecord *events.Event) (events.DomainEvent, error) {
+	var event events.DomainEvent
+	switch eventRecord.EventType {
+	case "NegotiationOpened":
+		event = &NegotiationOpened{}
+	case "NegotiationCountered":
+		event = &NegotiationCountered{}
+	case "NegotiationAccepted":
+		event = &NegotiationAccepted{}
+	case "NegotiationRejected":
+		event = &NegotiationRejected{}
+	default:
+		return nil, fmt.Errorf("unknown event type: %s", eventRecord.EventType)
+	}
+
+	if err := json.Unmarshal(eventRecord.EventData, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize %s: %w", eventRecord.EventType, err)
+	}
+	return event, nil
+}
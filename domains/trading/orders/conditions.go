@@ -0,0 +1,104 @@
+package orders
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeInForce controls how long an order may rest in the book
+type TimeInForce string
+
+const (
+	TimeInForceGTC TimeInForce = "GTC" // Good till cancelled
+	TimeInForceGTD TimeInForce = "GTD" // Good till date: expires at the order's expiration
+	TimeInForceDAY TimeInForce = "DAY" // Expires at the close of the market session it was placed in
+	TimeInForceIOC TimeInForce = "IOC" // Immediate or cancel: any remainder is cancelled after the next matching run
+)
+
+// DefaultTimeInForce returns the time in force of an order placed without
+// one: good till date if it has an expiration, good till cancelled otherwise
+func DefaultTimeInForce(expiresAt *time.Time) TimeInForce {
+	if expiresAt != nil {
+		return TimeInForceGTD
+	}
+	return TimeInForceGTC
+}
+
+// Conditions restrict how a listing or bid may be executed and how long it
+// lives. They are set when the order is placed and recorded in its creation
+// event.
+type Conditions struct {
+	AllOrNone   bool        `json:"allOrNone,omitempty"`   // Every execution must fill the whole remaining quantity
+	MinQuantity int64       `json:"minQuantity,omitempty"` // Smallest execution accepted, unless it fills the remainder
+	FillOrKill  bool        `json:"fillOrKill,omitempty"`  // All-or-none, and cancelled if the first matching run cannot fill it
+	TimeInForce TimeInForce `json:"timeInForce,omitempty"`
+}
+
+// Validate checks the conditions against the order quantity
+func (c Conditions) Validate(quantity int64) error {
+	if c.MinQuantity < 0 {
+		return fmt.Errorf("minimum quantity cannot be negative")
+	}
+
+	if c.MinQuantity > quantity {
+		return fmt.Errorf("minimum quantity %d exceeds order quantity %d", c.MinQuantity, quantity)
+	}
+
+	return nil
+}
+
+// Resolve fills in the time in force of an order with the given expiration
+// and checks that the two agree. Fill-or-kill orders are always
+// immediate-or-cancel, and only good-till-date orders have an expiration.
+func (c Conditions) Resolve(expiresAt *time.Time) (Conditions, error) {
+	if c.TimeInForce == "" {
+		c.TimeInForce = DefaultTimeInForce(expiresAt)
+		if c.FillOrKill {
+			c.TimeInForce = TimeInForceIOC
+		}
+	}
+
+	switch c.TimeInForce {
+	case TimeInForceGTD:
+		if expiresAt == nil {
+			return c, fmt.Errorf("good-till-date orders need an expiration date")
+		}
+	case TimeInForceGTC, TimeInForceDAY, TimeInForceIOC:
+		if expiresAt != nil {
+			return c, fmt.Errorf("only good-till-date orders can have an expiration date")
+		}
+	default:
+		return c, fmt.Errorf("unknown time in force %q", c.TimeInForce)
+	}
+
+	if c.FillOrKill && c.TimeInForce != TimeInForceIOC {
+		return c, fmt.Errorf("fill-or-kill orders are immediate-or-cancel")
+	}
+
+	return c, nil
+}
+
+// RequiresFullFill returns true if the order cannot be partially filled
+func (c Conditions) RequiresFullFill() bool {
+	return c.AllOrNone || c.FillOrKill
+}
+
+// IsImmediate returns true if the order may not rest in the book after the
+// matching run that first sees it
+func (c Conditions) IsImmediate() bool {
+	return c.FillOrKill || c.TimeInForce == TimeInForceIOC
+}
+
+// AllowsFill returns true if a single execution of fill shares is acceptable
+// for an order with remaining shares left
+func (c Conditions) AllowsFill(fill, remaining int64) bool {
+	if fill <= 0 || fill > remaining {
+		return false
+	}
+
+	if fill == remaining {
+		return true
+	}
+
+	return !c.RequiresFullFill() && fill >= c.MinQuantity
+}
@@ -0,0 +1,16 @@
+package orders
+
+// EntryGate decides whether orders in a security can be entered or modified
+// at the moment, for example because the market session is closed. Orders
+// can always be cancelled.
+type EntryGate interface {
+	CheckOrderEntry(securityID string) error
+}
+
+// Sequencer runs commands that change a security's orders one at a time, in
+// the order matching sees them. The command is given a fence to check right
+// before it saves events; the fence fails once the command may no longer
+// write for the security.
+type Sequencer interface {
+	SequenceOrders(securityID string, command func(fence func() error) error) error
+}
@@ -0,0 +1,306 @@
+package rfq
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+)
+
+// RFQStatus represents the current status of a request for quote
+type RFQStatus string
+
+const (
+	RFQStatusOpen     RFQStatus = "open"
+	RFQStatusAccepted RFQStatus = "accepted"
+	RFQStatusExpired  RFQStatus = "expired"
+)
+
+// Quote is a firm offer to sell made in answer to a request
+type Quote struct {
+	QuoteID  string        `json:"quoteId"`
+	HolderID string        `json:"holderId"`
+	Shares   int64         `json:"shares"`
+	Price    money.Decimal `json:"price"`
+	QuotedAt time.Time     `json:"quotedAt"`
+}
+
+// RFQAggregate is a request sent privately to selected holders of a security
+// asking for firm quotes. Only the requester and the recipients can see the
+// request, and recipients only see their own quotes.
+type RFQAggregate struct {
+	events.AggregateRoot
+
+	// Request details
+	SecurityID  string    `json:"securityId"`
+	RequesterID string    `json:"requesterId"`
+	Shares      int64     `json:"shares"`
+	Recipients  []string  `json:"recipients"`
+	Message     string    `json:"message,omitempty"`
+	RequestedAt time.Time `json:"requestedAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+
+	// Quotes received, one per recipient
+	Quotes []Quote `json:"quotes"`
+
+	// Status and outcome
+	Status          RFQStatus  `json:"status"`
+	AcceptedQuoteID string     `json:"acceptedQuoteId,omitempty"`
+	TradeID         string     `json:"tradeId,omitempty"`
+	AcceptedAt      *time.Time `json:"acceptedAt,omitempty"`
+	ExpiredAt       *time.Time `json:"expiredAt,omitempty"`
+}
+
+// NewRFQAggregate creates a new RFQ aggregate
+func NewRFQAggregate(rfqID string) *RFQAggregate {
+	return &RFQAggregate{
+		AggregateRoot: events.NewAggregateRoot(rfqID, "RFQ"),
+		Status:        RFQStatusOpen,
+		Recipients:    make([]string, 0),
+		Quotes:        make([]Quote, 0),
+	}
+}
+
+// Request sends the request to the given recipients. Checking that the
+// recipients hold the security is up to the caller.
+func (r *RFQAggregate) Request(securityID, requesterID string, shares int64, recipients []string, message string, expiresAt time.Time) error {
+	if r.Version > 0 {
+		return fmt.Errorf("request for quote already exists")
+	}
+
+	if shares <= 0 {
+		return fmt.Errorf("shares must be greater than zero")
+	}
+
+	if len(recipients) == 0 {
+		return fmt.Errorf("at least one recipient is required")
+	}
+
+	seen := make(map[string]bool)
+	for _, recipient := range recipients {
+		if recipient == "" || recipient == requesterID {
+			return fmt.Errorf("invalid recipient %q", recipient)
+		}
+		if seen[recipient] {
+			return fmt.Errorf("duplicate recipient %s", recipient)
+		}
+		seen[recipient] = true
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return fmt.Errorf("expiration must be in the future")
+	}
+
+	event := NewQuoteRequested(r.ID, securityID, requesterID, shares, recipients, message, expiresAt)
+	r.AddEvent(event)
+	return r.ApplyEvent(event)
+}
+
+// SubmitQuote records a recipient's firm quote. Each recipient quotes once.
+func (r *RFQAggregate) SubmitQuote(quoteID, holderID string, shares int64, price money.Decimal) error {
+	if !r.IsOpen() {
+		return fmt.Errorf("request for quote is no longer open")
+	}
+
+	if !r.IsRecipient(holderID) {
+		return fmt.Errorf("only recipients of the request can quote")
+	}
+
+	if r.QuoteFrom(holderID) != nil {
+		return fmt.Errorf("holder %s has already quoted", holderID)
+	}
+
+	if shares <= 0 || shares > r.Shares {
+		return fmt.Errorf("quoted shares must be between 1 and %d", r.Shares)
+	}
+
+	if !price.IsPositive() {
+		return fmt.Errorf("price must be greater than zero")
+	}
+
+	event := NewQuoteSubmitted(r.ID, quoteID, holderID, shares, price)
+	r.AddEvent(event)
+	return r.ApplyEvent(event)
+}
+
+// AcceptQuote accepts one quote on behalf of the requester. The trade ID is
+// recorded so the request can be traced to the trade it created.
+func (r *RFQAggregate) AcceptQuote(quoteID, acceptedBy, tradeID string) error {
+	if !r.IsOpen() {
+		return fmt.Errorf("request for quote is no longer open")
+	}
+
+	if acceptedBy != r.RequesterID {
+		return fmt.Errorf("only the requester can accept a quote")
+	}
+
+	if r.GetQuote(quoteID) == nil {
+		return fmt.Errorf("quote %s not found", quoteID)
+	}
+
+	if tradeID == "" {
+		return fmt.Errorf("trade ID is required")
+	}
+
+	event := NewQuoteAccepted(r.ID, quoteID, acceptedBy, tradeID)
+	r.AddEvent(event)
+	return r.ApplyEvent(event)
+}
+
+// Expire marks the request as expired
+func (r *RFQAggregate) Expire() error {
+	if r.Status != RFQStatusOpen {
+		return fmt.Errorf("can only expire open requests")
+	}
+
+	if time.Now().Before(r.ExpiresAt) {
+		return fmt.Errorf("request has not yet expired")
+	}
+
+	event := NewQuoteRequestExpired(r.ID, time.Now())
+	r.AddEvent(event)
+	return r.ApplyEvent(event)
+}
+
+// ApplyEvent applies an event to the aggregate
+func (r *RFQAggregate) ApplyEvent(event events.DomainEvent) error {
+	switch e := event.(type) {
+	case *QuoteRequested:
+		return r.applyQuoteRequested(e)
+	case *QuoteSubmitted:
+		return r.applyQuoteSubmitted(e)
+	case *QuoteAccepted:
+		return r.applyQuoteAccepted(e)
+	case *QuoteRequestExpired:
+		return r.applyQuoteRequestExpired(e)
+	default:
+		return fmt.Errorf("unknown event type: %T", event)
+	}
+}
+
+// LoadFromHistory loads the aggregate from a sequence of events
+func (r *RFQAggregate) LoadFromHistory(events []events.DomainEvent) error {
+	for _, event := range events {
+		if err := r.ApplyEvent(event); err != nil {
+			return fmt.Errorf("failed to apply event %s: %w", event.GetEventType(), err)
+		}
+		r.IncrementVersion()
+	}
+	return nil
+}
+
+// Event application methods
+
+func (r *RFQAggregate) applyQuoteRequested(event *QuoteRequested) error {
+	r.SecurityID = event.SecurityID
+	r.RequesterID = event.RequesterID
+	r.Shares = event.Shares
+	r.Recipients = event.Recipients
+	r.Message = event.Message
+	r.RequestedAt = event.Timestamp
+	r.ExpiresAt = event.ExpiresAt
+	r.Status = RFQStatusOpen
+
+	r.IncrementVersion()
+	return nil
+}
+
+func (r *RFQAggregate) applyQuoteSubmitted(event *QuoteSubmitted) error {
+	r.Quotes = append(r.Quotes, Quote{
+		QuoteID:  event.QuoteID,
+		HolderID: event.HolderID,
+		Shares:   event.Shares,
+		Price:    event.Price,
+		QuotedAt: event.Timestamp,
+	})
+
+	r.IncrementVersion()
+	return nil
+}
+
+func (r *RFQAggregate) applyQuoteAccepted(event *QuoteAccepted) error {
+	r.Status = RFQStatusAccepted
+	r.AcceptedQuoteID = event.QuoteID
+	r.TradeID = event.TradeID
+	r.AcceptedAt = &event.Timestamp
+
+	r.IncrementVersion()
+	return nil
+}
+
+func (r *RFQAggregate) applyQuoteRequestExpired(event *QuoteRequestExpired) error {
+	r.Status = RFQStatusExpired
+	r.ExpiredAt = &event.ExpiredAt
+
+	r.IncrementVersion()
+	return nil
+}
+
+// Helper methods
+
+// IsOpen returns true if the request can still be quoted on and accepted
+func (r *RFQAggregate) IsOpen() bool {
+	return r.Status == RFQStatusOpen && time.Now().Before(r.ExpiresAt)
+}
+
+// IsExpired returns true if the request's time-to-live has passed
+func (r *RFQAggregate) IsExpired() bool {
+	return r.Status == RFQStatusExpired || (r.Status == RFQStatusOpen && !time.Now().Before(r.ExpiresAt))
+}
+
+// IsRecipient returns true if the request was sent to the user
+func (r *RFQAggregate) IsRecipient(userID string) bool {
+	for _, recipient := range r.Recipients {
+		if recipient == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsVisibleTo returns true if the user is the requester or a recipient
+func (r *RFQAggregate) IsVisibleTo(userID string) bool {
+	return userID != "" && (userID == r.RequesterID || r.IsRecipient(userID))
+}
+
+// GetQuote returns a quote by ID
+func (r *RFQAggregate) GetQuote(quoteID string) *Quote {
+	for i := range r.Quotes {
+		if r.Quotes[i].QuoteID == quoteID {
+			return &r.Quotes[i]
+		}
+	}
+	return nil
+}
+
+// QuoteFrom returns the holder's quote, if they have made one
+func (r *RFQAggregate) QuoteFrom(holderID string) *Quote {
+	for i := range r.Quotes {
+		if r.Quotes[i].HolderID == holderID {
+			return &r.Quotes[i]
+		}
+	}
+	return nil
+}
+
+// ViewFor returns the request as the user may see it. The requester sees
+// every quote; a recipient sees only their own quote and not who else was
+// asked.
+func (r *RFQAggregate) ViewFor(userID string) *RFQAggregate {
+	if userID == r.RequesterID {
+		return r
+	}
+
+	view := *r
+	view.Recipients = []string{userID}
+	view.Quotes = make([]Quote, 0, 1)
+	if quote := r.QuoteFrom(userID); quote != nil {
+		view.Quotes = append(view.Quotes, *quote)
+	}
+	if r.AcceptedQuoteID != "" && (r.QuoteFrom(userID) == nil || r.QuoteFrom(userID).QuoteID != r.AcceptedQuoteID) {
+		view.AcceptedQuoteID = ""
+		view.TradeID = ""
+	}
+	return &view
+}
@@ -0,0 +1,167 @@
+package rfq
+
+import (
+	"encoding/json"
+	"time"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+)
+
+// RFQ Domain Events
+
+// QuoteRequested event is emitted when a buyer asks selected holders for
+// firm quotes
+type QuoteRequested struct {
+	events.BaseEvent
+	SecurityID  string    `json:"securityId"`
+	RequesterID string    `json:"requesterId"`
+	Shares      int64     `json:"shares"`
+	Recipients  []string  `json:"recipients"`
+	Message     string    `json:"message,omitempty"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// NewQuoteRequested creates a new QuoteRequested event
+func NewQuoteRequested(rfqID, securityID, requesterID string, shares int64, recipients []string, message string, expiresAt time.Time) *QuoteRequested {
+	return &QuoteRequested{
+		BaseEvent:   events.NewBaseEvent(rfqID, "RFQ"),
+		SecurityID:  securityID,
+		RequesterID: requesterID,
+		Shares:      shares,
+		Recipients:  recipients,
+		Message:     message,
+		ExpiresAt:   expiresAt,
+	}
+}
+
+func (e *QuoteRequested) GetEventType() string     { return "QuoteRequested" }
+func (e *QuoteRequested) GetAggregateID() string   { return e.AggregateID }
+func (e *QuoteRequested) GetAggregateType() string { return e.AggregateType }
+
+func (e *QuoteRequested) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *QuoteRequested) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// QuoteSubmitted event is emitted when a holder answers a request with a firm
+// quote
+type QuoteSubmitted struct {
+	events.BaseEvent
+	QuoteID  string        `json:"quoteId"`
+	HolderID string        `json:"holderId"`
+	Shares   int64         `json:"shares"`
+	Price    money.Decimal `json:"price"`
+}
+
+// NewQuoteSubmitted creates a new QuoteSubmitted event
+func NewQuoteSubmitted(rfqID, quoteID, holderID string, shares int64, price money.Decimal) *QuoteSubmitted {
+	return &QuoteSubmitted{
+		BaseEvent: events.NewBaseEvent(rfqID, "RFQ"),
+		QuoteID:   quoteID,
+		HolderID:  holderID,
+		Shares:    shares,
+		Price:     price,
+	}
+}
+
+func (e *QuoteSubmitted) GetEventType() string     { return "QuoteSubmitted" }
+func (e *QuoteSubmitted) GetAggregateID() string   { return e.AggregateID }
+func (e *QuoteSubmitted) GetAggregateType() string { return e.AggregateType }
+
+func (e *QuoteSubmitted) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *QuoteSubmitted) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// QuoteAccepted event is emitted when the requester accepts a quote and the
+// trade it creates is booked
+type QuoteAccepted struct {
+	events.BaseEvent
+	QuoteID    string `json:"quoteId"`
+	AcceptedBy string `json:"acceptedBy"`
+	TradeID    string `json:"tradeId"`
+}
+
+// NewQuoteAccepted creates a new QuoteAccepted event
+func NewQuoteAccepted(rfqID, quoteID, acceptedBy, tradeID string) *QuoteAccepted {
+	return &QuoteAccepted{
+		BaseEvent:  events.NewBaseEvent(rfqID, "RFQ"),
+		QuoteID:    quoteID,
+		AcceptedBy: acceptedBy,
+		TradeID:    tradeID,
+	}
+}
+
+func (e *QuoteAccepted) GetEventType() string     { return "QuoteAccepted" }
+func (e *QuoteAccepted) GetAggregateID() string   { return e.AggregateID }
+func (e *QuoteAccepted) GetAggregateType() string { return e.AggregateType }
+
+func (e *QuoteAccepted) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *QuoteAccepted) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+// QuoteRequestExpired event is emitted when a request's time-to-live passes
+// without a quote being accepted
+type QuoteRequestExpired struct {
+	events.BaseEvent
+	ExpiredAt time.Time `json:"expiredAt"`
+}
+
+// NewQuoteRequestExpired creates a new QuoteRequestExpired event
+func NewQuoteRequestExpired(rfqID string, expiredAt time.Time) *QuoteRequestExpired {
+	return &QuoteRequestExpired{
+		BaseEvent: events.NewBaseEvent(rfqID, "RFQ"),
+		ExpiredAt: expiredAt,
+	}
+}
+
+func (e *QuoteRequestExpired) GetEventType() string     { return "QuoteRequestExpired" }
+func (e *QuoteRequestExpired) GetAggregateID() string   { return e.AggregateID }
+func (e *QuoteRequestExpired) GetAggregateType() string { return e.AggregateType }
+
+func (e *QuoteRequestExpired) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *QuoteRequestExpired) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
@@ -0,0 +1,131 @@
+package rfq
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"securities-marketplace/domains/shared/events"
+)
+
+// RFQRepository defines the interface for request for quote persistence
+type RFQRepository interface {
+	FindByID(rfqID string) (*RFQAggregate, error)
+	FindByParticipant(userID string) ([]*RFQAggregate, error)
+	FindOpen() ([]*RFQAggregate, error)
+}
+
+// EventSourcedRFQRepository implements RFQRepository using event sourcing
+type EventSourcedRFQRepository struct {
+	eventStore events.EventStore
+}
+
+// NewEventSourcedRFQRepository creates a new event-sourced RFQ repository
+func NewEventSourcedRFQRepository(eventStore events.EventStore) *EventSourcedRFQRepository {
+	return &EventSourcedRFQRepository{
+		eventStore: eventStore,
+	}
+}
+
+// FindByID finds a request for quote by ID by replaying events
+func (r *EventSourcedRFQRepository) FindByID(rfqID string) (*RFQAggregate, error) {
+	eventRecords, err := r.eventStore.GetEvents(rfqID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	if len(eventRecords) == 0 {
+		return nil, fmt.Errorf("request for quote with id %s not found", rfqID)
+	}
+
+	var domainEvents []events.DomainEvent
+	for _, eventRecord := range eventRecords {
+		domainEvent, err := r.convertEventRecordToDomainEvent(eventRecord)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert event %s: %w", eventRecord.EventType, err)
+		}
+		domainEvents = append(domainEvents, domainEvent)
+	}
+
+	request := NewRFQAggregate(rfqID)
+	err = request.LoadFromHistory(domainEvents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load from history: %w", err)
+	}
+
+	request.SetLastEventNumber(eventRecords[len(eventRecords)-1].EventNumber)
+
+	return request, nil
+}
+
+// FindByParticipant finds every request the user sent or received
+func (r *EventSourcedRFQRepository) FindByParticipant(userID string) ([]*RFQAggregate, error) {
+	return r.findRequests(func(requested *QuoteRequested) bool {
+		if requested.RequesterID == userID {
+			return true
+		}
+		for _, recipient := range requested.Recipients {
+			if recipient == userID {
+				return true
+			}
+		}
+		return false
+	}, func(*RFQAggregate) bool { return true })
+}
+
+// FindOpen finds every request that has not been accepted or expired
+func (r *EventSourcedRFQRepository) FindOpen() ([]*RFQAggregate, error) {
+	return r.findRequests(func(*QuoteRequested) bool { return true }, func(request *RFQAggregate) bool {
+		return request.Status == RFQStatusOpen
+	})
+}
+
+func (r *EventSourcedRFQRepository) findRequests(matchRequest func(*QuoteRequested) bool, matchState func(*RFQAggregate) bool) ([]*RFQAggregate, error) {
+	// This is inefficient for event sourcing - would need projection in real system
+	requestedEvents, err := r.eventStore.GetEventsByType("QuoteRequested", 1000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get request for quote events: %w", err)
+	}
+
+	var requests []*RFQAggregate
+	for _, eventRecord := range requestedEvents {
+		domainEvent, err := r.convertEventRecordToDomainEvent(eventRecord)
+		if err != nil {
+			continue // Skip invalid events
+		}
+		if !matchRequest(domainEvent.(*QuoteRequested)) {
+			continue
+		}
+
+		request, err := r.FindByID(eventRecord.AggregateID)
+		if err != nil {
+			continue // Skip if can't load
+		}
+		if matchState(request) {
+			requests = append(requests, request)
+		}
+	}
+
+	return requests, nil
+}
+
+// convertEventRecordToDomainEvent converts a single event record to domain event
+func (r *EventSourcedRFQRepository) convertEventRecordToDomainEvent(eventRecord *events.Event) (events.DomainEvent, error) {
+	var event events.DomainEvent
+	switch eventRecord.EventType {
+	case "QuoteRequested":
+		event = &QuoteRequested{}
+	case "QuoteSubmitted":
+		event = &QuoteSubmitted{}
+	case "QuoteAccepted":
+		event = &QuoteAccepted{}
+	case "QuoteRequestExpired":
+		event = &QuoteRequestExpired{}
+	default:
+		return nil, fmt.Errorf("unknown event type: %s", eventRecord.EventType)
+	}
+
+	if err := json.Unmarshal(eventRecord.EventData, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize %s: %w", eventRecord.EventType, err)
+	}
+	return event, nil
+}
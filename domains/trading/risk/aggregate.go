@@ -0,0 +1,106 @@
+package risk
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+)
+
+// Stage says when a breach was found
+type Stage string
+
+const (
+	StagePreTrade  Stage = "pre_trade"  // The trade or order was stopped
+	StagePostTrade Stage = "post_trade" // The trade had already executed
+)
+
+// Breach is a limit a user went, or would have gone, past
+type Breach struct {
+	UserID         string        `json:"userId"`
+	Limit          Limit         `json:"limit"`
+	SecurityID     string        `json:"securityId,omitempty"`
+	CounterpartyID string        `json:"counterpartyId,omitempty"`
+	Exposure       money.Decimal `json:"exposure"`
+	Stage          Stage         `json:"stage"`
+	TradeID        string        `json:"tradeId,omitempty"`
+	BreachedAt     time.Time     `json:"breachedAt"`
+}
+
+// RiskProfileID returns the aggregate ID of a user's risk profile
+func RiskProfileID(userID string) string {
+	return "risk-" + userID
+}
+
+// RiskProfileAggregate records the risk limit breaches of one user
+type RiskProfileAggregate struct {
+	events.AggregateRoot
+
+	UserID   string   `json:"userId"`
+	Breaches []Breach `json:"breaches"`
+}
+
+// NewRiskProfileAggregate creates the risk profile aggregate of a user
+func NewRiskProfileAggregate(userID string) *RiskProfileAggregate {
+	return &RiskProfileAggregate{
+		AggregateRoot: events.NewAggregateRoot(RiskProfileID(userID), "RiskProfile"),
+		UserID:        userID,
+		Breaches:      make([]Breach, 0),
+	}
+}
+
+// RecordBreach records a breach of one of the user's limits
+func (p *RiskProfileAggregate) RecordBreach(breach Breach) error {
+	if breach.UserID != p.UserID {
+		return fmt.Errorf("breach by %s cannot be recorded for %s", breach.UserID, p.UserID)
+	}
+
+	event := NewRiskLimitBreached(p.ID, breach)
+	p.AddEvent(event)
+	return p.ApplyEvent(event)
+}
+
+// ApplyEvent applies an event to the aggregate
+func (p *RiskProfileAggregate) ApplyEvent(event events.DomainEvent) error {
+	switch e := event.(type) {
+	case *RiskLimitBreached:
+		return p.applyRiskLimitBreached(e)
+	default:
+		return fmt.Errorf("unknown event type: %T", event)
+	}
+}
+
+// LoadFromHistory loads the aggregate from a sequence of events
+func (p *RiskProfileAggregate) LoadFromHistory(events []events.DomainEvent) error {
+	for _, event := range events {
+		if err := p.ApplyEvent(event); err != nil {
+			return fmt.Errorf("failed to apply event %s: %w", event.GetEventType(), err)
+		}
+	}
+	return nil
+}
+
+func (p *RiskProfileAggregate) applyRiskLimitBreached(event *RiskLimitBreached) error {
+	p.UserID = event.UserID
+	p.Breaches = append(p.Breaches, breachFromEvent(event))
+	p.IncrementVersion()
+	return nil
+}
+
+func breachFromEvent(event *RiskLimitBreached) Breach {
+	return Breach{
+		UserID: event.UserID,
+		Limit: Limit{
+			LimitID: event.LimitID,
+			Type:    event.LimitType,
+			Value:   event.Limit,
+		},
+		SecurityID:     event.SecurityID,
+		CounterpartyID: event.CounterpartyID,
+		Exposure:       event.Exposure,
+		Stage:          event.Stage,
+		TradeID:        event.TradeID,
+		BreachedAt:     event.Timestamp,
+	}
+}
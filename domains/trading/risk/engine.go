@@ -0,0 +1,457 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/execution"
+)
+
+// Engine enforces the risk limits compliance configures. Before a trade it
+// checks the position, concentration, notional and counterparty exposure the
+// trade would lead to and scores it; after a trade it checks them again.
+// Every breach is recorded on the user's risk profile. It implements
+// execution.RiskEngine.
+type Engine struct {
+	limits     LimitStore
+	exposures  ExposureSource
+	profiles   RiskProfileRepository
+	eventStore events.EventStore
+	eventBus   events.EventBus
+	scoring    ScoringConfig
+	now        func() time.Time
+}
+
+var _ execution.RiskEngine = (*Engine)(nil)
+var _ execution.PostTradeRiskChecker = (*Engine)(nil)
+
+// NewEngine creates a new risk engine with the default scoring
+func NewEngine(limits LimitStore, exposures ExposureSource, eventStore events.EventStore, eventBus events.EventBus) *Engine {
+	return &Engine{
+		limits:     limits,
+		exposures:  exposures,
+		profiles:   NewEventSourcedRiskProfileRepository(eventStore),
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		scoring:    DefaultScoring(),
+		now:        time.Now,
+	}
+}
+
+// SetScoring replaces the rules that turn limit usage into risk scores
+func (e *Engine) SetScoring(config ScoringConfig) {
+	e.scoring = config
+}
+
+// check is one limit measured against the exposure a trade or order leads to
+type check struct {
+	limit          *Limit
+	userID         string
+	securityID     string
+	counterpartyID string
+	exposure       money.Decimal
+	headroom       int64 // Most shares the trade could be within this limit
+}
+
+func (c check) usage() float64 {
+	return c.exposure.Float64() / c.limit.Value.Float64()
+}
+
+func (c check) breached() bool {
+	return c.exposure.GreaterThan(c.limit.Value)
+}
+
+func (c check) breach(stage Stage, tradeID string, at time.Time) Breach {
+	return Breach{
+		UserID:         c.userID,
+		Limit:          *c.limit,
+		SecurityID:     c.securityID,
+		CounterpartyID: c.counterpartyID,
+		Exposure:       c.exposure,
+		Stage:          stage,
+		TradeID:        tradeID,
+		BreachedAt:     at,
+	}
+}
+
+func (c check) String() string {
+	subject := c.userID
+	if c.counterpartyID != "" {
+		subject += " with " + c.counterpartyID
+	} else if c.securityID != "" && c.limit.Type != LimitDailyNotional {
+		subject += " in " + c.securityID
+	}
+	return fmt.Sprintf("%s limit for %s at %.0f%%", c.limit.Type, subject, c.usage()*100)
+}
+
+// AssessTradeRisk scores a trade against the limits of both parties. A trade
+// that would breach a limit is rated extreme and the breach is recorded.
+func (e *Engine) AssessTradeRisk(match *execution.MatchResult) (*execution.RiskAssessment, error) {
+	checks, err := e.tradeChecks(match, true)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment := e.assess(checks)
+	if err := e.recordBreaches(checks, StagePreTrade, match.TradeID); err != nil {
+		return nil, err
+	}
+	return assessment, nil
+}
+
+// CheckPositionLimits returns an error if buying the quantity would take the
+// user past their position or concentration limit in the security
+func (e *Engine) CheckPositionLimits(userID, securityID string, quantity int64) error {
+	limits, err := e.limits.ListLimits()
+	if err != nil {
+		return fmt.Errorf("failed to load risk limits: %w", err)
+	}
+
+	checks, err := e.positionChecks(limits, userID, securityID, quantity)
+	if err != nil {
+		return err
+	}
+	if err := e.recordBreaches(checks, StagePreTrade, ""); err != nil {
+		return err
+	}
+	return breachError(checks)
+}
+
+// ValidateCounterparty returns an error if either party has already used up
+// their exposure limit to the other
+func (e *Engine) ValidateCounterparty(buyerID, sellerID string) error {
+	limits, err := e.limits.ListLimits()
+	if err != nil {
+		return fmt.Errorf("failed to load risk limits: %w", err)
+	}
+
+	var exhausted []check
+	for _, pair := range [][2]string{{buyerID, sellerID}, {sellerID, buyerID}} {
+		c, err := e.counterpartyCheck(limits, pair[0], pair[1], money.NewDecimalFromInt(0), money.NewDecimalFromInt(0))
+		if err != nil {
+			return err
+		}
+		if c != nil && !c.exposure.LessThan(c.limit.Value) {
+			exhausted = append(exhausted, *c)
+		}
+	}
+
+	if len(exhausted) == 0 {
+		return nil
+	}
+	return fmt.Errorf("counterparty exposure exhausted: %s", describe(exhausted))
+}
+
+// CheckExecutedTrade checks the limits of both parties again once a trade
+// has executed and records any breach, such as one made by trades that
+// executed together. It returns an error describing the breaches.
+func (e *Engine) CheckExecutedTrade(match *execution.MatchResult) error {
+	checks, err := e.tradeChecks(match, false)
+	if err != nil {
+		return err
+	}
+	if err := e.recordBreaches(checks, StagePostTrade, match.TradeID); err != nil {
+		return err
+	}
+	return breachError(checks)
+}
+
+// tradeChecks measures every limit that applies to a trade. Before the trade
+// executes, pending is true and the trade is added to current exposures.
+func (e *Engine) tradeChecks(match *execution.MatchResult, pending bool) ([]check, error) {
+	limits, err := e.limits.ListLimits()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load risk limits: %w", err)
+	}
+
+	shares, amount := int64(0), money.NewDecimalFromInt(0)
+	if pending {
+		shares, amount = match.SharesTraded, match.TotalAmount.Amount
+	}
+	price := match.TradePrice
+
+	checks, err := e.positionChecks(limits, match.BuyerID, match.SecurityID, shares)
+	if err != nil {
+		return nil, err
+	}
+
+	now := e.now()
+	for _, side := range [][2]string{{match.BuyerID, match.SellerID}, {match.SellerID, match.BuyerID}} {
+		userID, counterpartyID := side[0], side[1]
+
+		if limit := limits.For(LimitTradeNotional, userID, match.SecurityID); limit != nil {
+			checks = append(checks, check{
+				limit:      limit,
+				userID:     userID,
+				securityID: match.SecurityID,
+				exposure:   match.TotalAmount.Amount,
+				headroom:   sharesWithin(limit.Value, price),
+			})
+		}
+
+		if limit := limits.For(LimitDailyNotional, userID, ""); limit != nil {
+			traded, err := e.exposures.DailyNotional(userID, now)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get daily notional of %s: %w", userID, err)
+			}
+			exposure, remaining, err := afterTrade(traded, amount, limit.Value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to measure daily notional of %s: %w", userID, err)
+			}
+			checks = append(checks, check{
+				limit:      limit,
+				userID:     userID,
+				securityID: match.SecurityID,
+				exposure:   exposure,
+				headroom:   sharesWithin(remaining, price),
+			})
+		}
+
+		c, err := e.counterpartyCheck(limits, userID, counterpartyID, amount, price)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			checks = append(checks, *c)
+		}
+	}
+
+	return checks, nil
+}
+
+// positionChecks measures the position and concentration limits of a user
+// buying quantity shares of a security
+func (e *Engine) positionChecks(limits Limits, userID, securityID string, quantity int64) ([]check, error) {
+	positionLimit := limits.For(LimitPosition, userID, securityID)
+	concentrationLimit := limits.For(LimitConcentration, userID, securityID)
+	if positionLimit == nil && concentrationLimit == nil {
+		return nil, nil
+	}
+
+	held, err := e.exposures.Position(userID, securityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position of %s: %w", userID, err)
+	}
+	position := held + quantity
+
+	var checks []check
+	if positionLimit != nil {
+		remaining, err := positionLimit.Value.Sub(money.NewDecimalFromInt(held))
+		if err != nil {
+			return nil, fmt.Errorf("failed to measure position of %s: %w", userID, err)
+		}
+		checks = append(checks, check{
+			limit:      positionLimit,
+			userID:     userID,
+			securityID: securityID,
+			exposure:   money.NewDecimalFromInt(position),
+			headroom:   sharesWithin(remaining, money.NewDecimalFromInt(1)),
+		})
+	}
+
+	if concentrationLimit != nil {
+		outstanding, err := e.exposures.SharesOutstanding(securityID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get shares outstanding of %s: %w", securityID, err)
+		}
+		if outstanding > 0 {
+			share, err := money.NewDecimalFromInt(position).DivInt(outstanding, money.RoundUp)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute concentration: %w", err)
+			}
+			maxHolding, err := concentrationLimit.Value.MulInt(outstanding)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute concentration: %w", err)
+			}
+			remaining, err := maxHolding.Sub(money.NewDecimalFromInt(held))
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute concentration: %w", err)
+			}
+			checks = append(checks, check{
+				limit:      concentrationLimit,
+				userID:     userID,
+				securityID: securityID,
+				exposure:   share,
+				headroom:   sharesWithin(remaining, money.NewDecimalFromInt(1)),
+			})
+		}
+	}
+
+	return checks, nil
+}
+
+// counterpartyCheck measures the user's exposure limit to the counterparty
+// after adding amount, or returns nil when no limit applies
+func (e *Engine) counterpartyCheck(limits Limits, userID, counterpartyID string, amount, price money.Decimal) (*check, error) {
+	limit := limits.For(LimitCounterpartyExposure, userID, "")
+	if limit == nil {
+		return nil, nil
+	}
+
+	exposure, err := e.exposures.CounterpartyExposure(userID, counterpartyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exposure of %s to %s: %w", userID, counterpartyID, err)
+	}
+	after, remaining, err := afterTrade(exposure, amount, limit.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure exposure of %s to %s: %w", userID, counterpartyID, err)
+	}
+	return &check{
+		limit:          limit,
+		userID:         userID,
+		counterpartyID: counterpartyID,
+		exposure:       after,
+		headroom:       sharesWithin(remaining, price),
+	}, nil
+}
+
+// afterTrade returns the exposure once amount is added to current, and the
+// room current leaves under the limit
+func afterTrade(current, amount, limit money.Decimal) (money.Decimal, money.Decimal, error) {
+	exposure, err := current.Add(amount)
+	if err != nil {
+		return money.Zero, money.Zero, err
+	}
+	remaining, err := limit.Sub(current)
+	if err != nil {
+		return money.Zero, money.Zero, err
+	}
+	return exposure, remaining, nil
+}
+
+// assess scores the checks of a trade
+func (e *Engine) assess(checks []check) *execution.RiskAssessment {
+	assessment := &execution.RiskAssessment{
+		RiskFactors:    []string{},
+		MaxAllowedSize: math.MaxInt64,
+	}
+
+	breached := false
+	usages := make(map[LimitType]float64)
+	for _, c := range checks {
+		usage := c.usage()
+		usages[c.limit.Type] = math.Max(usages[c.limit.Type], math.Min(usage, 1))
+		if c.breached() {
+			breached = true
+		}
+		if usage >= e.scoring.FactorAt {
+			assessment.RiskFactors = append(assessment.RiskFactors, c.String())
+		}
+		assessment.MaxAllowedSize = min(assessment.MaxAllowedSize, max(c.headroom, 0))
+	}
+
+	for limitType, usage := range usages {
+		assessment.RiskScore += e.scoring.Weights[limitType] * usage
+	}
+	if breached {
+		assessment.RiskScore = 100
+	}
+	assessment.RiskScore = math.Min(assessment.RiskScore, 100)
+	assessment.RiskLevel = e.scoring.level(assessment.RiskScore, breached)
+	assessment.RequiresReview = breached || assessment.RiskScore >= e.scoring.ReviewAt
+	return assessment
+}
+
+// recordBreaches records each breached check on its user's risk profile
+func (e *Engine) recordBreaches(checks []check, stage Stage, tradeID string) error {
+	now := e.now()
+	for _, c := range checks {
+		if !c.breached() {
+			continue
+		}
+
+		profile, err := e.profiles.FindByUser(c.userID)
+		if err != nil {
+			return fmt.Errorf("failed to load risk profile of %s: %w", c.userID, err)
+		}
+		if err := profile.RecordBreach(c.breach(stage, tradeID, now)); err != nil {
+			return fmt.Errorf("failed to record breach: %w", err)
+		}
+		if err := e.saveAggregateEvents(profile); err != nil {
+			return fmt.Errorf("failed to save breach: %w", err)
+		}
+	}
+	return nil
+}
+
+// saveAggregateEvents saves uncommitted events and publishes them
+func (e *Engine) saveAggregateEvents(profile *RiskProfileAggregate) error {
+	uncommittedEvents := profile.GetUncommittedEvents()
+	if len(uncommittedEvents) == 0 {
+		return nil
+	}
+
+	var events []*events.Event
+	correlationID := uuid.New().String()
+
+	for i, domainEvent := range uncommittedEvents {
+		var causationID *string
+		if i > 0 {
+			prevEventID := events[i-1].EventID
+			causationID = &prevEventID
+		}
+
+		event, err := e.eventStore.CreateEventFromDomain(domainEvent, "system", correlationID, causationID)
+		if err != nil {
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+
+		event.AggregateVersion = profile.GetVersion() + i + 1
+		events = append(events, event)
+	}
+
+	if err := e.eventStore.SaveEvents(events); err != nil {
+		return fmt.Errorf("failed to save events: %w", err)
+	}
+
+	for _, domainEvent := range uncommittedEvents {
+		if err := e.eventBus.Publish(domainEvent); err != nil {
+			// Log error but don't fail the operation
+			fmt.Printf("Failed to publish event %s: %v\n", domainEvent.GetEventType(), err)
+		}
+	}
+
+	profile.MarkEventsAsCommitted()
+	return nil
+}
+
+// sharesWithin returns how many whole shares at the price fit in the
+// remaining amount
+func sharesWithin(remaining, price money.Decimal) int64 {
+	if !remaining.IsPositive() || !price.IsPositive() {
+		return 0
+	}
+	shares, err := remaining.Div(price, money.RoundDown)
+	if err != nil {
+		return 0
+	}
+	return int64(shares.Float64())
+}
+
+// breachError returns an error describing the breached checks, or nil
+func breachError(checks []check) error {
+	var breached []check
+	for _, c := range checks {
+		if c.breached() {
+			breached = append(breached, c)
+		}
+	}
+	if len(breached) == 0 {
+		return nil
+	}
+	return fmt.Errorf("risk limit exceeded: %s", describe(breached))
+}
+
+func describe(checks []check) string {
+	descriptions := make([]string, len(checks))
+	for i, c := range checks {
+		descriptions[i] = c.String()
+	}
+	return strings.Join(descriptions, "; ")
+}
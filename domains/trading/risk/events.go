@@ -0,0 +1,60 @@
+package risk
+
+import (
+	"encoding/json"
+
+	"securities-marketplace/domains/shared/events"
+	"securities-marketplace/domains/shared/money"
+)
+
+// Risk Domain Events
+
+// RiskLimitBreached event is emitted when a trade or order would take, or
+// has taken, a user past one of their risk limits
+type RiskLimitBreached struct {
+	events.BaseEvent
+	UserID         string        `json:"userId"`
+	LimitID        string        `json:"limitId"`
+	LimitType      LimitType     `json:"limitType"`
+	SecurityID     string        `json:"securityId,omitempty"`
+	CounterpartyID string        `json:"counterpartyId,omitempty"`
+	Limit          money.Decimal `json:"limit"`
+	Exposure       money.Decimal `json:"exposure"`
+	Stage          Stage         `json:"stage"`
+	TradeID        string        `json:"tradeId,omitempty"`
+}
+
+// NewRiskLimitBreached creates a new RiskLimitBreached event
+func NewRiskLimitBreached(profileID string, breach Breach) *RiskLimitBreached {
+	return &RiskLimitBreached{
+		BaseEvent:      events.NewBaseEvent(profileID, "RiskProfile"),
+		UserID:         breach.UserID,
+		LimitID:        breach.Limit.LimitID,
+		LimitType:      breach.Limit.Type,
+		SecurityID:     breach.SecurityID,
+		CounterpartyID: breach.CounterpartyID,
+		Limit:          breach.Limit.Value,
+		Exposure:       breach.Exposure,
+		Stage:          breach.Stage,
+		TradeID:        breach.TradeID,
+	}
+}
+
+func (e *RiskLimitBreached) GetEventType() string     { return "RiskLimitBreached" }
+func (e *RiskLimitBreached) GetAggregateID() string   { return e.AggregateID }
+func (e *RiskLimitBreached) GetAggregateType() string { return e.AggregateType }
+
+func (e *RiskLimitBreached) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *RiskLimitBreached) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
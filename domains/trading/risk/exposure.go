@@ -0,0 +1,136 @@
+package risk
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/securities"
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/trading/calendar"
+	"securities-marketplace/domains/trading/execution"
+)
+
+// ExposureSource reports what a user already holds and owes, before the
+// trade being checked
+type ExposureSource interface {
+	// Position returns the shares of a security the user holds, counting
+	// trades that have not settled yet
+	Position(userID, securityID string) (int64, error)
+	// SharesOutstanding returns the number of shares a security has issued
+	SharesOutstanding(securityID string) (int64, error)
+	// DailyNotional returns the value the user has traded on the trading day
+	// of now
+	DailyNotional(userID string, now time.Time) (money.Decimal, error)
+	// CounterpartyExposure returns the value of unsettled trades between the
+	// user and the counterparty
+	CounterpartyExposure(userID, counterpartyID string) (money.Decimal, error)
+}
+
+// TradeExposures computes exposures from security ownership and the trade
+// history
+type TradeExposures struct {
+	securities securities.SecurityRepository
+	trades     execution.TradeRepository
+	calendar   *calendar.Calendar
+}
+
+// NewTradeExposures creates exposures backed by the security and trade
+// repositories. Trading days follow the calendar, or the default calendar
+// when cal is nil.
+func NewTradeExposures(securityRepository securities.SecurityRepository, trades execution.TradeRepository, cal *calendar.Calendar) *TradeExposures {
+	if cal == nil {
+		cal = calendar.Default()
+	}
+	return &TradeExposures{
+		securities: securityRepository,
+		trades:     trades,
+		calendar:   cal,
+	}
+}
+
+// Position returns the user's registered holding plus their net unsettled
+// purchases
+func (x *TradeExposures) Position(userID, securityID string) (int64, error) {
+	security, err := x.securities.FindByID(securityID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find security: %w", err)
+	}
+
+	var position int64
+	if record, ok := security.Ownership[userID]; ok {
+		position = record.SharesOwned
+	}
+
+	trades, err := x.trades.FindByUser(userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find trades: %w", err)
+	}
+	for _, trade := range trades {
+		if trade.SecurityID != securityID || !isUnsettled(trade) {
+			continue
+		}
+		if trade.BuyerID == userID {
+			position += trade.SharesTraded
+		} else {
+			position -= trade.SharesTraded
+		}
+	}
+	return position, nil
+}
+
+// SharesOutstanding returns the total shares of the security
+func (x *TradeExposures) SharesOutstanding(securityID string) (int64, error) {
+	security, err := x.securities.FindByID(securityID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find security: %w", err)
+	}
+	return security.TotalShares, nil
+}
+
+// DailyNotional returns the value of the user's trades, bought or sold, that
+// belong to the trading day of now. Failed and cancelled trades do not count.
+func (x *TradeExposures) DailyNotional(userID string, now time.Time) (money.Decimal, error) {
+	trades, err := x.trades.FindByUser(userID)
+	if err != nil {
+		return money.Decimal{}, fmt.Errorf("failed to find trades: %w", err)
+	}
+
+	today := x.calendar.TradeDate(now)
+	total := money.NewDecimalFromInt(0)
+	for _, trade := range trades {
+		if trade.IsVoid() || !x.calendar.TradeDate(trade.MatchedAt).Equal(today) {
+			continue
+		}
+		if total, err = total.Add(trade.TotalAmount.Amount); err != nil {
+			return money.Decimal{}, fmt.Errorf("failed to total daily notional: %w", err)
+		}
+	}
+	return total, nil
+}
+
+// CounterpartyExposure returns the value of unsettled trades between the
+// user and the counterparty, in either direction
+func (x *TradeExposures) CounterpartyExposure(userID, counterpartyID string) (money.Decimal, error) {
+	trades, err := x.trades.FindByUser(userID)
+	if err != nil {
+		return money.Decimal{}, fmt.Errorf("failed to find trades: %w", err)
+	}
+
+	total := money.NewDecimalFromInt(0)
+	for _, trade := range trades {
+		if !isUnsettled(trade) {
+			continue
+		}
+		if trade.BuyerID == counterpartyID || trade.SellerID == counterpartyID {
+			if total, err = total.Add(trade.TotalAmount.Amount); err != nil {
+				return money.Decimal{}, fmt.Errorf("failed to total counterparty exposure: %w", err)
+			}
+		}
+	}
+	return total, nil
+}
+
+// isUnsettled returns true if the trade is still on its way to settlement
+func isUnsettled(trade *execution.TradeAggregate) bool {
+	return trade.Status != execution.TradeStatusSettled && !trade.IsVoid()
+}
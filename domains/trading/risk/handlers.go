@@ -0,0 +1,144 @@
+package risk
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"securities-marketplace/domains/shared/auth"
+)
+
+// Handler lets compliance view and edit risk limits and review breaches
+type Handler struct {
+	limits   LimitStore
+	profiles RiskProfileRepository
+}
+
+// NewHandler creates a new risk handler
+func NewHandler(limits LimitStore, profiles RiskProfileRepository) *Handler {
+	return &Handler{limits: limits, profiles: profiles}
+}
+
+// RegisterRoutes registers the handler routes. Callers are expected to mount
+// the router behind compliance authorization.
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/risk/limits", h.HandleListLimits).Methods("GET")
+	router.HandleFunc("/risk/limits", h.HandleCreateLimit).Methods("POST")
+	router.HandleFunc("/risk/limits/{id}", h.HandleUpdateLimit).Methods("PUT")
+	router.HandleFunc("/risk/limits/{id}", h.HandleDeleteLimit).Methods("DELETE")
+	router.HandleFunc("/risk/breaches", h.HandleListBreaches).Methods("GET")
+}
+
+// HandleListLimits returns every configured limit
+func (h *Handler) HandleListLimits(w http.ResponseWriter, r *http.Request) {
+	limits, err := h.limits.ListLimits()
+	if err != nil {
+		http.Error(w, "Failed to load risk limits", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"limits":  limits,
+		"count":   len(limits),
+	})
+}
+
+// HandleCreateLimit adds a limit
+func (h *Handler) HandleCreateLimit(w http.ResponseWriter, r *http.Request) {
+	h.saveLimit(w, r, "", http.StatusCreated)
+}
+
+// HandleUpdateLimit replaces a limit
+func (h *Handler) HandleUpdateLimit(w http.ResponseWriter, r *http.Request) {
+	h.saveLimit(w, r, mux.Vars(r)["id"], http.StatusOK)
+}
+
+// HandleDeleteLimit removes a limit
+func (h *Handler) HandleDeleteLimit(w http.ResponseWriter, r *http.Request) {
+	if err := h.limits.DeleteLimit(mux.Vars(r)["id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
+
+// HandleListBreaches returns the breaches of the user given by userId, or the
+// most recent breaches across all users
+func (h *Handler) HandleListBreaches(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var breaches []Breach
+	if userID := query.Get("userId"); userID != "" {
+		profile, err := h.profiles.FindByUser(userID)
+		if err != nil {
+			http.Error(w, "Failed to load risk profile", http.StatusInternalServerError)
+			return
+		}
+		breaches = profile.Breaches
+	} else {
+		limit := 100
+		if value := query.Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+
+		var err error
+		breaches, err = h.profiles.FindRecentBreaches(limit)
+		if err != nil {
+			http.Error(w, "Failed to load risk breaches", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"breaches": breaches,
+		"count":    len(breaches),
+	})
+}
+
+func (h *Handler) saveLimit(w http.ResponseWriter, r *http.Request, limitID string, status int) {
+	var limit Limit
+	if err := json.NewDecoder(r.Body).Decode(&limit); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := limit.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit.LimitID = limitID
+	limit.UpdatedBy = "system"
+	if user := auth.GetUserFromContext(r.Context()); user != nil {
+		limit.UpdatedBy = user.UserID
+	}
+	limit.UpdatedAt = time.Now()
+
+	if err := h.limits.SaveLimit(&limit); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	writeJSON(w, status, map[string]interface{}{
+		"success": true,
+		"limit":   limit,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, response map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,98 @@
+package risk
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+)
+
+// LimitType is what a risk limit caps
+type LimitType string
+
+const (
+	LimitPosition             LimitType = "position"              // Shares held in a security
+	LimitConcentration        LimitType = "concentration"         // Fraction of a security's shares held, e.g. 0.10
+	LimitCounterpartyExposure LimitType = "counterparty_exposure" // Unsettled trade value with any one counterparty
+	LimitTradeNotional        LimitType = "trade_notional"        // Value of a single trade
+	LimitDailyNotional        LimitType = "daily_notional"        // Value traded in one trading day
+)
+
+// limitTypes lists every limit type and whether it can be set per security
+var limitTypes = map[LimitType]bool{
+	LimitPosition:             true,
+	LimitConcentration:        true,
+	LimitCounterpartyExposure: false,
+	LimitTradeNotional:        true,
+	LimitDailyNotional:        false,
+}
+
+// Limit caps one kind of exposure. An empty UserID applies the limit to
+// every user and an empty SecurityID to every security; where several limits
+// apply, the most specific one wins. Notional and exposure limits are amounts
+// in the trade currency.
+type Limit struct {
+	LimitID    string        `json:"limitId"`
+	Type       LimitType     `json:"type"`
+	UserID     string        `json:"userId,omitempty"`
+	SecurityID string        `json:"securityId,omitempty"`
+	Value      money.Decimal `json:"value"`
+	UpdatedBy  string        `json:"updatedBy"`
+	UpdatedAt  time.Time     `json:"updatedAt"`
+}
+
+// Validate checks that the limit can be enforced
+func (l *Limit) Validate() error {
+	perSecurity, ok := limitTypes[l.Type]
+	if !ok {
+		return fmt.Errorf("unknown limit type %q", l.Type)
+	}
+	if l.SecurityID != "" && !perSecurity {
+		return fmt.Errorf("%s limits cannot be set per security", l.Type)
+	}
+	if !l.Value.IsPositive() {
+		return fmt.Errorf("limit value must be positive")
+	}
+	if l.Type == LimitConcentration && l.Value.GreaterThan(money.NewDecimalFromInt(1)) {
+		return fmt.Errorf("concentration limit must be a fraction no greater than 1")
+	}
+	return nil
+}
+
+// applies returns true if the limit covers the user and security
+func (l *Limit) applies(userID, securityID string) bool {
+	return (l.UserID == "" || l.UserID == userID) && (l.SecurityID == "" || l.SecurityID == securityID)
+}
+
+// specificity ranks limits for the same user and security: a limit for both
+// beats one for the user, which beats one for the security, which beats the
+// default
+func (l *Limit) specificity() int {
+	rank := 0
+	if l.UserID != "" {
+		rank += 2
+	}
+	if l.SecurityID != "" {
+		rank++
+	}
+	return rank
+}
+
+// Limits is the full set of configured limits
+type Limits []Limit
+
+// For returns the limit of the type that applies to the user and security,
+// or nil when none does
+func (ls Limits) For(limitType LimitType, userID, securityID string) *Limit {
+	var found *Limit
+	for i := range ls {
+		limit := &ls[i]
+		if limit.Type != limitType || !limit.applies(userID, securityID) {
+			continue
+		}
+		if found == nil || limit.specificity() > found.specificity() {
+			found = limit
+		}
+	}
+	return found
+}
@@ -0,0 +1,96 @@
+package risk
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"securities-marketplace/domains/shared/events"
+)
+
+// RiskProfileRepository defines the interface for risk profile persistence
+type RiskProfileRepository interface {
+	FindByUser(userID string) (*RiskProfileAggregate, error)
+	FindRecentBreaches(limit int) ([]Breach, error)
+}
+
+// EventSourcedRiskProfileRepository implements RiskProfileRepository using event sourcing
+type EventSourcedRiskProfileRepository struct {
+	eventStore events.EventStore
+}
+
+// NewEventSourcedRiskProfileRepository creates a new event-sourced risk profile repository
+func NewEventSourcedRiskProfileRepository(eventStore events.EventStore) *EventSourcedRiskProfileRepository {
+	return &EventSourcedRiskProfileRepository{
+		eventStore: eventStore,
+	}
+}
+
+// FindByUser loads a user's risk profile. Users without breaches have an
+// empty profile.
+func (r *EventSourcedRiskProfileRepository) FindByUser(userID string) (*RiskProfileAggregate, error) {
+	profile := NewRiskProfileAggregate(userID)
+
+	eventRecords, err := r.eventStore.GetEvents(profile.ID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	if len(eventRecords) == 0 {
+		return profile, nil
+	}
+
+	var domainEvents []events.DomainEvent
+	for _, eventRecord := range eventRecords {
+		domainEvent, err := r.convertEventRecordToDomainEvent(eventRecord)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert event %s: %w", eventRecord.EventType, err)
+		}
+		domainEvents = append(domainEvents, domainEvent)
+	}
+
+	if err := profile.LoadFromHistory(domainEvents); err != nil {
+		return nil, fmt.Errorf("failed to load from history: %w", err)
+	}
+
+	profile.SetLastEventNumber(eventRecords[len(eventRecords)-1].EventNumber)
+
+	return profile, nil
+}
+
+// FindRecentBreaches returns the latest breaches across every user, newest first
+func (r *EventSourcedRiskProfileRepository) FindRecentBreaches(limit int) ([]Breach, error) {
+	eventRecords, err := r.eventStore.GetEventsByType("RiskLimitBreached", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk limit breach events: %w", err)
+	}
+
+	breaches := make([]Breach, 0, len(eventRecords))
+	for _, eventRecord := range eventRecords {
+		domainEvent, err := r.convertEventRecordToDomainEvent(eventRecord)
+		if err != nil {
+			continue // Skip invalid events
+		}
+		breaches = append(breaches, breachFromEvent(domainEvent.(*RiskLimitBreached)))
+	}
+
+	sort.SliceStable(breaches, func(i, j int) bool {
+		return breaches[i].BreachedAt.After(breaches[j].BreachedAt)
+	})
+	return breaches, nil
+}
+
+// convertEventRecordToDomainEvent converts a single event record to domain event
+func (r *EventSourcedRiskProfileRepository) convertEventRecordToDomainEvent(eventRecord *events.Event) (events.DomainEvent, error) {
+	var event events.DomainEvent
+	switch eventRecord.EventType {
+	case "RiskLimitBreached":
+		event = &RiskLimitBreached{}
+	default:
+		return nil, fmt.Errorf("unknown event type: %s", eventRecord.EventType)
+	}
+
+	if err := json.Unmarshal(eventRecord.EventData, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize %s: %w", eventRecord.EventType, err)
+	}
+	return event, nil
+}
@@ -0,0 +1,153 @@
+package risk
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/shared/money"
+	"securities-marketplace/domains/shared/testutil"
+	"securities-marketplace/domains/trading/execution"
+)
+
+// stubExposures returns fixed exposures
+type stubExposures struct {
+	positions    map[string]int64
+	outstanding  int64
+	daily        money.Decimal
+	counterparty money.Decimal
+}
+
+func (s *stubExposures) Position(userID, securityID string) (int64, error) {
+	return s.positions[userID], nil
+}
+
+func (s *stubExposures) SharesOutstanding(securityID string) (int64, error) {
+	return s.outstanding, nil
+}
+
+func (s *stubExposures) DailyNotional(userID string, now time.Time) (money.Decimal, error) {
+	return s.daily, nil
+}
+
+func (s *stubExposures) CounterpartyExposure(userID, counterpartyID string) (money.Decimal, error) {
+	return s.counterparty, nil
+}
+
+func newTestEngine(t *testing.T, limits ...Limit) (*Engine, *stubExposures, *testutil.TestSetup) {
+	setup := testutil.NewTestSetup()
+	store := NewInMemoryLimitStore()
+	for i := range limits {
+		testutil.AssertNoError(t, store.SaveLimit(&limits[i]), "Limit should save")
+	}
+	exposures := &stubExposures{
+		positions:    map[string]int64{},
+		outstanding:  10000,
+		daily:        money.NewDecimalFromInt(0),
+		counterparty: money.NewDecimalFromInt(0),
+	}
+	return NewEngine(store, exposures, setup.EventStore, setup.EventBus), exposures, setup
+}
+
+func testMatch(shares int64, price string) *execution.MatchResult {
+	tradePrice := money.MustParseDecimal(price)
+	totalAmount, _ := tradePrice.MulInt(shares) // Test prices and sizes are small
+	return &execution.MatchResult{
+		TradeID:      "trade-1",
+		BuyerID:      "buyer-1",
+		SellerID:     "seller-1",
+		SecurityID:   "security-1",
+		SharesTraded: shares,
+		TradePrice:   tradePrice,
+		TotalAmount:  money.New(totalAmount, "USD"),
+	}
+}
+
+func TestLimits_MostSpecificLimitWins(t *testing.T) {
+	limits := Limits{
+		{LimitID: "default", Type: LimitPosition, Value: money.NewDecimalFromInt(1000)},
+		{LimitID: "security", Type: LimitPosition, SecurityID: "security-1", Value: money.NewDecimalFromInt(500)},
+		{LimitID: "user", Type: LimitPosition, UserID: "buyer-1", Value: money.NewDecimalFromInt(2000)},
+	}
+
+	testutil.AssertEqual(t, "user", limits.For(LimitPosition, "buyer-1", "security-1").LimitID, "User limit should beat security limit")
+	testutil.AssertEqual(t, "security", limits.For(LimitPosition, "buyer-2", "security-1").LimitID, "Security limit should beat default")
+	testutil.AssertEqual(t, "default", limits.For(LimitPosition, "buyer-2", "security-2").LimitID, "Default should apply otherwise")
+	testutil.AssertNil(t, limits.For(LimitTradeNotional, "buyer-1", "security-1"), "Unset limit types should not apply")
+
+	invalid := Limit{Type: LimitDailyNotional, SecurityID: "security-1", Value: money.NewDecimalFromInt(1)}
+	testutil.AssertError(t, invalid.Validate(), "Daily notional limits should not be set per security")
+	invalid = Limit{Type: LimitConcentration, Value: money.NewDecimalFromInt(2)}
+	testutil.AssertError(t, invalid.Validate(), "Concentration should be a fraction")
+}
+
+func TestEngine_AssessTradeRiskScoresAndRecordsBreaches(t *testing.T) {
+	// Arrange
+	engine, exposures, setup := newTestEngine(t,
+		Limit{Type: LimitTradeNotional, Value: money.NewDecimalFromInt(10000)},
+		Limit{Type: LimitDailyNotional, Value: money.NewDecimalFromInt(50000)},
+	)
+
+	// Act
+	assessment, err := engine.AssessTradeRisk(testMatch(50, "100"))
+
+	// Assert
+	testutil.AssertNoError(t, err, "Assessment should succeed")
+	testutil.AssertEqual(t, "low", assessment.RiskLevel, "Trade well within limits should be low risk")
+	testutil.AssertFalse(t, assessment.RequiresReview, "Low risk trades should not need review")
+	testutil.AssertEqual(t, int64(100), assessment.MaxAllowedSize, "Trade notional should cap the size at 100 shares")
+
+	// Act
+	exposures.daily = money.NewDecimalFromInt(48000)
+	assessment, err = engine.AssessTradeRisk(testMatch(50, "100"))
+
+	// Assert
+	testutil.AssertNoError(t, err, "Assessment should succeed")
+	testutil.AssertEqual(t, "extreme", assessment.RiskLevel, "Trade past the daily limit should be extreme")
+	testutil.AssertEqual(t, 100.0, assessment.RiskScore, "Breaches should score 100")
+	testutil.AssertTrue(t, assessment.RequiresReview, "Breaches should need review")
+	testutil.AssertEqual(t, int64(20), assessment.MaxAllowedSize, "Remaining daily notional should cap the size")
+
+	breaches := setup.EventBus.GetEventsByType("RiskLimitBreached")
+	testutil.AssertLengthEqual(t, 2, breaches, "Each party's breach should be recorded")
+	breach := breaches[0].(*RiskLimitBreached)
+	testutil.AssertEqual(t, LimitDailyNotional, breach.LimitType, "Breach should name the limit")
+	testutil.AssertEqual(t, StagePreTrade, breach.Stage, "Breach should be pre-trade")
+	testutil.AssertEqual(t, "trade-1", breach.TradeID, "Breach should name the trade")
+
+	profile, err := engine.profiles.FindByUser("buyer-1")
+	testutil.AssertNoError(t, err, "Profile should load")
+	testutil.AssertLengthEqual(t, 1, profile.Breaches, "Profile should hold the buyer's breach")
+}
+
+func TestEngine_PositionAndCounterpartyLimits(t *testing.T) {
+	// Arrange
+	engine, exposures, setup := newTestEngine(t,
+		Limit{Type: LimitPosition, SecurityID: "security-1", Value: money.NewDecimalFromInt(1000)},
+		Limit{Type: LimitConcentration, Value: money.MustParseDecimal("0.05")},
+		Limit{Type: LimitCounterpartyExposure, UserID: "buyer-1", Value: money.NewDecimalFromInt(5000)},
+	)
+	exposures.positions["buyer-1"] = 400
+
+	// Act & Assert
+	testutil.AssertNoError(t, engine.CheckPositionLimits("buyer-1", "security-1", 100), "Buying within limits should pass")
+	err := engine.CheckPositionLimits("buyer-1", "security-1", 200)
+	testutil.AssertError(t, err, "Buying past 5% of outstanding shares should fail")
+	testutil.AssertContains(t, err.Error(), "concentration", "Error should name the limit")
+	testutil.AssertError(t, engine.CheckPositionLimits("buyer-1", "security-1", 700), "Buying past the position limit should fail")
+	testutil.AssertLengthEqual(t, 3, setup.EventBus.GetEventsByType("RiskLimitBreached"), "Each breached limit should be recorded")
+
+	testutil.AssertNoError(t, engine.ValidateCounterparty("buyer-1", "seller-1"), "Unused exposure should pass")
+	exposures.counterparty = money.NewDecimalFromInt(5000)
+	testutil.AssertError(t, engine.ValidateCounterparty("buyer-1", "seller-1"), "Exhausted exposure should fail")
+
+	exposures.counterparty = money.NewDecimalFromInt(0)
+	assessment, err := engine.AssessTradeRisk(testMatch(1, "100"))
+	testutil.AssertNoError(t, err, "Assessment should succeed")
+	testutil.AssertEqual(t, int64(50), assessment.MaxAllowedSize, "Concentration headroom should cap the size")
+
+	unlimited, _, _ := newTestEngine(t)
+	assessment, err = unlimited.AssessTradeRisk(testMatch(1, "100"))
+	testutil.AssertNoError(t, err, "Assessment should succeed")
+	testutil.AssertEqual(t, int64(math.MaxInt64), assessment.MaxAllowedSize, "No limits should not cap the size")
+}
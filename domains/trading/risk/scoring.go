@@ -0,0 +1,51 @@
+package risk
+
+// Risk levels of an assessment
+const (
+	RiskLevelLow     = "low"
+	RiskLevelMedium  = "medium"
+	RiskLevelHigh    = "high"
+	RiskLevelExtreme = "extreme" // A limit would be breached; the trade must not execute
+)
+
+// ScoringConfig turns limit usage into a risk score from 0 to 100. Each limit
+// type adds its weight times the largest fraction of a limit of that type the
+// trade would use, on either side; a breached limit scores 100.
+type ScoringConfig struct {
+	Weights  map[LimitType]float64 `json:"weights"`
+	FactorAt float64               `json:"factorAt"` // Usage at which a check is listed as a risk factor
+	MediumAt float64               `json:"mediumAt"` // Lowest score rated medium
+	HighAt   float64               `json:"highAt"`   // Lowest score rated high
+	ReviewAt float64               `json:"reviewAt"` // Lowest score that requires review
+}
+
+// DefaultScoring weights single-security risk above trading volume
+func DefaultScoring() ScoringConfig {
+	return ScoringConfig{
+		Weights: map[LimitType]float64{
+			LimitPosition:             30,
+			LimitConcentration:        30,
+			LimitCounterpartyExposure: 20,
+			LimitTradeNotional:        25,
+			LimitDailyNotional:        20,
+		},
+		FactorAt: 0.8,
+		MediumAt: 25,
+		HighAt:   60,
+		ReviewAt: 60,
+	}
+}
+
+// level rates a score
+func (c ScoringConfig) level(score float64, breached bool) string {
+	switch {
+	case breached:
+		return RiskLevelExtreme
+	case score >= c.HighAt:
+		return RiskLevelHigh
+	case score >= c.MediumAt:
+		return RiskLevelMedium
+	default:
+		return RiskLevelLow
+	}
+}
@@ -0,0 +1,166 @@
+package risk
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LimitStore keeps the risk limits compliance configures
+type LimitStore interface {
+	ListLimits() (Limits, error)
+	SaveLimit(limit *Limit) error
+	DeleteLimit(limitID string) error
+}
+
+// PostgresLimitStore keeps risk limits in the risk_limits table
+type PostgresLimitStore struct {
+	db *sql.DB
+}
+
+// NewPostgresLimitStore creates a new PostgreSQL-backed limit store
+func NewPostgresLimitStore(db *sql.DB) *PostgresLimitStore {
+	return &PostgresLimitStore{db: db}
+}
+
+// ListLimits returns every configured limit
+func (s *PostgresLimitStore) ListLimits() (Limits, error) {
+	rows, err := s.db.Query(`
+		SELECT limit_id, limit_type, user_id, security_id, limit_value, updated_by, updated_at
+		FROM risk_limits
+		ORDER BY limit_type, user_id, security_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query risk limits: %w", err)
+	}
+	defer rows.Close()
+
+	limits := Limits{}
+	for rows.Next() {
+		var limit Limit
+		var limitType string
+		err := rows.Scan(&limit.LimitID, &limitType, &limit.UserID, &limit.SecurityID, &limit.Value, &limit.UpdatedBy, &limit.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan risk limit: %w", err)
+		}
+		limit.Type = LimitType(limitType)
+		limits = append(limits, limit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate risk limits: %w", err)
+	}
+
+	return limits, nil
+}
+
+// SaveLimit inserts a limit, or replaces the limit with the same ID
+func (s *PostgresLimitStore) SaveLimit(limit *Limit) error {
+	if err := limit.Validate(); err != nil {
+		return err
+	}
+	if limit.LimitID == "" {
+		limit.LimitID = uuid.New().String()
+	}
+	if limit.UpdatedAt.IsZero() {
+		limit.UpdatedAt = time.Now()
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO risk_limits (limit_id, limit_type, user_id, security_id, limit_value, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (limit_id) DO UPDATE SET
+			limit_type = EXCLUDED.limit_type,
+			user_id = EXCLUDED.user_id,
+			security_id = EXCLUDED.security_id,
+			limit_value = EXCLUDED.limit_value,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`, limit.LimitID, string(limit.Type), limit.UserID, limit.SecurityID, limit.Value, limit.UpdatedBy, limit.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save risk limit: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteLimit removes a limit
+func (s *PostgresLimitStore) DeleteLimit(limitID string) error {
+	result, err := s.db.Exec(`DELETE FROM risk_limits WHERE limit_id = $1`, limitID)
+	if err != nil {
+		return fmt.Errorf("failed to delete risk limit: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err == nil && deleted == 0 {
+		return fmt.Errorf("risk limit %s not found", limitID)
+	}
+	return nil
+}
+
+// InMemoryLimitStore keeps risk limits in memory for testing and development
+type InMemoryLimitStore struct {
+	mu     sync.RWMutex
+	limits map[string]Limit
+}
+
+// NewInMemoryLimitStore creates a new in-memory limit store
+func NewInMemoryLimitStore() *InMemoryLimitStore {
+	return &InMemoryLimitStore{
+		limits: make(map[string]Limit),
+	}
+}
+
+// ListLimits returns every configured limit
+func (s *InMemoryLimitStore) ListLimits() (Limits, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	limits := make(Limits, 0, len(s.limits))
+	for _, limit := range s.limits {
+		limits = append(limits, limit)
+	}
+	sort.Slice(limits, func(i, j int) bool {
+		return limits[i].LimitID < limits[j].LimitID
+	})
+	return limits, nil
+}
+
+// SaveLimit inserts a limit, or replaces the limit with the same ID. Like the
+// risk_limits table, it allows one limit per type, user and security.
+func (s *InMemoryLimitStore) SaveLimit(limit *Limit) error {
+	if err := limit.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if limit.LimitID == "" {
+		limit.LimitID = uuid.New().String()
+	}
+	if limit.UpdatedAt.IsZero() {
+		limit.UpdatedAt = time.Now()
+	}
+	for id, existing := range s.limits {
+		if id != limit.LimitID && existing.Type == limit.Type && existing.UserID == limit.UserID && existing.SecurityID == limit.SecurityID {
+			return fmt.Errorf("a %s limit already exists for this user and security", limit.Type)
+		}
+	}
+
+	s.limits[limit.LimitID] = *limit
+	return nil
+}
+
+// DeleteLimit removes a limit
+func (s *InMemoryLimitStore) DeleteLimit(limitID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.limits[limitID]; !ok {
+		return fmt.Errorf("risk limit %s not found", limitID)
+	}
+	delete(s.limits, limitID)
+	return nil
+}
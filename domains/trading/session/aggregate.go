@@ -0,0 +1,180 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/shared/events"
+)
+
+// State is a phase of the trading day
+type State string
+
+const (
+	StateClosed         State = "closed"
+	StatePreOpen        State = "pre_open"        // Orders are entered but nothing matches
+	StateOpeningAuction State = "opening_auction" // Orders collect for the opening call auction
+	StateOpen           State = "open"            // Continuous trading
+	StateHalted         State = "halted"          // Orders are entered but nothing matches until a reopening auction
+	StateClosingAuction State = "closing_auction" // Orders collect for the closing call auction
+)
+
+// transitions lists the states each state can move to
+var transitions = map[State][]State{
+	StateClosed:         {StatePreOpen},
+	StatePreOpen:        {StateOpeningAuction, StateHalted, StateClosed},
+	StateOpeningAuction: {StateOpen, StateHalted, StateClosed},
+	StateOpen:           {StateClosingAuction, StateHalted, StateClosed},
+	StateHalted:         {StateOpeningAuction, StateClosed},
+	StateClosingAuction: {StateClosed, StateHalted},
+}
+
+// ParseState returns the state with the given name
+func ParseState(value string) (State, error) {
+	state := State(value)
+	if _, ok := transitions[state]; !ok {
+		return "", fmt.Errorf("unknown session state %q", value)
+	}
+	return state, nil
+}
+
+// CanTransitionTo returns true if the session can move from s to the state
+func (s State) CanTransitionTo(to State) bool {
+	for _, next := range transitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsOrders returns true if orders can be entered and modified in the
+// state. Orders can always be cancelled.
+func (s State) AcceptsOrders() bool {
+	return s != StateClosed
+}
+
+// IsAuction returns true if the state is an opening or closing auction
+func (s State) IsAuction() bool {
+	return s == StateOpeningAuction || s == StateClosingAuction
+}
+
+// AllowsMatching returns true if a matching run may execute in the state.
+// Continuous trading allows every kind of run; auction states only allow
+// the call auction that uncrosses them.
+func (s State) AllowsMatching(auction bool) bool {
+	if s == StateOpen {
+		return true
+	}
+	return auction && s.IsAuction()
+}
+
+// Effective returns the state a security trades in given the state of the
+// market session and of the security's own session. An open security
+// follows the market; any other security state overrides it, unless the
+// market is closed.
+func Effective(market, security State) State {
+	if market == StateClosed || security == StateOpen {
+		return market
+	}
+	return security
+}
+
+// Scope says whether a session is the whole market's or one security's
+type Scope string
+
+const (
+	ScopeMarket   Scope = "market"
+	ScopeSecurity Scope = "security"
+)
+
+// MarketSessionID is the aggregate ID of the market session
+const MarketSessionID = "session-market"
+
+// SecuritySessionID returns the aggregate ID of a security's session
+func SecuritySessionID(securityID string) string {
+	return "session-" + securityID
+}
+
+// SessionAggregate is the trading session of the market or of one security.
+// The market session starts closed. A security session starts open, which
+// means it follows the market, and only moves to halt the security or to run
+// its own auctions.
+type SessionAggregate struct {
+	events.AggregateRoot
+
+	Scope      Scope      `json:"scope"`
+	SecurityID string     `json:"securityId,omitempty"`
+	State      State      `json:"state"`
+	Reason     string     `json:"reason,omitempty"`
+	ChangedBy  string     `json:"changedBy,omitempty"`
+	ChangedAt  *time.Time `json:"changedAt,omitempty"`
+}
+
+// NewMarketSession creates the market session aggregate
+func NewMarketSession() *SessionAggregate {
+	return &SessionAggregate{
+		AggregateRoot: events.NewAggregateRoot(MarketSessionID, "Session"),
+		Scope:         ScopeMarket,
+		State:         StateClosed,
+	}
+}
+
+// NewSecuritySession creates the session aggregate of a security
+func NewSecuritySession(securityID string) *SessionAggregate {
+	return &SessionAggregate{
+		AggregateRoot: events.NewAggregateRoot(SecuritySessionID(securityID), "Session"),
+		Scope:         ScopeSecurity,
+		SecurityID:    securityID,
+		State:         StateOpen,
+	}
+}
+
+// IsManaged returns true once the session has changed state. Sessions that
+// never have are not enforced.
+func (s *SessionAggregate) IsManaged() bool {
+	return s.Version > 0
+}
+
+// Transition moves the session to a new state
+func (s *SessionAggregate) Transition(to State, reason, changedBy string) error {
+	if !s.State.CanTransitionTo(to) {
+		return fmt.Errorf("%s session cannot move from %s to %s", s.Scope, s.State, to)
+	}
+
+	event := NewSessionStateChanged(s.ID, s.Scope, s.SecurityID, s.State, to, reason, changedBy)
+	s.AddEvent(event)
+	return s.ApplyEvent(event)
+}
+
+// ApplyEvent applies an event to the aggregate
+func (s *SessionAggregate) ApplyEvent(event events.DomainEvent) error {
+	switch e := event.(type) {
+	case *SessionStateChanged:
+		return s.applySessionStateChanged(e)
+	default:
+		return fmt.Errorf("unknown event type: %T", event)
+	}
+}
+
+// LoadFromHistory loads the aggregate from a sequence of events
+func (s *SessionAggregate) LoadFromHistory(events []events.DomainEvent) error {
+	for _, event := range events {
+		if err := s.ApplyEvent(event); err != nil {
+			return fmt.Errorf("failed to apply event %s: %w", event.GetEventType(), err)
+		}
+	}
+	return nil
+}
+
+func (s *SessionAggregate) applySessionStateChanged(event *SessionStateChanged) error {
+	s.Scope = event.Scope
+	s.SecurityID = event.SecurityID
+	s.State = event.To
+	s.Reason = event.Reason
+	s.ChangedBy = event.ChangedBy
+	changedAt := event.Timestamp
+	s.ChangedAt = &changedAt
+	s.IncrementVersion()
+	return nil
+}
@@ -0,0 +1,53 @@
+package session
+
+import (
+	"encoding/json"
+
+	"securities-marketplace/domains/shared/events"
+)
+
+// Session Domain Events
+
+// SessionStateChanged event is emitted when the market or a security moves
+// to a new phase of the trading day
+type SessionStateChanged struct {
+	events.BaseEvent
+	Scope      Scope  `json:"scope"`
+	SecurityID string `json:"securityId,omitempty"`
+	From       State  `json:"from"`
+	To         State  `json:"to"`
+	Reason     string `json:"reason,omitempty"`
+	ChangedBy  string `json:"changedBy"`
+}
+
+// NewSessionStateChanged creates a new SessionStateChanged event
+func NewSessionStateChanged(sessionID string, scope Scope, securityID string, from, to State, reason, changedBy string) *SessionStateChanged {
+	return &SessionStateChanged{
+		BaseEvent:  events.NewBaseEvent(sessionID, "Session"),
+		Scope:      scope,
+		SecurityID: securityID,
+		From:       from,
+		To:         to,
+		Reason:     reason,
+		ChangedBy:  changedBy,
+	}
+}
+
+func (e *SessionStateChanged) GetEventType() string     { return "SessionStateChanged" }
+func (e *SessionStateChanged) GetAggregateID() string   { return e.AggregateID }
+func (e *SessionStateChanged) GetAggregateType() string { return e.AggregateType }
+
+func (e *SessionStateChanged) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *SessionStateChanged) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
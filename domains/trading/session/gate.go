@@ -0,0 +1,65 @@
+package session
+
+import (
+	"fmt"
+)
+
+// Gate answers whether a security's session allows order entry and
+// matching. Sessions are only enforced once the market session or the
+// security's session has changed state; until then everything is allowed.
+type Gate struct {
+	repository SessionRepository
+}
+
+// NewGate creates a new session gate
+func NewGate(repository SessionRepository) *Gate {
+	return &Gate{repository: repository}
+}
+
+// State returns the state a security trades in. managed is false while
+// sessions are not enforced for the security.
+func (g *Gate) State(securityID string) (state State, managed bool, err error) {
+	market, err := g.repository.FindMarket()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to load market session: %w", err)
+	}
+	security, err := g.repository.FindSecurity(securityID)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to load session of %s: %w", securityID, err)
+	}
+
+	switch {
+	case market.IsManaged():
+		return Effective(market.State, security.State), true, nil
+	case security.IsManaged():
+		return security.State, true, nil
+	default:
+		return "", false, nil
+	}
+}
+
+// CheckOrderEntry returns an error if orders in the security cannot be
+// entered or modified
+func (g *Gate) CheckOrderEntry(securityID string) error {
+	state, managed, err := g.State(securityID)
+	if err != nil {
+		return err
+	}
+	if managed && !state.AcceptsOrders() {
+		return fmt.Errorf("%s is not accepting orders while the session is %s", securityID, state)
+	}
+	return nil
+}
+
+// CheckMatching returns an error if a matching run on the security cannot
+// execute. auction is true for call auctions.
+func (g *Gate) CheckMatching(securityID string, auction bool) error {
+	state, managed, err := g.State(securityID)
+	if err != nil {
+		return err
+	}
+	if managed && !state.AllowsMatching(auction) {
+		return fmt.Errorf("%s cannot match while the session is %s", securityID, state)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"securities-marketplace/domains/shared/events"
+)
+
+// SessionRepository defines the interface for session persistence. Sessions
+// always exist: one that has never changed state is returned in its initial
+// state.
+type SessionRepository interface {
+	FindMarket() (*SessionAggregate, error)
+	FindSecurity(securityID string) (*SessionAggregate, error)
+}
+
+// EventSourcedSessionRepository implements SessionRepository using event sourcing
+type EventSourcedSessionRepository struct {
+	eventStore events.EventStore
+}
+
+// NewEventSourcedSessionRepository creates a new event-sourced session repository
+func NewEventSourcedSessionRepository(eventStore events.EventStore) *EventSourcedSessionRepository {
+	return &EventSourcedSessionRepository{
+		eventStore: eventStore,
+	}
+}
+
+// FindMarket loads the market session
+func (r *EventSourcedSessionRepository) FindMarket() (*SessionAggregate, error) {
+	return r.load(NewMarketSession())
+}
+
+// FindSecurity loads the session of a security
+func (r *EventSourcedSessionRepository) FindSecurity(securityID string) (*SessionAggregate, error) {
+	return r.load(NewSecuritySession(securityID))
+}
+
+// load replays the events of a session onto its initial state
+func (r *EventSourcedSessionRepository) load(session *SessionAggregate) (*SessionAggregate, error) {
+	eventRecords, err := r.eventStore.GetEvents(session.ID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	if len(eventRecords) == 0 {
+		return session, nil
+	}
+
+	var domainEvents []events.DomainEvent
+	for _, eventRecord := range eventRecords {
+		domainEvent, err := r.convertEventRecordToDomainEvent(eventRecord)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert event %s: %w", eventRecord.EventType, err)
+		}
+		domainEvents = append(domainEvents, domainEvent)
+	}
+
+	if err := session.LoadFromHistory(domainEvents); err != nil {
+		return nil, fmt.Errorf("failed to load from history: %w", err)
+	}
+
+	session.SetLastEventNumber(eventRecords[len(eventRecords)-1].EventNumber)
+
+	return session, nil
+}
+
+// convertEventRecordToDomainEvent converts a single event record to domain event
+func (r *EventSourcedSessionRepository) convertEventRecordToDomainEvent(eventRecord *events.Event) (events.DomainEvent, error) {
+	var event events.DomainEvent
+	switch eventRecord.EventType {
+	case "SessionStateChanged":
+		event = &SessionStateChanged{}
+	default:
+		return nil, fmt.Errorf("unknown event type: %s", eventRecord.EventType)
+	}
+
+	if err := json.Unmarshal(eventRecord.EventData, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize %s: %w", eventRecord.EventType, err)
+	}
+	return event, nil
+}
@@ -0,0 +1,95 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"securities-marketplace/domains/trading/calendar"
+)
+
+// dailyCycle is the order the market moves through its states on a normal
+// trading day, starting from closed
+var dailyCycle = []State{StateClosed, StatePreOpen, StateOpeningAuction, StateOpen, StateClosingAuction}
+
+// Schedule times the phases of the trading day around the calendar's
+// session. Pre-open ends at the open, the opening auction starts at the
+// open, and the closing auction ends at the close.
+type Schedule struct {
+	PreOpen        time.Duration
+	OpeningAuction time.Duration
+	ClosingAuction time.Duration
+}
+
+// DefaultSchedule returns an hour of pre-open order entry, a five minute
+// opening auction and a ten minute closing auction
+func DefaultSchedule() Schedule {
+	return Schedule{
+		PreOpen:        time.Hour,
+		OpeningAuction: 5 * time.Minute,
+		ClosingAuction: 10 * time.Minute,
+	}
+}
+
+// Validate checks that the phases fit in a trading session of the calendar
+func (s Schedule) Validate(cal *calendar.Calendar) error {
+	if s.PreOpen < 0 || s.OpeningAuction < 0 || s.ClosingAuction < 0 {
+		return fmt.Errorf("session phases cannot be negative")
+	}
+	if s.PreOpen > cal.Open {
+		return fmt.Errorf("pre-open cannot start before midnight")
+	}
+	if s.OpeningAuction+s.ClosingAuction >= cal.Close-cal.Open {
+		return fmt.Errorf("auctions leave no time for continuous trading")
+	}
+	return nil
+}
+
+// StateAt returns the state the market should be in at now
+func (s Schedule) StateAt(cal *calendar.Calendar, now time.Time) State {
+	open, close, ok := cal.Session(now)
+	switch {
+	case !ok || !now.Before(close) || now.Before(open.Add(-s.PreOpen)):
+		return StateClosed
+	case now.Before(open):
+		return StatePreOpen
+	case now.Before(open.Add(s.OpeningAuction)):
+		return StateOpeningAuction
+	case !now.Before(close.Add(-s.ClosingAuction)):
+		return StateClosingAuction
+	default:
+		return StateOpen
+	}
+}
+
+// Path returns the transitions that take the market from one state to
+// another along the daily cycle. A target earlier in the day than the
+// current state closes the market first rather than running the rest of the
+// day. A halted market only moves on by a direct transition, such as closing
+// at the end of the day; reopening it is left to the operator.
+func Path(from, to State) []State {
+	if from == to {
+		return nil
+	}
+
+	i, j := cycleIndex(from), cycleIndex(to)
+	if i < 0 || j < 0 {
+		if from.CanTransitionTo(to) {
+			return []State{to}
+		}
+		return nil
+	}
+
+	if j <= i {
+		return append([]State{StateClosed}, dailyCycle[1:j+1]...)
+	}
+	return append([]State(nil), dailyCycle[i+1:j+1]...)
+}
+
+func cycleIndex(state State) int {
+	for i, s := range dailyCycle {
+		if s == state {
+			return i
+		}
+	}
+	return -1
+}
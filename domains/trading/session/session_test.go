@@ -0,0 +1,67 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"securities-marketplace/domains/shared/testutil"
+	"securities-marketplace/domains/trading/calendar"
+)
+
+func TestSession_TransitionsFollowTheTradingDay(t *testing.T) {
+	market := NewMarketSession()
+	testutil.AssertFalse(t, market.IsManaged(), "New sessions should not be enforced")
+	testutil.AssertError(t, market.Transition(StateOpen, "", "admin"), "Market should not open without pre-open")
+
+	for _, state := range []State{StatePreOpen, StateOpeningAuction, StateOpen, StateHalted, StateOpeningAuction, StateOpen, StateClosingAuction, StateClosed} {
+		testutil.AssertNoError(t, market.Transition(state, "", "admin"), "Transition to "+string(state)+" should be allowed")
+	}
+	testutil.AssertTrue(t, market.IsManaged(), "Sessions should be enforced once they change state")
+	testutil.AssertLengthEqual(t, 8, market.GetUncommittedEvents(), "Every transition should be recorded")
+
+	changed := market.GetUncommittedEvents()[3].(*SessionStateChanged)
+	testutil.AssertEqual(t, StateOpen, changed.From, "Event should record the previous state")
+	testutil.AssertEqual(t, StateHalted, changed.To, "Event should record the new state")
+}
+
+func TestState_GatesOrdersAndMatching(t *testing.T) {
+	testutil.AssertFalse(t, StateClosed.AcceptsOrders(), "Closed market should not take orders")
+	testutil.AssertTrue(t, StatePreOpen.AcceptsOrders(), "Pre-open should take orders")
+	testutil.AssertFalse(t, StatePreOpen.AllowsMatching(true), "Pre-open should not match")
+	testutil.AssertTrue(t, StateOpeningAuction.AllowsMatching(true), "Opening auction should uncross")
+	testutil.AssertFalse(t, StateClosingAuction.AllowsMatching(false), "Closing auction should not trade continuously")
+	testutil.AssertTrue(t, StateOpen.AllowsMatching(false), "Continuous trading should match")
+	testutil.AssertFalse(t, StateHalted.AllowsMatching(true), "Halted session should not match")
+
+	testutil.AssertEqual(t, StateClosingAuction, Effective(StateClosingAuction, StateOpen), "Open security should follow the market")
+	testutil.AssertEqual(t, StateHalted, Effective(StateOpen, StateHalted), "Halted security should override the market")
+	testutil.AssertEqual(t, StateClosed, Effective(StateClosed, StateHalted), "Closed market should override the security")
+}
+
+func TestSchedule_StateAtAndPath(t *testing.T) {
+	cal := calendar.Default()
+	schedule := DefaultSchedule()
+	testutil.AssertNoError(t, schedule.Validate(cal), "Default schedule should fit the default calendar")
+
+	day := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC) // A Wednesday
+	tests := []struct {
+		at    time.Duration
+		state State
+	}{
+		{8 * time.Hour, StateClosed},
+		{8*time.Hour + 30*time.Minute, StatePreOpen},
+		{9*time.Hour + 32*time.Minute, StateOpeningAuction},
+		{12 * time.Hour, StateOpen},
+		{15*time.Hour + 55*time.Minute, StateClosingAuction},
+		{16 * time.Hour, StateClosed},
+	}
+	for _, tt := range tests {
+		testutil.AssertEqual(t, tt.state, schedule.StateAt(cal, day.Add(tt.at)), "State at "+tt.at.String())
+	}
+	testutil.AssertEqual(t, StateClosed, schedule.StateAt(cal, day.AddDate(0, 0, 3).Add(12*time.Hour)), "Market should stay closed at weekends")
+
+	testutil.AssertEqual(t, []State{StatePreOpen, StateOpeningAuction, StateOpen}, Path(StateClosed, StateOpen), "Market should pass through every phase")
+	testutil.AssertEqual(t, []State{StateClosed, StatePreOpen}, Path(StateOpen, StatePreOpen), "Missed closes should close rather than auction")
+	testutil.AssertEqual(t, []State{StateClosed}, Path(StateHalted, StateClosed), "Halted market should close")
+	testutil.AssertLengthEqual(t, 0, Path(StateHalted, StateOpen), "Halted market should wait for the operator")
+}
@@ -91,6 +91,10 @@ type UserAggregate struct {
 	// Compliance information
 	Compliance ComplianceInfo `json:"compliance"`
 	
+	// Beneficial owner or broker controlling the account; empty when the
+	// user only trades for themselves
+	BeneficialOwnerID string `json:"beneficialOwnerId,omitempty"`
+	
 	// Suspension details (if applicable)
 	SuspendedAt     *time.Time `json:"suspendedAt,omitempty"`
 	SuspensionUntil *time.Time `json:"suspensionUntil,omitempty"`
@@ -219,6 +223,27 @@ func (u *UserAggregate) UpdateProfile(updatedFields map[string]interface{}, upda
 	return u.ApplyEvent(event)
 }
 
+// LinkBeneficialOwner links the account to the beneficial owner or broker
+// that controls it. Accounts with the same owner are treated as one party when
+// preventing self-trades. An empty owner unlinks the account.
+func (u *UserAggregate) LinkBeneficialOwner(beneficialOwnerID, linkedBy string) error {
+	if u.Status == "" {
+		return fmt.Errorf("user is not registered")
+	}
+
+	if beneficialOwnerID == u.ID {
+		return fmt.Errorf("user cannot be their own beneficial owner")
+	}
+
+	if beneficialOwnerID == u.BeneficialOwnerID {
+		return fmt.Errorf("account is already linked to this beneficial owner")
+	}
+
+	event := NewBeneficialOwnerLinked(u.ID, beneficialOwnerID, linkedBy)
+	u.AddEvent(event)
+	return u.ApplyEvent(event)
+}
+
 // ApplyEvent applies an event to the aggregate
 func (u *UserAggregate) ApplyEvent(event events.DomainEvent) error {
 	switch e := event.(type) {
@@ -238,6 +263,8 @@ func (u *UserAggregate) ApplyEvent(event events.DomainEvent) error {
 		return u.applyUserReinstated(e)
 	case *UserProfileUpdated:
 		return u.applyUserProfileUpdated(e)
+	case *BeneficialOwnerLinked:
+		return u.applyBeneficialOwnerLinked(e)
 	default:
 		return fmt.Errorf("unknown event type: %T", event)
 	}
@@ -373,6 +400,13 @@ func (u *UserAggregate) applyUserProfileUpdated(event *UserProfileUpdated) error
 	return nil
 }
 
+func (u *UserAggregate) applyBeneficialOwnerLinked(event *BeneficialOwnerLinked) error {
+	u.BeneficialOwnerID = event.BeneficialOwnerID
+	
+	u.IncrementVersion()
+	return nil
+}
+
 // Helper methods
 
 func (u *UserAggregate) updateOverallComplianceStatus() {
@@ -393,6 +427,15 @@ func (u *UserAggregate) updateOverallComplianceStatus() {
 	}
 }
 
+// GetBeneficialOwnerID returns the owner the account trades for: its linked
+// beneficial owner, or the user themselves
+func (u *UserAggregate) GetBeneficialOwnerID() string {
+	if u.BeneficialOwnerID != "" {
+		return u.BeneficialOwnerID
+	}
+	return u.ID
+}
+
 // IsAccredited returns true if the user is currently accredited
 func (u *UserAggregate) IsAccredited() bool {
 	if u.Accreditation.Status != AccreditationStatusVerified {
@@ -73,6 +73,13 @@ type UpdateUserProfileCommand struct {
 	UpdatedBy     string                 `json:"updatedBy"`
 }
 
+// LinkBeneficialOwnerCommand represents a command to link an account to its beneficial owner
+type LinkBeneficialOwnerCommand struct {
+	UserID            string `json:"userId"`
+	BeneficialOwnerID string `json:"beneficialOwnerId"`
+	LinkedBy          string `json:"linkedBy"`
+}
+
 // AuthenticateUserCommand represents a command to authenticate a user
 type AuthenticateUserCommand struct {
 	Email    string `json:"email"`
@@ -229,6 +236,17 @@ func (c *UpdateUserProfileCommand) Validate() error {
 	return nil
 }
 
+// Validate validates the LinkBeneficialOwnerCommand
+func (c *LinkBeneficialOwnerCommand) Validate() error {
+	if c.UserID == "" {
+		return NewValidationError("userId", "User ID is required")
+	}
+	if c.LinkedBy == "" {
+		return NewValidationError("linkedBy", "Linked by is required")
+	}
+	return nil
+}
+
 // Validate validates the AuthenticateUserCommand
 func (c *AuthenticateUserCommand) Validate() error {
 	if c.Email == "" {
@@ -304,4 +304,39 @@ func (e *UserProfileUpdated) GetMetadata() events.Metadata {
 		EventVersion:  e.Version,
 		Timestamp:     e.Timestamp,
 	}
-}
\ No newline at end of file
+}
+
+// BeneficialOwnerLinked event is emitted when a user's account is linked to
+// the beneficial owner or broker that controls it
+type BeneficialOwnerLinked struct {
+	events.BaseEvent
+	BeneficialOwnerID string `json:"beneficialOwnerId"`
+	LinkedBy          string `json:"linkedBy"`
+}
+
+func NewBeneficialOwnerLinked(userID, beneficialOwnerID, linkedBy string) *BeneficialOwnerLinked {
+	return &BeneficialOwnerLinked{
+		BaseEvent:         events.NewBaseEvent(userID, "User"),
+		BeneficialOwnerID: beneficialOwnerID,
+		LinkedBy:          linkedBy,
+	}
+}
+
+func (e *BeneficialOwnerLinked) GetEventType() string { return "BeneficialOwnerLinked" }
+func (e *BeneficialOwnerLinked) GetAggregateID() string { return e.AggregateID }
+func (e *BeneficialOwnerLinked) GetAggregateType() string { return e.AggregateType }
+
+func (e *BeneficialOwnerLinked) GetEventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *BeneficialOwnerLinked) GetMetadata() events.Metadata {
+	return events.Metadata{
+		EventID:       e.EventID,
+		EventType:     e.GetEventType(),
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		EventVersion:  e.Version,
+		Timestamp:     e.Timestamp,
+	}
+}
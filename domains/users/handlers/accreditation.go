@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"securities-marketplace/domains/users"
+	"securities-marketplace/domains/shared/audit"
 	"securities-marketplace/domains/shared/events"
 )
 
@@ -92,7 +93,7 @@ func (h *AccreditationHandler) SubmitAccreditation(w http.ResponseWriter, r *htt
 		},
 	}
 
-	if err := h.processSubmitAccreditation(cmd); err != nil {
+	if err := h.processSubmitAccreditation(r, cmd); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -114,7 +115,7 @@ func (h *AccreditationHandler) HandleAPISubmitAccreditation(w http.ResponseWrite
 
 	cmd.UserID = userID
 
-	if err := h.processSubmitAccreditation(&cmd); err != nil {
+	if err := h.processSubmitAccreditation(r, &cmd); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -124,6 +125,7 @@ func (h *AccreditationHandler) HandleAPISubmitAccreditation(w http.ResponseWrite
 		"message": "Accreditation submitted successfully",
 	}
 
+	events.SetConsistencyToken(w, userEventNumber(h.userService, userID))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -141,7 +143,7 @@ func (h *AccreditationHandler) HandleAPIVerifyAccreditation(w http.ResponseWrite
 
 	cmd.UserID = userID
 
-	if err := h.processVerifyAccreditation(&cmd); err != nil {
+	if err := h.processVerifyAccreditation(r, &cmd); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -151,6 +153,7 @@ func (h *AccreditationHandler) HandleAPIVerifyAccreditation(w http.ResponseWrite
 		"message": "Accreditation verified successfully",
 	}
 
+	events.SetConsistencyToken(w, userEventNumber(h.userService, userID))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -168,7 +171,7 @@ func (h *AccreditationHandler) HandleAPIRevokeAccreditation(w http.ResponseWrite
 
 	cmd.UserID = userID
 
-	if err := h.processRevokeAccreditation(&cmd); err != nil {
+	if err := h.processRevokeAccreditation(r, &cmd); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -178,21 +181,22 @@ func (h *AccreditationHandler) HandleAPIRevokeAccreditation(w http.ResponseWrite
 		"message": "Accreditation revoked successfully",
 	}
 
+	events.SetConsistencyToken(w, userEventNumber(h.userService, userID))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
 // processSubmitAccreditation handles the core accreditation submission logic
-func (h *AccreditationHandler) processSubmitAccreditation(cmd *users.SubmitAccreditationCommand) error {
-	return h.userService.SubmitAccreditation(cmd)
+func (h *AccreditationHandler) processSubmitAccreditation(r *http.Request, cmd *users.SubmitAccreditationCommand) error {
+	return h.userService.WithActor(audit.ActorFromContext(r.Context())).SubmitAccreditation(cmd)
 }
 
 // processVerifyAccreditation handles the core accreditation verification logic
-func (h *AccreditationHandler) processVerifyAccreditation(cmd *users.VerifyAccreditationCommand) error {
-	return h.userService.VerifyAccreditation(cmd)
+func (h *AccreditationHandler) processVerifyAccreditation(r *http.Request, cmd *users.VerifyAccreditationCommand) error {
+	return h.userService.WithActor(audit.ActorFromContext(r.Context())).VerifyAccreditation(cmd)
 }
 
 // processRevokeAccreditation handles the core accreditation revocation logic
-func (h *AccreditationHandler) processRevokeAccreditation(cmd *users.RevokeAccreditationCommand) error {
-	return h.userService.RevokeAccreditation(cmd)
+func (h *AccreditationHandler) processRevokeAccreditation(r *http.Request, cmd *users.RevokeAccreditationCommand) error {
+	return h.userService.WithActor(audit.ActorFromContext(r.Context())).RevokeAccreditation(cmd)
 }
\ No newline at end of file
@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"securities-marketplace/domains/users"
+	"securities-marketplace/domains/shared/audit"
 	"securities-marketplace/domains/shared/events"
 )
 
@@ -78,13 +79,18 @@ func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		UpdatedBy:     getCurrentUserID(r), // Get from session/JWT
 	}
 
-	if err := h.processUpdateProfile(cmd); err != nil {
+	if err := h.processUpdateProfile(r, cmd); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Redirect back to profile
-	http.Redirect(w, r, "/users/"+userID+"/profile?updated=true", http.StatusSeeOther)
+	// Redirect back to profile, carrying the consistency token so the page
+	// waits for the projection to reflect the update
+	redirectURL := "/users/" + userID + "/profile?updated=true"
+	if eventNumber := userEventNumber(h.userService, userID); eventNumber > 0 {
+		redirectURL += "&" + events.ConsistencyTokenQueryParam + "=" + events.FormatConsistencyToken(eventNumber)
+	}
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
 }
 
 // HandleAPIGetProfile handles JSON API profile retrieval
@@ -146,7 +152,7 @@ func (h *ProfileHandler) HandleAPIUpdateProfile(w http.ResponseWriter, r *http.R
 	cmd.UserID = userID
 	cmd.UpdatedBy = getCurrentUserID(r)
 
-	if err := h.processUpdateProfile(&cmd); err != nil {
+	if err := h.processUpdateProfile(r, &cmd); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -156,6 +162,7 @@ func (h *ProfileHandler) HandleAPIUpdateProfile(w http.ResponseWriter, r *http.R
 		"message": "Profile updated successfully",
 	}
 
+	events.SetConsistencyToken(w, userEventNumber(h.userService, userID))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -174,7 +181,7 @@ func (h *ProfileHandler) HandleAPISuspendUser(w http.ResponseWriter, r *http.Req
 	cmd.UserID = userID
 	cmd.SuspendedBy = getCurrentUserID(r)
 
-	if err := h.processSuspendUser(&cmd); err != nil {
+	if err := h.processSuspendUser(r, &cmd); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -184,6 +191,7 @@ func (h *ProfileHandler) HandleAPISuspendUser(w http.ResponseWriter, r *http.Req
 		"message": "User suspended successfully",
 	}
 
+	events.SetConsistencyToken(w, userEventNumber(h.userService, userID))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -202,7 +210,7 @@ func (h *ProfileHandler) HandleAPIReinstateUser(w http.ResponseWriter, r *http.R
 	cmd.UserID = userID
 	cmd.ReinstatedBy = getCurrentUserID(r)
 
-	if err := h.processReinstateUser(&cmd); err != nil {
+	if err := h.processReinstateUser(r, &cmd); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -212,23 +220,34 @@ func (h *ProfileHandler) HandleAPIReinstateUser(w http.ResponseWriter, r *http.R
 		"message": "User reinstated successfully",
 	}
 
+	events.SetConsistencyToken(w, userEventNumber(h.userService, userID))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
 // processUpdateProfile handles the core profile update logic
-func (h *ProfileHandler) processUpdateProfile(cmd *users.UpdateUserProfileCommand) error {
-	return h.userService.UpdateUserProfile(cmd)
+func (h *ProfileHandler) processUpdateProfile(r *http.Request, cmd *users.UpdateUserProfileCommand) error {
+	return h.userService.WithActor(audit.ActorFromContext(r.Context())).UpdateUserProfile(cmd)
 }
 
 // processSuspendUser handles the core user suspension logic
-func (h *ProfileHandler) processSuspendUser(cmd *users.SuspendUserCommand) error {
-	return h.userService.SuspendUser(cmd)
+func (h *ProfileHandler) processSuspendUser(r *http.Request, cmd *users.SuspendUserCommand) error {
+	return h.userService.WithActor(audit.ActorFromContext(r.Context())).SuspendUser(cmd)
 }
 
 // processReinstateUser handles the core user reinstatement logic
-func (h *ProfileHandler) processReinstateUser(cmd *users.ReinstateUserCommand) error {
-	return h.userService.ReinstateUser(cmd)
+func (h *ProfileHandler) processReinstateUser(r *http.Request, cmd *users.ReinstateUserCommand) error {
+	return h.userService.WithActor(audit.ActorFromContext(r.Context())).ReinstateUser(cmd)
+}
+
+// userEventNumber returns the global position of the user's latest event for
+// use as a consistency token, or zero if it cannot be determined
+func userEventNumber(userService *users.UserService, userID string) int64 {
+	user, err := userService.GetUser(userID)
+	if err != nil {
+		return 0
+	}
+	return user.GetLastEventNumber()
 }
 
 // getCurrentUserID extracts the current user ID from the request context
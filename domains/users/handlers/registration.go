@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"securities-marketplace/domains/users"
+	"securities-marketplace/domains/shared/audit"
 	"securities-marketplace/domains/shared/events"
 )
 
@@ -60,7 +61,7 @@ func (h *RegistrationHandler) HandleRegistration(w http.ResponseWriter, r *http.
 		},
 	}
 
-	if err := h.processRegistration(cmd); err != nil {
+	if _, err := h.processRegistration(r, cmd); err != nil {
 		// TODO: Implement template rendering with error data
 	w.Header().Set("Content-Type", "text/html")
 	_, _ = w.Write([]byte("<h1>Registration</h1>"))
@@ -79,7 +80,8 @@ func (h *RegistrationHandler) HandleAPIRegistration(w http.ResponseWriter, r *ht
 		return
 	}
 
-	if err := h.processRegistration(&cmd); err != nil {
+	user, err := h.processRegistration(r, &cmd)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -90,14 +92,14 @@ func (h *RegistrationHandler) HandleAPIRegistration(w http.ResponseWriter, r *ht
 		"userId":  cmd.UserID,
 	}
 
+	events.SetConsistencyToken(w, user.GetLastEventNumber())
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
 // processRegistration handles the core registration logic
-func (h *RegistrationHandler) processRegistration(cmd *users.RegisterUserCommand) error {
-	_, err := h.userService.RegisterUser(cmd)
-	return err
+func (h *RegistrationHandler) processRegistration(r *http.Request, cmd *users.RegisterUserCommand) (*users.UserAggregate, error) {
+	return h.userService.WithActor(audit.ActorFromContext(r.Context())).RegisterUser(cmd)
 }
 
 // generateUserID generates a unique user ID
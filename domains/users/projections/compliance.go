@@ -10,6 +10,9 @@ import (
 	"securities-marketplace/domains/users"
 )
 
+// ComplianceProjectionName is the checkpoint name of the compliance projection
+const ComplianceProjectionName = "compliance_projection"
+
 // ComplianceProjection maintains read models for compliance records
 type ComplianceProjection struct {
 	db *sql.DB
@@ -41,12 +44,23 @@ func (p *ComplianceProjection) Handle(event events.DomainEvent) error {
 
 // GetProjectionName returns the name of this projection
 func (p *ComplianceProjection) GetProjectionName() string {
-	return "compliance_projection"
+	return ComplianceProjectionName
 }
 
-// handleUserRegistered creates a new compliance record
+// handleUserRegistered creates a new compliance record. A record left from
+// before a projection reset is replaced, so replays start the user over.
 func (p *ComplianceProjection) handleUserRegistered(event *users.UserRegistered) error {
 	recordID := fmt.Sprintf("compliance-%s", event.AggregateID)
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM compliance_records WHERE user_id = $1", event.AggregateID); err != nil {
+		return fmt.Errorf("failed to replace compliance record: %w", err)
+	}
 	
 	query := `
 		INSERT INTO compliance_records (
@@ -58,7 +72,7 @@ func (p *ComplianceProjection) handleUserRegistered(event *users.UserRegistered)
 
 	riskFactors, _ := json.Marshal([]string{})
 
-	_, err := p.db.Exec(query,
+	_, err = tx.Exec(query,
 		recordID,
 		event.AggregateID,
 		0,              // initial risk score
@@ -68,8 +82,11 @@ func (p *ComplianceProjection) handleUserRegistered(event *users.UserRegistered)
 		event.Timestamp,
 		event.Timestamp,
 	)
+	if err != nil {
+		return fmt.Errorf("failed to insert compliance record: %w", err)
+	}
 
-	return err
+	return tx.Commit()
 }
 
 // handleComplianceCheckPerformed updates compliance records
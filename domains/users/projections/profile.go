@@ -10,6 +10,14 @@ import (
 	"securities-marketplace/domains/users"
 )
 
+// UserProfileProjectionName is the checkpoint name of the user profile projection
+const UserProfileProjectionName = "user_profile_projection"
+
+// ProjectionNames lists the user read model projections run by the worker
+func ProjectionNames() []string {
+	return []string{UserProfileProjectionName, ComplianceProjectionName}
+}
+
 // UserProfileProjection maintains read models for user profiles
 type UserProfileProjection struct {
 	db *sql.DB
@@ -49,11 +57,22 @@ func (p *UserProfileProjection) Handle(event events.DomainEvent) error {
 
 // GetProjectionName returns the name of this projection
 func (p *UserProfileProjection) GetProjectionName() string {
-	return "user_profile_projection"
+	return UserProfileProjectionName
 }
 
-// handleUserRegistered creates a new user profile record
+// handleUserRegistered creates a new user profile record. A profile left
+// from before a projection reset is replaced, so replays start the user over.
 func (p *UserProfileProjection) handleUserRegistered(event *users.UserRegistered) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM user_profiles WHERE user_id = $1", event.AggregateID); err != nil {
+		return fmt.Errorf("failed to replace user profile: %w", err)
+	}
+
 	query := `
 		INSERT INTO user_profiles (
 			user_id, email, first_name, last_name,
@@ -65,7 +84,7 @@ func (p *UserProfileProjection) handleUserRegistered(event *users.UserRegistered
 
 	accreditationDocs, _ := json.Marshal([]interface{}{})
 
-	_, err := p.db.Exec(query,
+	_, err = tx.Exec(query,
 		event.AggregateID,
 		event.Email,
 		event.FirstName,
@@ -87,13 +106,16 @@ func (p *UserProfileProjection) handleUserRegistered(event *users.UserRegistered
 	}
 
 	// Also update accreditation_documents as empty array
-	_, err = p.db.Exec(
+	_, err = tx.Exec(
 		"UPDATE user_profiles SET accreditation_documents = $1 WHERE user_id = $2",
 		accreditationDocs,
 		event.AggregateID,
 	)
+	if err != nil {
+		return fmt.Errorf("failed to update accreditation documents: %w", err)
+	}
 
-	return err
+	return tx.Commit()
 }
 
 // handleAccreditationSubmitted updates accreditation information
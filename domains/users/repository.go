@@ -2,6 +2,7 @@ package users
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"securities-marketplace/domains/shared/events"
@@ -67,6 +68,10 @@ func (r *EventSourcedUserRepository) FindByID(userID string) (*UserAggregate, er
 		return nil, fmt.Errorf("failed to load from history: %w", err)
 	}
 
+	if len(eventRecords) > 0 {
+		user.SetLastEventNumber(eventRecords[len(eventRecords)-1].EventNumber)
+	}
+
 	return user, nil
 }
 
@@ -182,44 +187,93 @@ func (r *EventSourcedUserRepository) convertEventRecordToDomainEvent(eventRecord
 		return r.deserializeUserReinstated(eventRecord.EventData)
 	case "UserProfileUpdated":
 		return r.deserializeUserProfileUpdated(eventRecord.EventData)
+	case "BeneficialOwnerLinked":
+		return r.deserializeBeneficialOwnerLinked(eventRecord.EventData)
 	default:
 		return nil, fmt.Errorf("unknown event type: %s", eventRecord.EventType)
 	}
 }
 
-// Event deserialization methods (simplified implementations)
+// DecodeEvent converts a stored event into a user domain event for projection
+// runners. Events from other aggregates are skipped with a nil event.
+func (r *EventSourcedUserRepository) DecodeEvent(eventRecord *events.Event) (events.DomainEvent, error) {
+	if eventRecord.AggregateType != "User" {
+		return nil, nil
+	}
+	return r.convertEventRecordToDomainEvent(eventRecord)
+}
+
+// Event deserialization methods
 func (r *EventSourcedUserRepository) deserializeUserRegistered(data []byte) (*UserRegistered, error) {
-	// Implementation would deserialize JSON to UserRegistered
-	// For now, return a basic event
-	return &UserRegistered{}, nil
+	event := &UserRegistered{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize UserRegistered: %w", err)
+	}
+	return event, nil
 }
 
 func (r *EventSourcedUserRepository) deserializeAccreditationSubmitted(data []byte) (*AccreditationSubmitted, error) {
-	return &AccreditationSubmitted{}, nil
+	event := &AccreditationSubmitted{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize AccreditationSubmitted: %w", err)
+	}
+	return event, nil
 }
 
 func (r *EventSourcedUserRepository) deserializeAccreditationVerified(data []byte) (*AccreditationVerified, error) {
-	return &AccreditationVerified{}, nil
+	event := &AccreditationVerified{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize AccreditationVerified: %w", err)
+	}
+	return event, nil
 }
 
 func (r *EventSourcedUserRepository) deserializeAccreditationRevoked(data []byte) (*AccreditationRevoked, error) {
-	return &AccreditationRevoked{}, nil
+	event := &AccreditationRevoked{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize AccreditationRevoked: %w", err)
+	}
+	return event, nil
 }
 
 func (r *EventSourcedUserRepository) deserializeComplianceCheckPerformed(data []byte) (*ComplianceCheckPerformed, error) {
-	return &ComplianceCheckPerformed{}, nil
+	event := &ComplianceCheckPerformed{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize ComplianceCheckPerformed: %w", err)
+	}
+	return event, nil
 }
 
 func (r *EventSourcedUserRepository) deserializeUserSuspended(data []byte) (*UserSuspended, error) {
-	return &UserSuspended{}, nil
+	event := &UserSuspended{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize UserSuspended: %w", err)
+	}
+	return event, nil
 }
 
 func (r *EventSourcedUserRepository) deserializeUserReinstated(data []byte) (*UserReinstated, error) {
-	return &UserReinstated{}, nil
+	event := &UserReinstated{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize UserReinstated: %w", err)
+	}
+	return event, nil
 }
 
 func (r *EventSourcedUserRepository) deserializeUserProfileUpdated(data []byte) (*UserProfileUpdated, error) {
-	return &UserProfileUpdated{}, nil
+	event := &UserProfileUpdated{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize UserProfileUpdated: %w", err)
+	}
+	return event, nil
+}
+
+func (r *EventSourcedUserRepository) deserializeBeneficialOwnerLinked(data []byte) (*BeneficialOwnerLinked, error) {
+	event := &BeneficialOwnerLinked{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("failed to deserialize BeneficialOwnerLinked: %w", err)
+	}
+	return event, nil
 }
 
 // NotFoundError represents a resource not found error
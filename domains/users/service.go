@@ -10,6 +10,7 @@ import (
 	"golang.org/x/crypto/argon2"
 	"github.com/google/uuid"
 	
+	"securities-marketplace/domains/shared/audit"
 	"securities-marketplace/domains/shared/events"
 )
 
@@ -18,6 +19,8 @@ type UserService struct {
 	repository UserRepository
 	eventStore events.EventStore
 	eventBus   events.EventBus
+	auditLog   audit.Logger
+	actor      *audit.Actor
 }
 
 // NewUserService creates a new user service
@@ -29,6 +32,19 @@ func NewUserService(repository UserRepository, eventStore events.EventStore, eve
 	}
 }
 
+// SetAuditLogger enables audit logging of every command executed through the service
+func (s *UserService) SetAuditLogger(logger audit.Logger) {
+	s.auditLog = logger
+}
+
+// WithActor returns a copy of the service that attributes its audit entries to
+// the given actor. Handlers pass the actor from the request context.
+func (s *UserService) WithActor(actor *audit.Actor) *UserService {
+	scoped := *s
+	scoped.actor = actor
+	return &scoped
+}
+
 // RegisterUser handles user registration
 func (s *UserService) RegisterUser(cmd *RegisterUserCommand) (*UserAggregate, error) {
 	if err := cmd.Validate(); err != nil {
@@ -204,6 +220,25 @@ func (s *UserService) UpdateUserProfile(cmd *UpdateUserProfileCommand) error {
 	return s.saveAggregateEvents(user, cmd.UpdatedBy)
 }
 
+// LinkBeneficialOwner handles linking an account to its beneficial owner
+func (s *UserService) LinkBeneficialOwner(cmd *LinkBeneficialOwnerCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	user, err := s.repository.FindByID(cmd.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	err = user.LinkBeneficialOwner(cmd.BeneficialOwnerID, cmd.LinkedBy)
+	if err != nil {
+		return fmt.Errorf("failed to link beneficial owner: %w", err)
+	}
+
+	return s.saveAggregateEvents(user, cmd.LinkedBy)
+}
+
 // AuthenticateUser handles user authentication
 func (s *UserService) AuthenticateUser(cmd *AuthenticateUserCommand) (*UserAggregate, error) {
 	if err := cmd.Validate(); err != nil {
@@ -308,6 +343,14 @@ func (s *UserService) saveAggregateEvents(user *UserAggregate, userID string) er
 		return nil
 	}
 
+	// Capture the stored state before this command for the audit diff
+	var before *UserAggregate
+	if s.auditLog != nil {
+		if previous, err := s.repository.FindByID(user.ID); err == nil {
+			before = previous
+		}
+	}
+
 	// Convert domain events to event store events
 	var events []*events.Event
 	correlationID := uuid.New().String()
@@ -331,9 +374,14 @@ func (s *UserService) saveAggregateEvents(user *UserAggregate, userID string) er
 	// Save events
 	err := s.eventStore.SaveEvents(events)
 	if err != nil {
+		s.recordAudit(before, user, uncommittedEvents, userID, correlationID, err)
 		return fmt.Errorf("failed to save events: %w", err)
 	}
 
+	// Remember where this command landed in the global stream so callers can
+	// return it as a consistency token
+	user.SetLastEventNumber(events[len(events)-1].EventNumber)
+
 	// Publish events to event bus
 	for _, domainEvent := range uncommittedEvents {
 		err = s.eventBus.Publish(domainEvent)
@@ -344,8 +392,33 @@ func (s *UserService) saveAggregateEvents(user *UserAggregate, userID string) er
 		}
 	}
 
+	s.recordAudit(before, user, uncommittedEvents, userID, correlationID, nil)
+
 	// Mark events as committed
 	user.MarkEventsAsCommitted()
 
 	return nil
-}
\ No newline at end of file
+}
+
+// recordAudit writes an audit entry for a command's events and state change
+func (s *UserService) recordAudit(before, after *UserAggregate, uncommittedEvents []events.DomainEvent, userID, correlationID string, cmdErr error) {
+	if s.auditLog == nil {
+		return
+	}
+
+	err := audit.RecordCommand(s.auditLog, audit.CommandRecord{
+		AggregateID:   after.ID,
+		AggregateType: after.Type,
+		UserID:        userID,
+		Actor:         s.actor,
+		CorrelationID: correlationID,
+		Before:        before,
+		After:         after,
+		Events:        uncommittedEvents,
+		Err:           cmdErr,
+	})
+	if err != nil {
+		// Log error but don't fail the operation
+		fmt.Printf("Failed to record audit entry for %s %s: %v\n", after.Type, after.ID, err)
+	}
+}
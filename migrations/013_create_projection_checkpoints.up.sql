@@ -0,0 +1,22 @@
+-- Give every stored event a global, monotonically increasing position and
+-- add the columns the event store writes alongside it
+ALTER TABLE events ADD COLUMN IF NOT EXISTS event_number BIGSERIAL;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS metadata JSONB;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE events ADD COLUMN IF NOT EXISTS session_id VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_event_number ON events(event_number);
+
+-- Create projection checkpoints table tracking the last event_number each
+-- projection has applied. Queries compare it against consistency tokens.
+CREATE TABLE projection_checkpoints (
+    projection_name VARCHAR(100) PRIMARY KEY,
+    last_processed_event_number BIGINT NOT NULL DEFAULT 0,
+    last_processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+
+    CHECK (status IN ('active', 'rebuilding', 'failed')),
+    CHECK (last_processed_event_number >= 0)
+);
+
+CREATE INDEX idx_projection_checkpoints_status ON projection_checkpoints(status);
@@ -0,0 +1,10 @@
+-- Track projection runner statistics and errors so operators can judge
+-- whether read models are stale, and allow projections to be paused
+ALTER TABLE projection_checkpoints ADD COLUMN events_processed BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE projection_checkpoints ADD COLUMN throughput DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE projection_checkpoints ADD COLUMN last_error TEXT;
+ALTER TABLE projection_checkpoints ADD COLUMN last_error_at TIMESTAMPTZ;
+
+ALTER TABLE projection_checkpoints DROP CONSTRAINT IF EXISTS projection_checkpoints_status_check;
+ALTER TABLE projection_checkpoints ADD CONSTRAINT projection_checkpoints_status_check
+    CHECK (status IN ('active', 'rebuilding', 'paused', 'failed'));
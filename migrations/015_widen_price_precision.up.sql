@@ -0,0 +1,30 @@
+-- Per-share prices and trade and bid amounts are fixed-point decimals with
+-- six places; store them at that scale without rounding to cents.
+ALTER TABLE listings_projection ALTER COLUMN listing_price TYPE DECIMAL(19,6);
+ALTER TABLE listings_projection ALTER COLUMN minimum_price TYPE DECIMAL(19,6);
+ALTER TABLE listings_projection ALTER COLUMN current_price TYPE DECIMAL(19,6);
+ALTER TABLE listings_projection ALTER COLUMN highest_bid TYPE DECIMAL(19,6);
+
+ALTER TABLE bids_projection ALTER COLUMN bid_price TYPE DECIMAL(19,6);
+ALTER TABLE bids_projection ALTER COLUMN average_fill_price TYPE DECIMAL(19,6);
+ALTER TABLE bids_projection ALTER COLUMN total_bid_amount TYPE DECIMAL(19,6);
+
+ALTER TABLE trades_projection ALTER COLUMN trade_price TYPE DECIMAL(19,6);
+ALTER TABLE trades_projection ALTER COLUMN total_amount TYPE DECIMAL(19,6);
+ALTER TABLE trades_projection ALTER COLUMN fees TYPE DECIMAL(19,6);
+ALTER TABLE trades_projection ALTER COLUMN taxes TYPE DECIMAL(19,6);
+ALTER TABLE trades_projection ALTER COLUMN net_amount TYPE DECIMAL(19,6);
+ALTER TABLE trades_projection ALTER COLUMN payment_amount TYPE DECIMAL(19,6);
+ALTER TABLE trades_projection ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE market_data_projection ALTER COLUMN last_price TYPE DECIMAL(19,6);
+ALTER TABLE market_data_projection ALTER COLUMN high_price TYPE DECIMAL(19,6);
+ALTER TABLE market_data_projection ALTER COLUMN low_price TYPE DECIMAL(19,6);
+ALTER TABLE market_data_projection ALTER COLUMN open_price TYPE DECIMAL(19,6);
+ALTER TABLE market_data_projection ALTER COLUMN average_price TYPE DECIMAL(19,6);
+ALTER TABLE market_data_projection ALTER COLUMN vwap TYPE DECIMAL(19,6);
+ALTER TABLE market_data_projection ALTER COLUMN best_bid TYPE DECIMAL(19,6);
+ALTER TABLE market_data_projection ALTER COLUMN best_ask TYPE DECIMAL(19,6);
+ALTER TABLE market_data_projection ALTER COLUMN spread TYPE DECIMAL(19,6);
+
+ALTER TABLE securities_projection ALTER COLUMN par_value TYPE DECIMAL(19,6);
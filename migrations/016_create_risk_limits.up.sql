@@ -0,0 +1,17 @@
+-- Risk limits edited by compliance and enforced by the risk engine. An empty
+-- user_id or security_id applies the limit to every user or security; the
+-- most specific limit wins.
+CREATE TABLE risk_limits (
+    limit_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    limit_type VARCHAR(50) NOT NULL, -- 'position', 'concentration', 'counterparty_exposure', 'trade_notional', 'daily_notional'
+    user_id VARCHAR(100) NOT NULL DEFAULT '',
+    security_id VARCHAR(100) NOT NULL DEFAULT '',
+    limit_value DECIMAL(24,6) NOT NULL CHECK (limit_value > 0), -- Shares, a fraction of shares outstanding, or an amount
+
+    updated_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    UNIQUE (limit_type, user_id, security_id)
+);
+
+CREATE INDEX idx_risk_limits_user_id ON risk_limits(user_id);
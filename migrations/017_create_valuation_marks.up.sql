@@ -0,0 +1,10 @@
+-- Valuation marks set by administrators for illiquid securities. A mark is
+-- used as the security's reference price in place of one computed from trades.
+CREATE TABLE valuation_marks (
+    security_id VARCHAR(100) PRIMARY KEY,
+    mark_price DECIMAL(24,6) NOT NULL CHECK (mark_price > 0),
+    note TEXT,
+
+    set_by VARCHAR(100) NOT NULL,
+    set_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
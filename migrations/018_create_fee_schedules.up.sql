@@ -0,0 +1,19 @@
+-- Fee schedules edited by administrators and applied when trades settle. An
+-- empty security_type makes the schedule the default for security types
+-- without their own. Commission rates are in basis points of the trade value.
+CREATE TABLE fee_schedules (
+    schedule_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    security_type VARCHAR(50) NOT NULL DEFAULT '' UNIQUE,
+    buyer_maker_bps DECIMAL(12,6) NOT NULL DEFAULT 0 CHECK (buyer_maker_bps >= 0),
+    buyer_taker_bps DECIMAL(12,6) NOT NULL DEFAULT 0 CHECK (buyer_taker_bps >= 0),
+    seller_maker_bps DECIMAL(12,6) NOT NULL DEFAULT 0 CHECK (seller_maker_bps >= 0),
+    seller_taker_bps DECIMAL(12,6) NOT NULL DEFAULT 0 CHECK (seller_taker_bps >= 0),
+    tiers JSONB NOT NULL DEFAULT '[]', -- Volume discounts: [{"minimumVolume": "...", "discount": "..."}]
+    minimum_fee DECIMAL(24,6) NOT NULL DEFAULT 0 CHECK (minimum_fee >= 0),
+    maximum_fee DECIMAL(24,6) NOT NULL DEFAULT 0 CHECK (maximum_fee >= 0), -- 0 for no cap
+    broker_share DECIMAL(7,6) NOT NULL DEFAULT 0 CHECK (broker_share BETWEEN 0 AND 1),
+    platform_fee DECIMAL(24,6) NOT NULL DEFAULT 0 CHECK (platform_fee >= 0),
+
+    updated_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
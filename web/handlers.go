@@ -1,10 +1,13 @@
 package web
 
 import (
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/gorilla/mux"
+
+	"securities-marketplace/domains/shared/audit"
 )
 
 // Page data structures for templates
@@ -41,6 +44,14 @@ type TradingData struct {
 	MarketData     []*MarketStats
 }
 
+type AuditLogData struct {
+	PageData
+	Entries  []*audit.Entry
+	Filter   audit.Filter
+	NextPage int
+	PrevPage int
+}
+
 // handleHome serves the home page
 func (s *Server) handleHome(w http.ResponseWriter, r *http.Request) {
 	user, _ := s.getCurrentUser(r)
@@ -287,6 +298,20 @@ func (s *Server) handleTrading(w http.ResponseWriter, r *http.Request) {
 	s.renderTemplate(w, "trading.html", data)
 }
 
+// handleOrderBook shows a security's depth, top of book and indicative
+// auction price; the page loads the book from the trading API
+func (s *Server) handleOrderBook(w http.ResponseWriter, r *http.Request) {
+	user, _ := s.getCurrentUser(r)
+	
+	data := PageData{
+		Title:      "Order Book",
+		User:       user,
+		IsLoggedIn: true,
+	}
+	
+	s.renderTemplate(w, "orderbook.html", data)
+}
+
 // handleListings shows all listings
 func (s *Server) handleListings(w http.ResponseWriter, r *http.Request) {
 	user, _ := s.getCurrentUser(r)
@@ -508,15 +533,54 @@ func (s *Server) handleAdminCompliance(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleAdminAudit(w http.ResponseWriter, r *http.Request) {
 	user, _ := s.getCurrentUser(r)
 	
-	data := PageData{
-		Title:      "Audit Log",
-		User:       user,
-		IsLoggedIn: true,
+	data := AuditLogData{
+		PageData: PageData{
+			Title:      "Audit Log",
+			User:       user,
+			IsLoggedIn: true,
+		},
+		PrevPage: -1,
+		NextPage: -1,
+	}
+	
+	filter, err := audit.ParseFilter(r)
+	if err != nil {
+		data.Messages = append(data.Messages, Message{Type: "error", Content: err.Error()})
+	}
+	data.Filter = filter
+	
+	if s.auditLog != nil {
+		entries, err := s.auditLog.Query(filter)
+		if err != nil {
+			log.Printf("Failed to load audit entries: %v", err)
+			data.Messages = append(data.Messages, Message{Type: "error", Content: "Failed to load audit log"})
+		}
+		data.Entries = entries
+	}
+	
+	if filter.Offset > 0 {
+		data.PrevPage = filter.Offset - filter.Limit
+		if data.PrevPage < 0 {
+			data.PrevPage = 0
+		}
+	}
+	if len(data.Entries) == filter.Limit {
+		data.NextPage = filter.Offset + filter.Limit
 	}
 	
 	s.renderTemplate(w, "admin_audit.html", data)
 }
 
+// handleAuditExport streams the filtered audit log as CSV
+func (s *Server) handleAuditExport(w http.ResponseWriter, r *http.Request) {
+	if s.auditLog == nil {
+		http.Error(w, "Audit log not available", http.StatusServiceUnavailable)
+		return
+	}
+	
+	audit.NewHandler(s.auditLog).HandleExport(w, r)
+}
+
 // API handlers
 func (s *Server) handleAPIMarketData(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
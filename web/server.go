@@ -10,6 +10,8 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/sessions"
+
+	"securities-marketplace/domains/shared/audit"
 )
 
 // Server represents the web server
@@ -25,6 +27,7 @@ type Server struct {
 	listingService  ListingService
 	biddingService  BiddingService
 	executionService ExecutionService
+	auditLog         audit.Logger
 }
 
 // UserService interface for user operations
@@ -95,6 +98,11 @@ func (s *Server) SetServices(
 	s.executionService = executionService
 }
 
+// SetAuditLogger sets the audit log shown on the admin and compliance pages
+func (s *Server) SetAuditLogger(auditLog audit.Logger) {
+	s.auditLog = auditLog
+}
+
 // loadTemplates loads HTML templates
 func (s *Server) loadTemplates() {
 	templatePattern := "templates/**/*.html"
@@ -140,6 +148,7 @@ func (s *Server) setupRoutes() {
 	
 	// Trading
 	protected.HandleFunc("/trading", s.handleTrading).Methods("GET")
+	protected.HandleFunc("/trading/orderbook", s.handleOrderBook).Methods("GET")
 	protected.HandleFunc("/trading/listings", s.handleListings).Methods("GET")
 	protected.HandleFunc("/trading/listings/{id}", s.handleListingDetail).Methods("GET")
 	protected.HandleFunc("/trading/bids", s.handleBids).Methods("GET")
@@ -158,6 +167,12 @@ func (s *Server) setupRoutes() {
 	admin.HandleFunc("/compliance", s.handleAdminCompliance).Methods("GET")
 	admin.HandleFunc("/audit", s.handleAdminAudit).Methods("GET")
 	
+	// Compliance routes (require compliance or admin role)
+	compliance := protected.PathPrefix("/compliance").Subrouter()
+	compliance.Use(s.complianceMiddleware)
+	compliance.HandleFunc("/audit", s.handleAdminAudit).Methods("GET")
+	compliance.HandleFunc("/audit/export", s.handleAuditExport).Methods("GET")
+	
 	// API routes for AJAX/JSON
 	api := s.router.PathPrefix("/api").Subrouter()
 	api.Use(s.authMiddleware)